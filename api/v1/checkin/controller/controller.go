@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type CheckInController interface {
+	Create(c *gin.Context)
+	Manifest(c *gin.Context)
+	ManifestKey(c *gin.Context)
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"bioskuy/api/v1/checkin/dto"
+	"bioskuy/api/v1/checkin/service"
+	"bioskuy/exception"
+	"bioskuy/web"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type checkInControllerImpl struct {
+	checkInService service.CheckInService
+}
+
+func NewCheckInController(checkInService service.CheckInService) CheckInController {
+	return &checkInControllerImpl{checkInService: checkInService}
+}
+
+func (ctl *checkInControllerImpl) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.CheckInRequest{}
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, err := ctl.checkInService.CheckIn(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusCreated, web.FormatResponse{ResponseCode: http.StatusCreated, Data: result})
+}
+
+// Manifest returns the signed list of a showtime's tickets for scanners to
+// download before doors open.
+func (ctl *checkInControllerImpl) Manifest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.checkInService.Manifest(ctx, c.Param("showtimeId"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+// ManifestKey returns the public key scanners pin to verify manifests.
+func (ctl *checkInControllerImpl) ManifestKey(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.checkInService.ManifestKey(ctx, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"bioskuy/api/v1/checkin/dto"
+	"bioskuy/api/v1/checkin/mock/servicemock"
+	"bioskuy/exception"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckInControllerTestSuite struct {
+	suite.Suite
+	mockService *servicemock.MockCheckInService
+	controller  CheckInController
+	router      *gin.Engine
+}
+
+func (suite *CheckInControllerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockService = new(servicemock.MockCheckInService)
+	suite.controller = NewCheckInController(suite.mockService)
+	suite.router = gin.New()
+	suite.router.Use(exception.ErrorHandler)
+
+	signedIn := func(c *gin.Context) {
+		c.Set("user_id", "staff-1")
+		c.Set("role", "staff")
+		c.Set("cinema_id", "cinema-1")
+	}
+	suite.router.POST("/checkin", signedIn, suite.controller.Create)
+	suite.router.GET("/checkin/showtimes/:showtimeId/manifest", signedIn, suite.controller.Manifest)
+	suite.router.GET("/checkin/manifest-key", signedIn, suite.controller.ManifestKey)
+}
+
+func TestCheckInControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckInControllerTestSuite))
+}
+
+func (suite *CheckInControllerTestSuite) TestCreate_Success() {
+	request := dto.CheckInRequest{Token: "payload.signature", StudioID: "studio-1"}
+	suite.mockService.On("CheckIn", mock.Anything, request, mock.Anything).Return(dto.CheckInResponse{ID: "checkin-1", TicketID: "ticket-1", SeatName: "A1"}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/checkin", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"seat_name":"A1"`)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *CheckInControllerTestSuite) TestCreate_AlreadyCheckedIn() {
+	request := dto.CheckInRequest{Token: "payload.signature", StudioID: "studio-1"}
+	suite.mockService.On("CheckIn", mock.Anything, request, mock.Anything).Return(dto.CheckInResponse{}, errors.New("ticket was already checked in")).Run(func(args mock.Arguments) {
+		c := args.Get(2).(*gin.Context)
+		c.Error(exception.ConflictError{Message: "ticket was already checked in at 2024-08-02 19:02"}).SetType(gin.ErrorTypePublic)
+	})
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/checkin", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "already checked in")
+}
+
+func (suite *CheckInControllerTestSuite) TestCreate_BadJSON() {
+	req := httptest.NewRequest(http.MethodPost, "/checkin", bytes.NewBufferString(`{"token":`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "CheckIn")
+}
+
+func (suite *CheckInControllerTestSuite) TestManifest_Success() {
+	suite.mockService.On("Manifest", mock.Anything, "showtime-1", mock.Anything).Return(dto.ManifestResponse{
+		Manifest:  json.RawMessage(`{"showtime_id":"showtime-1","tickets":[]}`),
+		Signature: "signature",
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/checkin/showtimes/showtime-1/manifest", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"manifest":{"showtime_id":"showtime-1","tickets":[]}`)
+	assert.Contains(suite.T(), w.Body.String(), `"signature":"signature"`)
+}
+
+func (suite *CheckInControllerTestSuite) TestManifestKey_Success() {
+	suite.mockService.On("ManifestKey", mock.Anything, mock.Anything).Return(dto.ManifestKeyResponse{PublicKey: "public-key"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/checkin/manifest-key", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"public_key":"public-key"`)
+}
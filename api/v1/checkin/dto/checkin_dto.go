@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CheckInRequest is a scanned ticket. Scanners that worked offline upload
+// their check-ins afterwards with the time each ticket was scanned.
+type CheckInRequest struct {
+	Token     string     `json:"token" validate:"required"`
+	StudioID  string     `json:"studio_id" validate:"required"`
+	ScannedAt *time.Time `json:"scanned_at"`
+}
+
+// CheckInResponse shows show times in the cinema's timezone.
+type CheckInResponse struct {
+	ID          string    `json:"id"`
+	TicketID    string    `json:"ticket_id"`
+	ShowtimeID  string    `json:"showtime_id"`
+	SeatName    string    `json:"seat_name"`
+	MovieTitle  string    `json:"movie_title"`
+	StudioName  string    `json:"studio_name"`
+	ShowStart   time.Time `json:"show_start"`
+	ScannedAt   time.Time `json:"scanned_at"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+}
+
+// ManifestResponse carries a signed manifest. Manifest is kept as the exact
+// bytes that were signed, so scanners verify Signature against it as sent,
+// using the public key they pinned from ManifestKeyResponse.
+type ManifestResponse struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	Signature string          `json:"signature"`
+}
+
+// ManifestKeyResponse is the key manifests are verified against. It is
+// served on its own so a scanner pins it once instead of trusting whatever
+// key comes with a manifest.
+type ManifestKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+// Manifest is what a scanner needs to admit a showtime's audience offline.
+// Tickets are listed by the hash of their token, see manifest.TokenHash.
+type Manifest struct {
+	ShowtimeID     string           `json:"showtime_id"`
+	StudioID       string           `json:"studio_id"`
+	ShowStart      time.Time        `json:"show_start"`
+	ShowEnd        time.Time        `json:"show_end"`
+	Timezone       string           `json:"timezone"`
+	CheckInOpensAt time.Time        `json:"check_in_opens_at"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+	ValidUntil     time.Time        `json:"valid_until"`
+	Tickets        []ManifestTicket `json:"tickets"`
+}
+
+type ManifestTicket struct {
+	TicketID    string     `json:"ticket_id"`
+	SeatName    string     `json:"seat_name"`
+	TokenHash   string     `json:"token_hash"`
+	CheckedInAt *time.Time `json:"checked_in_at"`
+}
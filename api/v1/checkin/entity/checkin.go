@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// CheckIn records a ticket being admitted at the door. A ticket can be
+// checked in once.
+type CheckIn struct {
+	ID          string `json:"id"`
+	TicketID    string `json:"ticket_id"`
+	StudioID    string `json:"studio_id"`
+	CheckedInBy string `json:"checked_in_by"`
+	// ScannedAt is when the scanner read the ticket, which is earlier than
+	// CheckedInAt for check-ins uploaded after working offline.
+	ScannedAt   time.Time `json:"scanned_at"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+}
+
+// Manifest lists the valid tickets of a showtime for scanners to work from
+// while offline.
+type Manifest struct {
+	ShowtimeID string    `json:"showtime_id"`
+	StudioID   string    `json:"studio_id"`
+	CinemaID   string    `json:"cinema_id"`
+	ShowStart  time.Time `json:"show_start"`
+	ShowEnd    time.Time `json:"show_end"`
+	// Timezone is the IANA timezone of the cinema.
+	Timezone string           `json:"timezone"`
+	Tickets  []ManifestTicket `json:"tickets"`
+}
+
+type ManifestTicket struct {
+	TicketID  string `json:"ticket_id"`
+	PaymentID string `json:"payment_id"`
+	SeatID    string `json:"seat_id"`
+	SeatName  string `json:"seat_name"`
+	// CheckedInAt is set for tickets already checked in when the manifest
+	// was made.
+	CheckedInAt *time.Time `json:"checked_in_at"`
+}
@@ -0,0 +1,61 @@
+// Package manifest signs the per-showtime ticket lists that scanners download
+// to keep admitting people while the network is down. Manifests are signed
+// with Ed25519 so a scanner can check one with the public key alone and never
+// holds the secret that ticket tokens are signed with.
+package manifest
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+// ParseKey reads the signing key from its base64 Ed25519 seed, as set in
+// MANIFEST_SIGNING_KEY. The key is kept apart from SECRET_KEY so a leaked
+// JWT or ticket secret does not let anyone sign manifests too.
+func ParseKey(seed string) (ed25519.PrivateKey, error) {
+	if seed == "" {
+		return nil, errors.New("manifest signing key is not configured")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil || len(decoded) != ed25519.SeedSize {
+		return nil, errors.New("manifest signing key must be a base64 32-byte seed")
+	}
+	return ed25519.NewKeyFromSeed(decoded), nil
+}
+
+// PublicKey is the base64 public key that manifests signed with key verify
+// against. Scanners fetch it once and pin it rather than trusting a key
+// that arrives with a manifest.
+func PublicKey(key ed25519.PrivateKey) string {
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+// Sign returns the base64 Ed25519 signature of body.
+func Sign(key ed25519.PrivateKey, body []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, body))
+}
+
+// Verify reports whether signature is a valid signature of body under the
+// base64 public key.
+func Verify(publicKey string, body []byte, signature string) bool {
+	pub, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, body, sig)
+}
+
+// TokenHash is how a manifest refers to a ticket token: the hex SHA-256 of
+// the token as scanned. Listing hashes rather than tokens keeps a leaked
+// manifest from being turned into tickets.
+func TokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,53 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	seed      = "0iCGB/qN/KR+UbR/PVsML3JppaNvn3zsMXmECAwlI64="
+	otherSeed = "UK1JnmWwfFQfp1CQhlpylccGJ6zgXQL47z2s2coXLs8="
+)
+
+func TestSignAndVerify(t *testing.T) {
+	key, err := ParseKey(seed)
+	assert.NoError(t, err)
+	other, err := ParseKey(otherSeed)
+	assert.NoError(t, err)
+	body := []byte(`{"showtime_id":"showtime-1"}`)
+
+	signature := Sign(key, body)
+
+	assert.True(t, Verify(PublicKey(key), body, signature))
+	assert.False(t, Verify(PublicKey(other), body, signature))
+	assert.False(t, Verify(PublicKey(key), []byte(`{"showtime_id":"showtime-2"}`), signature))
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	key, err := ParseKey(seed)
+	assert.NoError(t, err)
+	body := []byte(`{}`)
+
+	assert.False(t, Verify("not base64!", body, Sign(key, body)))
+	assert.False(t, Verify("c2hvcnQ=", body, Sign(key, body)))
+	assert.False(t, Verify(PublicKey(key), body, "not base64!"))
+}
+
+func TestParseKey_Invalid(t *testing.T) {
+	_, err := ParseKey("")
+	assert.Error(t, err)
+
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+
+	_, err = ParseKey("c2hvcnQ=")
+	assert.Error(t, err)
+}
+
+func TestTokenHash(t *testing.T) {
+	assert.Equal(t, TokenHash("payload.signature"), TokenHash("payload.signature"))
+	assert.NotEqual(t, TokenHash("payload.signature"), TokenHash("payload.signature2"))
+	assert.Len(t, TokenHash("payload.signature"), 64)
+}
@@ -0,0 +1,24 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/checkin/entity"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCheckInRepository struct {
+	mock.Mock
+}
+
+func (m *MockCheckInRepository) Save(ctx context.Context, tx *sql.Tx, checkIn entity.CheckIn, c *gin.Context) (entity.CheckIn, bool, error) {
+	args := m.Called(ctx, tx, checkIn, c)
+	return args.Get(0).(entity.CheckIn), args.Bool(1), args.Error(2)
+}
+
+func (m *MockCheckInRepository) FindManifest(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (entity.Manifest, error) {
+	args := m.Called(ctx, tx, showtimeID, c)
+	return args.Get(0).(entity.Manifest), args.Error(1)
+}
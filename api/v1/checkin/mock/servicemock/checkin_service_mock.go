@@ -0,0 +1,28 @@
+package servicemock
+
+import (
+	"bioskuy/api/v1/checkin/dto"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCheckInService struct {
+	mock.Mock
+}
+
+func (m *MockCheckInService) CheckIn(ctx context.Context, request dto.CheckInRequest, c *gin.Context) (dto.CheckInResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.CheckInResponse), args.Error(1)
+}
+
+func (m *MockCheckInService) Manifest(ctx context.Context, showtimeID string, c *gin.Context) (dto.ManifestResponse, error) {
+	args := m.Called(ctx, showtimeID, c)
+	return args.Get(0).(dto.ManifestResponse), args.Error(1)
+}
+
+func (m *MockCheckInService) ManifestKey(ctx context.Context, c *gin.Context) (dto.ManifestKeyResponse, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).(dto.ManifestKeyResponse), args.Error(1)
+}
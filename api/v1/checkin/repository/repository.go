@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"bioskuy/api/v1/checkin/entity"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckInRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, checkIn entity.CheckIn, c *gin.Context) (entity.CheckIn, bool, error)
+	FindManifest(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (entity.Manifest, error)
+}
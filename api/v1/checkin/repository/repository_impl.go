@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"bioskuy/api/v1/checkin/entity"
+	"bioskuy/exception"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type checkInRepository struct {
+}
+
+func NewCheckInRepository() CheckInRepository {
+	return &checkInRepository{}
+}
+
+// Save records the check-in and reports whether it was new. When the ticket
+// was already checked in, the earlier check-in is returned instead.
+func (r *checkInRepository) Save(ctx context.Context, tx *sql.Tx, checkIn entity.CheckIn, c *gin.Context) (entity.CheckIn, bool, error) {
+	query := `INSERT INTO ticket_checkins (ticket_id, studio_id, checked_in_by, scanned_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (ticket_id) DO NOTHING
+		RETURNING id, checked_in_at`
+
+	err := tx.QueryRowContext(ctx, query, checkIn.TicketID, checkIn.StudioID, checkIn.CheckedInBy, checkIn.ScannedAt).Scan(&checkIn.ID, &checkIn.CheckedInAt)
+	if err == nil {
+		return checkIn, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkIn, false, err
+	}
+
+	existing := entity.CheckIn{}
+	query = `SELECT id, ticket_id, studio_id, checked_in_by, scanned_at, checked_in_at
+		FROM ticket_checkins WHERE ticket_id = $1`
+
+	err = tx.QueryRowContext(ctx, query, checkIn.TicketID).Scan(&existing.ID, &existing.TicketID, &existing.StudioID, &existing.CheckedInBy, &existing.ScannedAt, &existing.CheckedInAt)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return existing, false, err
+	}
+
+	return existing, false, nil
+}
+
+// FindManifest returns a showtime that has not been cancelled together with
+// its valid tickets.
+func (r *checkInRepository) FindManifest(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (entity.Manifest, error) {
+	manifest := entity.Manifest{}
+
+	query := `SELECT sh.id, sh.studio_id, st.cinema_id, sh.show_start, sh.show_end, ci.timezone
+		FROM showtimes sh
+		JOIN studios st ON sh.studio_id = st.id
+		JOIN cinemas ci ON st.cinema_id = ci.id
+		WHERE sh.id::text = $1 AND sh.cancelled_at IS NULL`
+
+	err := tx.QueryRowContext(ctx, query, showtimeID).Scan(&manifest.ShowtimeID, &manifest.StudioID, &manifest.CinemaID, &manifest.ShowStart, &manifest.ShowEnd, &manifest.Timezone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return manifest, errors.New("showtime not found")
+	}
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifest, err
+	}
+
+	query = `SELECT t.id, t.payment_id, t.seat_id, se.seat_name, ck.checked_in_at
+		FROM tickets t
+		JOIN seats se ON t.seat_id = se.id
+		LEFT JOIN ticket_checkins ck ON ck.ticket_id = t.id
+		WHERE t.showtime_id = $1 AND t.voided_at IS NULL
+		ORDER BY se.seat_name, t.id`
+
+	rows, err := tx.QueryContext(ctx, query, manifest.ShowtimeID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifest, err
+	}
+	defer rows.Close()
+
+	manifest.Tickets = []entity.ManifestTicket{}
+	for rows.Next() {
+		ticket := entity.ManifestTicket{}
+		var checkedInAt sql.NullTime
+
+		if err := rows.Scan(&ticket.TicketID, &ticket.PaymentID, &ticket.SeatID, &ticket.SeatName, &checkedInAt); err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return manifest, err
+		}
+		if checkedInAt.Valid {
+			ticket.CheckedInAt = &checkedInAt.Time
+		}
+		manifest.Tickets = append(manifest.Tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifest, err
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"bioskuy/api/v1/checkin/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckInRepositoryTestSuite struct {
+	suite.Suite
+	mockDb  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    CheckInRepository
+	ctx     context.Context
+	ginCtx  *gin.Context
+}
+
+var scannedAt = time.Date(2024, 8, 2, 12, 15, 0, 0, time.UTC)
+
+func (suite *CheckInRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.repo = NewCheckInRepository()
+	suite.ctx = context.Background()
+	suite.ginCtx = &gin.Context{}
+}
+
+func (suite *CheckInRepositoryTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestCheckInRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckInRepositoryTestSuite))
+}
+
+func (suite *CheckInRepositoryTestSuite) begin() *sql.Tx {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+	return tx
+}
+
+func (suite *CheckInRepositoryTestSuite) TestSave_New() {
+	tx := suite.begin()
+	checkIn := entity.CheckIn{TicketID: "ticket-1", StudioID: "studio-1", CheckedInBy: "staff-1", ScannedAt: scannedAt}
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`INSERT INTO ticket_checkins (ticket_id, studio_id, checked_in_by, scanned_at)`)+`.*`+regexp.QuoteMeta(`ON CONFLICT (ticket_id) DO NOTHING`)).
+		WithArgs("ticket-1", "studio-1", "staff-1", scannedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "checked_in_at"}).AddRow("checkin-1", scannedAt.Add(time.Second)))
+
+	saved, isNew, err := suite.repo.Save(suite.ctx, tx, checkIn, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), isNew)
+	assert.Equal(suite.T(), "checkin-1", saved.ID)
+	assert.Equal(suite.T(), scannedAt.Add(time.Second), saved.CheckedInAt)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CheckInRepositoryTestSuite) TestSave_AlreadyCheckedIn() {
+	tx := suite.begin()
+	checkIn := entity.CheckIn{TicketID: "ticket-1", StudioID: "studio-1", CheckedInBy: "staff-2", ScannedAt: scannedAt.Add(time.Hour)}
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`INSERT INTO ticket_checkins`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "checked_in_at"}))
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM ticket_checkins WHERE ticket_id = $1`)).
+		WithArgs("ticket-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticket_id", "studio_id", "checked_in_by", "scanned_at", "checked_in_at"}).
+			AddRow("checkin-1", "ticket-1", "studio-1", "staff-1", scannedAt, scannedAt))
+
+	existing, isNew, err := suite.repo.Save(suite.ctx, tx, checkIn, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), isNew)
+	assert.Equal(suite.T(), "checkin-1", existing.ID)
+	assert.Equal(suite.T(), "staff-1", existing.CheckedInBy)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CheckInRepositoryTestSuite) TestSave_Error() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`INSERT INTO ticket_checkins`)).
+		WillReturnError(errors.New("insert error"))
+
+	_, _, err := suite.repo.Save(suite.ctx, tx, entity.CheckIn{TicketID: "ticket-1"}, suite.ginCtx)
+	assert.Error(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CheckInRepositoryTestSuite) TestFindManifest_Success() {
+	tx := suite.begin()
+	showStart := time.Date(2024, 8, 2, 12, 30, 0, 0, time.UTC)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`WHERE sh.id::text = $1 AND sh.cancelled_at IS NULL`)).
+		WithArgs("showtime-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "cinema_id", "show_start", "show_end", "timezone"}).
+			AddRow("showtime-1", "studio-1", "cinema-1", showStart, showStart.Add(2*time.Hour), "Asia/Jakarta"))
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`WHERE t.showtime_id = $1 AND t.voided_at IS NULL`)).
+		WithArgs("showtime-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payment_id", "seat_id", "seat_name", "checked_in_at"}).
+			AddRow("ticket-1", "payment-1", "seat-1", "A1", scannedAt).
+			AddRow("ticket-2", "payment-1", "seat-2", "A2", nil))
+
+	manifest, err := suite.repo.FindManifest(suite.ctx, tx, "showtime-1", suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "cinema-1", manifest.CinemaID)
+	assert.Len(suite.T(), manifest.Tickets, 2)
+	assert.Equal(suite.T(), scannedAt, *manifest.Tickets[0].CheckedInAt)
+	assert.Nil(suite.T(), manifest.Tickets[1].CheckedInAt)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CheckInRepositoryTestSuite) TestFindManifest_NotFound() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM showtimes sh`)).
+		WithArgs("showtime-9").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := suite.repo.FindManifest(suite.ctx, tx, "showtime-9", suite.ginCtx)
+	assert.EqualError(suite.T(), err, "showtime not found")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,35 @@
+package route
+
+import (
+	"bioskuy/api/v1/checkin/controller"
+	"bioskuy/api/v1/checkin/repository"
+	"bioskuy/api/v1/checkin/service"
+	ticketRepo "bioskuy/api/v1/ticket/repository"
+	"bioskuy/auth"
+	"bioskuy/helper"
+	"bioskuy/middleware"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func CheckInRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB, config *helper.Config) {
+
+	authService := auth.NewService(config)
+
+	checkInRepo := repository.NewCheckInRepository()
+	ticketRepo := ticketRepo.NewTicketRepository()
+	checkInService := service.NewCheckInService(checkInRepo, ticketRepo, validate, db, config)
+	checkInController := controller.NewCheckInController(checkInService)
+
+	v1 := router.Group("/api/v1")
+	{
+		checkin := v1.Group("/checkin")
+		{
+			checkin.POST("/", middleware.AuthMiddleware(authService, "staff", "branch admin", "admin", "super admin"), checkInController.Create)
+			checkin.GET("/showtimes/:showtimeId/manifest", middleware.AuthMiddleware(authService, "staff", "branch admin", "admin", "super admin"), checkInController.Manifest)
+			checkin.GET("/manifest-key", middleware.AuthMiddleware(authService, "staff", "branch admin", "admin", "super admin"), checkInController.ManifestKey)
+		}
+	}
+}
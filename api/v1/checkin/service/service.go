@@ -0,0 +1,14 @@
+package service
+
+import (
+	"bioskuy/api/v1/checkin/dto"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckInService interface {
+	CheckIn(ctx context.Context, request dto.CheckInRequest, c *gin.Context) (dto.CheckInResponse, error)
+	Manifest(ctx context.Context, showtimeID string, c *gin.Context) (dto.ManifestResponse, error)
+	ManifestKey(ctx context.Context, c *gin.Context) (dto.ManifestKeyResponse, error)
+}
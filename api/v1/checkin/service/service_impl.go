@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bioskuy/api/v1/checkin/dto"
+	"bioskuy/api/v1/checkin/entity"
+	"bioskuy/api/v1/checkin/manifest"
+	"bioskuy/api/v1/checkin/repository"
+	entityTicket "bioskuy/api/v1/ticket/entity"
+	RepoTicket "bioskuy/api/v1/ticket/repository"
+	"bioskuy/api/v1/ticket/token"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// clockSkew is how far ahead of the server a scanner's clock may run.
+const clockSkew = time.Minute
+
+type checkInServiceImpl struct {
+	Repo       repository.CheckInRepository
+	RepoTicket RepoTicket.TicketRepository
+	Validate   *validator.Validate
+	DB         *sql.DB
+	Env        *helper.Config
+}
+
+func NewCheckInService(repo repository.CheckInRepository, repoTicket RepoTicket.TicketRepository, validate *validator.Validate, DB *sql.DB, env *helper.Config) CheckInService {
+	return &checkInServiceImpl{
+		Repo:       repo,
+		RepoTicket: repoTicket,
+		Validate:   validate,
+		DB:         DB,
+		Env:        env,
+	}
+}
+
+// CheckIn admits the scanned ticket once, provided its signature holds, it is
+// for the studio being scanned at and its showtime is open for check-in at
+// the time it was scanned.
+func (s *checkInServiceImpl) CheckIn(ctx context.Context, request dto.CheckInRequest, c *gin.Context) (dto.CheckInResponse, error) {
+	checkInResponse := dto.CheckInResponse{}
+
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	now := time.Now().UTC()
+	scannedAt := now
+	if request.ScannedAt != nil {
+		scannedAt = request.ScannedAt.UTC()
+	}
+	if scannedAt.After(now.Add(clockSkew)) {
+		err := errors.New("scanned_at is in the future")
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+	if scannedAt.Before(now.Add(-s.Env.ManifestValidity())) {
+		err := errors.New("scanned_at is older than a manifest stays valid offline")
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	payload, err := token.Verify(s.Env.SecretKey, request.Token)
+	if err != nil {
+		c.Error(exception.ForbiddenError{Message: "invalid ticket signature"}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	ticket, err := s.RepoTicket.FindByID(ctx, tx, payload.TicketID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	if ticket.PaymentID != payload.PaymentID || ticket.ShowtimeID != payload.ShowtimeID || ticket.SeatID != payload.SeatID {
+		err := errors.New("invalid ticket signature")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	if !helper.CanWorkAtCinema(c, ticket.CinemaID) {
+		err := errors.New("you can only check in tickets at your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	if err := s.admits(ticket, request.StudioID, scannedAt); err != nil {
+		c.Error(exception.ConflictError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	checkIn, saved, err := s.Repo.Save(ctx, tx, entity.CheckIn{
+		TicketID:    ticket.ID,
+		StudioID:    request.StudioID,
+		CheckedInBy: c.GetString("user_id"),
+		ScannedAt:   scannedAt,
+	}, c)
+	if err != nil {
+		return checkInResponse, err
+	}
+	if !saved {
+		err := errors.New("ticket was already checked in at " + localTime(checkIn.ScannedAt, ticket.Timezone))
+		c.Error(exception.ConflictError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return checkInResponse, err
+	}
+
+	checkInResponse = dto.CheckInResponse{
+		ID:          checkIn.ID,
+		TicketID:    ticket.ID,
+		ShowtimeID:  ticket.ShowtimeID,
+		SeatName:    ticket.SeatName,
+		MovieTitle:  ticket.MovieTitle,
+		StudioName:  ticket.StudioName,
+		ShowStart:   helper.InZone(ticket.ShowStart, ticket.Timezone),
+		ScannedAt:   helper.InZone(checkIn.ScannedAt, ticket.Timezone),
+		CheckedInAt: helper.InZone(checkIn.CheckedInAt, ticket.Timezone),
+	}
+
+	return checkInResponse, nil
+}
+
+// admits reports why the ticket cannot be let into studioID at scannedAt.
+// Check-in opens CHECKIN_OPENS_BEFORE ahead of the show and closes when it
+// ends.
+func (s *checkInServiceImpl) admits(ticket entityTicket.Ticket, studioID string, scannedAt time.Time) error {
+	if ticket.Status() != entityTicket.TicketStatusValid {
+		return errors.New("ticket is " + ticket.Status())
+	}
+	if ticket.StudioID != studioID {
+		return errors.New("ticket is for " + ticket.StudioName)
+	}
+
+	opensAt := ticket.ShowStart.Add(-s.Env.CheckInOpensBeforeDuration())
+	if scannedAt.Before(opensAt) {
+		return errors.New("check-in opens at " + localTime(opensAt, ticket.Timezone))
+	}
+	if !scannedAt.Before(ticket.ShowEnd) {
+		return errors.New("showtime has ended")
+	}
+
+	return nil
+}
+
+// Manifest lists the showtime's valid tickets by token hash and signs the
+// list, so scanners can keep checking tickets in while offline.
+func (s *checkInServiceImpl) Manifest(ctx context.Context, showtimeID string, c *gin.Context) (dto.ManifestResponse, error) {
+	manifestResponse := dto.ManifestResponse{}
+
+	key, err := manifest.ParseKey(s.Env.ManifestSigningKey)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifestResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifestResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	result, err := s.Repo.FindManifest(ctx, tx, showtimeID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifestResponse, err
+	}
+
+	if !helper.CanWorkAtCinema(c, result.CinemaID) {
+		err := errors.New("you can only download manifests for your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifestResponse, err
+	}
+
+	generatedAt := time.Now().UTC()
+	body := dto.Manifest{
+		ShowtimeID:     result.ShowtimeID,
+		StudioID:       result.StudioID,
+		ShowStart:      helper.InZone(result.ShowStart, result.Timezone),
+		ShowEnd:        helper.InZone(result.ShowEnd, result.Timezone),
+		Timezone:       helper.Location(result.Timezone).String(),
+		CheckInOpensAt: helper.InZone(result.ShowStart.Add(-s.Env.CheckInOpensBeforeDuration()), result.Timezone),
+		GeneratedAt:    generatedAt,
+		ValidUntil:     generatedAt.Add(s.Env.ManifestValidity()),
+		Tickets:        []dto.ManifestTicket{},
+	}
+
+	for _, ticket := range result.Tickets {
+		signed, err := token.Sign(s.Env.SecretKey, token.Payload{
+			TicketID:   ticket.TicketID,
+			PaymentID:  ticket.PaymentID,
+			ShowtimeID: result.ShowtimeID,
+			SeatID:     ticket.SeatID,
+		})
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return manifestResponse, err
+		}
+
+		body.Tickets = append(body.Tickets, dto.ManifestTicket{
+			TicketID:    ticket.TicketID,
+			SeatName:    ticket.SeatName,
+			TokenHash:   manifest.TokenHash(signed),
+			CheckedInAt: ticket.CheckedInAt,
+		})
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifestResponse, err
+	}
+
+	manifestResponse = dto.ManifestResponse{
+		Manifest:  encoded,
+		Signature: manifest.Sign(key, encoded),
+	}
+
+	return manifestResponse, nil
+}
+
+// ManifestKey returns the public key manifests are signed under, for
+// scanners to pin.
+func (s *checkInServiceImpl) ManifestKey(ctx context.Context, c *gin.Context) (dto.ManifestKeyResponse, error) {
+	manifestKeyResponse := dto.ManifestKeyResponse{}
+
+	key, err := manifest.ParseKey(s.Env.ManifestSigningKey)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return manifestKeyResponse, err
+	}
+
+	manifestKeyResponse.PublicKey = manifest.PublicKey(key)
+
+	return manifestKeyResponse, nil
+}
+
+func localTime(t time.Time, timezone string) string {
+	return helper.InZone(t, timezone).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bioskuy/api/v1/checkin/dto"
+	"bioskuy/api/v1/checkin/entity"
+	"bioskuy/api/v1/checkin/manifest"
+	"bioskuy/api/v1/checkin/mock/repomock"
+	entityTicket "bioskuy/api/v1/ticket/entity"
+	ticketRepomock "bioskuy/api/v1/ticket/mock/repomock"
+	"bioskuy/api/v1/ticket/token"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckInServiceTestSuite struct {
+	suite.Suite
+	mockRepo       *repomock.MockCheckInRepository
+	mockRepoTicket *ticketRepomock.MockTicketRepository
+	mockDb         *sql.DB
+	mockSql        sqlmock.Sqlmock
+	service        CheckInService
+	ctx            context.Context
+	ginContext     *gin.Context
+	ticket         entityTicket.Ticket
+	token          string
+}
+
+var env = &helper.Config{SecretKey: "secret", CheckInOpensBefore: "60", ManifestSigningKey: "0iCGB/qN/KR+UbR/PVsML3JppaNvn3zsMXmECAwlI64=", ManifestValidFor: "720"}
+
+func (suite *CheckInServiceTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.mockRepo = new(repomock.MockCheckInRepository)
+	suite.mockRepoTicket = new(ticketRepomock.MockTicketRepository)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.service = NewCheckInService(suite.mockRepo, suite.mockRepoTicket, validator.New(), db, env)
+	suite.ctx = context.Background()
+	suite.ginContext, _ = gin.CreateTestContext(httptest.NewRecorder())
+	suite.ginContext.Set("user_id", "staff-1")
+	suite.ginContext.Set("role", "staff")
+	suite.ginContext.Set("cinema_id", "cinema-1")
+
+	showStart := time.Now().UTC().Add(30 * time.Minute)
+	suite.ticket = entityTicket.Ticket{
+		ID: "ticket-1", PaymentID: "payment-1", ShowtimeID: "showtime-1", SeatID: "seat-1", SeatName: "A1",
+		ShowStart: showStart, ShowEnd: showStart.Add(2 * time.Hour), Timezone: "Asia/Jakarta",
+		MovieTitle: "Movie", StudioID: "studio-1", StudioName: "Studio 1", CinemaID: "cinema-1",
+	}
+	suite.token, err = token.Sign(env.SecretKey, token.Payload{TicketID: "ticket-1", PaymentID: "payment-1", ShowtimeID: "showtime-1", SeatID: "seat-1"})
+	assert.NoError(suite.T(), err)
+}
+
+func TestCheckInServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckInServiceTestSuite))
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_Success() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepoTicket.On("FindByID", suite.ctx, mock.Anything, "ticket-1", suite.ginContext).Return(suite.ticket, nil)
+	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.MatchedBy(func(checkIn entity.CheckIn) bool {
+		return checkIn.TicketID == "ticket-1" && checkIn.StudioID == "studio-1" && checkIn.CheckedInBy == "staff-1"
+	}), suite.ginContext).Return(entity.CheckIn{ID: "checkin-1", TicketID: "ticket-1", ScannedAt: time.Now().UTC(), CheckedInAt: time.Now().UTC()}, true, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token, StudioID: "studio-1"}, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "checkin-1", response.ID)
+	assert.Equal(suite.T(), "A1", response.SeatName)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_InvalidSignature() {
+	_, err := suite.service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token + "x", StudioID: "studio-1"}, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ForbiddenError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepoTicket.AssertNotCalled(suite.T(), "FindByID")
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_AlreadyCheckedIn() {
+	earlier := time.Now().UTC().Add(-5 * time.Minute)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepoTicket.On("FindByID", suite.ctx, mock.Anything, "ticket-1", suite.ginContext).Return(suite.ticket, nil)
+	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.Anything, suite.ginContext).Return(entity.CheckIn{ID: "checkin-1", ScannedAt: earlier, CheckedInAt: earlier}, false, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token, StudioID: "studio-1"}, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ConflictError{}, suite.ginContext.Errors.Last().Err)
+	assert.Contains(suite.T(), err.Error(), "ticket was already checked in at ")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_NotAdmitted() {
+	voided := suite.ticket
+	voidedAt := time.Now().UTC()
+	voided.VoidedAt = &voidedAt
+	cancelled := suite.ticket
+	cancelled.ShowtimeCancelledAt = &voidedAt
+	later := suite.ticket
+	later.ShowStart = time.Now().UTC().Add(3 * time.Hour)
+	later.ShowEnd = later.ShowStart.Add(2 * time.Hour)
+	ended := suite.ticket
+	ended.ShowStart = time.Now().UTC().Add(-3 * time.Hour)
+	ended.ShowEnd = ended.ShowStart.Add(2 * time.Hour)
+
+	cases := []struct {
+		name     string
+		ticket   entityTicket.Ticket
+		studioID string
+		message  string
+	}{
+		{"voided", voided, "studio-1", "ticket is voided"},
+		{"showtime cancelled", cancelled, "studio-1", "ticket is cancelled"},
+		{"other studio", suite.ticket, "studio-2", "ticket is for Studio 1"},
+		{"too early", later, "studio-1", "check-in opens at " + localTime(later.ShowStart.Add(-time.Hour), "Asia/Jakarta")},
+		{"ended", ended, "studio-1", "showtime has ended"},
+	}
+
+	for _, tc := range cases {
+		db, sqlMock, err := sqlmock.New()
+		assert.NoError(suite.T(), err)
+		repoTicket := new(ticketRepomock.MockTicketRepository)
+		service := NewCheckInService(suite.mockRepo, repoTicket, validator.New(), db, env)
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ginContext.Set("role", "staff")
+		ginContext.Set("cinema_id", "cinema-1")
+
+		sqlMock.ExpectBegin()
+		repoTicket.On("FindByID", suite.ctx, mock.Anything, "ticket-1", ginContext).Return(tc.ticket, nil)
+		sqlMock.ExpectRollback()
+
+		_, err = service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token, StudioID: tc.studioID}, ginContext)
+
+		assert.EqualError(suite.T(), err, tc.message, tc.name)
+		assert.IsType(suite.T(), exception.ConflictError{}, ginContext.Errors.Last().Err, tc.name)
+		assert.NoError(suite.T(), sqlMock.ExpectationsWereMet(), tc.name)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save")
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_OfflineScan() {
+	ended := suite.ticket
+	ended.ShowStart = time.Now().UTC().Add(-3 * time.Hour)
+	ended.ShowEnd = ended.ShowStart.Add(2 * time.Hour)
+	scannedAt := ended.ShowStart.Add(-10 * time.Minute)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepoTicket.On("FindByID", suite.ctx, mock.Anything, "ticket-1", suite.ginContext).Return(ended, nil)
+	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.MatchedBy(func(checkIn entity.CheckIn) bool {
+		return checkIn.ScannedAt.Equal(scannedAt)
+	}), suite.ginContext).Return(entity.CheckIn{ID: "checkin-1", ScannedAt: scannedAt, CheckedInAt: time.Now().UTC()}, true, nil)
+	suite.mockSql.ExpectCommit()
+
+	_, err := suite.service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token, StudioID: "studio-1", ScannedAt: &scannedAt}, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_ScannedInFuture() {
+	future := time.Now().UTC().Add(time.Hour)
+
+	_, err := suite.service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token, StudioID: "studio-1", ScannedAt: &future}, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ValidationError{}, suite.ginContext.Errors.Last().Err)
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_ScannedBeforeOfflineWindow() {
+	stale := time.Now().UTC().Add(-env.ManifestValidity() - time.Minute)
+
+	_, err := suite.service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token, StudioID: "studio-1", ScannedAt: &stale}, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ValidationError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepoTicket.AssertNotCalled(suite.T(), "FindByID")
+}
+
+func (suite *CheckInServiceTestSuite) TestCheckIn_OtherCinemaForbidden() {
+	suite.ginContext.Set("cinema_id", "cinema-2")
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepoTicket.On("FindByID", suite.ctx, mock.Anything, "ticket-1", suite.ginContext).Return(suite.ticket, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.CheckIn(suite.ctx, dto.CheckInRequest{Token: suite.token, StudioID: "studio-1"}, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ForbiddenError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save")
+}
+
+func (suite *CheckInServiceTestSuite) TestManifest_Success() {
+	checkedInAt := time.Now().UTC()
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindManifest", suite.ctx, mock.Anything, "showtime-1", suite.ginContext).Return(entity.Manifest{
+		ShowtimeID: "showtime-1", StudioID: "studio-1", CinemaID: "cinema-1",
+		ShowStart: suite.ticket.ShowStart, ShowEnd: suite.ticket.ShowEnd, Timezone: "Asia/Jakarta",
+		Tickets: []entity.ManifestTicket{
+			{TicketID: "ticket-1", PaymentID: "payment-1", SeatID: "seat-1", SeatName: "A1", CheckedInAt: &checkedInAt},
+		},
+	}, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Manifest(suite.ctx, "showtime-1", suite.ginContext)
+	assert.NoError(suite.T(), err)
+	pinned, err := suite.service.ManifestKey(suite.ctx, suite.ginContext)
+	assert.NoError(suite.T(), err)
+
+	assert.True(suite.T(), manifest.Verify(pinned.PublicKey, response.Manifest, response.Signature))
+
+	body := dto.Manifest{}
+	assert.NoError(suite.T(), json.Unmarshal(response.Manifest, &body))
+	assert.Len(suite.T(), body.Tickets, 1)
+	assert.Equal(suite.T(), manifest.TokenHash(suite.token), body.Tickets[0].TokenHash)
+	assert.NotNil(suite.T(), body.Tickets[0].CheckedInAt)
+	assert.Equal(suite.T(), "Asia/Jakarta", body.Timezone)
+	assert.Equal(suite.T(), body.GeneratedAt.Add(12*time.Hour), body.ValidUntil)
+}
+
+func (suite *CheckInServiceTestSuite) TestManifest_SigningKeyNotConfigured() {
+	suite.service = NewCheckInService(suite.mockRepo, suite.mockRepoTicket, validator.New(), suite.mockDb, &helper.Config{SecretKey: "secret"})
+
+	_, err := suite.service.Manifest(suite.ctx, "showtime-1", suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.InternalServerError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindManifest")
+}
+
+func (suite *CheckInServiceTestSuite) TestManifestKey_DiffersFromSecretKey() {
+	other := &helper.Config{SecretKey: env.SecretKey, ManifestSigningKey: "UK1JnmWwfFQfp1CQhlpylccGJ6zgXQL47z2s2coXLs8="}
+
+	pinned, err := suite.service.ManifestKey(suite.ctx, suite.ginContext)
+	assert.NoError(suite.T(), err)
+	rotated, err := NewCheckInService(suite.mockRepo, suite.mockRepoTicket, validator.New(), suite.mockDb, other).ManifestKey(suite.ctx, suite.ginContext)
+	assert.NoError(suite.T(), err)
+
+	assert.NotEqual(suite.T(), pinned.PublicKey, rotated.PublicKey)
+}
+
+func (suite *CheckInServiceTestSuite) TestManifest_NotFound() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindManifest", suite.ctx, mock.Anything, "showtime-9", suite.ginContext).Return(entity.Manifest{}, errors.New("showtime not found"))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Manifest(suite.ctx, "showtime-9", suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.NotFoundError{}, suite.ginContext.Errors.Last().Err)
+}
+
+func (suite *CheckInServiceTestSuite) TestManifest_OtherCinemaForbidden() {
+	suite.ginContext.Set("cinema_id", "cinema-2")
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindManifest", suite.ctx, mock.Anything, "showtime-1", suite.ginContext).Return(entity.Manifest{ShowtimeID: "showtime-1", CinemaID: "cinema-1"}, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Manifest(suite.ctx, "showtime-1", suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ForbiddenError{}, suite.ginContext.Errors.Last().Err)
+}
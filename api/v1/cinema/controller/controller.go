@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type CinemaController interface {
+	Create(c *gin.Context)
+	FindById(c *gin.Context)
+	FindAll(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+}
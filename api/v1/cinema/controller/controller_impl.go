@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"bioskuy/api/v1/cinema/dto"
+	"bioskuy/api/v1/cinema/service"
+	"bioskuy/exception"
+	"bioskuy/web"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type cinemaControllerImpl struct {
+	cinemaService service.CinemaService
+}
+
+func NewCinemaController(cinemaService service.CinemaService) CinemaController {
+	return &cinemaControllerImpl{cinemaService: cinemaService}
+}
+
+func (ctl *cinemaControllerImpl) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.CreateCinemaRequest{}
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, err := ctl.cinemaService.Create(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusCreated, web.FormatResponse{ResponseCode: http.StatusCreated, Data: result})
+}
+
+func (ctl *cinemaControllerImpl) FindById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.cinemaService.FindByID(ctx, c.Param("cinemaId"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *cinemaControllerImpl) FindAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.cinemaService.FindAll(ctx, c.Query("city"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *cinemaControllerImpl) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.UpdateCinemaRequest{}
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	request.ID = c.Param("cinemaId")
+
+	result, err := ctl.cinemaService.Update(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *cinemaControllerImpl) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := ctl.cinemaService.Delete(ctx, c.Param("cinemaId"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: "OK"})
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"bioskuy/api/v1/cinema/dto"
+	"bioskuy/api/v1/cinema/mock/servicemock"
+	"bioskuy/exception"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CinemaControllerTestSuite struct {
+	suite.Suite
+	mockService *servicemock.MockCinemaService
+	controller  CinemaController
+	router      *gin.Engine
+}
+
+func (suite *CinemaControllerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockService = new(servicemock.MockCinemaService)
+	suite.controller = NewCinemaController(suite.mockService)
+	suite.router = gin.New()
+	suite.router.Use(exception.ErrorHandler)
+
+	suite.router.POST("/cinemas", suite.controller.Create)
+	suite.router.GET("/cinemas", suite.controller.FindAll)
+	suite.router.PUT("/cinemas/:cinemaId", suite.controller.Update)
+}
+
+func TestCinemaControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(CinemaControllerTestSuite))
+}
+
+func (suite *CinemaControllerTestSuite) TestCreate_Success() {
+	request := dto.CreateCinemaRequest{Name: "Bioskuy Dago", Address: "Jl. Dago 1", City: "Bandung", Timezone: "Asia/Jakarta", OpensAt: "10:00", ClosesAt: "23:00"}
+	suite.mockService.On("Create", mock.Anything, request, mock.Anything).Return(dto.CinemaResponse{ID: "cinema-1", Name: "Bioskuy Dago"}, nil)
+
+	payload, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/cinemas", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"id":"cinema-1"`)
+}
+
+func (suite *CinemaControllerTestSuite) TestFindAll_ByCity() {
+	suite.mockService.On("FindAll", mock.Anything, "Bandung", mock.Anything).Return([]dto.CinemaResponse{{ID: "cinema-1", City: "Bandung"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/cinemas?city=Bandung", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"city":"Bandung"`)
+}
+
+func (suite *CinemaControllerTestSuite) TestUpdate_Forbidden() {
+	request := dto.UpdateCinemaRequest{ID: "cinema-1", Name: "Renamed"}
+	suite.mockService.On("Update", mock.Anything, request, mock.Anything).Return(dto.CinemaResponse{}, errors.New("you can only manage your own cinema")).Run(func(args mock.Arguments) {
+		c := args.Get(2).(*gin.Context)
+		c.Error(exception.ForbiddenError{Message: "you can only manage your own cinema"}).SetType(gin.ErrorTypePublic)
+	})
+
+	payload, _ := json.Marshal(dto.UpdateCinemaRequest{Name: "Renamed"})
+	req := httptest.NewRequest(http.MethodPut, "/cinemas/cinema-1", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,31 @@
+package dto
+
+type CreateCinemaRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Address  string `json:"address" validate:"required"`
+	City     string `json:"city" validate:"required"`
+	Timezone string `json:"timezone" validate:"required"`
+	OpensAt  string `json:"opens_at" validate:"required,datetime=15:04"`
+	ClosesAt string `json:"closes_at" validate:"required,datetime=15:04"`
+}
+
+// UpdateCinemaRequest only changes the fields that are set.
+type UpdateCinemaRequest struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	City     string `json:"city"`
+	Timezone string `json:"timezone"`
+	OpensAt  string `json:"opens_at" validate:"omitempty,datetime=15:04"`
+	ClosesAt string `json:"closes_at" validate:"omitempty,datetime=15:04"`
+}
+
+type CinemaResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	City     string `json:"city"`
+	Timezone string `json:"timezone"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+}
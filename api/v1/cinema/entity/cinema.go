@@ -0,0 +1,16 @@
+package entity
+
+// Cinema is one location of the chain. Its studios, and through them its
+// showtimes, belong to it.
+type Cinema struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	City    string `json:"city"`
+	// Timezone is an IANA name such as "Asia/Jakarta".
+	Timezone string `json:"timezone"`
+	// OpensAt and ClosesAt are local "15:04" times. A closing time before the
+	// opening time is past midnight.
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+}
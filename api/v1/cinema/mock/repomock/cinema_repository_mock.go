@@ -0,0 +1,39 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/cinema/entity"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCinemaRepository struct {
+	mock.Mock
+}
+
+func (m *MockCinemaRepository) Save(ctx context.Context, tx *sql.Tx, cinema entity.Cinema, c *gin.Context) (entity.Cinema, error) {
+	args := m.Called(ctx, tx, cinema, c)
+	return args.Get(0).(entity.Cinema), args.Error(1)
+}
+
+func (m *MockCinemaRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Cinema, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).(entity.Cinema), args.Error(1)
+}
+
+func (m *MockCinemaRepository) FindAll(ctx context.Context, tx *sql.Tx, city string, c *gin.Context) ([]entity.Cinema, error) {
+	args := m.Called(ctx, tx, city, c)
+	return args.Get(0).([]entity.Cinema), args.Error(1)
+}
+
+func (m *MockCinemaRepository) Update(ctx context.Context, tx *sql.Tx, cinema entity.Cinema, c *gin.Context) (entity.Cinema, error) {
+	args := m.Called(ctx, tx, cinema, c)
+	return args.Get(0).(entity.Cinema), args.Error(1)
+}
+
+func (m *MockCinemaRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
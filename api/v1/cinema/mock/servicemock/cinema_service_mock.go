@@ -0,0 +1,38 @@
+package servicemock
+
+import (
+	"bioskuy/api/v1/cinema/dto"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCinemaService struct {
+	mock.Mock
+}
+
+func (m *MockCinemaService) Create(ctx context.Context, request dto.CreateCinemaRequest, c *gin.Context) (dto.CinemaResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.CinemaResponse), args.Error(1)
+}
+
+func (m *MockCinemaService) FindByID(ctx context.Context, id string, c *gin.Context) (dto.CinemaResponse, error) {
+	args := m.Called(ctx, id, c)
+	return args.Get(0).(dto.CinemaResponse), args.Error(1)
+}
+
+func (m *MockCinemaService) FindAll(ctx context.Context, city string, c *gin.Context) ([]dto.CinemaResponse, error) {
+	args := m.Called(ctx, city, c)
+	return args.Get(0).([]dto.CinemaResponse), args.Error(1)
+}
+
+func (m *MockCinemaService) Update(ctx context.Context, request dto.UpdateCinemaRequest, c *gin.Context) (dto.CinemaResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.CinemaResponse), args.Error(1)
+}
+
+func (m *MockCinemaService) Delete(ctx context.Context, id string, c *gin.Context) error {
+	args := m.Called(ctx, id, c)
+	return args.Error(0)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"bioskuy/api/v1/cinema/entity"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CinemaRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, cinema entity.Cinema, c *gin.Context) (entity.Cinema, error)
+	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Cinema, error)
+	FindAll(ctx context.Context, tx *sql.Tx, city string, c *gin.Context) ([]entity.Cinema, error)
+	Update(ctx context.Context, tx *sql.Tx, cinema entity.Cinema, c *gin.Context) (entity.Cinema, error)
+	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+}
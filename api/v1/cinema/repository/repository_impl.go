@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"bioskuy/api/v1/cinema/entity"
+	"bioskuy/exception"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type cinemaRepository struct {
+}
+
+func NewCinemaRepository() CinemaRepository {
+	return &cinemaRepository{}
+}
+
+const cinemaColumns = `id, name, address, city, timezone, to_char(opens_at, 'HH24:MI'), to_char(closes_at, 'HH24:MI')`
+
+func (r *cinemaRepository) Save(ctx context.Context, tx *sql.Tx, cinema entity.Cinema, c *gin.Context) (entity.Cinema, error) {
+	query := `INSERT INTO cinemas (name, address, city, timezone, opens_at, closes_at)
+		VALUES ($1, $2, $3, $4, $5::time, $6::time) RETURNING id`
+
+	err := tx.QueryRowContext(ctx, query,
+		cinema.Name, cinema.Address, cinema.City, cinema.Timezone, cinema.OpensAt, cinema.ClosesAt,
+	).Scan(&cinema.ID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinema, err
+	}
+
+	return cinema, nil
+}
+
+func (r *cinemaRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Cinema, error) {
+	query := `SELECT ` + cinemaColumns + ` FROM cinemas WHERE id = $1`
+
+	cinema := entity.Cinema{}
+	err := tx.QueryRowContext(ctx, query, id).Scan(
+		&cinema.ID, &cinema.Name, &cinema.Address, &cinema.City, &cinema.Timezone, &cinema.OpensAt, &cinema.ClosesAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return cinema, errors.New("cinema not found")
+	}
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinema, err
+	}
+
+	return cinema, nil
+}
+
+// FindAll lists cinemas by city and name. A non-empty city only keeps the
+// cinemas of that city, ignoring case.
+func (r *cinemaRepository) FindAll(ctx context.Context, tx *sql.Tx, city string, c *gin.Context) ([]entity.Cinema, error) {
+	query := `SELECT ` + cinemaColumns + ` FROM cinemas
+		WHERE $1 = '' OR LOWER(city) = LOWER($1)
+		ORDER BY city, name, id`
+
+	rows, err := tx.QueryContext(ctx, query, city)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	cinemas := []entity.Cinema{}
+	for rows.Next() {
+		cinema := entity.Cinema{}
+		err := rows.Scan(&cinema.ID, &cinema.Name, &cinema.Address, &cinema.City, &cinema.Timezone, &cinema.OpensAt, &cinema.ClosesAt)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		cinemas = append(cinemas, cinema)
+	}
+
+	return cinemas, rows.Err()
+}
+
+func (r *cinemaRepository) Update(ctx context.Context, tx *sql.Tx, cinema entity.Cinema, c *gin.Context) (entity.Cinema, error) {
+	query := `UPDATE cinemas SET name = $1, address = $2, city = $3, timezone = $4, opens_at = $5::time, closes_at = $6::time WHERE id = $7`
+
+	_, err := tx.ExecContext(ctx, query,
+		cinema.Name, cinema.Address, cinema.City, cinema.Timezone, cinema.OpensAt, cinema.ClosesAt, cinema.ID,
+	)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinema, err
+	}
+
+	return cinema, nil
+}
+
+// Delete removes a cinema. One that still has studios or branch admins cannot
+// be removed and yields a ConflictError.
+func (r *cinemaRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM cinemas WHERE id = $1`, id)
+	if isForeignKeyViolation(err) {
+		conflict := exception.ConflictError{Message: "cinema still has studios or branch admins and cannot be deleted"}
+		c.Error(conflict).SetType(gin.ErrorTypePublic)
+		return conflict
+	}
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"bioskuy/api/v1/cinema/entity"
+	"bioskuy/exception"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CinemaRepositoryTestSuite struct {
+	suite.Suite
+	mockDb  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    CinemaRepository
+	ctx     context.Context
+	ginCtx  *gin.Context
+}
+
+var cinemaRows = []string{"id", "name", "address", "city", "timezone", "opens_at", "closes_at"}
+
+func (suite *CinemaRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.repo = NewCinemaRepository()
+	suite.ctx = context.Background()
+	suite.ginCtx = &gin.Context{}
+}
+
+func (suite *CinemaRepositoryTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestCinemaRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CinemaRepositoryTestSuite))
+}
+
+func (suite *CinemaRepositoryTestSuite) TestSave_Success() {
+	cinema := entity.Cinema{Name: "Bioskuy Dago", Address: "Jl. Dago 1", City: "Bandung", Timezone: "Asia/Jakarta", OpensAt: "10:00", ClosesAt: "23:00"}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`INSERT INTO cinemas (name, address, city, timezone, opens_at, closes_at) VALUES ($1, $2, $3, $4, $5::time, $6::time) RETURNING id`)).
+		WithArgs(cinema.Name, cinema.Address, cinema.City, cinema.Timezone, cinema.OpensAt, cinema.ClosesAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("cinema-1"))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.Save(suite.ctx, tx, cinema, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "cinema-1", result.ID)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CinemaRepositoryTestSuite) TestFindByID_Success() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, address, city, timezone, to_char(opens_at, 'HH24:MI'), to_char(closes_at, 'HH24:MI') FROM cinemas WHERE id = $1`)).
+		WithArgs("cinema-1").
+		WillReturnRows(sqlmock.NewRows(cinemaRows).AddRow("cinema-1", "Bioskuy Dago", "Jl. Dago 1", "Bandung", "Asia/Jakarta", "10:00", "23:00"))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.FindByID(suite.ctx, tx, "cinema-1", suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Bandung", result.City)
+	assert.Equal(suite.T(), "23:00", result.ClosesAt)
+}
+
+func (suite *CinemaRepositoryTestSuite) TestFindByID_NotFound() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM cinemas WHERE id = $1`)).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(cinemaRows))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.FindByID(suite.ctx, tx, "missing", suite.ginCtx)
+	assert.EqualError(suite.T(), err, "cinema not found")
+}
+
+func (suite *CinemaRepositoryTestSuite) TestFindAll_ByCity() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM cinemas WHERE $1 = '' OR LOWER(city) = LOWER($1) ORDER BY city, name, id`)).
+		WithArgs("bandung").
+		WillReturnRows(sqlmock.NewRows(cinemaRows).
+			AddRow("cinema-1", "Bioskuy Dago", "Jl. Dago 1", "Bandung", "Asia/Jakarta", "10:00", "23:00").
+			AddRow("cinema-2", "Bioskuy Pasteur", "Jl. Pasteur 2", "Bandung", "Asia/Jakarta", "09:00", "00:00"))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	cinemas, err := suite.repo.FindAll(suite.ctx, tx, "bandung", suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), cinemas, 2)
+	assert.Equal(suite.T(), "Bioskuy Pasteur", cinemas[1].Name)
+}
+
+func (suite *CinemaRepositoryTestSuite) TestUpdate_Success() {
+	cinema := entity.Cinema{ID: "cinema-1", Name: "Bioskuy Dago", Address: "Jl. Dago 1", City: "Bandung", Timezone: "Asia/Jakarta", OpensAt: "09:00", ClosesAt: "23:30"}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE cinemas SET name = $1, address = $2, city = $3, timezone = $4, opens_at = $5::time, closes_at = $6::time WHERE id = $7`)).
+		WithArgs(cinema.Name, cinema.Address, cinema.City, cinema.Timezone, cinema.OpensAt, cinema.ClosesAt, cinema.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.Update(suite.ctx, tx, cinema, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), cinema, result)
+}
+
+func (suite *CinemaRepositoryTestSuite) TestDelete_HasStudios() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`DELETE FROM cinemas WHERE id = $1`)).
+		WithArgs("cinema-1").
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.Delete(suite.ctx, tx, "cinema-1", ginCtx)
+	assert.IsType(suite.T(), exception.ConflictError{}, err)
+	assert.IsType(suite.T(), exception.ConflictError{}, ginCtx.Errors.Last().Err)
+}
+
+func (suite *CinemaRepositoryTestSuite) TestDelete_Error() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`DELETE FROM cinemas WHERE id = $1`)).
+		WithArgs("cinema-1").
+		WillReturnError(errors.New("connection reset"))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.Delete(suite.ctx, tx, "cinema-1", ginCtx)
+	assert.EqualError(suite.T(), err, "connection reset")
+	assert.IsType(suite.T(), exception.InternalServerError{}, ginCtx.Errors.Last().Err)
+}
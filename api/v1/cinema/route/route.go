@@ -0,0 +1,35 @@
+package route
+
+import (
+	"bioskuy/api/v1/cinema/controller"
+	"bioskuy/api/v1/cinema/repository"
+	"bioskuy/api/v1/cinema/service"
+	"bioskuy/auth"
+	"bioskuy/helper"
+	"bioskuy/middleware"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func CinemaRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB, config *helper.Config) {
+
+	authService := auth.NewService(config)
+
+	cinemaRepo := repository.NewCinemaRepository()
+	cinemaService := service.NewCinemaService(cinemaRepo, validate, db)
+	cinemaController := controller.NewCinemaController(cinemaService)
+
+	v1 := router.Group("/api/v1")
+	{
+		cinemas := v1.Group("/cinemas")
+		{
+			cinemas.POST("/", middleware.AuthMiddleware(authService, "admin"), cinemaController.Create)
+			cinemas.GET("/", cinemaController.FindAll)
+			cinemas.GET("/:cinemaId", cinemaController.FindById)
+			cinemas.PUT("/:cinemaId", middleware.AuthMiddleware(authService, "admin", "branch admin"), cinemaController.Update)
+			cinemas.DELETE("/:cinemaId", middleware.AuthMiddleware(authService, "admin"), cinemaController.Delete)
+		}
+	}
+}
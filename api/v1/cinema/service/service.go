@@ -0,0 +1,16 @@
+package service
+
+import (
+	"bioskuy/api/v1/cinema/dto"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CinemaService interface {
+	Create(ctx context.Context, request dto.CreateCinemaRequest, c *gin.Context) (dto.CinemaResponse, error)
+	FindByID(ctx context.Context, id string, c *gin.Context) (dto.CinemaResponse, error)
+	FindAll(ctx context.Context, city string, c *gin.Context) ([]dto.CinemaResponse, error)
+	Update(ctx context.Context, request dto.UpdateCinemaRequest, c *gin.Context) (dto.CinemaResponse, error)
+	Delete(ctx context.Context, id string, c *gin.Context) error
+}
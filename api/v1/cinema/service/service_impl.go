@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bioskuy/api/v1/cinema/dto"
+	"bioskuy/api/v1/cinema/entity"
+	"bioskuy/api/v1/cinema/repository"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type cinemaServiceImpl struct {
+	Repo     repository.CinemaRepository
+	Validate *validator.Validate
+	DB       *sql.DB
+}
+
+func NewCinemaService(repo repository.CinemaRepository, validate *validator.Validate, DB *sql.DB) CinemaService {
+	return &cinemaServiceImpl{Repo: repo, Validate: validate, DB: DB}
+}
+
+func (s *cinemaServiceImpl) Create(ctx context.Context, request dto.CreateCinemaRequest, c *gin.Context) (dto.CinemaResponse, error) {
+	cinemaResponse := dto.CinemaResponse{}
+
+	err := s.Validate.Struct(request)
+	if err == nil {
+		err = validateTimezone(request.Timezone)
+	}
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	cinema := entity.Cinema{
+		Name:     request.Name,
+		Address:  request.Address,
+		City:     request.City,
+		Timezone: request.Timezone,
+		OpensAt:  request.OpensAt,
+		ClosesAt: request.ClosesAt,
+	}
+
+	result, err := s.Repo.Save(ctx, tx, cinema, c)
+	if err != nil {
+		return cinemaResponse, err
+	}
+
+	return toCinemaResponse(result), nil
+}
+
+func (s *cinemaServiceImpl) FindByID(ctx context.Context, id string, c *gin.Context) (dto.CinemaResponse, error) {
+	cinemaResponse := dto.CinemaResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	result, err := s.Repo.FindByID(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+
+	return toCinemaResponse(result), nil
+}
+
+func (s *cinemaServiceImpl) FindAll(ctx context.Context, city string, c *gin.Context) ([]dto.CinemaResponse, error) {
+	cinemaResponses := []dto.CinemaResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponses, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	results, err := s.Repo.FindAll(ctx, tx, city, c)
+	if err != nil {
+		return cinemaResponses, err
+	}
+
+	for _, result := range results {
+		cinemaResponses = append(cinemaResponses, toCinemaResponse(result))
+	}
+
+	return cinemaResponses, nil
+}
+
+// Update changes the fields set on the request. Branch admins may only update
+// their own cinema.
+func (s *cinemaServiceImpl) Update(ctx context.Context, request dto.UpdateCinemaRequest, c *gin.Context) (dto.CinemaResponse, error) {
+	cinemaResponse := dto.CinemaResponse{}
+
+	err := s.Validate.Struct(request)
+	if err == nil && request.Timezone != "" {
+		err = validateTimezone(request.Timezone)
+	}
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+
+	if !helper.CanManageCinema(c, request.ID) {
+		err := errors.New("you can only manage your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	cinema, err := s.Repo.FindByID(ctx, tx, request.ID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return cinemaResponse, err
+	}
+
+	if request.Name != "" {
+		cinema.Name = request.Name
+	}
+	if request.Address != "" {
+		cinema.Address = request.Address
+	}
+	if request.City != "" {
+		cinema.City = request.City
+	}
+	if request.Timezone != "" {
+		cinema.Timezone = request.Timezone
+	}
+	if request.OpensAt != "" {
+		cinema.OpensAt = request.OpensAt
+	}
+	if request.ClosesAt != "" {
+		cinema.ClosesAt = request.ClosesAt
+	}
+
+	result, err := s.Repo.Update(ctx, tx, cinema, c)
+	if err != nil {
+		return cinemaResponse, err
+	}
+
+	return toCinemaResponse(result), nil
+}
+
+func (s *cinemaServiceImpl) Delete(ctx context.Context, id string, c *gin.Context) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	cinema, err := s.Repo.FindByID(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return s.Repo.Delete(ctx, tx, cinema.ID, c)
+}
+
+func validateTimezone(name string) error {
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("unknown timezone %q", name)
+	}
+	return nil
+}
+
+func toCinemaResponse(cinema entity.Cinema) dto.CinemaResponse {
+	return dto.CinemaResponse{
+		ID:       cinema.ID,
+		Name:     cinema.Name,
+		Address:  cinema.Address,
+		City:     cinema.City,
+		Timezone: cinema.Timezone,
+		OpensAt:  cinema.OpensAt,
+		ClosesAt: cinema.ClosesAt,
+	}
+}
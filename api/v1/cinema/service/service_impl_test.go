@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bioskuy/api/v1/cinema/dto"
+	"bioskuy/api/v1/cinema/entity"
+	"bioskuy/api/v1/cinema/mock/repomock"
+	"bioskuy/exception"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CinemaServiceTestSuite struct {
+	suite.Suite
+	mockRepo   *repomock.MockCinemaRepository
+	mockDb     *sql.DB
+	mockSql    sqlmock.Sqlmock
+	service    CinemaService
+	ctx        context.Context
+	ginContext *gin.Context
+}
+
+var dago = entity.Cinema{ID: "cinema-1", Name: "Bioskuy Dago", Address: "Jl. Dago 1", City: "Bandung", Timezone: "Asia/Jakarta", OpensAt: "10:00", ClosesAt: "23:00"}
+
+func (suite *CinemaServiceTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.mockRepo = new(repomock.MockCinemaRepository)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.service = NewCinemaService(suite.mockRepo, validator.New(), db)
+	suite.ctx = context.Background()
+	suite.ginContext, _ = gin.CreateTestContext(httptest.NewRecorder())
+}
+
+func TestCinemaServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CinemaServiceTestSuite))
+}
+
+func (suite *CinemaServiceTestSuite) TestCreate_Success() {
+	request := dto.CreateCinemaRequest{Name: dago.Name, Address: dago.Address, City: dago.City, Timezone: dago.Timezone, OpensAt: "10:00", ClosesAt: "23:00"}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.MatchedBy(func(cinema entity.Cinema) bool {
+		return cinema.Name == dago.Name && cinema.City == "Bandung" && cinema.Timezone == "Asia/Jakarta"
+	}), suite.ginContext).Return(dago, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Create(suite.ctx, request, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "cinema-1", response.ID)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *CinemaServiceTestSuite) TestCreate_ValidationError() {
+	invalid := []dto.CreateCinemaRequest{
+		{Name: "no address", City: "Bandung", Timezone: "Asia/Jakarta", OpensAt: "10:00", ClosesAt: "23:00"},
+		{Name: "bad hours", Address: "Jl. 1", City: "Bandung", Timezone: "Asia/Jakarta", OpensAt: "10am", ClosesAt: "23:00"},
+		{Name: "bad zone", Address: "Jl. 1", City: "Bandung", Timezone: "Mars/Olympus", OpensAt: "10:00", ClosesAt: "23:00"},
+	}
+
+	for _, request := range invalid {
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+		_, err := suite.service.Create(suite.ctx, request, ginContext)
+
+		assert.Error(suite.T(), err, request.Name)
+		assert.IsType(suite.T(), exception.ValidationError{}, ginContext.Errors.Last().Err, request.Name)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save")
+}
+
+func (suite *CinemaServiceTestSuite) TestFindAll_ByCity() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, "Bandung", suite.ginContext).Return([]entity.Cinema{dago}, nil)
+	suite.mockSql.ExpectCommit()
+
+	responses, err := suite.service.FindAll(suite.ctx, "Bandung", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), responses, 1)
+	assert.Equal(suite.T(), "Bioskuy Dago", responses[0].Name)
+}
+
+func (suite *CinemaServiceTestSuite) TestUpdate_OwnCinema() {
+	suite.ginContext.Set("role", "branch admin")
+	suite.ginContext.Set("cinema_id", "cinema-1")
+	request := dto.UpdateCinemaRequest{ID: "cinema-1", ClosesAt: "23:30"}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "cinema-1", suite.ginContext).Return(dago, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(cinema entity.Cinema) bool {
+		return cinema.ClosesAt == "23:30" && cinema.OpensAt == "10:00" && cinema.Name == dago.Name
+	}), suite.ginContext).Return(entity.Cinema{ID: "cinema-1", Name: dago.Name, ClosesAt: "23:30"}, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Update(suite.ctx, request, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "23:30", response.ClosesAt)
+}
+
+func (suite *CinemaServiceTestSuite) TestUpdate_OtherCinemaForbidden() {
+	suite.ginContext.Set("role", "branch admin")
+	suite.ginContext.Set("cinema_id", "cinema-2")
+	request := dto.UpdateCinemaRequest{ID: "cinema-1", Name: "Renamed"}
+
+	_, err := suite.service.Update(suite.ctx, request, suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "you can only manage your own cinema")
+	assert.IsType(suite.T(), exception.ForbiddenError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindByID")
+}
+
+func (suite *CinemaServiceTestSuite) TestDelete_NotFound() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "missing", suite.ginContext).Return(entity.Cinema{}, errors.New("cinema not found"))
+	suite.mockSql.ExpectRollback()
+
+	err := suite.service.Delete(suite.ctx, "missing", suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "cinema not found")
+	assert.IsType(suite.T(), exception.NotFoundError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete")
+}
@@ -34,8 +34,10 @@ func (gc *genreControllerImpl) GetAll(c *gin.Context) {
 		ResponseCode: http.StatusOK,
 		Data:         genres,
 		Paging: web.Paging{
-			Page:      paging.Page,
-			TotalData: paging.TotalRows,
+			Page:       paging.Page,
+			Size:       paging.Size,
+			TotalData:  paging.TotalRows,
+			TotalPages: paging.TotalPages,
 		},
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"bioskuy/api/v1/genretomovie/dto"
 	"bioskuy/api/v1/genretomovie/service"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"bioskuy/web"
 	"net/http"
 
@@ -63,15 +64,22 @@ func (controller *genretomovieControllerImpl) FindById(c *gin.Context){
 func (controller *genretomovieControllerImpl) FindAll(c *gin.Context) {
     ctx := c.Request.Context()
 
-    result, err := controller.Service.FindAll(ctx, c)
+    query, err := helper.ListQueryFrom(c, "genre_id", "movie_id")
+    if err != nil {
+        c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return
+    }
+
+    result, paging, err := controller.Service.FindAll(ctx, query, c)
     if err != nil {
         c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return
     }
 
-    response := web.FormatResponse{
+    response := web.FormatResponsePaging{
         ResponseCode: http.StatusOK,
         Data:    result,
+        Paging:   paging,
     }
 
     c.JSON(http.StatusOK, response)
@@ -4,6 +4,8 @@ import (
 	"bioskuy/api/v1/genretomovie/dto"
 	"bioskuy/api/v1/genretomovie/mock/servicemock"
 	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"bytes"
 	"errors"
 	"net/http"
@@ -27,6 +29,7 @@ func (suite *GenreControllerTestSuite) SetupTest() {
 	suite.mockService = new(servicemock.MockGenreToMovieService)
 	controller := NewGenreToMovieController(suite.mockService)
 	suite.router = gin.Default()
+	suite.router.Use(exception.ErrorHandler)
 	suite.router.POST("/genretomovie", controller.Create)
 	suite.router.GET("/genretomovie/:genretomovieId", controller.FindById)
 	suite.router.GET("/genretomovie", controller.FindAll)
@@ -94,20 +97,21 @@ func (suite *GenreControllerTestSuite) TestFindById_NotFound() {
 // TestFindAll_Success
 func (suite *GenreControllerTestSuite) TestFindAll_Success() {
 	mockResponse := []dto.GenreToMovieResponse{{ID: "1", GenreID: "1", MovieID: "1"}}
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(mockResponse, nil)
+	suite.mockService.On("FindAll", mock.Anything, helper.ListQuery{Page: 2, Size: 5}.With("genre_id", "1"), mock.Anything).Return(mockResponse, web.Paging{Page: 2, Size: 5, TotalData: 6, TotalPages: 2}, nil)
 
-	req, _ := http.NewRequest(http.MethodGet, "/genretomovie", nil)
+	req, _ := http.NewRequest(http.MethodGet, "/genretomovie?page=2&size=5&genre_id=1", nil)
 	resp := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Contains(suite.T(), resp.Body.String(), `"total-pages":2`)
 	suite.mockService.AssertExpectations(suite.T())
 }
 
 // TestFindAll_InternalServerError
 func (suite *GenreControllerTestSuite) TestFindAll_InternalServerError() {
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(nil, errors.New("internal server error"))
+	suite.mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return([]dto.GenreToMovieResponse{}, web.Paging{}, errors.New("internal server error"))
 
 	req, _ := http.NewRequest(http.MethodGet, "/genretomovie", nil)
 	resp := httptest.NewRecorder()
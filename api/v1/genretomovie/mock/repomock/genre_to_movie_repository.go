@@ -2,6 +2,7 @@ package repomock
 
 import (
 	"bioskuy/api/v1/genretomovie/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 
@@ -23,9 +24,9 @@ func (m *MockGenreToMovieRepository) FindByID(ctx context.Context, tx *sql.Tx, i
 	return args.Get(0).(entity.GenreToMovie), args.Error(1)
 }
 
-func (m *MockGenreToMovieRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.GenreToMovie, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]entity.GenreToMovie), args.Error(1)
+func (m *MockGenreToMovieRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.GenreToMovie, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entity.GenreToMovie), args.Int(1), args.Error(2)
 }
 
 func (m *MockGenreToMovieRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
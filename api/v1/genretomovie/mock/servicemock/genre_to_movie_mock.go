@@ -2,6 +2,8 @@ package servicemock
 
 import (
 	"bioskuy/api/v1/genretomovie/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -23,9 +25,9 @@ func (m *MockGenreToMovieService) FindByID(ctx context.Context, id string, c *gi
 	return args.Get(0).(dto.GenreToMovieResponse), args.Error(1)
 }
 
-func (m *MockGenreToMovieService) FindAll(ctx context.Context, c *gin.Context) ([]dto.GenreToMovieResponse, error) {
-	args := m.Called(ctx, c)
-	return args.Get(0).([]dto.GenreToMovieResponse), args.Error(1)
+func (m *MockGenreToMovieService) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.GenreToMovieResponse, web.Paging, error) {
+	args := m.Called(ctx, query, c)
+	return args.Get(0).([]dto.GenreToMovieResponse), args.Get(1).(web.Paging), args.Error(2)
 }
 
 func (m *MockGenreToMovieService) Delete(ctx context.Context, id string, c *gin.Context) error {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bioskuy/api/v1/genretomovie/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 
@@ -11,6 +12,6 @@ import (
 type GenreToMovieRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, user entity.GenreToMovie, c *gin.Context) (entity.GenreToMovie, error)
 	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.GenreToMovie, error)
-	FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.GenreToMovie, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.GenreToMovie, int, error)
 	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"bioskuy/api/v1/genretomovie/entity"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"errors"
@@ -59,18 +60,42 @@ func (r *genretomovieRepository) FindByID(ctx context.Context, tx *sql.Tx, id st
 	}
 }
 
-func (r *genretomovieRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.GenreToMovie, error){
-	query := `SELECT gtm.id, gtm.genre_id, gtm.movie_id, g.name as genre_name, m.title as movie_title, m.description as movie_description, 
-                  m.price as movie_price, m.duration as movie_duration, m.status as movie_status
-	          FROM genre_to_movies gtm 
+// genretomovieListSpec is what the genre-to-movie listing can be sorted,
+// filtered and searched by.
+var genretomovieListSpec = helper.ListSpec{
+	Sorts:       map[string]string{"genre_name": "g.name", "movie_title": "m.title"},
+	DefaultSort: "genre_name",
+	Key:         "gtm.id",
+	Filters:     map[string]string{"genre_id": "gtm.genre_id::text", "movie_id": "gtm.movie_id::text"},
+	Search:      []string{"g.name", "m.title"},
+}
+
+func (r *genretomovieRepository) FindAll(ctx context.Context, tx *sql.Tx, listQuery helper.ListQuery, c *gin.Context) ([]entity.GenreToMovie, int, error){
+	clauses, err := listQuery.Clauses(genretomovieListSpec)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	joins := `FROM genre_to_movies gtm 
 	          JOIN genres g ON gtm.genre_id = g.id 
 	          JOIN movies m ON gtm.movie_id = m.id`
 
+	total := 0
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) `+joins+` `+clauses.Where, clauses.Args...).Scan(&total); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	query := `SELECT gtm.id, gtm.genre_id, gtm.movie_id, g.name as genre_name, m.title as movie_title, m.description as movie_description, 
+                  m.price as movie_price, m.duration as movie_duration, m.status as movie_status
+	          ` + joins + ` ` + clauses.Where + ` ` + clauses.Page
+
 	genretomovies := []entity.GenreToMovie{}
-	rows, err := tx.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query, clauses.AllArgs()...)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return genretomovies, err
+		return genretomovies, 0, err
 	}
 	defer rows.Close()
 
@@ -78,11 +103,11 @@ func (r *genretomovieRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin
 		genretomovie := entity.GenreToMovie{}
 		if err := rows.Scan(&genretomovie.ID, &genretomovie.GenreID, &genretomovie.MovieID, &genretomovie.GenreName, 
 			&genretomovie.MovieTitle, &genretomovie.MovieDescription, &genretomovie.MoviePrice, &genretomovie.MovieDuration, &genretomovie.MovieStatus); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		genretomovies = append(genretomovies, genretomovie)
 	}
-	return genretomovies, nil
+	return genretomovies, total, nil
 }
 
 func (r *genretomovieRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
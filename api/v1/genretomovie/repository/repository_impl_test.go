@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bioskuy/api/v1/genretomovie/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"errors"
@@ -162,6 +163,7 @@ func (suite *GenreToMovieRepositoryTestSuite) TestFindAll_Success() {
 		FROM genre_to_movies gtm 
 		JOIN genres g ON gtm.genre_id = g.id 
 		JOIN movies m ON gtm.movie_id = m.id
+		WHERE gtm.genre_id::text = $1 ORDER BY m.title DESC, gtm.id LIMIT $2 OFFSET $3
 	`)
 
 	genreToMovies := []entity.GenreToMovie{
@@ -203,15 +205,20 @@ func (suite *GenreToMovieRepositoryTestSuite) TestFindAll_Success() {
 	)
 
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(query).WillReturnRows(rows)
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM genre_to_movies gtm JOIN genres g ON gtm.genre_id = g.id JOIN movies m ON gtm.movie_id = m.id WHERE gtm.genre_id::text = $1`)).
+		WithArgs("genre1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	suite.mockSql.ExpectQuery(query).WithArgs("genre1", 2, 4).WillReturnRows(rows)
 
 	tx, err := suite.mockDb.Begin()
 	suite.NoError(err)
 
+	listQuery := helper.ListQuery{Page: 3, Size: 2, Sort: "movie_title", Desc: true}.With("genre_id", "genre1")
 	ginContext, _ := gin.CreateTestContext(nil)
-	result, err := suite.repo.FindAll(context.Background(), tx, ginContext)
+	result, total, err := suite.repo.FindAll(context.Background(), tx, listQuery, ginContext)
 	suite.NoError(err)
 	suite.Len(result, 2)
+	suite.Equal(7, total)
 	suite.Equal(genreToMovies[0].ID, result[0].ID)
 	suite.Equal(genreToMovies[1].GenreID, result[1].GenreID)
 
@@ -233,13 +240,14 @@ func (suite *GenreToMovieRepositoryTestSuite) TestFindAll_Error() {
 	`)
 
 	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	suite.mockSql.ExpectQuery(query).WillReturnError(errors.New("query error"))
 
 	tx, err := suite.mockDb.Begin()
 	suite.NoError(err)
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	_, err = suite.repo.FindAll(context.Background(), tx, ginContext)
+	_, _, err = suite.repo.FindAll(context.Background(), tx, helper.ListQuery{Page: 1, Size: 10}, ginContext)
 	suite.Error(err)
 	suite.EqualError(err, "query error")
 
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bioskuy/api/v1/genretomovie/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -10,6 +12,6 @@ import (
 type GenreToMovieService interface {
 	Create(ctx context.Context, request dto.CreateGenreToMovieRequest, c *gin.Context) (dto.GenreToMovieCreateResponse, error) 
 	FindByID(ctx context.Context, id string, c *gin.Context) (dto.GenreToMovieResponse, error)
-	FindAll(ctx context.Context, c *gin.Context) ([]dto.GenreToMovieResponse, error)
+	FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.GenreToMovieResponse, web.Paging, error)
 	Delete(ctx context.Context, id string, c *gin.Context) error
 }
@@ -6,6 +6,7 @@ import (
 	"bioskuy/api/v1/genretomovie/repository"
 	"bioskuy/exception"
 	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 	"database/sql"
 
@@ -91,20 +92,20 @@ func (s *genretomovieServiceImpl) FindByID(ctx context.Context, id string, c *gi
 	return GenreToMovieResponse, nil
 }
 
-func (s *genretomovieServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto.GenreToMovieResponse, error){
+func (s *genretomovieServiceImpl) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.GenreToMovieResponse, web.Paging, error){
 	GenreToMovieResponses := []dto.GenreToMovieResponse{}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  GenreToMovieResponses, err
+		return  GenreToMovieResponses, web.Paging{}, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	results, err := s.Repo.FindAll(ctx, tx, c)
+	results, total, err := s.Repo.FindAll(ctx, tx, query, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  GenreToMovieResponses, err
+		return  GenreToMovieResponses, web.Paging{}, err
 	}
 
 	for _, result := range results {
@@ -122,7 +123,7 @@ func (s *genretomovieServiceImpl) FindAll(ctx context.Context, c *gin.Context) (
 		GenreToMovieResponses = append(GenreToMovieResponses, GenreToMovieResponse)
 	}
 
-	return GenreToMovieResponses, nil
+	return GenreToMovieResponses, query.Paging(total), nil
 }
 
 func (s *genretomovieServiceImpl) Delete(ctx context.Context, id string, c *gin.Context) error{
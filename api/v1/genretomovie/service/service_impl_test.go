@@ -5,6 +5,8 @@ import (
 	"bioskuy/api/v1/genretomovie/entity"
 	"bioskuy/api/v1/genretomovie/mock/repomock"
 	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 	"database/sql"
 	"errors"
@@ -141,14 +143,16 @@ func (suite *GenreToMovieServiceTestSuite) TestFindAll_Success() {
 		{ID: "id2", GenreID: "genre2", MovieID: "movie2"},
 	}
 
+	query := helper.ListQuery{Page: 2, Size: 2}
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, query, suite.ginContext).Return(expectedResult, 5, nil)
 	suite.mockSql.ExpectCommit()
 
-	response, err := suite.service.FindAll(suite.ctx, suite.ginContext)
+	response, paging, err := suite.service.FindAll(suite.ctx, query, suite.ginContext)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), response, 2)
+	assert.Equal(suite.T(), web.Paging{Page: 2, Size: 2, TotalData: 5, TotalPages: 3}, paging)
 	assert.Equal(suite.T(), expectedResult[0].ID, response[0].ID)
 	assert.Equal(suite.T(), expectedResult[0].GenreID, response[0].GenreID)
 	assert.Equal(suite.T(), expectedResult[0].MovieID, response[0].MovieID)
@@ -158,10 +162,10 @@ func (suite *GenreToMovieServiceTestSuite) TestFindAll_Error() {
 	findAllError := errors.New("Find All Error")
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, suite.ginContext).Return([]entity.GenreToMovie{}, findAllError)
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, mock.Anything, suite.ginContext).Return([]entity.GenreToMovie{}, 0, findAllError)
 	suite.mockSql.ExpectRollback()
 
-	response, err := suite.service.FindAll(suite.ctx, suite.ginContext)
+	response, _, err := suite.service.FindAll(suite.ctx, helper.ListQuery{Page: 1, Size: 10}, suite.ginContext)
 
 	assert.Error(suite.T(), err)
 	assert.Len(suite.T(), response, 0)
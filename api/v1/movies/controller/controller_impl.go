@@ -39,8 +39,10 @@ func (ctrl *movieControllerImpl) GetAllMovies(c *gin.Context) {
 		ResponseCode: http.StatusOK,
 		Data:         movies,
 		Paging: web.Paging{
-			Page:      paging.Page,
-			TotalData: paging.TotalRows,
+			Page:       paging.Page,
+			Size:       paging.Size,
+			TotalData:  paging.TotalRows,
+			TotalPages: paging.TotalPages,
 		},
 	}
 	c.JSON(http.StatusOK, response)
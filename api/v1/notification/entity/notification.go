@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// Kinds of email sent to customers.
+const (
+	KindBookingConfirmed = "booking_confirmed"
+	KindBookingExpired   = "booking_expired"
+	KindBookingCancelled = "booking_cancelled"
+	KindBookingRefunded  = "booking_refunded"
+	KindShowtimeReminder = "showtime_reminder"
+
+	// KindLatePaymentRefunded tells a customer their payment arrived after
+	// the booking expired and was refunded.
+	KindLatePaymentRefunded = "late_payment_refunded"
+
+	// KindShowtimeCancelled and KindShowtimeRescheduled tell a customer with
+	// tickets that their showtime was cancelled or moved.
+	KindShowtimeCancelled   = "showtime_cancelled"
+	KindShowtimeRescheduled = "showtime_rescheduled"
+)
+
+// Notification is one email queued for a user. Recipient and RecipientName
+// are the user's email and name, filled in when the notification is queued.
+type Notification struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Kind          string    `json:"kind"`
+	Recipient     string    `json:"recipient"`
+	RecipientName string    `json:"recipient_name"`
+	Data          Data      `json:"data"`
+	DedupeKey     string    `json:"dedupe_key"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Data is what the email templates are rendered from. It is captured when
+// the notification is queued, since the booking's seats may be released by
+// the time the email is sent.
+type Data struct {
+	SeatBookingID string `json:"seat_booking_id"`
+	PaymentID     string `json:"payment_id,omitempty"`
+	MovieTitle    string `json:"movie_title"`
+	StudioName    string `json:"studio_name"`
+	// ShowStart is in UTC; Timezone is the IANA timezone of the cinema.
+	ShowStart      time.Time `json:"show_start"`
+	Timezone       string    `json:"timezone"`
+	Seats          []string  `json:"seats"`
+	TotalPrice     int       `json:"total_price"`
+	RefundedAmount int       `json:"refunded_amount,omitempty"`
+}
@@ -0,0 +1,45 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Queue(ctx context.Context, tx *sql.Tx, notification entity.Notification, c *gin.Context) error {
+	args := m.Called(ctx, tx, notification, c)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) FindDueReminders(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time, c *gin.Context) ([]entity.Notification, error) {
+	args := m.Called(ctx, tx, from, to, c)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ClaimDue(ctx context.Context, tx *sql.Tx, now time.Time, leaseUntil time.Time, limit int, c *gin.Context) ([]entity.Notification, error) {
+	args := m.Called(ctx, tx, now, leaseUntil, limit, c)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkSent(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) Reschedule(ctx context.Context, tx *sql.Tx, id string, lastError string, nextAttemptAt time.Time, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, lastError, nextAttemptAt, c)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) Abandon(ctx context.Context, tx *sql.Tx, id string, lastError string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, lastError, c)
+	return args.Error(0)
+}
@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"bioskuy/api/v1/notification/repository"
+	"bioskuy/api/v1/notification/sender"
+	"bioskuy/api/v1/notification/templates"
+	"bioskuy/api/v1/webhook/dispatcher"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	batchSize   = 50
+	sendTimeout = 30 * time.Second
+	// claimLease covers a whole batch, whose emails are sent one after
+	// another, plus a send's worth of time to record the outcomes.
+	claimLease = (batchSize + 1) * sendTimeout
+)
+
+// Notifier periodically queues showtime reminders and sends queued emails.
+// Failed emails are retried with the webhook backoff until MaxAttempts.
+type Notifier struct {
+	Repo           repository.NotificationRepository
+	DB             *sql.DB
+	Sender         sender.Sender
+	Interval       time.Duration
+	MaxAttempts    int
+	ReminderBefore time.Duration
+	Now            func() time.Time
+}
+
+func NewNotifier(repo repository.NotificationRepository, DB *sql.DB, sender sender.Sender, interval time.Duration, maxAttempts int, reminderBefore time.Duration) *Notifier {
+	return &Notifier{
+		Repo:           repo,
+		DB:             DB,
+		Sender:         sender,
+		Interval:       interval,
+		MaxAttempts:    maxAttempts,
+		ReminderBefore: reminderBefore,
+		Now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Start runs Notify every Interval in its own goroutine until ctx is done.
+func (n *Notifier) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(n.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sent, err := n.Notify(ctx)
+				if err != nil {
+					slog.Error("email notification failed", "error", err)
+					continue
+				}
+				if sent > 0 {
+					slog.Info("sent email notifications", "sent", sent)
+				}
+			}
+		}
+	}()
+}
+
+// Notify queues reminders for shows starting within ReminderBefore, then
+// sends one batch of due emails and returns how many were sent. Like the
+// webhook dispatcher, emails are claimed in their own transaction before
+// sending so a slow mail server holds no locks.
+func (n *Notifier) Notify(ctx context.Context) (int, error) {
+	now := n.Now()
+
+	if err := n.queueReminders(ctx, now); err != nil {
+		return 0, err
+	}
+
+	notifications, err := n.claim(ctx, now)
+	if err != nil || len(notifications) == 0 {
+		return 0, err
+	}
+
+	errs := map[string]error{}
+	for _, notification := range notifications {
+		errs[notification.ID] = n.send(ctx, notification)
+	}
+
+	return n.record(ctx, notifications, errs)
+}
+
+func (n *Notifier) queueReminders(ctx context.Context, now time.Time) error {
+	c := &gin.Context{}
+
+	tx, err := n.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	reminders, err := n.Repo.FindDueReminders(ctx, tx, now, now.Add(n.ReminderBefore), c)
+	if err != nil {
+		c.Error(err)
+		return err
+	}
+
+	for _, reminder := range reminders {
+		err = n.Repo.Queue(ctx, tx, reminder, c)
+		if err != nil {
+			c.Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *Notifier) claim(ctx context.Context, now time.Time) ([]entity.Notification, error) {
+	c := &gin.Context{}
+
+	tx, err := n.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	// The claim lasts as long as the batch may take to send, after which an
+	// email whose outcome was never recorded becomes due again.
+	notifications, err := n.Repo.ClaimDue(ctx, tx, now, now.Add(claimLease), batchSize, c)
+	if err != nil {
+		c.Error(err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (n *Notifier) record(ctx context.Context, notifications []entity.Notification, errs map[string]error) (int, error) {
+	c := &gin.Context{}
+
+	tx, err := n.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	sent := 0
+	for _, notification := range notifications {
+		sendErr := errs[notification.ID]
+
+		switch {
+		case sendErr == nil:
+			err = n.Repo.MarkSent(ctx, tx, notification.ID, c)
+			sent++
+		case notification.Attempts >= n.MaxAttempts:
+			err = n.Repo.Abandon(ctx, tx, notification.ID, sendErr.Error(), c)
+		default:
+			err = n.Repo.Reschedule(ctx, tx, notification.ID, sendErr.Error(), n.Now().Add(dispatcher.Backoff(notification.Attempts)), c)
+		}
+		if err != nil {
+			c.Error(err)
+			return 0, err
+		}
+	}
+
+	return sent, nil
+}
+
+func (n *Notifier) send(ctx context.Context, notification entity.Notification) error {
+	message, err := templates.Render(notification)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	return n.Sender.Send(ctx, message)
+}
@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"bioskuy/api/v1/notification/mock/repomock"
+	"bioskuy/api/v1/notification/sender"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotifierTestSuite struct {
+	suite.Suite
+	repo     *repomock.MockNotificationRepository
+	sender   *sender.CaptureSender
+	mockDb   *sql.DB
+	mockSql  sqlmock.Sqlmock
+	notifier *Notifier
+	now      time.Time
+}
+
+func (suite *NotifierTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.repo = new(repomock.MockNotificationRepository)
+	suite.sender = sender.NewCaptureSender()
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.now = time.Date(2024, 8, 3, 9, 0, 0, 0, time.UTC)
+	suite.notifier = NewNotifier(suite.repo, suite.mockDb, suite.sender, time.Second, 3, 3*time.Hour)
+	suite.notifier.Now = func() time.Time { return suite.now }
+}
+
+func (suite *NotifierTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestNotifierTestSuite(t *testing.T) {
+	suite.Run(t, new(NotifierTestSuite))
+}
+
+func (suite *NotifierTestSuite) notification(attempts int) entity.Notification {
+	return entity.Notification{
+		ID:            "notification-1",
+		UserID:        "user-1",
+		Kind:          entity.KindBookingConfirmed,
+		Recipient:     "budi@example.com",
+		RecipientName: "Budi",
+		Attempts:      attempts,
+		Data: entity.Data{
+			MovieTitle: "Dune",
+			StudioName: "Studio 1",
+			ShowStart:  suite.now.Add(5 * time.Hour),
+			Timezone:   "Asia/Jakarta",
+			Seats:      []string{"A1"},
+			TotalPrice: 50000,
+		},
+	}
+}
+
+func (suite *NotifierTestSuite) expectReminders(reminders []entity.Notification) {
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("FindDueReminders", mock.Anything, mock.Anything, suite.now, suite.now.Add(3*time.Hour), mock.Anything).Return(reminders, nil)
+	suite.mockSql.ExpectCommit()
+}
+
+func (suite *NotifierTestSuite) expectClaim(notifications []entity.Notification) {
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("ClaimDue", mock.Anything, mock.Anything, suite.now, suite.now.Add(claimLease), batchSize, mock.Anything).Return(notifications, nil)
+	suite.mockSql.ExpectCommit()
+}
+
+func (suite *NotifierTestSuite) TestNotify_SendsRenderedEmail() {
+	suite.expectReminders([]entity.Notification{})
+	suite.expectClaim([]entity.Notification{suite.notification(1)})
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("MarkSent", mock.Anything, mock.Anything, "notification-1", mock.Anything).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	sent, err := suite.notifier.Notify(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, sent)
+	messages := suite.sender.Messages()
+	assert.Len(suite.T(), messages, 1)
+	assert.Equal(suite.T(), "budi@example.com", messages[0].To)
+	assert.Equal(suite.T(), "Your booking for Dune is confirmed", messages[0].Subject)
+	suite.repo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotifierTestSuite) TestNotify_QueuesReminders() {
+	reminder := suite.notification(0)
+	reminder.Kind = entity.KindShowtimeReminder
+	reminder.DedupeKey = "reminder:payment-1"
+
+	suite.expectReminders([]entity.Notification{reminder})
+	suite.repo.On("Queue", mock.Anything, mock.Anything, reminder, mock.Anything).Return(nil)
+	suite.expectClaim([]entity.Notification{})
+
+	sent, err := suite.notifier.Notify(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, sent)
+	suite.repo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotifierTestSuite) TestNotify_ReschedulesFailedEmail() {
+	suite.sender.Err = errors.New("connection refused")
+
+	suite.expectReminders([]entity.Notification{})
+	suite.expectClaim([]entity.Notification{suite.notification(2)})
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("Reschedule", mock.Anything, mock.Anything, "notification-1", "connection refused", suite.now.Add(time.Minute), mock.Anything).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	sent, err := suite.notifier.Notify(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, sent)
+	suite.repo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotifierTestSuite) TestNotify_AbandonsAfterMaxAttempts() {
+	suite.sender.Err = errors.New("mailbox unavailable")
+
+	suite.expectReminders([]entity.Notification{})
+	suite.expectClaim([]entity.Notification{suite.notification(3)})
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("Abandon", mock.Anything, mock.Anything, "notification-1", "mailbox unavailable", mock.Anything).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	_, err := suite.notifier.Notify(context.Background())
+
+	assert.NoError(suite.T(), err)
+	suite.repo.AssertNotCalled(suite.T(), "Reschedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.repo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotifierTestSuite) TestNotify_ReminderErrorRollsBack() {
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("FindDueReminders", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]entity.Notification{}, errors.New("query error"))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.notifier.Notify(context.Background())
+
+	assert.Error(suite.T(), err)
+	suite.repo.AssertNotCalled(suite.T(), "ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationRepository queues customer emails and tracks their sending.
+type NotificationRepository interface {
+	Queue(ctx context.Context, tx *sql.Tx, notification entity.Notification, c *gin.Context) error
+	FindDueReminders(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time, c *gin.Context) ([]entity.Notification, error)
+	ClaimDue(ctx context.Context, tx *sql.Tx, now time.Time, leaseUntil time.Time, limit int, c *gin.Context) ([]entity.Notification, error)
+	MarkSent(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	Reschedule(ctx context.Context, tx *sql.Tx, id string, lastError string, nextAttemptAt time.Time, c *gin.Context) error
+	Abandon(ctx context.Context, tx *sql.Tx, id string, lastError string, c *gin.Context) error
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"bioskuy/exception"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type notificationRepository struct {
+}
+
+func NewNotificationRepository() NotificationRepository {
+	return &notificationRepository{}
+}
+
+// Queue records an email to the notification's user. It must run in the
+// transaction of the change it announces. A notification whose DedupeKey was
+// queued before is dropped.
+func (r *notificationRepository) Queue(ctx context.Context, tx *sql.Tx, notification entity.Notification, c *gin.Context) error {
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	query := `INSERT INTO notifications (user_id, kind, recipient, recipient_name, data, dedupe_key)
+		SELECT u.id, $2, u.email, u.name, $3, NULLIF($4, '') FROM users u WHERE u.id = $1
+		ON CONFLICT (dedupe_key) DO NOTHING`
+
+	_, err = tx.ExecContext(ctx, query, notification.UserID, notification.Kind, string(data), notification.DedupeKey)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// FindDueReminders returns a reminder, ready to queue, for every paid booking
+// whose show starts after from and no later than to and that has not been
+// reminded yet.
+func (r *notificationRepository) FindDueReminders(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time, c *gin.Context) ([]entity.Notification, error) {
+	query := `SELECT p.id, p.user_id, p.total_price, sb.id, m.title, st.name, sh.show_start, ci.timezone,
+			ARRAY_AGG(se.seat_name ORDER BY se.seat_name)
+		FROM payments p
+		JOIN seat_bookings sb ON p.seatbooking_id = sb.id
+		JOIN showtimes sh ON sb.showtime_id = sh.id
+		JOIN movies m ON sh.movie_id = m.id
+		JOIN studios st ON sh.studio_id = st.id
+		JOIN cinemas ci ON st.cinema_id = ci.id
+		JOIN seat_detail_for_bookings sdfb ON sb.id = sdfb.seatBooking_id
+		JOIN seats se ON sdfb.seat_id = se.id
+		WHERE p.status IN ('paid', 'partially_refunded') AND sh.show_start > $1 AND sh.show_start <= $2
+		AND sh.cancelled_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM notifications n WHERE n.dedupe_key = 'reminder:' || p.id)
+		GROUP BY p.id, sb.id, m.title, st.name, sh.show_start, ci.timezone
+		ORDER BY sh.show_start, p.id`
+
+	rows, err := tx.QueryContext(ctx, query, from, to)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []entity.Notification{}
+	for rows.Next() {
+		reminder := entity.Notification{Kind: entity.KindShowtimeReminder}
+		data := &reminder.Data
+
+		err := rows.Scan(&data.PaymentID, &reminder.UserID, &data.TotalPrice, &data.SeatBookingID, &data.MovieTitle, &data.StudioName, &data.ShowStart, &data.Timezone,
+			pq.Array(&data.Seats))
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		reminder.DedupeKey = "reminder:" + data.PaymentID
+		reminders = append(reminders, reminder)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+// ClaimDue takes up to limit unsent notifications that are due at now,
+// counts an attempt on each and holds them until leaseUntil, like the
+// webhook outbox does with its deliveries.
+func (r *notificationRepository) ClaimDue(ctx context.Context, tx *sql.Tx, now time.Time, leaseUntil time.Time, limit int, c *gin.Context) ([]entity.Notification, error) {
+	query := `UPDATE notifications n
+		SET attempts = n.attempts + 1, next_attempt_at = $2
+		WHERE n.id IN (
+			SELECT due.id FROM notifications due
+			WHERE due.sent_at IS NULL AND due.failed_at IS NULL AND due.next_attempt_at <= $1
+			ORDER BY due.next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING n.id, n.user_id, n.kind, n.recipient, n.recipient_name, n.data, COALESCE(n.dedupe_key, ''),
+			n.attempts, n.next_attempt_at, n.created_at`
+
+	rows, err := tx.QueryContext(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []entity.Notification{}
+	for rows.Next() {
+		notification := entity.Notification{}
+		var data []byte
+
+		err := rows.Scan(&notification.ID, &notification.UserID, &notification.Kind, &notification.Recipient, &notification.RecipientName, &data, &notification.DedupeKey,
+			&notification.Attempts, &notification.NextAttemptAt, &notification.CreatedAt)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &notification.Data); err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationRepository) MarkSent(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	query := `UPDATE notifications SET sent_at = (NOW() AT TIME ZONE 'UTC'), last_error = NULL WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// Reschedule records a failed attempt and when to try again.
+func (r *notificationRepository) Reschedule(ctx context.Context, tx *sql.Tx, id string, lastError string, nextAttemptAt time.Time, c *gin.Context) error {
+	query := `UPDATE notifications SET last_error = $2, next_attempt_at = $3 WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, lastError, nextAttemptAt)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// Abandon records the last failed attempt of a notification that is not
+// retried.
+func (r *notificationRepository) Abandon(ctx context.Context, tx *sql.Tx, id string, lastError string, c *gin.Context) error {
+	query := `UPDATE notifications SET last_error = $2, failed_at = (NOW() AT TIME ZONE 'UTC') WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, lastError)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
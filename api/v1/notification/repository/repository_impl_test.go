@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationRepositoryTestSuite struct {
+	suite.Suite
+	mockDb  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    NotificationRepository
+	ctx     context.Context
+	ginCtx  *gin.Context
+}
+
+var showStart = time.Date(2024, 8, 3, 12, 0, 0, 0, time.UTC)
+
+func (suite *NotificationRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.repo = NewNotificationRepository()
+	suite.ctx = context.Background()
+	suite.ginCtx = &gin.Context{}
+}
+
+func (suite *NotificationRepositoryTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestNotificationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationRepositoryTestSuite))
+}
+
+func (suite *NotificationRepositoryTestSuite) begin() *sql.Tx {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+	return tx
+}
+
+func (suite *NotificationRepositoryTestSuite) TestQueue() {
+	tx := suite.begin()
+	notification := entity.Notification{
+		UserID: "user-1",
+		Kind:   entity.KindBookingConfirmed,
+		Data:   entity.Data{SeatBookingID: "booking-1", MovieTitle: "Dune", ShowStart: showStart, Seats: []string{"A1"}},
+	}
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`INSERT INTO notifications (user_id, kind, recipient, recipient_name, data, dedupe_key)`)+`.*`+regexp.QuoteMeta(`ON CONFLICT (dedupe_key) DO NOTHING`)).
+		WithArgs("user-1", entity.KindBookingConfirmed, sqlmock.AnyArg(), "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Queue(suite.ctx, tx, notification, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotificationRepositoryTestSuite) TestQueue_Error() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`INSERT INTO notifications`)).WillReturnError(errors.New("insert error"))
+
+	err := suite.repo.Queue(suite.ctx, tx, entity.Notification{UserID: "user-1", Kind: entity.KindBookingConfirmed}, suite.ginCtx)
+
+	assert.EqualError(suite.T(), err, "insert error")
+	assert.Len(suite.T(), suite.ginCtx.Errors, 1)
+}
+
+func (suite *NotificationRepositoryTestSuite) TestFindDueReminders() {
+	tx := suite.begin()
+	from := showStart.Add(-3 * time.Hour)
+	to := showStart.Add(time.Hour)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM payments p`)+`.*`+regexp.QuoteMeta(`AND sh.cancelled_at IS NULL`)+`.*`+regexp.QuoteMeta(`n.dedupe_key = 'reminder:' || p.id`)).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "total_price", "id", "title", "name", "show_start", "timezone", "seats"}).
+			AddRow("payment-1", "user-1", 100000, "booking-1", "Dune", "Studio 1", showStart, "Asia/Jakarta", "{A1,A2}"))
+
+	reminders, err := suite.repo.FindDueReminders(suite.ctx, tx, from, to, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), reminders, 1)
+	assert.Equal(suite.T(), entity.KindShowtimeReminder, reminders[0].Kind)
+	assert.Equal(suite.T(), "reminder:payment-1", reminders[0].DedupeKey)
+	assert.Equal(suite.T(), []string{"A1", "A2"}, reminders[0].Data.Seats)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotificationRepositoryTestSuite) TestClaimDue() {
+	tx := suite.begin()
+	now := showStart.Add(-time.Hour)
+	leaseUntil := now.Add(time.Minute)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`UPDATE notifications n`)+`.*`+regexp.QuoteMeta(`FOR UPDATE SKIP LOCKED`)).
+		WithArgs(now, leaseUntil, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "kind", "recipient", "recipient_name", "data", "dedupe_key", "attempts", "next_attempt_at", "created_at"}).
+			AddRow("notification-1", "user-1", entity.KindBookingConfirmed, "budi@example.com", "Budi", []byte(`{"movie_title":"Dune","seats":["A1"]}`), "", 1, leaseUntil, now))
+
+	notifications, err := suite.repo.ClaimDue(suite.ctx, tx, now, leaseUntil, 50, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), notifications, 1)
+	assert.Equal(suite.T(), "budi@example.com", notifications[0].Recipient)
+	assert.Equal(suite.T(), "Dune", notifications[0].Data.MovieTitle)
+	assert.Equal(suite.T(), []string{"A1"}, notifications[0].Data.Seats)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotificationRepositoryTestSuite) TestReschedule() {
+	tx := suite.begin()
+	next := showStart.Add(-time.Hour)
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE notifications SET last_error = $2, next_attempt_at = $3 WHERE id = $1`)).
+		WithArgs("notification-1", "connection refused", next).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Reschedule(suite.ctx, tx, "notification-1", "connection refused", next, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *NotificationRepositoryTestSuite) TestAbandon() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE notifications SET last_error = $2, failed_at = (NOW() AT TIME ZONE 'UTC') WHERE id = $1`)).
+		WithArgs("notification-1", "mailbox unavailable").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Abandon(suite.ctx, tx, "notification-1", "mailbox unavailable", suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
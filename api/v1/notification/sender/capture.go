@@ -0,0 +1,38 @@
+package sender
+
+import (
+	"context"
+	"sync"
+)
+
+// CaptureSender keeps sent emails in memory for tests. When Err is set,
+// Send fails with it instead.
+type CaptureSender struct {
+	Err      error
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewCaptureSender() *CaptureSender {
+	return &CaptureSender{}
+}
+
+func (s *CaptureSender) Send(ctx context.Context, message Message) error {
+	if s.Err != nil {
+		return s.Err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, message)
+
+	return nil
+}
+
+// Messages returns the emails sent so far, oldest first.
+func (s *CaptureSender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message{}, s.messages...)
+}
@@ -0,0 +1,43 @@
+package sender
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileSender writes each email as a .eml file in Dir instead of sending it,
+// for development. The files open in any mail client.
+type FileSender struct {
+	Dir  string
+	From string
+	Now  func() time.Time
+}
+
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{
+		Dir:  dir,
+		From: from,
+		Now:  time.Now,
+	}
+}
+
+func (s *FileSender) Send(ctx context.Context, message Message) error {
+	now := s.Now()
+
+	email, err := Build(s.From, message, now)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+
+	recipient := strings.NewReplacer("/", "_", "\\", "_").Replace(message.To)
+	name := now.UTC().Format("20060102T150405.000000000") + "-" + recipient + ".eml"
+
+	return os.WriteFile(filepath.Join(s.Dir, name), email, 0o644)
+}
@@ -0,0 +1,86 @@
+// Package sender delivers rendered emails. Production uses SMTP; development
+// writes .eml files, or points SMTP at a capture server such as MailHog, and
+// tests keep messages in memory.
+package sender
+
+import (
+	"bioskuy/helper"
+	"bytes"
+	"context"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender delivers one email. An error means the email may be retried.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+const (
+	ProviderSMTP = "smtp"
+	ProviderFile = "file"
+)
+
+// NewSender returns the sender selected by EMAIL_SENDER, falling back to
+// writing files to EMAIL_OUTBOX_DIR so that development never mails real
+// customers by accident.
+func NewSender(config *helper.Config) Sender {
+	if config.EmailSender == ProviderSMTP {
+		return NewSMTPSender(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.EmailFrom)
+	}
+
+	return NewFileSender(config.EmailOutboxDirectory(), config.EmailFrom)
+}
+
+// Build encodes message as a multipart/alternative email from from, with the
+// text part first so that clients prefer the HTML one.
+func Build(from string, message Message, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var email bytes.Buffer
+	email.WriteString("From: " + from + "\r\n")
+	email.WriteString("To: " + message.To + "\r\n")
+	email.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	email.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	email.WriteString("MIME-Version: 1.0\r\n")
+	email.WriteString("Content-Type: multipart/alternative; boundary=" + parts.Boundary() + "\r\n")
+	email.WriteString("\r\n")
+	email.Write(body.Bytes())
+
+	return email.Bytes(), nil
+}
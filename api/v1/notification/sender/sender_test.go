@@ -0,0 +1,145 @@
+package sender
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var message = Message{
+	To:      "budi@example.com",
+	Subject: "Your booking for Dune is confirmed",
+	Text:    "Hi Budi,\n\nSeats: A1, A2\n",
+	HTML:    "<p>Hi Budi,</p>",
+}
+
+var sentAt = time.Date(2024, 8, 3, 9, 0, 0, 0, time.UTC)
+
+// parts reads the text and HTML bodies back out of an email built by Build,
+// with the CRLF line endings of the wire format turned back into LF.
+func parts(t *testing.T, email []byte) (*mail.Message, []string) {
+	parsed, err := mail.ReadMessage(strings.NewReader(string(email)))
+	assert.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	bodies := []string{}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(part)
+		bodies = append(bodies, strings.ReplaceAll(string(body), "\r\n", "\n"))
+	}
+
+	return parsed, bodies
+}
+
+func TestBuild(t *testing.T) {
+	email, err := Build("Bioskuy <no-reply@bioskuy.id>", message, sentAt)
+	assert.NoError(t, err)
+
+	parsed, bodies := parts(t, email)
+
+	assert.Equal(t, "Bioskuy <no-reply@bioskuy.id>", parsed.Header.Get("From"))
+	assert.Equal(t, "budi@example.com", parsed.Header.Get("To"))
+	assert.Equal(t, message.Subject, parsed.Header.Get("Subject"))
+	assert.Equal(t, []string{message.Text, message.HTML}, bodies)
+}
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "emails")
+	sender := NewFileSender(dir, "no-reply@bioskuy.id")
+	sender.Now = func() time.Time { return sentAt }
+
+	err := sender.Send(context.Background(), message)
+	assert.NoError(t, err)
+
+	email, err := os.ReadFile(filepath.Join(dir, "20240803T090000.000000000-budi@example.com.eml"))
+	assert.NoError(t, err)
+	_, bodies := parts(t, email)
+	assert.Equal(t, message.Text, bodies[0])
+}
+
+func TestCaptureSender(t *testing.T) {
+	sender := NewCaptureSender()
+
+	assert.NoError(t, sender.Send(context.Background(), message))
+	assert.Equal(t, []Message{message}, sender.Messages())
+
+	sender.Err = errors.New("mailbox unavailable")
+	assert.EqualError(t, sender.Send(context.Background(), message), "mailbox unavailable")
+	assert.Len(t, sender.Messages(), 1)
+}
+
+// TestSMTPSender talks to a minimal SMTP server that accepts one email.
+func TestSMTPSender(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		lines := []string{}
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			lines = append(lines, line)
+
+			switch {
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				text.PrintfLine("250 localhost")
+			case line == "DATA":
+				text.PrintfLine("354 go ahead")
+				data, _ := io.ReadAll(bufio.NewReader(text.DotReader()))
+				lines = append(lines, string(data))
+				text.PrintfLine("250 queued")
+			case line == "QUIT":
+				text.PrintfLine("221 bye")
+				received <- lines
+				return
+			default:
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	sender := NewSMTPSender(host, port, "", "", "Bioskuy <no-reply@bioskuy.id>")
+
+	err = sender.Send(context.Background(), message)
+	assert.NoError(t, err)
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<no-reply@bioskuy.id>")
+	assert.Contains(t, lines, "RCPT TO:<budi@example.com>")
+	assert.Contains(t, strings.Join(lines, "\n"), "Subject: Your booking for Dune is confirmed")
+}
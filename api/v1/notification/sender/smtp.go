@@ -0,0 +1,49 @@
+package sender
+
+import (
+	"context"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPSender sends through an SMTP server, authenticating with PLAIN when a
+// username is set. Capture servers such as MailHog need no username.
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, message Message) error {
+	email, err := Build(s.From, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	// The envelope takes the bare address of From, which may carry a name.
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, from.Address, []string{message.To}, email)
+}
@@ -0,0 +1,109 @@
+// Package templates renders the customer emails. Each kind has a text and an
+// HTML template; the text one also defines the subject.
+package templates
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"bioskuy/api/v1/notification/sender"
+	"bioskuy/helper"
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"strconv"
+	"strings"
+	textTemplate "text/template"
+	"time"
+)
+
+//go:embed *.tmpl
+var files embed.FS
+
+var funcs = map[string]interface{}{
+	"local":  local,
+	"rupiah": rupiah,
+	"join":   strings.Join,
+}
+
+var (
+	texts = map[string]*textTemplate.Template{}
+	htmls = map[string]*htmlTemplate.Template{}
+)
+
+func init() {
+	kinds := []string{
+		entity.KindBookingConfirmed,
+		entity.KindBookingExpired,
+		entity.KindBookingCancelled,
+		entity.KindBookingRefunded,
+		entity.KindLatePaymentRefunded,
+		entity.KindShowtimeReminder,
+		entity.KindShowtimeCancelled,
+		entity.KindShowtimeRescheduled,
+	}
+
+	for _, kind := range kinds {
+		texts[kind] = textTemplate.Must(textTemplate.New(kind).Funcs(funcs).ParseFS(files, "layout.txt.tmpl", kind+".txt.tmpl"))
+		htmls[kind] = htmlTemplate.Must(htmlTemplate.New(kind).Funcs(funcs).ParseFS(files, "layout.html.tmpl", kind+".html.tmpl"))
+	}
+}
+
+// view is what the templates see: the notification's data and the name of
+// the recipient.
+type view struct {
+	entity.Data
+	Name string
+}
+
+// Render builds the email for notification.
+func Render(notification entity.Notification) (sender.Message, error) {
+	text, ok := texts[notification.Kind]
+	if !ok {
+		return sender.Message{}, fmt.Errorf("no template for notification kind %s", notification.Kind)
+	}
+	html := htmls[notification.Kind]
+
+	data := view{Data: notification.Data, Name: notification.RecipientName}
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return sender.Message{}, err
+	}
+	if err := text.ExecuteTemplate(&textBody, "layout", data); err != nil {
+		return sender.Message{}, err
+	}
+	if err := html.ExecuteTemplate(&htmlBody, "layout", data); err != nil {
+		return sender.Message{}, err
+	}
+
+	return sender.Message{
+		To:      notification.Recipient,
+		Subject: subject.String(),
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+	}, nil
+}
+
+// local formats t as wall-clock time at the cinema, like the printed ticket.
+func local(t time.Time, timezone string) string {
+	return helper.InZone(t, timezone).Format("Mon, 02 Jan 2006 15:04") + " (" + helper.Location(timezone).String() + ")"
+}
+
+// rupiah formats amount with thousands separators, e.g. Rp100.000.
+func rupiah(amount int) string {
+	digits := strconv.Itoa(amount)
+	sign := ""
+	if amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return sign + "Rp" + grouped.String()
+}
@@ -0,0 +1,65 @@
+package templates
+
+import (
+	"bioskuy/api/v1/notification/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func notification(kind string) entity.Notification {
+	return entity.Notification{
+		Kind:          kind,
+		Recipient:     "budi@example.com",
+		RecipientName: "Budi",
+		Data: entity.Data{
+			MovieTitle:     "Dune <Part Two>",
+			StudioName:     "Studio 1",
+			ShowStart:      time.Date(2024, 8, 3, 12, 0, 0, 0, time.UTC),
+			Timezone:       "Asia/Jakarta",
+			Seats:          []string{"A1", "A2"},
+			TotalPrice:     100000,
+			RefundedAmount: 50000,
+		},
+	}
+}
+
+func TestRender_BookingConfirmed(t *testing.T) {
+	message, err := Render(notification(entity.KindBookingConfirmed))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "budi@example.com", message.To)
+	assert.Equal(t, "Your booking for Dune <Part Two> is confirmed", message.Subject)
+	assert.Contains(t, message.Text, "Hi Budi,")
+	assert.Contains(t, message.Text, "Rp100.000")
+	assert.Contains(t, message.Text, "Sat, 03 Aug 2024 19:00 (Asia/Jakarta)")
+	assert.Contains(t, message.Text, "A1, A2")
+	assert.Contains(t, message.HTML, "Dune &lt;Part Two&gt;")
+}
+
+func TestRender_EveryKind(t *testing.T) {
+	kinds := []string{entity.KindBookingConfirmed, entity.KindBookingExpired, entity.KindBookingCancelled, entity.KindBookingRefunded, entity.KindLatePaymentRefunded, entity.KindShowtimeReminder, entity.KindShowtimeCancelled, entity.KindShowtimeRescheduled}
+
+	for _, kind := range kinds {
+		message, err := Render(notification(kind))
+
+		assert.NoError(t, err, kind)
+		assert.NotEmpty(t, message.Subject, kind)
+		assert.NotEmpty(t, message.Text, kind)
+		assert.NotEmpty(t, message.HTML, kind)
+	}
+}
+
+func TestRender_UnknownKind(t *testing.T) {
+	_, err := Render(notification("newsletter"))
+
+	assert.EqualError(t, err, "no template for notification kind newsletter")
+}
+
+func TestRupiah(t *testing.T) {
+	assert.Equal(t, "Rp0", rupiah(0))
+	assert.Equal(t, "Rp500", rupiah(500))
+	assert.Equal(t, "Rp50.000", rupiah(50000))
+	assert.Equal(t, "Rp1.250.000", rupiah(1250000))
+}
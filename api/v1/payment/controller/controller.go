@@ -8,5 +8,7 @@ type PaymentController interface {
 	Create(c *gin.Context)
 	FindById(c *gin.Context)
 	FindAll(c *gin.Context)
+	FindMine(c *gin.Context)
 	Notification(c *gin.Context)
+	Refund(c *gin.Context)
 }
@@ -4,6 +4,7 @@ import (
 	"bioskuy/api/v1/payment/dto"
 	"bioskuy/api/v1/payment/service"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"bioskuy/web"
 	"net/http"
 
@@ -49,7 +50,7 @@ func (controller *paymentControllerImpl) FindById(c *gin.Context){
 	ctx := c.Request.Context()
 	id := c.Param("paymentId")
 
-	result, err := controller.Service.FindByID(ctx, id, c)
+	result, err := controller.Service.FindByID(ctx, id, c.GetString("user_id"), c.GetString("role"), c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return
@@ -62,38 +63,95 @@ func (controller *paymentControllerImpl) FindById(c *gin.Context){
 }
 
 func (controller *paymentControllerImpl) FindAll(c *gin.Context) {
-    ctx := c.Request.Context()
-
-    result, err := controller.Service.FindAll(ctx, c)
+    query, err := helper.ListQueryFrom(c, "status", "user_id", "showtime_id", "movie_id", "cinema_id")
     if err != nil {
-        c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return
     }
 
-    response := web.FormatResponse{
-        ResponseCode: http.StatusOK,
-        Data:    result,
+    query, ok := helper.ScopeToCinema(c, query)
+    if !ok {
+        c.Error(exception.ForbiddenError{Message: "your account is not assigned to a cinema"}).SetType(gin.ErrorTypePublic)
+        return
     }
 
-    c.JSON(http.StatusOK, response)
+    controller.list(c, query)
+}
+
+// FindMine lists the signed-in user's own payments, optionally only those for
+// upcoming or past showtimes.
+func (controller *paymentControllerImpl) FindMine(c *gin.Context) {
+	query, err := helper.OwnListQuery(c, "status", "movie_id", "cinema_id")
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	controller.list(c, query)
+}
+
+func (controller *paymentControllerImpl) list(c *gin.Context, query helper.ListQuery) {
+	ctx := c.Request.Context()
+
+	result, paging, err := controller.Service.FindAll(ctx, query, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	response := web.FormatResponsePaging{
+		ResponseCode: http.StatusOK,
+		Data:         result,
+		Paging:       paging,
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (controller *paymentControllerImpl) Notification(c *gin.Context){
     ctx := c.Request.Context()
+    notification := dto.PaymentNotificationRequest{}
 
-	var notificationPayload map[string]interface{}
-
-	if err := c.ShouldBindJSON(&notificationPayload); err != nil {
+	if err := c.ShouldBindJSON(&notification); err != nil {
         c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return
 	}
 
-	_, exists := notificationPayload["order_id"].(string)
-	if !exists {
-        c.Error(exception.ValidationError{Message: "order id not found"}).SetType(gin.ErrorTypePublic)
+    err := controller.Service.Update(ctx, notification, c)
+    if err != nil {
         return
+    }
+
+    response := web.FormatResponse{
+        ResponseCode: http.StatusOK,
+        Data:    "OK",
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+func (controller *paymentControllerImpl) Refund(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("paymentId")
+	request := dto.RefundRequest{}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
 	}
 
-    controller.Service.Update(ctx, notificationPayload, c)
+	userId := c.MustGet("user_id").(string)
+	role := c.MustGet("role").(string)
+
+	result, err := controller.Service.Refund(ctx, id, request, userId, role, c)
+	if err != nil {
+		return
+	}
+
+	response := web.FormatResponse{
+		ResponseCode: http.StatusOK,
+		Data:         result,
+	}
 
+	c.JSON(http.StatusOK, response)
 }
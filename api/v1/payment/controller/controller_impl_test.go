@@ -2,8 +2,11 @@ package controller
 
 import (
 	"bioskuy/api/v1/payment/dto"
+	"bioskuy/api/v1/payment/gateway"
+	"bioskuy/api/v1/payment/mock/notificationmock"
 	"bioskuy/api/v1/payment/mock/servicemock"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"bioskuy/web"
 	"bytes"
 	"context"
@@ -30,12 +33,21 @@ func (suite *PaymentControllerTestSuite) SetupTest() {
 	suite.mockService = new(servicemock.MockPaymentService)
 	suite.controller = NewPaymentController(suite.mockService)
 	suite.router = gin.Default()
+	suite.router.Use(exception.ErrorHandler)
 	suite.ctx = context.Background()
 
 	suite.router.POST("/payments", suite.controller.Create)
 	suite.router.GET("/payments/:paymentId", suite.controller.FindById)
 	suite.router.GET("/payments", suite.controller.FindAll)
+	suite.router.GET("/me/payments", func(c *gin.Context) {
+		c.Set("user_id", "user-id")
+		c.Set("role", "user")
+	}, suite.controller.FindMine)
 	suite.router.POST("/payments/notification", suite.controller.Notification)
+	suite.router.POST("/payments/:paymentId/refund", func(c *gin.Context) {
+		c.Set("user_id", "user-id")
+		c.Set("role", "user")
+	}, suite.controller.Refund)
 }
 
 func TestPaymentControllerTestSuite(t *testing.T) {
@@ -45,13 +57,13 @@ func TestPaymentControllerTestSuite(t *testing.T) {
 // Create
 func (suite *PaymentControllerTestSuite) TestCreate_Success() {
 	paymentRequest := dto.PaymentRequest{
-		SeatDetailForBookingID: "seat-id",
+		SeatBookingID: "booking-id",
 	}
 	paymentResponse := dto.PaymentResponse{
-		ID:                     "new-id",
-		SeatDetailForBookingID: "seat-id",
-		TotalSeat:              1,
-		TotalPrice:             10000,
+		ID:            "new-id",
+		SeatBookingID: "booking-id",
+		TotalSeat:     1,
+		TotalPrice:    10000,
 	}
 
 	suite.mockService.On("Create", mock.Anything, mock.AnythingOfType("dto.PaymentRequest"), "user-id", mock.Anything).Return(paymentResponse, nil)
@@ -85,7 +97,7 @@ func (suite *PaymentControllerTestSuite) TestCreate_BindError() {
 
 func (suite *PaymentControllerTestSuite) TestCreate_ServiceError() {
 	paymentRequest := dto.PaymentRequest{
-		SeatDetailForBookingID: "seat-id",
+		SeatBookingID: "booking-id",
 	}
 	serviceError := exception.ForbiddenError{Message: "service error"}
 
@@ -105,13 +117,13 @@ func (suite *PaymentControllerTestSuite) TestCreate_ServiceError() {
 // FindById
 func (suite *PaymentControllerTestSuite) TestFindById_Success() {
 	paymentResponse := dto.PaymentResponse{
-		ID:                     "some-id",
-		SeatDetailForBookingID: "seat-id",
-		TotalSeat:              1,
-		TotalPrice:             10000,
+		ID:            "some-id",
+		SeatBookingID: "booking-id",
+		TotalSeat:     1,
+		TotalPrice:    10000,
 	}
 
-	suite.mockService.On("FindByID", mock.Anything, "some-id", mock.Anything).Return(paymentResponse, nil)
+	suite.mockService.On("FindByID", mock.Anything, "some-id", mock.Anything, mock.Anything, mock.Anything).Return(paymentResponse, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/payments/some-id", nil)
 	w := httptest.NewRecorder()
@@ -128,7 +140,7 @@ func (suite *PaymentControllerTestSuite) TestFindById_Success() {
 func (suite *PaymentControllerTestSuite) TestFindById_NotFoundError() {
 	serviceError := exception.NotFoundError{Message: "not found"}
 
-	suite.mockService.On("FindByID", mock.Anything, "some-id", mock.Anything).Return(dto.PaymentResponse{}, serviceError)
+	suite.mockService.On("FindByID", mock.Anything, "some-id", mock.Anything, mock.Anything, mock.Anything).Return(dto.PaymentResponse{}, serviceError)
 
 	req := httptest.NewRequest(http.MethodGet, "/payments/some-id", nil)
 	w := httptest.NewRecorder()
@@ -141,13 +153,14 @@ func (suite *PaymentControllerTestSuite) TestFindById_NotFoundError() {
 // FindAll
 func (suite *PaymentControllerTestSuite) TestFindAll_Success() {
 	paymentResponses := []dto.PaymentResponse{
-		{ID: "id1", SeatDetailForBookingID: "seat1", TotalSeat: 1, TotalPrice: 10000},
-		{ID: "id2", SeatDetailForBookingID: "seat2", TotalSeat: 2, TotalPrice: 20000},
+		{ID: "id1", SeatBookingID: "booking1", TotalSeat: 1, TotalPrice: 10000},
+		{ID: "id2", SeatBookingID: "booking2", TotalSeat: 2, TotalPrice: 20000},
 	}
 
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(paymentResponses, nil)
+	suite.mockService.On("FindAll", mock.Anything, helper.ListQuery{Page: 1, Size: 10, Sort: "total_price", Desc: true}.With("status", "paid"), mock.Anything).
+		Return(paymentResponses, web.Paging{Page: 1, Size: 10, TotalData: 2, TotalPages: 1}, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/payments", nil)
+	req := httptest.NewRequest(http.MethodGet, "/payments?status=paid&sort=total_price&order=desc", nil)
 	w := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
@@ -162,7 +175,7 @@ func (suite *PaymentControllerTestSuite) TestFindAll_Success() {
 func (suite *PaymentControllerTestSuite) TestFindAll_ServiceError() {
 	serviceError := exception.InternalServerError{Message: "internal error"}
 
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(nil, serviceError)
+	suite.mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return([]dto.PaymentResponse{}, web.Paging{}, serviceError)
 
 	req := httptest.NewRequest(http.MethodGet, "/payments", nil)
 	w := httptest.NewRecorder()
@@ -172,22 +185,36 @@ func (suite *PaymentControllerTestSuite) TestFindAll_ServiceError() {
 	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
 }
 
-// Notification
-func (suite *PaymentControllerTestSuite) TestNotification_Success() {
-	notificationPayload := map[string]interface{}{
-		"order_id": "some-id",
-	}
-
-	suite.mockService.On("Update", mock.Anything, notificationPayload, mock.Anything).Return()
+func (suite *PaymentControllerTestSuite) TestFindMine_Upcoming() {
+	query := helper.ListQuery{Page: 1, Size: 10}.With("when", "upcoming").With("user_id", "user-id")
+	suite.mockService.On("FindAll", mock.Anything, query, mock.Anything).Return([]dto.PaymentResponse{}, web.Paging{Page: 1, Size: 10}, nil)
 
-	payload, _ := json.Marshal(notificationPayload)
-	req := httptest.NewRequest(http.MethodPost, "/payments/notification", bytes.NewBuffer(payload))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodGet, "/me/payments?when=upcoming", nil)
 	w := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
 
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Notification
+func (suite *PaymentControllerTestSuite) notificationSender(serverKey string) *notificationmock.FakeNotificationSender {
+	sender := notificationmock.NewFakeNotificationSender(serverKey, suite.router)
+	sender.Path = "/payments/notification"
+	return sender
+}
+
+func (suite *PaymentControllerTestSuite) TestNotification_Success() {
+	sender := suite.notificationSender("server-key")
+	notification := sender.Notification("some-id", "settlement", 10000)
+
+	suite.mockService.On("Update", mock.Anything, notification, mock.Anything).Return(nil)
+
+	w := sender.Send(notification)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
 }
 
 func (suite *PaymentControllerTestSuite) TestNotification_BindError() {
@@ -198,20 +225,94 @@ func (suite *PaymentControllerTestSuite) TestNotification_BindError() {
 
 	suite.router.ServeHTTP(w, req)
 
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+func (suite *PaymentControllerTestSuite) TestNotification_Rejected() {
+	sender := suite.notificationSender("wrong-key")
+	notification := sender.Notification("some-id", "settlement", 10000)
+	rejected := exception.ForbiddenError{Message: "invalid notification signature"}
+
+	suite.mockService.On("Update", mock.Anything, notification, mock.Anything).Return(rejected).Run(func(args mock.Arguments) {
+		args.Get(2).(*gin.Context).Error(rejected).SetType(gin.ErrorTypePublic)
+	})
+
+	w := sender.Send(notification)
+
 	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
 }
 
-func (suite *PaymentControllerTestSuite) TestNotification_ValidationError() {
-	notificationPayload := map[string]interface{}{
-		"invalid_key": "value",
+// Refund
+func (suite *PaymentControllerTestSuite) TestRefund_Success() {
+	refundRequest := dto.RefundRequest{SeatIDs: []string{"seat-1"}, Reason: "cannot attend"}
+	refundResponse := dto.RefundResponse{ID: "refund-id", PaymentID: "payment-id", Amount: 10000, Reason: "cannot attend", SeatIDs: []string{"seat-1"}, PaymentStatus: "partially_refunded"}
+
+	suite.mockService.On("Refund", mock.Anything, "payment-id", refundRequest, "user-id", "user", mock.Anything).Return(refundResponse, nil)
+
+	payload, _ := json.Marshal(refundRequest)
+	req := httptest.NewRequest(http.MethodPost, "/payments/payment-id/refund", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	var response struct {
+		Data dto.RefundResponse `json:"data"`
 	}
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), refundResponse, response.Data)
+}
 
-	payload, _ := json.Marshal(notificationPayload)
-	req := httptest.NewRequest(http.MethodPost, "/payments/notification", bytes.NewBuffer(payload))
+func (suite *PaymentControllerTestSuite) TestRefund_BindError() {
+	req := httptest.NewRequest(http.MethodPost, "/payments/payment-id/refund", bytes.NewBufferString(`{"seat_ids": "seat-1"}`))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
 
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentControllerTestSuite) TestRefund_WindowClosed() {
+	refundRequest := dto.RefundRequest{Reason: "cannot attend"}
+	closed := exception.ForbiddenError{Message: "refunds are closed for this showtime"}
+
+	suite.mockService.On("Refund", mock.Anything, "payment-id", refundRequest, "user-id", "user", mock.Anything).Return(dto.RefundResponse{}, closed).Run(func(args mock.Arguments) {
+		args.Get(5).(*gin.Context).Error(closed).SetType(gin.ErrorTypePublic)
+	})
+
+	payload, _ := json.Marshal(refundRequest)
+	req := httptest.NewRequest(http.MethodPost, "/payments/payment-id/refund", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
+
+// Settle
+func (suite *PaymentControllerTestSuite) TestSettle_OtherUserForbidden() {
+	fakeGateway, err := gateway.NewFakeGateway("server-key")
+	assert.NoError(suite.T(), err)
+	fakeGatewayController := NewFakeGatewayController(fakeGateway, suite.mockService)
+	suite.router.POST("/payments/fake/:paymentId", func(c *gin.Context) {
+		c.Set("user_id", "someone-else")
+		c.Set("role", "user")
+	}, fakeGatewayController.Settle)
+
+	suite.mockService.On("FindByID", mock.Anything, "some-id", "someone-else", "user", mock.Anything).Return(dto.PaymentResponse{}, exception.ForbiddenError{Message: "payment belongs to another user"}).Run(func(args mock.Arguments) {
+		c := args.Get(4).(*gin.Context)
+		c.Error(exception.ForbiddenError{Message: "payment belongs to another user"}).SetType(gin.ErrorTypePublic)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/payments/fake/some-id", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
 	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
 }
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"bioskuy/api/v1/payment/gateway"
+	"bioskuy/api/v1/payment/service"
+	"bioskuy/exception"
+	"bioskuy/web"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FakeGatewayController stands in for the provider's payment page when the
+// fake gateway is selected: settling a payment here delivers the same signed
+// notification Midtrans would send.
+type FakeGatewayController struct {
+	Gateway *gateway.FakeGateway
+	Service service.PaymentService
+}
+
+func NewFakeGatewayController(gateway *gateway.FakeGateway, service service.PaymentService) *FakeGatewayController {
+	return &FakeGatewayController{Gateway: gateway, Service: service}
+}
+
+func (controller *FakeGatewayController) Settle(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("paymentId")
+	status := c.DefaultQuery("status", "settlement")
+
+	// Only the payment's owner, or an admin, may settle it.
+	_, err := controller.Service.FindByID(ctx, id, c.GetString("user_id"), c.GetString("role"), c)
+	if err != nil {
+		return
+	}
+
+	notification, err := controller.Gateway.Settle(id, status)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	err = controller.Service.Update(ctx, notification, c)
+	if err != nil {
+		return
+	}
+
+	response := web.FormatResponse{
+		ResponseCode: http.StatusOK,
+		Data:         notification,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
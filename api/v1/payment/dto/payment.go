@@ -1,33 +1,40 @@
 package dto
 
+import "time"
+
 type PaymentRequest struct {
-	SeatDetailForBookingID string `json:"seat_detail_for_booking_id" validate:"required"`
+	SeatBookingID string `json:"seat_booking_id" validate:"required"`
 }
 
 type CreatePaymentRequest struct {
-	TotalSeat              int    `json:"total_seat"`
-	TotalPrice             int    `json:"total_price"`
-	SeatDetailForBookingID string `json:"seat_detail_for_booking_id" validate:"required"`
-	UserID                 string `json:"user_id" validate:"required"`
+	TotalSeat     int    `json:"total_seat"`
+	TotalPrice    int    `json:"total_price"`
+	SeatBookingID string `json:"seat_booking_id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`
 }
 
 type CreatePaymentResponse struct {
-	ID                     string `json:"id"`
-	TotalSeat              int    `json:"total_seat"`
-	TotalPrice             int    `json:"total_price"`
-	SeatDetailForBookingID string `json:"seat_detail_for_booking_id" validate:"required"`
-	UserID                 string `json:"user_id" validate:"required"`
-	URL                    string `json:"url"`
+	ID            string `json:"id"`
+	TotalSeat     int    `json:"total_seat"`
+	TotalPrice    int    `json:"total_price"`
+	SeatBookingID string `json:"seat_booking_id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`
+	URL           string `json:"url"`
 }
 
+// PaymentResponse renders show_start and show_end in the cinema's timezone;
+// the *_utc fields carry the same instants in UTC.
 type PaymentResponse struct {
 	ID string `json:"id"`
 
 	UserID string `json:"user_id"`
 
-	ShowtimeID string `json:"showtime_id"`
-	ShowStart  string `json:"show_start"`
-	ShowEnd    string `json:"show_end"`
+	ShowtimeID   string    `json:"showtime_id"`
+	ShowStart    time.Time `json:"show_start"`
+	ShowEnd      time.Time `json:"show_end"`
+	ShowStartUTC time.Time `json:"show_start_utc"`
+	ShowEndUTC   time.Time `json:"show_end_utc"`
+	Timezone     string    `json:"timezone"`
 
 	MovieID          string `json:"movie_id"`
 	MovieTitle       string `json:"movie_title"`
@@ -39,12 +46,6 @@ type PaymentResponse struct {
 	StudioID   string `json:"studio_id" validate:"required"`
 	StudioName string `json:"studio_name"`
 
-	SeatID          string `json:"seat_id"`
-	SeatName        string `json:"seat_name"`
-	SeatIsAvailable string `json:"seat_isAvailabe"`
-
-	SeatDetailForBookingID string `json:"seat_detail_for_booking_id"`
-
 	SeatBookingID     string `json:"seat_booking_id"`
 	SeatBookingStatus string `json:"seat_booking_status"`
 
@@ -52,3 +53,27 @@ type PaymentResponse struct {
 	TotalPrice int    `json:"total_price"`
 	Status     string `json:"status"`
 }
+
+type PaymentNotificationRequest struct {
+	OrderID           string `json:"order_id" validate:"required"`
+	TransactionID     string `json:"transaction_id" validate:"required"`
+	TransactionStatus string `json:"transaction_status" validate:"required"`
+	StatusCode        string `json:"status_code" validate:"required"`
+	GrossAmount       string `json:"gross_amount" validate:"required"`
+	SignatureKey      string `json:"signature_key" validate:"required"`
+	FraudStatus       string `json:"fraud_status"`
+}
+
+type RefundRequest struct {
+	SeatIDs []string `json:"seat_ids" validate:"omitempty,unique,dive,required"`
+	Reason  string   `json:"reason" validate:"required"`
+}
+
+type RefundResponse struct {
+	ID            string   `json:"id"`
+	PaymentID     string   `json:"payment_id"`
+	Amount        int      `json:"amount"`
+	Reason        string   `json:"reason"`
+	SeatIDs       []string `json:"seat_ids"`
+	PaymentStatus string   `json:"payment_status"`
+}
@@ -1,13 +1,16 @@
 package entity
 
+import "time"
+
 type Payment struct {
 	ID string `json:"id"`
 
 	UserID string `json:"user_id"`
 
-	ShowtimeID string `json:"showtime_id"`
-	ShowStart  string `json:"show_start"`
-	ShowEnd    string `json:"show_end"`
+	ShowtimeID string    `json:"showtime_id"`
+	ShowStart  time.Time `json:"show_start"`
+	ShowEnd    time.Time `json:"show_end"`
+	Timezone   string    `json:"timezone"`
 
 	MovieID          string `json:"movie_id"`
 	MovieTitle       string `json:"movie_title"`
@@ -19,16 +22,39 @@ type Payment struct {
 	StudioID   string `json:"studio_id" validate:"required"`
 	StudioName string `json:"studio_name"`
 
-	SeatID          string `json:"seat_id"`
-	SeatName        string `json:"seat_name"`
-	SeatIsAvailable string `json:"seat_isAvailabe"`
-
-	SeatDetailForBookingID string `json:"seat_detail_for_booking_id"`
-
 	SeatBookingID     string `json:"seat_booking_id"`
 	SeatBookingStatus string `json:"seat_booking_status"`
 
 	TotalSeat  int    `json:"total_seat"`
 	TotalPrice int    `json:"total_price"`
 	Status     string `json:"status"`
-}
\ No newline at end of file
+}
+
+type PaymentEvent struct {
+	ID                string `json:"id"`
+	PaymentID         string `json:"payment_id"`
+	TransactionID     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	FraudStatus       string `json:"fraud_status"`
+}
+
+type Refund struct {
+	ID         string   `json:"id"`
+	PaymentID  string   `json:"payment_id"`
+	Amount     int      `json:"amount"`
+	Reason     string   `json:"reason"`
+	RefundedBy string   `json:"refunded_by"`
+	SeatIDs    []string `json:"seat_ids"`
+}
+
+// FailedPayment is an open payment failed because its booking was released.
+// PreviousStatus is the status it had before.
+type FailedPayment struct {
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	SeatBookingID  string `json:"seatbooking_id"`
+	PreviousStatus string `json:"previous_status"`
+	TotalPrice     int    `json:"total_price"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+const (
+	PaymentStatusUnpaid            = "unpaid"
+	PaymentStatusPending           = "pending"
+	PaymentStatusPaid              = "paid"
+	PaymentStatusFailed            = "failed"
+	PaymentStatusRefunded          = "refunded"
+	PaymentStatusPartiallyRefunded = "partially_refunded"
+)
+
+// paymentTransitions lists, for every payment status, the statuses it may move
+// to. Failed and refunded payments are terminal.
+var paymentTransitions = map[string][]string{
+	PaymentStatusUnpaid:            {PaymentStatusPending, PaymentStatusPaid, PaymentStatusFailed},
+	PaymentStatusPending:           {PaymentStatusPaid, PaymentStatusFailed},
+	PaymentStatusPaid:              {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+	PaymentStatusPartiallyRefunded: {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+}
+
+func CanTransition(from, to string) bool {
+	for _, next := range paymentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
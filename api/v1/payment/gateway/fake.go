@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"bioskuy/api/v1/payment/dto"
+	"bioskuy/helper"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// FakeGateway is an in-memory provider for local development and tests. It
+// never touches the network; notifications it builds are signed the same way
+// Midtrans signs them, so they pass through the regular notification endpoint.
+type FakeGateway struct {
+	ServerKey string
+
+	mu      sync.Mutex
+	charges map[string]*fakeCharge
+}
+
+type fakeCharge struct {
+	grossAmount int
+	refunded    int
+	status      string
+}
+
+// NewFakeGateway refuses an empty server key, as Midtrans would, so
+// notifications are never signed with a key anyone can guess.
+func NewFakeGateway(serverKey string) (*FakeGateway, error) {
+	if serverKey == "" {
+		return nil, errors.New("MIDTRANS_SERVER_KEY is required for the fake payment gateway")
+	}
+
+	return &FakeGateway{
+		ServerKey: serverKey,
+		charges:   map[string]*fakeCharge{},
+	}, nil
+}
+
+func (g *FakeGateway) CreateCharge(ctx context.Context, request ChargeRequest) (Charge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.charges[request.OrderID] = &fakeCharge{grossAmount: request.GrossAmount, status: "pending"}
+
+	return Charge{
+		Token:       "fake-" + request.OrderID,
+		RedirectURL: "/api/v1/payments/fake/" + request.OrderID,
+	}, nil
+}
+
+func (g *FakeGateway) Status(ctx context.Context, orderID string) (TransactionStatus, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[orderID]
+	if !ok {
+		return TransactionStatus{}, errors.New("transaction not found")
+	}
+
+	return g.transactionStatus(orderID, charge), nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, request RefundRequest) (Refund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[request.OrderID]
+	if !ok {
+		return Refund{}, errors.New("transaction not found")
+	}
+	if charge.status != "settlement" && charge.status != "partial_refund" {
+		return Refund{}, errors.New("transaction is not refundable")
+	}
+	if request.Amount <= 0 || charge.refunded+request.Amount > charge.grossAmount {
+		return Refund{}, errors.New("refund amount exceeds the remaining balance")
+	}
+
+	charge.refunded += request.Amount
+	charge.status = "partial_refund"
+	if charge.refunded == charge.grossAmount {
+		charge.status = "refund"
+	}
+
+	return Refund{RefundKey: request.RefundKey, Amount: request.Amount, TransactionStatus: charge.status}, nil
+}
+
+func (g *FakeGateway) VerifyNotification(notification dto.PaymentNotificationRequest) bool {
+	return helper.VerifyMidtransSignature(notification.OrderID, notification.StatusCode, notification.GrossAmount, g.ServerKey, notification.SignatureKey)
+}
+
+// Settle moves a charge to transactionStatus (settlement, deny, expire, ...)
+// and returns the signed notification Midtrans would send for it.
+func (g *FakeGateway) Settle(orderID, transactionStatus string) (dto.PaymentNotificationRequest, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[orderID]
+	if !ok {
+		return dto.PaymentNotificationRequest{}, errors.New("transaction not found")
+	}
+	charge.status = transactionStatus
+
+	status := g.transactionStatus(orderID, charge)
+	notification := dto.PaymentNotificationRequest{
+		OrderID:           status.OrderID,
+		TransactionID:     status.TransactionID,
+		TransactionStatus: status.TransactionStatus,
+		StatusCode:        status.StatusCode,
+		GrossAmount:       status.GrossAmount,
+		FraudStatus:       status.FraudStatus,
+	}
+	notification.SignatureKey = helper.MidtransSignature(notification.OrderID, notification.StatusCode, notification.GrossAmount, g.ServerKey)
+
+	return notification, nil
+}
+
+func (g *FakeGateway) transactionStatus(orderID string, charge *fakeCharge) TransactionStatus {
+	statusCode := "200"
+	switch charge.status {
+	case "pending":
+		statusCode = "201"
+	case "deny", "expire", "cancel", "failure":
+		statusCode = "202"
+	}
+
+	return TransactionStatus{
+		OrderID:           orderID,
+		TransactionID:     "fake-trx-" + orderID,
+		TransactionStatus: charge.status,
+		StatusCode:        statusCode,
+		GrossAmount:       strconv.Itoa(charge.grossAmount) + ".00",
+		FraudStatus:       "accept",
+	}
+}
@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"bioskuy/helper"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaymentGateway_SelectsProvider(t *testing.T) {
+	fake, err := NewPaymentGateway(&helper.Config{PaymentGateway: "fake", MIDTRANS_SERVER_KEY: "key"})
+	assert.NoError(t, err)
+	_, isFake := fake.(*FakeGateway)
+	assert.True(t, isFake)
+
+	midtrans, err := NewPaymentGateway(&helper.Config{MIDTRANS_SERVER_KEY: "key"})
+	assert.NoError(t, err)
+	_, isMidtrans := midtrans.(*midtransGateway)
+	assert.True(t, isMidtrans)
+}
+
+func TestNewPaymentGateway_FakeRequiresServerKey(t *testing.T) {
+	_, err := NewPaymentGateway(&helper.Config{PaymentGateway: "fake"})
+	assert.Error(t, err)
+}
+
+func TestFakeGateway_ChargeAndSettle(t *testing.T) {
+	ctx := context.Background()
+	gateway, err := NewFakeGateway("server-key")
+	assert.NoError(t, err)
+
+	charge, err := gateway.CreateCharge(ctx, ChargeRequest{OrderID: "order-1", GrossAmount: 20000})
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/payments/fake/order-1", charge.RedirectURL)
+
+	status, err := gateway.Status(ctx, "order-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", status.TransactionStatus)
+	assert.Equal(t, "20000.00", status.GrossAmount)
+
+	notification, err := gateway.Settle("order-1", "settlement")
+	assert.NoError(t, err)
+	assert.Equal(t, "settlement", notification.TransactionStatus)
+	assert.Equal(t, "200", notification.StatusCode)
+	assert.True(t, gateway.VerifyNotification(notification))
+
+	notification.GrossAmount = "1.00"
+	assert.False(t, gateway.VerifyNotification(notification))
+}
+
+func TestFakeGateway_SettleUnknownOrder(t *testing.T) {
+	gateway, err := NewFakeGateway("server-key")
+	assert.NoError(t, err)
+
+	_, err = gateway.Settle("missing", "settlement")
+	assert.Error(t, err)
+}
+
+func TestFakeGateway_Refund(t *testing.T) {
+	ctx := context.Background()
+	gateway, err := NewFakeGateway("server-key")
+	assert.NoError(t, err)
+
+	_, err = gateway.CreateCharge(ctx, ChargeRequest{OrderID: "order-1", GrossAmount: 20000})
+	assert.NoError(t, err)
+
+	_, err = gateway.Refund(ctx, RefundRequest{OrderID: "order-1", Amount: 10000})
+	assert.EqualError(t, err, "transaction is not refundable")
+
+	_, err = gateway.Settle("order-1", "settlement")
+	assert.NoError(t, err)
+
+	refund, err := gateway.Refund(ctx, RefundRequest{OrderID: "order-1", RefundKey: "r-1", Amount: 10000})
+	assert.NoError(t, err)
+	assert.Equal(t, "partial_refund", refund.TransactionStatus)
+
+	_, err = gateway.Refund(ctx, RefundRequest{OrderID: "order-1", Amount: 20000})
+	assert.EqualError(t, err, "refund amount exceeds the remaining balance")
+
+	refund, err = gateway.Refund(ctx, RefundRequest{OrderID: "order-1", RefundKey: "r-2", Amount: 10000})
+	assert.NoError(t, err)
+	assert.Equal(t, "refund", refund.TransactionStatus)
+}
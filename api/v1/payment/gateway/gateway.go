@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"bioskuy/api/v1/payment/dto"
+	"bioskuy/helper"
+	"context"
+)
+
+// PaymentGateway is the payment provider a payment is charged, queried and
+// refunded through.
+type PaymentGateway interface {
+	CreateCharge(ctx context.Context, request ChargeRequest) (Charge, error)
+	Status(ctx context.Context, orderID string) (TransactionStatus, error)
+	Refund(ctx context.Context, request RefundRequest) (Refund, error)
+	VerifyNotification(notification dto.PaymentNotificationRequest) bool
+}
+
+type ChargeRequest struct {
+	OrderID     string
+	GrossAmount int
+}
+
+type Charge struct {
+	Token       string
+	RedirectURL string
+}
+
+type TransactionStatus struct {
+	OrderID           string
+	TransactionID     string
+	TransactionStatus string
+	StatusCode        string
+	GrossAmount       string
+	FraudStatus       string
+}
+
+type RefundRequest struct {
+	OrderID   string
+	RefundKey string
+	Amount    int
+	Reason    string
+}
+
+type Refund struct {
+	RefundKey         string
+	Amount            int
+	TransactionStatus string
+}
+
+const (
+	ProviderMidtrans = "midtrans"
+	ProviderFake     = "fake"
+)
+
+// NewPaymentGateway returns the provider selected by PAYMENT_GATEWAY,
+// falling back to Midtrans.
+func NewPaymentGateway(config *helper.Config) (PaymentGateway, error) {
+	if config.PaymentGateway == ProviderFake {
+		return NewFakeGateway(config.MIDTRANS_SERVER_KEY)
+	}
+
+	return NewMidtransGateway(config.MIDTRANS_SERVER_KEY, config.MidtransProduction()), nil
+}
@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"bioskuy/api/v1/payment/dto"
+	"bioskuy/helper"
+	"context"
+	"strconv"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+type midtransGateway struct {
+	ServerKey string
+	Snap      snap.Client
+	Core      coreapi.Client
+}
+
+func NewMidtransGateway(serverKey string, production bool) PaymentGateway {
+	env := midtrans.Sandbox
+	if production {
+		env = midtrans.Production
+	}
+
+	gateway := &midtransGateway{ServerKey: serverKey}
+	gateway.Snap.New(serverKey, env)
+	gateway.Core.New(serverKey, env)
+
+	return gateway
+}
+
+func (g *midtransGateway) CreateCharge(ctx context.Context, request ChargeRequest) (Charge, error) {
+	req := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  request.OrderID,
+			GrossAmt: int64(request.GrossAmount),
+		},
+		CreditCard: &snap.CreditCardDetails{
+			Secure: true,
+		},
+	}
+
+	resp, midtransErr := g.Snap.CreateTransaction(req)
+	if midtransErr != nil {
+		return Charge{}, midtransErr
+	}
+
+	return Charge{Token: resp.Token, RedirectURL: resp.RedirectURL}, nil
+}
+
+func (g *midtransGateway) Status(ctx context.Context, orderID string) (TransactionStatus, error) {
+	resp, midtransErr := g.Core.CheckTransaction(orderID)
+	if midtransErr != nil {
+		return TransactionStatus{}, midtransErr
+	}
+
+	return TransactionStatus{
+		OrderID:           resp.OrderID,
+		TransactionID:     resp.TransactionID,
+		TransactionStatus: resp.TransactionStatus,
+		StatusCode:        resp.StatusCode,
+		GrossAmount:       resp.GrossAmount,
+		FraudStatus:       resp.FraudStatus,
+	}, nil
+}
+
+func (g *midtransGateway) Refund(ctx context.Context, request RefundRequest) (Refund, error) {
+	req := &coreapi.RefundReq{
+		RefundKey: request.RefundKey,
+		Amount:    int64(request.Amount),
+		Reason:    request.Reason,
+	}
+
+	resp, midtransErr := g.Core.RefundTransaction(request.OrderID, req)
+	if midtransErr != nil {
+		return Refund{}, midtransErr
+	}
+
+	amount, err := strconv.ParseFloat(resp.RefundAmount, 64)
+	if err != nil {
+		amount = float64(request.Amount)
+	}
+
+	return Refund{
+		RefundKey:         resp.RefundKey,
+		Amount:            int(amount),
+		TransactionStatus: resp.TransactionStatus,
+	}, nil
+}
+
+func (g *midtransGateway) VerifyNotification(notification dto.PaymentNotificationRequest) bool {
+	return helper.VerifyMidtransSignature(notification.OrderID, notification.StatusCode, notification.GrossAmount, g.ServerKey, notification.SignatureKey)
+}
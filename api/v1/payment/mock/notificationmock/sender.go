@@ -0,0 +1,62 @@
+package notificationmock
+
+import (
+	"bioskuy/api/v1/payment/dto"
+	"bioskuy/helper"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+)
+
+// FakeNotificationSender plays the role of Midtrans in tests: it builds
+// notifications signed with ServerKey and posts them to Handler.
+type FakeNotificationSender struct {
+	ServerKey string
+	Handler   http.Handler
+	Path      string
+}
+
+func NewFakeNotificationSender(serverKey string, handler http.Handler) *FakeNotificationSender {
+	return &FakeNotificationSender{
+		ServerKey: serverKey,
+		Handler:   handler,
+		Path:      "/api/v1/payments/notification",
+	}
+}
+
+// Notification returns a correctly signed notification for orderID.
+func (s *FakeNotificationSender) Notification(orderID, transactionStatus string, grossAmount int) dto.PaymentNotificationRequest {
+	statusCode := "200"
+	if transactionStatus == "pending" {
+		statusCode = "201"
+	} else if transactionStatus == "deny" || transactionStatus == "expire" {
+		statusCode = "202"
+	}
+
+	notification := dto.PaymentNotificationRequest{
+		OrderID:           orderID,
+		TransactionID:     "trx-" + orderID,
+		TransactionStatus: transactionStatus,
+		StatusCode:        statusCode,
+		GrossAmount:       strconv.Itoa(grossAmount) + ".00",
+		FraudStatus:       "accept",
+	}
+	notification.SignatureKey = helper.MidtransSignature(notification.OrderID, notification.StatusCode, notification.GrossAmount, s.ServerKey)
+
+	return notification
+}
+
+// Send posts notification as JSON and returns the recorded response.
+func (s *FakeNotificationSender) Send(notification dto.PaymentNotificationRequest) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(notification)
+
+	req := httptest.NewRequest(http.MethodPost, s.Path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.Handler.ServeHTTP(w, req)
+
+	return w
+}
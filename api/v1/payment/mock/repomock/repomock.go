@@ -2,10 +2,12 @@ package repomock
 
 import (
 	"bioskuy/api/v1/payment/entity"
-	eS "bioskuy/api/v1/seat/entity"
 	eSB "bioskuy/api/v1/seatbooking/entity"
+	eTicket "bioskuy/api/v1/ticket/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
@@ -25,53 +27,29 @@ func (m *MockPaymentRepository) FindByID(ctx context.Context, tx *sql.Tx, id str
 	return args.Get(0).(entity.Payment), args.Error(1)
 }
 
-func (m *MockPaymentRepository) Update(ctx context.Context, tx *sql.Tx, payment entity.Payment, c *gin.Context) (entity.Payment, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).(entity.Payment), args.Error(1)
-}
-
-func (m *MockPaymentRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Payment, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]entity.Payment), args.Error(1)
-}
-
-func (m *MockPaymentRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
-	args := m.Called(ctx, tx, id, c)
-	return args.Error(0)
-}
-
-type MockSeatRepository struct {
-	mock.Mock
-}
-
-func (m *MockSeatRepository) Save(ctx context.Context, tx *sql.Tx, seat eS.Seat, c *gin.Context) (eS.Seat, error) {
-	args := m.Called(ctx, tx, seat, c)
-	return args.Get(0).(eS.Seat), args.Error(1)
-}
-
-func (m *MockSeatRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (eS.Seat, error) {
+func (m *MockPaymentRepository) FindByIDForUpdate(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Payment, error) {
 	args := m.Called(ctx, tx, id, c)
-	return args.Get(0).(eS.Seat), args.Error(1)
+	return args.Get(0).(entity.Payment), args.Error(1)
 }
 
-func (m *MockSeatRepository) FindByIDWithNotAvailable(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (eS.Seat, error) {
-	args := m.Called(ctx, tx, id, c)
-	return args.Get(0).(eS.Seat), args.Error(1)
+func (m *MockPaymentRepository) Update(ctx context.Context, tx *sql.Tx, payment entity.Payment, c *gin.Context) (entity.Payment, error) {
+	args := m.Called(ctx, tx, payment, c)
+	return args.Get(0).(entity.Payment), args.Error(1)
 }
 
-func (m *MockSeatRepository) Update(ctx context.Context, tx *sql.Tx, seat eS.Seat, c *gin.Context) (eS.Seat, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).(eS.Seat), args.Error(1)
+func (m *MockPaymentRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Payment, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entity.Payment), args.Int(1), args.Error(2)
 }
 
-func (m *MockSeatRepository) FindAll(ctx context.Context, id string, tx *sql.Tx, c *gin.Context) ([]eS.Seat, error) {
-	args := m.Called(ctx, id, tx, c)
-	return args.Get(0).([]eS.Seat), args.Error(1)
+func (m *MockPaymentRepository) SaveEvent(ctx context.Context, tx *sql.Tx, event entity.PaymentEvent, c *gin.Context) (bool, error) {
+	args := m.Called(ctx, tx, event, c)
+	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockSeatRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
-	args := m.Called(ctx, tx, id, c)
-	return args.Error(0)
+func (m *MockPaymentRepository) SaveRefund(ctx context.Context, tx *sql.Tx, refund entity.Refund, c *gin.Context) (entity.Refund, error) {
+	args := m.Called(ctx, tx, refund, c)
+	return args.Get(0).(entity.Refund), args.Error(1)
 }
 
 type MockSeatBookingRepository struct {
@@ -93,13 +71,18 @@ func (m *MockSeatBookingRepository) FindAllPendingByUserID(ctx context.Context,
 	return args.Get(0).([]eSB.SeatBooking), args.Error(1)
 }
 
-func (m *MockSeatBookingRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]eSB.SeatBooking, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]eSB.SeatBooking), args.Error(1)
+func (m *MockSeatBookingRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]eSB.SeatBooking, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]eSB.SeatBooking), args.Int(1), args.Error(2)
 }
 
-func (m *MockSeatBookingRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+func (m *MockSeatBookingRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) ([]entity.FailedPayment, error) {
 	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).([]entity.FailedPayment), args.Error(1)
+}
+
+func (m *MockSeatBookingRepository) DeleteSeats(ctx context.Context, tx *sql.Tx, id string, seatIDs []string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, seatIDs, c)
 	return args.Error(0)
 }
 
@@ -107,3 +90,42 @@ func (m *MockSeatBookingRepository) Update(ctx context.Context, tx *sql.Tx, paym
 	args := m.Called(ctx, tx, payment, c)
 	return args.Get(0).(eSB.SeatBooking), args.Error(1)
 }
+
+func (m *MockSeatBookingRepository) FindExpiredPending(ctx context.Context, tx *sql.Tx, now time.Time, c *gin.Context) ([]eSB.SeatBooking, error) {
+	args := m.Called(ctx, tx, now, c)
+	return args.Get(0).([]eSB.SeatBooking), args.Error(1)
+}
+
+type MockTicketRepository struct {
+	mock.Mock
+}
+
+func (m *MockTicketRepository) Issue(ctx context.Context, tx *sql.Tx, paymentID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, paymentID, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) Void(ctx context.Context, tx *sql.Tx, paymentID string, seatIDs []string, c *gin.Context) error {
+	args := m.Called(ctx, tx, paymentID, seatIDs, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) Move(ctx context.Context, tx *sql.Tx, showtimeID string, fromSeatID string, toSeatID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, showtimeID, fromSeatID, toSeatID, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) VoidByShowtime(ctx context.Context, tx *sql.Tx, showtimeID string, seatIDs []string, c *gin.Context) error {
+	args := m.Called(ctx, tx, showtimeID, seatIDs, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (eTicket.Ticket, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).(eTicket.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]eTicket.Ticket, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]eTicket.Ticket), args.Int(1), args.Error(2)
+}
@@ -2,6 +2,8 @@ package servicemock
 
 import (
 	"bioskuy/api/v1/payment/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -18,17 +20,22 @@ func (m *MockPaymentService) Create(ctx context.Context, request dto.PaymentRequ
 	return args.Get(0).(dto.CreatePaymentResponse), args.Error(1)
 }
 
-func (m *MockPaymentService) Update(ctx context.Context, notificationPayload map[string]interface{}, c *gin.Context) {
-	m.Called(ctx, notificationPayload, c)
-	return
+func (m *MockPaymentService) Update(ctx context.Context, notification dto.PaymentNotificationRequest, c *gin.Context) error {
+	args := m.Called(ctx, notification, c)
+	return args.Error(0)
 }
 
-func (m *MockPaymentService) FindByID(ctx context.Context, id string, c *gin.Context) (dto.PaymentResponse, error) {
-	args := m.Called(ctx, id, c)
+func (m *MockPaymentService) FindByID(ctx context.Context, id string, userID string, role string, c *gin.Context) (dto.PaymentResponse, error) {
+	args := m.Called(ctx, id, userID, role, c)
 	return args.Get(0).(dto.PaymentResponse), args.Error(1)
 }
 
-func (m *MockPaymentService) FindAll(ctx context.Context, c *gin.Context) ([]dto.PaymentResponse, error) {
-	args := m.Called(ctx, c)
-	return args.Get(0).([]dto.PaymentResponse), args.Error(1)
+func (m *MockPaymentService) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.PaymentResponse, web.Paging, error) {
+	args := m.Called(ctx, query, c)
+	return args.Get(0).([]dto.PaymentResponse), args.Get(1).(web.Paging), args.Error(2)
+}
+
+func (m *MockPaymentService) Refund(ctx context.Context, id string, request dto.RefundRequest, userID string, role string, c *gin.Context) (dto.RefundResponse, error) {
+	args := m.Called(ctx, id, request, userID, role, c)
+	return args.Get(0).(dto.RefundResponse), args.Error(1)
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bioskuy/api/v1/payment/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 
@@ -11,7 +12,9 @@ import (
 type PaymentRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, payment entity.Payment, c *gin.Context) (entity.Payment, error)
 	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Payment, error)
+	FindByIDForUpdate(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Payment, error)
 	Update(ctx context.Context, tx *sql.Tx, payment entity.Payment, c *gin.Context) (entity.Payment, error)
-	FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Payment, error)
-	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Payment, int, error)
+	SaveEvent(ctx context.Context, tx *sql.Tx, event entity.PaymentEvent, c *gin.Context) (bool, error)
+	SaveRefund(ctx context.Context, tx *sql.Tx, refund entity.Refund, c *gin.Context) (entity.Refund, error)
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"bioskuy/api/v1/payment/entity"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"errors"
@@ -19,9 +20,9 @@ import (
 
     func (r *paymentRepository) Save(ctx context.Context, tx *sql.Tx, payment entity.Payment, c *gin.Context) (entity.Payment, error){
 
-        query := "INSERT INTO payments (user_id, seatdetailforbooking_id, total_seat, total_price) VALUES ($1, $2, $3, $4) RETURNING id"
+        query := "INSERT INTO payments (user_id, seatbooking_id, total_seat, total_price) VALUES ($1, $2, $3, $4) RETURNING id"
 
-        err := tx.QueryRowContext(ctx, query, payment.UserID, payment.SeatDetailForBookingID, payment.TotalSeat, payment.TotalPrice).Scan(&payment.ID)
+        err := tx.QueryRowContext(ctx, query, payment.UserID, payment.SeatBookingID, payment.TotalSeat, payment.TotalPrice).Scan(&payment.ID)
         if err != nil {
             c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
             return payment, err
@@ -31,23 +32,33 @@ import (
     }
 
     func (r *paymentRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Payment, error) {
+        return r.findByID(ctx, tx, id, "", c)
+    }
+
+    // FindByIDForUpdate is FindByID that also locks the payment row, then its
+    // booking's row, until tx ends. Concurrent refunds and notifications apply
+    // one at a time, and the hold sweeper, which locks the booking, skips a
+    // booking whose payment is being settled or waits for it to commit.
+    func (r *paymentRepository) FindByIDForUpdate(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Payment, error) {
+        return r.findByID(ctx, tx, id, " FOR UPDATE OF p, sb", c)
+    }
+
+    func (r *paymentRepository) findByID(ctx context.Context, tx *sql.Tx, id string, lock string, c *gin.Context) (entity.Payment, error) {
         query := `
         SELECT 
-            p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+            p.id, p.user_id, p.total_seat, p.total_price, p.status,
             sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-            s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
             sh.id AS showtime_id, sh.show_start, sh.show_end,
             m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
             m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-            st.id AS studio_id, st.name AS studio_name
+            st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone
         FROM payments p
-        JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-        JOIN seats s ON sdfb.seat_id = s.id
-        JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+        JOIN seat_bookings sb ON p.seatbooking_id = sb.id
         JOIN showtimes sh ON sb.showtime_id = sh.id
         JOIN movies m ON sh.movie_id = m.id
         JOIN studios st ON sh.studio_id = st.id
-        WHERE p.id = $1`
+        JOIN cinemas ci ON st.cinema_id = ci.id
+        WHERE p.id = $1` + lock
         
         paymentResponse := entity.Payment{}
         rows, err := tx.QueryContext(ctx, query, id)
@@ -59,12 +70,11 @@ import (
 
         if rows.Next() {
             err := rows.Scan(
-                &paymentResponse.ID, &paymentResponse.UserID, &paymentResponse.SeatDetailForBookingID, &paymentResponse.TotalSeat, &paymentResponse.TotalPrice, &paymentResponse.Status,
+                &paymentResponse.ID, &paymentResponse.UserID, &paymentResponse.TotalSeat, &paymentResponse.TotalPrice, &paymentResponse.Status,
                 &paymentResponse.SeatBookingID, &paymentResponse.SeatBookingStatus,
-                &paymentResponse.SeatID, &paymentResponse.SeatName, &paymentResponse.SeatIsAvailable,
                 &paymentResponse.ShowtimeID, &paymentResponse.ShowStart, &paymentResponse.ShowEnd,
                 &paymentResponse.MovieID, &paymentResponse.MovieTitle, &paymentResponse.MovieDescription, &paymentResponse.MoviePrice, &paymentResponse.MovieDuration, &paymentResponse.MovieStatus,
-                &paymentResponse.StudioID, &paymentResponse.StudioName,
+                &paymentResponse.StudioID, &paymentResponse.StudioName, &paymentResponse.Timezone,
             )
             if err != nil {
                 c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -77,28 +87,68 @@ import (
         }
     }
 
-    func (r *paymentRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Payment, error) {
+    // paymentListSpec is what the payment listing can be sorted, filtered and
+    // searched by.
+    var paymentListSpec = helper.ListSpec{
+        Sorts: map[string]string{
+            "show_start":  "sh.show_start",
+            "total_price": "p.total_price",
+            "status":      "p.status",
+            "movie_title": "m.title",
+        },
+        DefaultSort: "show_start",
+        Key:         "p.id",
+        Filters: map[string]string{
+            "status":      "p.status::text",
+            "user_id":     "p.user_id::text",
+            "showtime_id": "sh.id::text",
+            "movie_id":    "m.id::text",
+            "cinema_id":   "st.cinema_id::text",
+        },
+        Scopes: map[string]map[string]string{
+            "when": {
+                "upcoming": "sh.show_end > NOW() AT TIME ZONE 'UTC'",
+                "past":     "sh.show_end <= NOW() AT TIME ZONE 'UTC'",
+            },
+        },
+        Search: []string{"m.title", "st.name"},
+    }
+
+    func (r *paymentRepository) FindAll(ctx context.Context, tx *sql.Tx, listQuery helper.ListQuery, c *gin.Context) ([]entity.Payment, int, error) {
+        clauses, err := listQuery.Clauses(paymentListSpec)
+        if err != nil {
+            c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+            return nil, 0, err
+        }
+
+        joins := `
+        FROM payments p
+        JOIN seat_bookings sb ON p.seatbooking_id = sb.id
+        JOIN showtimes sh ON sb.showtime_id = sh.id
+        JOIN movies m ON sh.movie_id = m.id
+        JOIN studios st ON sh.studio_id = st.id
+        JOIN cinemas ci ON st.cinema_id = ci.id`
+
+        total := 0
+        if err := tx.QueryRowContext(ctx, `SELECT COUNT(*)`+joins+` `+clauses.Where, clauses.Args...).Scan(&total); err != nil {
+            c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+            return nil, 0, err
+        }
+
         query := `
         SELECT 
-            p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+            p.id, p.user_id, p.total_seat, p.total_price, p.status,
             sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-            s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
             sh.id AS showtime_id, sh.show_start, sh.show_end,
             m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
             m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-            st.id AS studio_id, st.name AS studio_name
-        FROM payments p
-        JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-        JOIN seats s ON sdfb.seat_id = s.id
-        JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
-        JOIN showtimes sh ON sb.showtime_id = sh.id
-        JOIN movies m ON sh.movie_id = m.id
-        JOIN studios st ON sh.studio_id = st.id`
+            st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone` + joins + `
+        ` + clauses.Where + ` ` + clauses.Page
         
-        rows, err := tx.QueryContext(ctx, query)
+        rows, err := tx.QueryContext(ctx, query, clauses.AllArgs()...)
         if err != nil {
             c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-            return nil, err
+            return nil, 0, err
         }
         defer rows.Close()
 
@@ -107,16 +157,15 @@ import (
         for rows.Next() {
             var paymentResponse entity.Payment
             err := rows.Scan(
-                &paymentResponse.ID, &paymentResponse.UserID, &paymentResponse.SeatDetailForBookingID, &paymentResponse.TotalSeat, &paymentResponse.TotalPrice, &paymentResponse.Status,
+                &paymentResponse.ID, &paymentResponse.UserID, &paymentResponse.TotalSeat, &paymentResponse.TotalPrice, &paymentResponse.Status,
                 &paymentResponse.SeatBookingID, &paymentResponse.SeatBookingStatus,
-                &paymentResponse.SeatID, &paymentResponse.SeatName, &paymentResponse.SeatIsAvailable,
                 &paymentResponse.ShowtimeID, &paymentResponse.ShowStart, &paymentResponse.ShowEnd,
                 &paymentResponse.MovieID, &paymentResponse.MovieTitle, &paymentResponse.MovieDescription, &paymentResponse.MoviePrice, &paymentResponse.MovieDuration, &paymentResponse.MovieStatus,
-                &paymentResponse.StudioID, &paymentResponse.StudioName,
+                &paymentResponse.StudioID, &paymentResponse.StudioName, &paymentResponse.Timezone,
             )
             if err != nil {
                 c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-                return nil, err
+                return nil, 0, err
             }
 
             payments = append(payments, paymentResponse)
@@ -124,10 +173,10 @@ import (
 
         if err = rows.Err(); err != nil {
             c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-            return nil, err
+            return nil, 0, err
         }
 
-        return payments, nil
+        return payments, total, nil
     }
 
     func (r *paymentRepository) Update(ctx context.Context, tx *sql.Tx, payment entity.Payment, c *gin.Context) (entity.Payment, error){
@@ -144,14 +193,48 @@ import (
         return payment, nil
     }
 
-    func (r *paymentRepository) Delete(ctx context.Context, tx *sql.Tx, paymentId string, c *gin.Context) error {
+    // SaveEvent records a gateway notification. It reports false when the same
+    // transaction status was already recorded, so retried notifications are no-ops.
+    func (r *paymentRepository) SaveEvent(ctx context.Context, tx *sql.Tx, event entity.PaymentEvent, c *gin.Context) (bool, error) {
 
-        deleteSeatDetailQuery := `DELETE FROM payments WHERE id = $1 `
-        _, err := tx.ExecContext(ctx, deleteSeatDetailQuery, paymentId)
+        query := `
+        INSERT INTO payment_events (payment_id, transaction_id, transaction_status, status_code, gross_amount, fraud_status)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (transaction_id, transaction_status) DO NOTHING
+        RETURNING id`
+
+        err := tx.QueryRowContext(ctx, query, event.PaymentID, event.TransactionID, event.TransactionStatus, event.StatusCode, event.GrossAmount, event.FraudStatus).Scan(&event.ID)
+        if err == sql.ErrNoRows {
+            return false, nil
+        }
         if err != nil {
             c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-            return err
+            return false, err
         }
-    
-        return nil
+
+        return true, nil
+    }
+
+    // SaveRefund records a refund. An empty RefundedBy marks one the system
+    // made on its own.
+    func (r *paymentRepository) SaveRefund(ctx context.Context, tx *sql.Tx, refund entity.Refund, c *gin.Context) (entity.Refund, error) {
+
+        query := "INSERT INTO payment_refunds (payment_id, amount, reason, refunded_by) VALUES ($1, $2, $3, NULLIF($4, '')::uuid) RETURNING id"
+
+        err := tx.QueryRowContext(ctx, query, refund.PaymentID, refund.Amount, refund.Reason, refund.RefundedBy).Scan(&refund.ID)
+        if err != nil {
+            c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+            return refund, err
+        }
+
+        seatQuery := "INSERT INTO payment_refund_seats (refund_id, seat_id) VALUES ($1, $2)"
+        for _, seatID := range refund.SeatIDs {
+            _, err := tx.ExecContext(ctx, seatQuery, refund.ID, seatID)
+            if err != nil {
+                c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+                return refund, err
+            }
+        }
+
+        return refund, nil
     }
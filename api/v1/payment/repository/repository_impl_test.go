@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bioskuy/api/v1/payment/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"errors"
@@ -48,7 +49,6 @@ func TestPaymentRepositoryTestSuite(t *testing.T) {
 func (suite *PaymentRepositoryTestSuite) TestSave_Success() {
 	payment := entity.Payment{
 		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-detail-id",
 		TotalSeat:              5,
 		TotalPrice:             100,
 	}
@@ -58,7 +58,7 @@ func (suite *PaymentRepositoryTestSuite) TestSave_Success() {
 	assert.NoError(suite.T(), err)
 
 	suite.mockSql.ExpectQuery("INSERT INTO payments").
-		WithArgs(payment.UserID, payment.SeatDetailForBookingID, payment.TotalSeat, payment.TotalPrice).
+		WithArgs(payment.UserID, payment.SeatBookingID, payment.TotalSeat, payment.TotalPrice).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("payment-id"))
 
 	savedPayment, err := suite.repo.Save(suite.ctx, tx, payment, suite.ginContext)
@@ -69,38 +69,33 @@ func (suite *PaymentRepositoryTestSuite) TestSave_Success() {
 func (suite *PaymentRepositoryTestSuite) TestFindAll_Success() {
 	query := regexp.QuoteMeta(`
 		SELECT 
-			p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+			p.id, p.user_id, p.total_seat, p.total_price, p.status,
 			sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-			s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
 			sh.id AS showtime_id, sh.show_start, sh.show_end,
 			m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
 			m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-			st.id AS studio_id, st.name AS studio_name
+			st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone
 		FROM payments p
-		JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-		JOIN seats s ON sdfb.seat_id = s.id
-		JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+		JOIN seat_bookings sb ON p.seatbooking_id = sb.id
 		JOIN showtimes sh ON sb.showtime_id = sh.id
 		JOIN movies m ON sh.movie_id = m.id
 		JOIN studios st ON sh.studio_id = st.id
+		JOIN cinemas ci ON st.cinema_id = ci.id
+		WHERE p.status::text = $1 ORDER BY p.total_price DESC, p.id LIMIT $2 OFFSET $3
 	`)
 	now := time.Now()
 	payments := []entity.Payment{
 		{
 			ID:                     "1",
 			UserID:                 "user1",
-			SeatDetailForBookingID: "seatbooking1",
 			TotalSeat:              2,
 			TotalPrice:             200,
 			Status:                 "paid",
 			SeatBookingID:          "seatbooking1",
 			SeatBookingStatus:      "confirmed",
-			SeatID:                 "seat1",
-			SeatName:               "A1",
-			SeatIsAvailable:        "isAvailable",
 			ShowtimeID:             "showtime1",
-			ShowStart:              now.Format(time.RFC3339),
-			ShowEnd:                now.Add(time.Hour).Format(time.RFC3339),
+			ShowStart:              now,
+			ShowEnd:                now.Add(time.Hour),
 			MovieID:                "movie1",
 			MovieTitle:             "Movie 1",
 			MovieDescription:       "Description 1",
@@ -109,22 +104,19 @@ func (suite *PaymentRepositoryTestSuite) TestFindAll_Success() {
 			MovieStatus:            "active",
 			StudioID:               "studio1",
 			StudioName:             "Studio 1",
+			Timezone:               "Asia/Jakarta",
 		},
 		{
 			ID:                     "2",
 			UserID:                 "user2",
-			SeatDetailForBookingID: "seatbooking2",
 			TotalSeat:              3,
 			TotalPrice:             300,
 			Status:                 "pending",
 			SeatBookingID:          "seatbooking2",
 			SeatBookingStatus:      "pending",
-			SeatID:                 "seat2",
-			SeatName:               "B1",
-			SeatIsAvailable:        "isAvailable",
 			ShowtimeID:             "showtime2",
-			ShowStart:              now.Format(time.RFC3339),
-			ShowEnd:                now.Add(time.Hour).Format(time.RFC3339),
+			ShowStart:              now,
+			ShowEnd:                now.Add(time.Hour),
 			MovieID:                "movie2",
 			MovieTitle:             "Movie 2",
 			MovieDescription:       "Description 2",
@@ -133,35 +125,41 @@ func (suite *PaymentRepositoryTestSuite) TestFindAll_Success() {
 			MovieStatus:            "inactive",
 			StudioID:               "studio2",
 			StudioName:             "Studio 2",
+			Timezone:               "Asia/Jakarta",
 		},
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "seatdetailforbooking_id", "total_seat", "total_price", "status",
-		"seat_booking_id", "seat_booking_status", "seat_id", "seat_name", "seat_isAvailable",
+		"id", "user_id", "total_seat", "total_price", "status",
+		"seat_booking_id", "seat_booking_status",
 		"showtime_id", "show_start", "show_end", "movie_id", "movie_title", "movie_description",
-		"movie_price", "movie_duration", "movie_status", "studio_id", "studio_name",
+		"movie_price", "movie_duration", "movie_status", "studio_id", "studio_name", "cinema_timezone",
 	}).AddRow(
-		payments[0].ID, payments[0].UserID, payments[0].SeatDetailForBookingID, payments[0].TotalSeat, payments[0].TotalPrice, payments[0].Status,
-		payments[0].SeatBookingID, payments[0].SeatBookingStatus, payments[0].SeatID, payments[0].SeatName, payments[0].SeatIsAvailable,
+		payments[0].ID, payments[0].UserID, payments[0].TotalSeat, payments[0].TotalPrice, payments[0].Status,
+		payments[0].SeatBookingID, payments[0].SeatBookingStatus,
 		payments[0].ShowtimeID, payments[0].ShowStart, payments[0].ShowEnd, payments[0].MovieID, payments[0].MovieTitle, payments[0].MovieDescription,
-		payments[0].MoviePrice, payments[0].MovieDuration, payments[0].MovieStatus, payments[0].StudioID, payments[0].StudioName,
+		payments[0].MoviePrice, payments[0].MovieDuration, payments[0].MovieStatus, payments[0].StudioID, payments[0].StudioName, payments[0].Timezone,
 	).AddRow(
-		payments[1].ID, payments[1].UserID, payments[1].SeatDetailForBookingID, payments[1].TotalSeat, payments[1].TotalPrice, payments[1].Status,
-		payments[1].SeatBookingID, payments[1].SeatBookingStatus, payments[1].SeatID, payments[1].SeatName, payments[1].SeatIsAvailable,
+		payments[1].ID, payments[1].UserID, payments[1].TotalSeat, payments[1].TotalPrice, payments[1].Status,
+		payments[1].SeatBookingID, payments[1].SeatBookingStatus,
 		payments[1].ShowtimeID, payments[1].ShowStart, payments[1].ShowEnd, payments[1].MovieID, payments[1].MovieTitle, payments[1].MovieDescription,
-		payments[1].MoviePrice, payments[1].MovieDuration, payments[1].MovieStatus, payments[1].StudioID, payments[1].StudioName,
+		payments[1].MoviePrice, payments[1].MovieDuration, payments[1].MovieStatus, payments[1].StudioID, payments[1].StudioName, payments[1].Timezone,
 	)
 
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(query).WillReturnRows(rows)
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`)).
+		WithArgs("paid").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	suite.mockSql.ExpectQuery(query).WithArgs("paid", 10, 10).WillReturnRows(rows)
 
 	tx, err := suite.mockDb.Begin()
 	suite.NoError(err)
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	result, err := suite.repo.FindAll(context.Background(), tx, ginContext)
+	listQuery := helper.ListQuery{Page: 2, Size: 10, Sort: "total_price", Desc: true}.With("status", "paid")
+	result, total, err := suite.repo.FindAll(context.Background(), tx, listQuery, ginContext)
 	suite.NoError(err)
+	suite.Equal(12, total)
 	suite.Len(result, 2)
 	suite.Equal(payments[0].ID, result[0].ID)
 	suite.Equal(payments[1].UserID, result[1].UserID)
@@ -177,30 +175,30 @@ func (suite *PaymentRepositoryTestSuite) TestFindAll_Success() {
 func (suite *PaymentRepositoryTestSuite) TestFindAll_Error() {
 	query := regexp.QuoteMeta(`
 		SELECT 
-			p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+			p.id, p.user_id, p.total_seat, p.total_price, p.status,
 			sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-			s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
 			sh.id AS showtime_id, sh.show_start, sh.show_end,
 			m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
 			m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-			st.id AS studio_id, st.name AS studio_name
+			st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone
 		FROM payments p
-		JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-		JOIN seats s ON sdfb.seat_id = s.id
-		JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+		JOIN seat_bookings sb ON p.seatbooking_id = sb.id
 		JOIN showtimes sh ON sb.showtime_id = sh.id
 		JOIN movies m ON sh.movie_id = m.id
 		JOIN studios st ON sh.studio_id = st.id
+		JOIN cinemas ci ON st.cinema_id = ci.id
 	`)
 
 	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	suite.mockSql.ExpectQuery(query).WillReturnError(errors.New("query error"))
 
 	tx, err := suite.mockDb.Begin()
 	suite.NoError(err)
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	_, err = suite.repo.FindAll(context.Background(), tx, ginContext)
+	_, _, err = suite.repo.FindAll(context.Background(), tx, helper.ListQuery{Page: 1, Size: 10}, ginContext)
 	suite.Error(err)
 	suite.EqualError(err, "query error")
 
@@ -232,26 +230,9 @@ func (suite *PaymentRepositoryTestSuite) TestUpdate_Success() {
 	assert.Equal(suite.T(), "new-status", updatedPayment.Status)
 }
 
-func (suite *PaymentRepositoryTestSuite) TestDelete_Success() {
-	id := "payment-id"
-
-	suite.mockSql.ExpectBegin()
-	tx, err := suite.mockDb.Begin()
-	assert.NoError(suite.T(), err)
-
-	query := `DELETE FROM payments WHERE id = $1`
-
-	suite.mockSql.ExpectExec(query).WithArgs(id).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-
-	err = suite.repo.Delete(suite.ctx, tx, id, suite.ginContext)
-	assert.NoError(suite.T(), err)
-}
-
 func (suite *PaymentRepositoryTestSuite) TestSave_InsertError() {
 	payment := entity.Payment{
 		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-detail-id",
 		TotalSeat:              5,
 		TotalPrice:             100,
 	}
@@ -261,7 +242,7 @@ func (suite *PaymentRepositoryTestSuite) TestSave_InsertError() {
 	assert.NoError(suite.T(), err)
 
 	suite.mockSql.ExpectQuery("INSERT INTO payments").
-		WithArgs(payment.UserID, payment.SeatDetailForBookingID, payment.TotalSeat, payment.TotalPrice).
+		WithArgs(payment.UserID, payment.SeatBookingID, payment.TotalSeat, payment.TotalPrice).
 		WillReturnError(errors.New("insert error"))
 
 	savedPayment, err := suite.repo.Save(suite.ctx, tx, payment, suite.ginContext)
@@ -275,24 +256,24 @@ func (suite *PaymentRepositoryTestSuite) TestFindAll_QueryError() {
 	tx, err := suite.mockDb.Begin()
 	assert.NoError(suite.T(), err)
 
-	query := `SELECT p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+	query := `SELECT p.id, p.user_id, p.total_seat, p.total_price, p.status,
                      sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-                     s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
                      sh.id AS showtime_id, sh.show_start, sh.show_end,
                      m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
                      m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-                     st.id AS studio_id, st.name AS studio_name
+                     st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone
               FROM payments p
-              JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-              JOIN seats s ON sdfb.seat_id = s.id
-              JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+              JOIN seat_bookings sb ON p.seatbooking_id = sb.id
               JOIN showtimes sh ON sb.showtime_id = sh.id
               JOIN movies m ON sh.movie_id = m.id
-              JOIN studios st ON sh.studio_id = st.id`
+              JOIN studios st ON sh.studio_id = st.id
+              JOIN cinemas ci ON st.cinema_id = ci.id`
 
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	suite.mockSql.ExpectQuery(query).WillReturnError(errors.New("query error"))
 
-	payments, err := suite.repo.FindAll(suite.ctx, tx, suite.ginContext)
+	payments, _, err := suite.repo.FindAll(suite.ctx, tx, helper.ListQuery{Page: 1, Size: 10}, suite.ginContext)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "query error", err.Error())
 	assert.Nil(suite.T(), payments)
@@ -344,28 +325,9 @@ func (suite *PaymentRepositoryTestSuite) TestUpdate_Error() {
 	assert.Equal(suite.T(), "", updatedPayment.Status)
 }
 
-func (suite *PaymentRepositoryTestSuite) TestDelete_Error() {
-	paymentId := "payment-id"
-
-	suite.mockSql.ExpectBegin()
-	tx, err := suite.mockDb.Begin()
-	assert.NoError(suite.T(), err)
-
-	query := `DELETE FROM payments WHERE id = $1`
-
-	suite.mockSql.ExpectExec(query).
-		WithArgs(paymentId).
-		WillReturnError(errors.New("delete error"))
-
-	err = suite.repo.Delete(suite.ctx, tx, paymentId, suite.ginContext)
-	assert.Error(suite.T(), err)
-	assert.Equal(suite.T(), "delete error", err.Error())
-}
-
 func (suite *PaymentRepositoryTestSuite) TestSave_NullOrEmptyValues() {
 	payment := entity.Payment{
 		UserID:                 "",
-		SeatDetailForBookingID: "",
 		TotalSeat:              0,
 		TotalPrice:             0,
 	}
@@ -375,7 +337,7 @@ func (suite *PaymentRepositoryTestSuite) TestSave_NullOrEmptyValues() {
 	assert.NoError(suite.T(), err)
 
 	suite.mockSql.ExpectQuery("INSERT INTO payments").
-		WithArgs(payment.UserID, payment.SeatDetailForBookingID, payment.TotalSeat, payment.TotalPrice).
+		WithArgs(payment.UserID, payment.SeatBookingID, payment.TotalSeat, payment.TotalPrice).
 		WillReturnError(errors.New("insert error"))
 
 	savedPayment, err := suite.repo.Save(suite.ctx, tx, payment, suite.ginContext)
@@ -387,7 +349,6 @@ func (suite *PaymentRepositoryTestSuite) TestSave_NullOrEmptyValues() {
 func (suite *PaymentRepositoryTestSuite) TestSave_InvalidDataTypes() {
 	payment := entity.Payment{
 		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-detail-id",
 		TotalSeat:              5, // Invalid data type
 		TotalPrice:             100,
 	}
@@ -397,7 +358,7 @@ func (suite *PaymentRepositoryTestSuite) TestSave_InvalidDataTypes() {
 	assert.NoError(suite.T(), err)
 
 	suite.mockSql.ExpectQuery("INSERT INTO payments").
-		WithArgs(payment.UserID, payment.SeatDetailForBookingID, payment.TotalSeat, payment.TotalPrice).
+		WithArgs(payment.UserID, payment.SeatBookingID, payment.TotalSeat, payment.TotalPrice).
 		WillReturnError(errors.New("invalid data type"))
 
 	savedPayment, err := suite.repo.Save(suite.ctx, tx, payment, suite.ginContext)
@@ -408,37 +369,31 @@ func (suite *PaymentRepositoryTestSuite) TestSave_InvalidDataTypes() {
 func (suite *PaymentRepositoryTestSuite) TestFindByID_Success() {
 	query := regexp.QuoteMeta(`
         SELECT 
-            p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+            p.id, p.user_id, p.total_seat, p.total_price, p.status,
             sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-            s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
             sh.id AS showtime_id, sh.show_start, sh.show_end,
             m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
             m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-            st.id AS studio_id, st.name AS studio_name
+            st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone
         FROM payments p
-        JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-        JOIN seats s ON sdfb.seat_id = s.id
-        JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+        JOIN seat_bookings sb ON p.seatbooking_id = sb.id
         JOIN showtimes sh ON sb.showtime_id = sh.id
         JOIN movies m ON sh.movie_id = m.id
         JOIN studios st ON sh.studio_id = st.id
+        JOIN cinemas ci ON st.cinema_id = ci.id
         WHERE p.id = $1`)
 	now := time.Now()
 	expectedPayment := entity.Payment{
 		ID:                     "1",
 		UserID:                 "1",
-		SeatDetailForBookingID: "1",
 		TotalSeat:              2,
 		TotalPrice:             50000,
 		Status:                 "PAID",
 		SeatBookingID:          "1",
 		SeatBookingStatus:      "CONFIRMED",
-		SeatID:                 "1",
-		SeatName:               "A1",
-		SeatIsAvailable:        "isAvailable",
 		ShowtimeID:             "showtime1",
-		ShowStart:              now.Format(time.RFC3339),
-		ShowEnd:                now.Add(time.Hour).Format(time.RFC3339),
+		ShowStart:              now,
+		ShowEnd:                now.Add(time.Hour),
 		MovieID:                "1",
 		MovieTitle:             "Avengers",
 		MovieDescription:       "Superhero movie",
@@ -447,22 +402,21 @@ func (suite *PaymentRepositoryTestSuite) TestFindByID_Success() {
 		MovieStatus:            "AVAILABLE",
 		StudioID:               "1",
 		StudioName:             "Studio 1",
+		Timezone:               "Asia/Jakarta",
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "seatdetailforbooking_id", "total_seat", "total_price", "status",
+		"id", "user_id", "total_seat", "total_price", "status",
 		"seat_booking_id", "seat_booking_status",
-		"seat_id", "seat_name", "seat_isAvailable",
 		"showtime_id", "show_start", "show_end",
 		"movie_id", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
-		"studio_id", "studio_name",
+		"studio_id", "studio_name", "cinema_timezone",
 	}).AddRow(
-		expectedPayment.ID, expectedPayment.UserID, expectedPayment.SeatDetailForBookingID, expectedPayment.TotalSeat, expectedPayment.TotalPrice, expectedPayment.Status,
+		expectedPayment.ID, expectedPayment.UserID, expectedPayment.TotalSeat, expectedPayment.TotalPrice, expectedPayment.Status,
 		expectedPayment.SeatBookingID, expectedPayment.SeatBookingStatus,
-		expectedPayment.SeatID, expectedPayment.SeatName, expectedPayment.SeatIsAvailable,
 		expectedPayment.ShowtimeID, expectedPayment.ShowStart, expectedPayment.ShowEnd,
 		expectedPayment.MovieID, expectedPayment.MovieTitle, expectedPayment.MovieDescription, expectedPayment.MoviePrice, expectedPayment.MovieDuration, expectedPayment.MovieStatus,
-		expectedPayment.StudioID, expectedPayment.StudioName,
+		expectedPayment.StudioID, expectedPayment.StudioName, expectedPayment.Timezone,
 	)
 
 	tx, err := suite.mockDb.Begin()
@@ -475,23 +429,41 @@ func (suite *PaymentRepositoryTestSuite) TestFindByID_Success() {
 	suite.Equal(expectedPayment, result)
 }
 
+func (suite *PaymentRepositoryTestSuite) TestFindByIDForUpdate_LocksPaymentAndBooking() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "total_seat", "total_price", "status",
+		"seat_booking_id", "seat_booking_status",
+		"showtime_id", "show_start", "show_end",
+		"movie_id", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
+		"studio_id", "studio_name", "cinema_timezone",
+	}).AddRow("1", "1", 2, 50000, "paid", "1", "success", "showtime1", time.Now(), time.Now(), "1", "Avengers", "Superhero movie", 25000, 120, "AVAILABLE", "1", "Studio 1", "Asia/Jakarta")
+	suite.mockSql.ExpectQuery(`WHERE p.id = \$1 FOR UPDATE OF p, sb$`).WithArgs("1").WillReturnRows(rows)
+
+	result, err := suite.repo.FindByIDForUpdate(context.Background(), tx, "1", suite.ginContext)
+	suite.NoError(err)
+	suite.Equal("paid", result.Status)
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
 func (suite *PaymentRepositoryTestSuite) TestFindByID_NotFound() {
 	query := regexp.QuoteMeta(`
         SELECT 
-            p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+            p.id, p.user_id, p.total_seat, p.total_price, p.status,
             sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-            s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
             sh.id AS showtime_id, sh.show_start, sh.show_end,
             m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
             m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-            st.id AS studio_id, st.name AS studio_name
+            st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone
         FROM payments p
-        JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-        JOIN seats s ON sdfb.seat_id = s.id
-        JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+        JOIN seat_bookings sb ON p.seatbooking_id = sb.id
         JOIN showtimes sh ON sb.showtime_id = sh.id
         JOIN movies m ON sh.movie_id = m.id
         JOIN studios st ON sh.studio_id = st.id
+        JOIN cinemas ci ON st.cinema_id = ci.id
         WHERE p.id = $1`)
 
 	tx, err := suite.mockDb.Begin()
@@ -508,20 +480,18 @@ func (suite *PaymentRepositoryTestSuite) TestFindByID_NotFound() {
 func (suite *PaymentRepositoryTestSuite) TestFindByID_ErrorScan() {
 	query := regexp.QuoteMeta(`
         SELECT 
-            p.id, p.user_id, p.seatdetailforbooking_id, p.total_seat, p.total_price, p.status,
+            p.id, p.user_id, p.total_seat, p.total_price, p.status,
             sb.id AS seat_booking_id, sb.status AS seat_booking_status,
-            s.id AS seat_id, s.seat_name, s.isAvailable AS seat_isAvailable,
             sh.id AS showtime_id, sh.show_start, sh.show_end,
             m.id AS movie_id, m.title AS movie_title, m.description AS movie_description, 
             m.price AS movie_price, m.duration AS movie_duration, m.status AS movie_status,
-            st.id AS studio_id, st.name AS studio_name
+            st.id AS studio_id, st.name AS studio_name, ci.timezone AS cinema_timezone
         FROM payments p
-        JOIN seat_detail_for_bookings sdfb ON p.seatdetailforbooking_id = sdfb.id
-        JOIN seats s ON sdfb.seat_id = s.id
-        JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+        JOIN seat_bookings sb ON p.seatbooking_id = sb.id
         JOIN showtimes sh ON sb.showtime_id = sh.id
         JOIN movies m ON sh.movie_id = m.id
         JOIN studios st ON sh.studio_id = st.id
+        JOIN cinemas ci ON st.cinema_id = ci.id
         WHERE p.id = $1`)
 
 	expectedErr := errors.New("scan error")
@@ -530,29 +500,27 @@ func (suite *PaymentRepositoryTestSuite) TestFindByID_ErrorScan() {
 	suite.mockSql.ExpectBegin()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "seatdetailforbooking_id", "total_seat", "total_price", "status",
+		"id", "user_id", "total_seat", "total_price", "status",
 		"seat_booking_id", "seat_booking_status",
-		"seat_id", "seat_name", "seat_isAvailable",
 		"showtime_id", "show_start", "show_end",
 		"movie_id", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
-		"studio_id", "studio_name",
-	}).AddRow("1", "1", "1", 2, 50000, "PAID", "1", "CONFIRMED", "1", "A1", true,
+		"studio_id", "studio_name", "cinema_timezone",
+	}).AddRow("1", "1", 2, 50000, "PAID", "1", "CONFIRMED",
 		"1", time.Now(), time.Now().Add(2*time.Hour), "1", "Avengers", "Superhero movie", 25000, 120, "AVAILABLE",
-		"1", "Studio 1")
+		"1", "Studio 1", "Asia/Jakarta")
 
 	suite.mockSql.ExpectQuery(query).WithArgs("1").WillReturnRows(rows)
 
 	// Cause a scan error by expecting more columns than returned
 	rows = sqlmock.NewRows([]string{
-		"id", "user_id", "seatdetailforbooking_id", "total_seat", "total_price", "status",
+		"id", "user_id", "total_seat", "total_price", "status",
 		"seat_booking_id", "seat_booking_status",
-		"seat_id", "seat_name", "seat_isAvailable",
 		"showtime_id", "show_start", "show_end",
 		"movie_id", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
-		"studio_id", "studio_name",
-	}).AddRow("1", "1", "1", 2, 50000, "PAID", "1", "CONFIRMED", "1", "A1", true,
+		"studio_id", "studio_name", "cinema_timezone",
+	}).AddRow("1", "1", 2, 50000, "PAID", "1", "CONFIRMED",
 		"1", time.Now(), time.Now().Add(2*time.Hour), "1", "Avengers", "Superhero movie", 25000, 120, "AVAILABLE",
-		"1", "Studio 1", "extra_column")
+		"1", "Studio 1", "Asia/Jakarta", "extra_column")
 
 	suite.mockSql.ExpectQuery(query).WithArgs("1").WillReturnRows(rows)
 
@@ -568,3 +536,99 @@ func (suite *PaymentRepositoryTestSuite) TestDatabaseConnectionError() {
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "db connection error", err.Error())
 }
+
+func (suite *PaymentRepositoryTestSuite) TestSaveEvent_Recorded() {
+	event := entity.PaymentEvent{
+		PaymentID:         "payment-id",
+		TransactionID:     "trx-1",
+		TransactionStatus: "settlement",
+		StatusCode:        "200",
+		GrossAmount:       "10000.00",
+		FraudStatus:       "accept",
+	}
+	query := regexp.QuoteMeta(`INSERT INTO payment_events (payment_id, transaction_id, transaction_status, status_code, gross_amount, fraud_status)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (transaction_id, transaction_status) DO NOTHING
+        RETURNING id`)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	suite.mockSql.ExpectQuery(query).
+		WithArgs(event.PaymentID, event.TransactionID, event.TransactionStatus, event.StatusCode, event.GrossAmount, event.FraudStatus).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("event-id"))
+
+	saved, err := suite.repo.SaveEvent(suite.ctx, tx, event, suite.ginContext)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), saved)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PaymentRepositoryTestSuite) TestSaveEvent_Duplicate() {
+	event := entity.PaymentEvent{PaymentID: "payment-id", TransactionID: "trx-1", TransactionStatus: "settlement"}
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	suite.mockSql.ExpectQuery("INSERT INTO payment_events").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	saved, err := suite.repo.SaveEvent(suite.ctx, tx, event, suite.ginContext)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), saved)
+	assert.Empty(suite.T(), suite.ginContext.Errors)
+}
+
+func (suite *PaymentRepositoryTestSuite) TestSaveEvent_Error() {
+	event := entity.PaymentEvent{PaymentID: "payment-id", TransactionID: "trx-1", TransactionStatus: "settlement"}
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	suite.mockSql.ExpectQuery("INSERT INTO payment_events").
+		WillReturnError(errors.New("insert error"))
+
+	saved, err := suite.repo.SaveEvent(suite.ctx, tx, event, suite.ginContext)
+	assert.EqualError(suite.T(), err, "insert error")
+	assert.False(suite.T(), saved)
+}
+
+func (suite *PaymentRepositoryTestSuite) TestSaveRefund_Success() {
+	refund := entity.Refund{
+		PaymentID:  "payment-id",
+		Amount:     10000,
+		Reason:     "cannot attend",
+		RefundedBy: "user-id",
+		SeatIDs:    []string{"seat-1", "seat-2"},
+	}
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta("INSERT INTO payment_refunds (payment_id, amount, reason, refunded_by) VALUES ($1, $2, $3, NULLIF($4, '')::uuid) RETURNING id")).
+		WithArgs(refund.PaymentID, refund.Amount, refund.Reason, refund.RefundedBy).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("refund-id"))
+	seatQuery := regexp.QuoteMeta("INSERT INTO payment_refund_seats (refund_id, seat_id) VALUES ($1, $2)")
+	suite.mockSql.ExpectExec(seatQuery).WithArgs("refund-id", "seat-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSql.ExpectExec(seatQuery).WithArgs("refund-id", "seat-2").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	saved, err := suite.repo.SaveRefund(suite.ctx, tx, refund, suite.ginContext)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "refund-id", saved.ID)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PaymentRepositoryTestSuite) TestSaveRefund_InsertError() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	suite.mockSql.ExpectQuery("INSERT INTO payment_refunds").WillReturnError(errors.New("insert error"))
+
+	_, err = suite.repo.SaveRefund(suite.ctx, tx, entity.Refund{PaymentID: "payment-id", SeatIDs: []string{"seat-1"}}, suite.ginContext)
+	assert.EqualError(suite.T(), err, "insert error")
+}
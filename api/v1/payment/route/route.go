@@ -1,11 +1,14 @@
 package movieroute
 
 import (
+	notificationRepo "bioskuy/api/v1/notification/repository"
 	"bioskuy/api/v1/payment/controller"
+	"bioskuy/api/v1/payment/gateway"
 	paymentRepo "bioskuy/api/v1/payment/repository"
 	"bioskuy/api/v1/payment/service"
-	seatRepo "bioskuy/api/v1/seat/repository"
 	seatBookingRepo "bioskuy/api/v1/seatbooking/repository"
+	ticketRepo "bioskuy/api/v1/ticket/repository"
+	webhookRepo "bioskuy/api/v1/webhook/repository"
 	"bioskuy/auth"
 	"bioskuy/helper"
 	"bioskuy/middleware"
@@ -21,17 +24,34 @@ func PaymentRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB,
 
 	paymentRepo := paymentRepo.NewPaymentRepository()
 	seatBookingRepo := seatBookingRepo.NewSeatBookingRepository()
-	seatRepo := seatRepo.NewSeatRepository()
-	paymentService := service.NewPaymentService(paymentRepo,seatRepo, seatBookingRepo, validate, db, config)
+	ticketRepo := ticketRepo.NewTicketRepository()
+	outboxRepo := webhookRepo.NewOutboxRepository()
+	notificationRepo := notificationRepo.NewNotificationRepository()
+	paymentGateway, err := gateway.NewPaymentGateway(config)
+	if err != nil {
+		panic(err)
+	}
+	paymentService := service.NewPaymentService(paymentRepo, seatBookingRepo, ticketRepo, outboxRepo, notificationRepo, paymentGateway, validate, db, config)
 	paymentController := controller.NewPaymentController(paymentService)
 	v1 := router.Group("/api/v1")
 	{
 		paymentRoutes := v1.Group("/payments")
 		{
 			paymentRoutes.POST("/", middleware.AuthMiddleware(authService, "user"), paymentController.Create)
-			paymentRoutes.GET("/", paymentController.FindAll)
-			paymentRoutes.GET("/:paymentId", paymentController.FindById)
+			paymentRoutes.GET("/", middleware.AuthMiddleware(authService, "admin", "super admin", "branch admin"), paymentController.FindAll)
+			paymentRoutes.GET("/:paymentId", middleware.AuthMiddleware(authService, "user", "admin", "super admin"), paymentController.FindById)
 			paymentRoutes.POST("/notification", paymentController.Notification)
+			paymentRoutes.POST("/:paymentId/refund", middleware.AuthMiddleware(authService, "user", "admin", "super admin"), paymentController.Refund)
+
+			if fakeGateway, ok := paymentGateway.(*gateway.FakeGateway); ok {
+				fakeGatewayController := controller.NewFakeGatewayController(fakeGateway, paymentService)
+				paymentRoutes.POST("/fake/:paymentId", middleware.AuthMiddleware(authService, "user", "admin", "super admin"), fakeGatewayController.Settle)
+			}
+		}
+
+		me := v1.Group("/me")
+		{
+			me.GET("/payments", middleware.AuthMiddleware(authService, "user"), paymentController.FindMine)
 		}
 	}
 }
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bioskuy/api/v1/payment/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -9,7 +11,8 @@ import (
 
 type PaymentService interface {
 	Create(ctx context.Context, request dto.PaymentRequest, userid string, c *gin.Context) (dto.CreatePaymentResponse, error)
-	Update(ctx context.Context, notificationPayload map[string]interface{}, c *gin.Context) 
-	FindByID(ctx context.Context, id string, c *gin.Context) (dto.PaymentResponse, error)
-	FindAll(ctx context.Context, c *gin.Context) ([]dto.PaymentResponse, error)
+	Update(ctx context.Context, notification dto.PaymentNotificationRequest, c *gin.Context) error
+	FindByID(ctx context.Context, id string, userID string, role string, c *gin.Context) (dto.PaymentResponse, error)
+	FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.PaymentResponse, web.Paging, error)
+	Refund(ctx context.Context, id string, request dto.RefundRequest, userID string, role string, c *gin.Context) (dto.RefundResponse, error)
 }
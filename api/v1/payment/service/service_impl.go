@@ -1,39 +1,51 @@
 package service
 
 import (
+	entityNotification "bioskuy/api/v1/notification/entity"
+	RepoNotification "bioskuy/api/v1/notification/repository"
 	"bioskuy/api/v1/payment/dto"
 	"bioskuy/api/v1/payment/entity"
+	"bioskuy/api/v1/payment/gateway"
 	"bioskuy/api/v1/payment/repository"
-	entitySeat "bioskuy/api/v1/seat/entity"
-	RepoSeat "bioskuy/api/v1/seat/repository"
 	entitySeatBooking "bioskuy/api/v1/seatbooking/entity"
 	RepoSeatBooking "bioskuy/api/v1/seatbooking/repository"
+	RepoTicket "bioskuy/api/v1/ticket/repository"
+	dtoWebhook "bioskuy/api/v1/webhook/dto"
+	entityWebhook "bioskuy/api/v1/webhook/entity"
+	RepoWebhook "bioskuy/api/v1/webhook/repository"
 	"bioskuy/exception"
 	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 	"database/sql"
-	"fmt"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/midtrans/midtrans-go"
-	"github.com/midtrans/midtrans-go/snap"
 )
 
 type paymentServiceImpl struct {
 	Repo repository.PaymentRepository
 	RepoSeatBooking RepoSeatBooking.SeatBookingRepository
-	RepoSeat RepoSeat.SeatRepository
+	RepoTicket RepoTicket.TicketRepository
+	RepoOutbox RepoWebhook.OutboxRepository
+	RepoNotification RepoNotification.NotificationRepository
+	Gateway gateway.PaymentGateway
 	Validate *validator.Validate
 	DB *sql.DB
 	Env *helper.Config
 }
 
-func NewPaymentService(Repo repository.PaymentRepository, RepoSeat RepoSeat.SeatRepository, RepoSeatBooking RepoSeatBooking.SeatBookingRepository, validate *validator.Validate, DB *sql.DB, env *helper.Config) PaymentService {
+func NewPaymentService(Repo repository.PaymentRepository, RepoSeatBooking RepoSeatBooking.SeatBookingRepository, RepoTicket RepoTicket.TicketRepository, RepoOutbox RepoWebhook.OutboxRepository, RepoNotification RepoNotification.NotificationRepository, Gateway gateway.PaymentGateway, validate *validator.Validate, DB *sql.DB, env *helper.Config) PaymentService {
 	return &paymentServiceImpl{
 		Repo: Repo,
 		RepoSeatBooking: RepoSeatBooking,
-		RepoSeat: RepoSeat,
+		RepoTicket: RepoTicket,
+		RepoOutbox: RepoOutbox,
+		RepoNotification: RepoNotification,
+		Gateway: Gateway,
 		Validate: validate,
 		DB: DB,
 		Env: env,
@@ -45,7 +57,7 @@ func (s *paymentServiceImpl)Create(ctx context.Context, request dto.PaymentReque
 	var PaymentRequest = dto.CreatePaymentRequest{}
 	
 	PaymentRequest.UserID = userid
-	PaymentRequest.SeatDetailForBookingID = request.SeatDetailForBookingID
+	PaymentRequest.SeatBookingID = request.SeatBookingID
 
 	err := s.Validate.Struct(request)
 	if err != nil {
@@ -60,18 +72,31 @@ func (s *paymentServiceImpl)Create(ctx context.Context, request dto.PaymentReque
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	seatbookingExist, err := s.RepoSeatBooking.FindAllPendingByUserID(ctx, tx, PaymentRequest.UserID, c)
+	seatbooking, err := s.RepoSeatBooking.FindByID(ctx, tx, PaymentRequest.SeatBookingID, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return PaymentResponse, err
 	}
 
-	totalSeat := len(seatbookingExist)
-	total_price := totalSeat * seatbookingExist[0].MoviePrice
+	if seatbooking.UserID != userid {
+		err := exception.ForbiddenError{Message: "seat booking belongs to another user"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return PaymentResponse, err
+	}
+
+	if seatbooking.SeatBookingStatus != "pending" || !time.Now().UTC().Before(seatbooking.HoldExpiresAt) {
+		err := exception.ConflictError{Message: "seat booking is no longer awaiting payment"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return PaymentResponse, err
+	}
+
+	// Each seat is charged at the unit price snapshotted when it was reserved.
+	totalSeat := len(seatbooking.Seats)
+	total_price := seatbooking.TotalPrice()
 
 	payment := entity.Payment{
 		UserID: userid,
-		SeatDetailForBookingID: PaymentRequest.SeatDetailForBookingID,
+		SeatBookingID: PaymentRequest.SeatBookingID,
 		TotalSeat: totalSeat,
 		TotalPrice: total_price,
 	}
@@ -82,34 +107,27 @@ func (s *paymentServiceImpl)Create(ctx context.Context, request dto.PaymentReque
 		return PaymentResponse, err
 	}
 
-	var servicemidtrans = snap.Client{}
-	servicemidtrans.New(s.Env.MIDTRANS_SERVER_KEY, midtrans.Sandbox)
-
-	req := & snap.Request{
-		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  result.ID,
-			GrossAmt: int64(result.TotalPrice),
-		}, 
-		CreditCard: &snap.CreditCardDetails{
-			Secure: true,
-		},
+	charge, err := s.Gateway.CreateCharge(ctx, gateway.ChargeRequest{
+		OrderID:     result.ID,
+		GrossAmount: result.TotalPrice,
+	})
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return PaymentResponse, err
 	}
 
-	snapResp, _ := servicemidtrans.CreateTransaction(req)
-
-	fmt.Println("snap response: ", snapResp)
-
 	PaymentResponse.ID = result.ID
 	PaymentResponse.UserID = userid
-	PaymentResponse.SeatDetailForBookingID = request.SeatDetailForBookingID
+	PaymentResponse.SeatBookingID = request.SeatBookingID
 	PaymentResponse.TotalSeat = totalSeat
 	PaymentResponse.TotalPrice = total_price
-	PaymentResponse.URL = snapResp.RedirectURL
+	PaymentResponse.URL = charge.RedirectURL
 
 	return PaymentResponse, nil
 }
 
-func (s *paymentServiceImpl) FindByID(ctx context.Context, id string, c *gin.Context) (dto.PaymentResponse, error) {
+// FindByID returns the payment to its owner, or to any admin.
+func (s *paymentServiceImpl) FindByID(ctx context.Context, id string, userID string, role string, c *gin.Context) (dto.PaymentResponse, error) {
 	paymentResponse := dto.PaymentResponse{}
 
 	tx, err := s.DB.Begin()
@@ -125,12 +143,21 @@ func (s *paymentServiceImpl) FindByID(ctx context.Context, id string, c *gin.Con
 		return paymentResponse, err
 	}
 
+	if role != "admin" && role != "super admin" && result.UserID != userID {
+		err := exception.ForbiddenError{Message: "payment belongs to another user"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return paymentResponse, err
+	}
+
 	paymentResponse.ID = result.ID
 	paymentResponse.SeatBookingStatus = result.SeatBookingStatus
 	paymentResponse.UserID = result.UserID
 	paymentResponse.ShowtimeID = result.ShowtimeID
-	paymentResponse.ShowStart = result.ShowStart
-	paymentResponse.ShowEnd = result.ShowEnd
+	paymentResponse.ShowStart = helper.InZone(result.ShowStart, result.Timezone)
+	paymentResponse.ShowEnd = helper.InZone(result.ShowEnd, result.Timezone)
+	paymentResponse.ShowStartUTC = result.ShowStart.UTC()
+	paymentResponse.ShowEndUTC = result.ShowEnd.UTC()
+	paymentResponse.Timezone = helper.Location(result.Timezone).String()
 	paymentResponse.StudioID = result.StudioID
 	paymentResponse.StudioName = result.StudioName
 	paymentResponse.MovieID = result.MovieID
@@ -139,10 +166,7 @@ func (s *paymentServiceImpl) FindByID(ctx context.Context, id string, c *gin.Con
 	paymentResponse.MoviePrice = result.MoviePrice
 	paymentResponse.MovieDuration = result.MovieDuration
 	paymentResponse.MovieStatus = result.MovieStatus
-	paymentResponse.SeatID = result.SeatID
-	paymentResponse.SeatDetailForBookingID = result.SeatDetailForBookingID
-	paymentResponse.SeatName = result.SeatName
-	paymentResponse.SeatIsAvailable = result.SeatIsAvailable
+	paymentResponse.SeatBookingID = result.SeatBookingID
 	paymentResponse.TotalPrice = result.TotalPrice
 	paymentResponse.TotalSeat = result.TotalSeat
 	paymentResponse.Status = result.Status
@@ -150,20 +174,20 @@ func (s *paymentServiceImpl) FindByID(ctx context.Context, id string, c *gin.Con
 	return paymentResponse, nil
 }
 
-func (s *paymentServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto.PaymentResponse, error) {
+func (s *paymentServiceImpl) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.PaymentResponse, web.Paging, error) {
 	paymentResponses := []dto.PaymentResponse{}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return paymentResponses, err
+		return paymentResponses, web.Paging{}, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	results, err := s.Repo.FindAll(ctx, tx, c)
+	results, total, err := s.Repo.FindAll(ctx, tx, query, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return paymentResponses, err
+		return paymentResponses, web.Paging{}, err
 	}
 
 	for _, result := range results {
@@ -172,8 +196,11 @@ func (s *paymentServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto
 			SeatBookingStatus:     result.SeatBookingStatus,
 			UserID:                result.UserID,
 			ShowtimeID:            result.ShowtimeID,
-			ShowStart:             result.ShowStart,
-			ShowEnd:               result.ShowEnd,
+			ShowStart:             helper.InZone(result.ShowStart, result.Timezone),
+			ShowEnd:               helper.InZone(result.ShowEnd, result.Timezone),
+			ShowStartUTC:          result.ShowStart.UTC(),
+			ShowEndUTC:            result.ShowEnd.UTC(),
+			Timezone:              helper.Location(result.Timezone).String(),
 			StudioID:              result.StudioID,
 			StudioName:            result.StudioName,
 			MovieID:               result.MovieID,
@@ -182,10 +209,7 @@ func (s *paymentServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto
 			MoviePrice:            result.MoviePrice,
 			MovieDuration:         result.MovieDuration,
 			MovieStatus:           result.MovieStatus,
-			SeatID:                result.SeatID,
-			SeatDetailForBookingID: result.SeatDetailForBookingID,
-			SeatName:              result.SeatName,
-			SeatIsAvailable:       result.SeatIsAvailable,
+			SeatBookingID:         result.SeatBookingID,
 			TotalPrice: result.TotalPrice,
 			TotalSeat: result.TotalSeat,
 			Status: result.Status,
@@ -193,109 +217,458 @@ func (s *paymentServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto
 		paymentResponses = append(paymentResponses, paymentResponse)
 	}
 
-	return paymentResponses, nil
+	return paymentResponses, query.Paging(total), nil
 }
 
-func (s *paymentServiceImpl) Update(ctx context.Context, notificationPayload map[string]interface{}, c *gin.Context) {
+func (s *paymentServiceImpl) Update(ctx context.Context, notification dto.PaymentNotificationRequest, c *gin.Context) error {
+
+	err := s.Validate.Struct(notification)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	if !s.Gateway.VerifyNotification(notification) {
+		err := exception.ForbiddenError{Message: "invalid notification signature"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return err
+	}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	paymentId := notificationPayload["order_id"].(string)
+	// Notifications of different statuses for one payment, such as a capture
+	// and a settlement, are applied one at a time against the latest status.
+	payment, err := s.Repo.FindByIDForUpdate(ctx, tx, notification.OrderID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
 
-	payment, _ := s.Repo.FindByID(ctx, tx, paymentId, c)
+	grossAmount, err := strconv.ParseFloat(notification.GrossAmount, 64)
+	if err != nil || int(math.Round(grossAmount)) != payment.TotalPrice {
+		err := exception.ForbiddenError{Message: "gross amount does not match payment"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return err
+	}
 
-	fmt.Println(notificationPayload["order_id"])
+	saved, err := s.Repo.SaveEvent(ctx, tx, entity.PaymentEvent{
+		PaymentID:         payment.ID,
+		TransactionID:     notification.TransactionID,
+		TransactionStatus: notification.TransactionStatus,
+		StatusCode:        notification.StatusCode,
+		GrossAmount:       notification.GrossAmount,
+		FraudStatus:       notification.FraudStatus,
+	}, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
 
-	if notificationPayload["transaction_status"] == "settlement"{
+	// Midtrans retries a notification until it gets a 200; a repeat has already been applied.
+	if !saved {
+		return nil
+	}
 
-		payment.Status = "paid"
-		_, err := s.Repo.Update(ctx, tx, payment, c)
-		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		}
+	status := paymentStatusFor(notification)
+	if status == entity.PaymentStatusPaid && payment.Status == entity.PaymentStatusFailed {
+		return s.refundLateSettlement(ctx, tx, payment, c)
+	}
 
-		seatbooking := entitySeatBooking.SeatBooking{}
-		seatbooking.SeatBookingStatus = "success"
-		seatbooking.ID = payment.SeatBookingID
+	if status == "" || status == payment.Status || !entity.CanTransition(payment.Status, status) {
+		return nil
+	}
+
+	previousStatus := payment.Status
+	payment.Status = status
+	_, err = s.Repo.Update(ctx, tx, payment, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
 
-		_, err =  s.RepoSeatBooking.Update(ctx, tx, seatbooking, c )
+	err = s.RepoOutbox.Publish(ctx, tx, entityWebhook.PaymentEventType(status), dtoWebhook.PaymentData{
+		ID:             payment.ID,
+		UserID:         payment.UserID,
+		SeatBookingID:  payment.SeatBookingID,
+		Status:         status,
+		PreviousStatus: previousStatus,
+		TotalPrice:     payment.TotalPrice,
+	}, c)
+	if err != nil {
+		return err
+	}
+
+	if kind := notificationKindFor(status, notification); kind != "" {
+		// The seats are read before a failure or refund releases them below.
+		seatbooking, err := s.RepoSeatBooking.FindByID(ctx, tx, payment.SeatBookingID, c)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
 
-	}else if notificationPayload["transaction_status"] == "deny"{
+		refundedAmount := 0
+		if status == entity.PaymentStatusRefunded {
+			refundedAmount = payment.TotalPrice
+		}
 
-		err := s.Repo.Delete(ctx, tx, payment.ID, c)
+		err = s.queueNotification(ctx, tx, kind, payment, seatbooking.Seats, refundedAmount, c)
 		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
+	}
+
+	switch status {
+	case entity.PaymentStatusPaid:
 
 		seatbooking := entitySeatBooking.SeatBooking{}
+		seatbooking.SeatBookingStatus = "success"
 		seatbooking.ID = payment.SeatBookingID
-		
-		err =  s.RepoSeatBooking.Delete(ctx, tx, seatbooking.ID, c )
+
+		_, err = s.RepoSeatBooking.Update(ctx, tx, seatbooking, c)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
 
-		seat := entitySeat.Seat{}
-		seat.ID = payment.SeatID
-		seat.IsAvailable = true
-		_, err = s.RepoSeat.Update(ctx, tx, seat, c)
+		err = s.RepoTicket.Issue(ctx, tx, payment.ID, c)
 		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
 
-	}else if notificationPayload["transaction_status"] == "cancel" {
+	case entity.PaymentStatusFailed, entity.PaymentStatusRefunded:
 
-		err := s.Repo.Delete(ctx, tx, payment.ID, c)
+		err = s.RepoTicket.Void(ctx, tx, payment.ID, nil, c)
 		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
 
-		seatbooking := entitySeatBooking.SeatBooking{}
-		seatbooking.ID = payment.SeatBookingID
-		
-		err =  s.RepoSeatBooking.Delete(ctx, tx, seatbooking.ID, c )
+		failedPayments, err := s.RepoSeatBooking.Delete(ctx, tx, payment.SeatBookingID, c)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
 
-		seat := entitySeat.Seat{}
-		seat.ID = payment.SeatID
-		seat.IsAvailable = true
-		_, err = s.RepoSeat.Update(ctx, tx, seat, c)
+		err = s.publishFailedPayments(ctx, tx, failedPayments, c)
 		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
+	}
+
+	return nil
+}
 
-	}else if notificationPayload["transaction_status"] == "expire" {
+// Refund returns some or all of a paid booking's seats. Owners may refund up
+// to the configured cutoff before the show starts; admins at any time.
+func (s *paymentServiceImpl) Refund(ctx context.Context, id string, request dto.RefundRequest, userID string, role string, c *gin.Context) (dto.RefundResponse, error) {
+	refundResponse := dto.RefundResponse{}
 
-		err := s.Repo.Delete(ctx, tx, payment.ID, c)
-		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	// The lock keeps a concurrent refund from passing the status check below
+	// until this one has committed.
+	payment, err := s.Repo.FindByIDForUpdate(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	if role != "admin" && role != "super admin" {
+		if payment.UserID != userID {
+			err := exception.ForbiddenError{Message: "payment belongs to another user"}
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return refundResponse, err
 		}
 
-		seatbooking := entitySeatBooking.SeatBooking{}
-		seatbooking.ID = payment.SeatBookingID
-		
-		err =  s.RepoSeatBooking.Delete(ctx, tx, seatbooking.ID, c )
-		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		if time.Now().UTC().Add(s.Env.RefundCutoffDuration()).After(payment.ShowStart) {
+			err := exception.ForbiddenError{Message: "refunds are closed for this showtime"}
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return refundResponse, err
+		}
+	}
+
+	if payment.Status != entity.PaymentStatusPaid && payment.Status != entity.PaymentStatusPartiallyRefunded {
+		err := exception.ConflictError{Message: "only paid payments can be refunded"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	seatbooking, err := s.RepoSeatBooking.FindByID(ctx, tx, payment.SeatBookingID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	seatPrices := map[string]int{}
+	for _, seat := range seatbooking.Seats {
+		seatPrices[seat.SeatID] = seat.UnitPrice
+	}
+
+	seatIDs := request.SeatIDs
+	if len(seatIDs) == 0 {
+		for _, seat := range seatbooking.Seats {
+			seatIDs = append(seatIDs, seat.SeatID)
+		}
+	}
+
+	amount := 0
+	for _, seatID := range seatIDs {
+		price, ok := seatPrices[seatID]
+		if !ok {
+			err := exception.ValidationError{Message: "seat " + seatID + " is not part of this payment"}
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return refundResponse, err
+		}
+		amount += price
+	}
+
+	refund, err := s.Repo.SaveRefund(ctx, tx, entity.Refund{
+		PaymentID:  payment.ID,
+		Amount:     amount,
+		Reason:     request.Reason,
+		RefundedBy: userID,
+		SeatIDs:    seatIDs,
+	}, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	err = s.RepoTicket.Void(ctx, tx, payment.ID, seatIDs, c)
+	if err != nil {
+		return refundResponse, err
+	}
+
+	previousStatus := payment.Status
+	failedPayments := []entity.FailedPayment{}
+	if len(seatIDs) == len(seatbooking.Seats) {
+		payment.Status = entity.PaymentStatusRefunded
+		failedPayments, err = s.RepoSeatBooking.Delete(ctx, tx, seatbooking.ID, c)
+	} else {
+		payment.Status = entity.PaymentStatusPartiallyRefunded
+		err = s.RepoSeatBooking.DeleteSeats(ctx, tx, seatbooking.ID, seatIDs, c)
+	}
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	err = s.publishFailedPayments(ctx, tx, failedPayments, c)
+	if err != nil {
+		return refundResponse, err
+	}
+
+	_, err = s.Repo.Update(ctx, tx, payment, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	err = s.RepoOutbox.Publish(ctx, tx, entityWebhook.PaymentEventType(payment.Status), dtoWebhook.PaymentData{
+		ID:              payment.ID,
+		UserID:          payment.UserID,
+		SeatBookingID:   payment.SeatBookingID,
+		Status:          payment.Status,
+		PreviousStatus:  previousStatus,
+		TotalPrice:      payment.TotalPrice,
+		RefundedSeatIDs: seatIDs,
+		RefundedAmount:  refund.Amount,
+	}, c)
+	if err != nil {
+		return refundResponse, err
+	}
+
+	refundedSeats := []entitySeatBooking.SeatDetail{}
+	for _, seat := range seatbooking.Seats {
+		for _, seatID := range seatIDs {
+			if seat.SeatID == seatID {
+				refundedSeats = append(refundedSeats, seat)
+			}
 		}
+	}
+
+	err = s.queueNotification(ctx, tx, entityNotification.KindBookingRefunded, payment, refundedSeats, refund.Amount, c)
+	if err != nil {
+		return refundResponse, err
+	}
+
+	// The money moves last, once every local write has succeeded, so a failed
+	// write cannot return money the payment does not record as refunded. The
+	// refund ID is the idempotency key, so the gateway applies it only once.
+	_, err = s.Gateway.Refund(ctx, gateway.RefundRequest{
+		OrderID:   payment.ID,
+		RefundKey: refund.ID,
+		Amount:    refund.Amount,
+		Reason:    refund.Reason,
+	})
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return refundResponse, err
+	}
+
+	refundResponse.ID = refund.ID
+	refundResponse.PaymentID = payment.ID
+	refundResponse.Amount = refund.Amount
+	refundResponse.Reason = refund.Reason
+	refundResponse.SeatIDs = seatIDs
+	refundResponse.PaymentStatus = payment.Status
+
+	return refundResponse, nil
+}
+
+// refundLateSettlement gives the money back for a payment that settled after
+// its booking expired. The sweeper has already failed the payment and put its
+// seats back on sale, so the customer is refunded in full rather than left
+// charged without a seat.
+func (s *paymentServiceImpl) refundLateSettlement(ctx context.Context, tx *sql.Tx, payment entity.Payment, c *gin.Context) error {
+	refund, err := s.Repo.SaveRefund(ctx, tx, entity.Refund{
+		PaymentID: payment.ID,
+		Amount:    payment.TotalPrice,
+		Reason:    "paid after the booking expired",
+	}, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	previousStatus := payment.Status
+	payment.Status = entity.PaymentStatusRefunded
+	_, err = s.Repo.Update(ctx, tx, payment, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	err = s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventPaymentRefunded, dtoWebhook.PaymentData{
+		ID:             payment.ID,
+		UserID:         payment.UserID,
+		SeatBookingID:  payment.SeatBookingID,
+		Status:         payment.Status,
+		PreviousStatus: previousStatus,
+		TotalPrice:     payment.TotalPrice,
+		RefundedAmount: refund.Amount,
+	}, c)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueNotification(ctx, tx, entityNotification.KindLatePaymentRefunded, payment, nil, refund.Amount, c)
+	if err != nil {
+		return err
+	}
+
+	// As in Refund, the money moves once every local write has succeeded.
+	// Should it fail, the notification is rolled back and Midtrans retries it.
+	_, err = s.Gateway.Refund(ctx, gateway.RefundRequest{
+		OrderID:   payment.ID,
+		RefundKey: refund.ID,
+		Amount:    refund.Amount,
+		Reason:    refund.Reason,
+	})
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
 
-		seat := entitySeat.Seat{}
-		seat.ID = payment.SeatID
-		seat.IsAvailable = true
-		_, err = s.RepoSeat.Update(ctx, tx, seat, c)
+// queueNotification queues the email of kind about payment to its owner,
+// listing seats.
+func (s *paymentServiceImpl) queueNotification(ctx context.Context, tx *sql.Tx, kind string, payment entity.Payment, seats []entitySeatBooking.SeatDetail, refundedAmount int, c *gin.Context) error {
+	seatNames := []string{}
+	for _, seat := range seats {
+		seatNames = append(seatNames, seat.SeatName)
+	}
+
+	return s.RepoNotification.Queue(ctx, tx, entityNotification.Notification{
+		UserID: payment.UserID,
+		Kind:   kind,
+		Data: entityNotification.Data{
+			SeatBookingID:  payment.SeatBookingID,
+			PaymentID:      payment.ID,
+			MovieTitle:     payment.MovieTitle,
+			StudioName:     payment.StudioName,
+			ShowStart:      payment.ShowStart,
+			Timezone:       payment.Timezone,
+			Seats:          seatNames,
+			TotalPrice:     payment.TotalPrice,
+			RefundedAmount: refundedAmount,
+		},
+	}, c)
+}
+
+// publishFailedPayments publishes payment.failed for each open payment failed
+// when its booking was released.
+func (s *paymentServiceImpl) publishFailedPayments(ctx context.Context, tx *sql.Tx, payments []entity.FailedPayment, c *gin.Context) error {
+	for _, payment := range payments {
+		err := s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventPaymentFailed, dtoWebhook.FailedPaymentData(payment), c)
 		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return err
 		}
-		
 	}
 
-}
\ No newline at end of file
+	return nil
+}
+
+// notificationKindFor is the email sent when a Midtrans notification moves a
+// payment to status, or "" for none. Partial refunds made in the Midtrans
+// dashboard send nothing, since which seats they cover is not known here;
+// those made through Refund are announced there.
+func notificationKindFor(status string, notification dto.PaymentNotificationRequest) string {
+	switch status {
+	case entity.PaymentStatusPaid:
+		return entityNotification.KindBookingConfirmed
+	case entity.PaymentStatusFailed:
+		if notification.TransactionStatus == "expire" {
+			return entityNotification.KindBookingExpired
+		}
+		return entityNotification.KindBookingCancelled
+	case entity.PaymentStatusRefunded:
+		return entityNotification.KindBookingRefunded
+	}
+
+	return ""
+}
+
+// paymentStatusFor maps a Midtrans transaction status to the payment status it
+// leads to, or "" when the notification does not move the payment.
+func paymentStatusFor(notification dto.PaymentNotificationRequest) string {
+	switch notification.TransactionStatus {
+	case "capture":
+		if notification.FraudStatus == "challenge" {
+			return entity.PaymentStatusPending
+		}
+		if notification.FraudStatus == "" || notification.FraudStatus == "accept" {
+			return entity.PaymentStatusPaid
+		}
+		return entity.PaymentStatusFailed
+	case "settlement":
+		return entity.PaymentStatusPaid
+	case "pending":
+		return entity.PaymentStatusPending
+	case "deny", "cancel", "expire", "failure":
+		return entity.PaymentStatusFailed
+	case "refund":
+		return entity.PaymentStatusRefunded
+	case "partial_refund":
+		return entity.PaymentStatusPartiallyRefunded
+	}
+
+	return ""
+}
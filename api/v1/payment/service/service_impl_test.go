@@ -1,16 +1,24 @@
 package service
 
 import (
+	entityNotification "bioskuy/api/v1/notification/entity"
+	notificationRepomock "bioskuy/api/v1/notification/mock/repomock"
 	"bioskuy/api/v1/payment/dto"
 	"bioskuy/api/v1/payment/entity"
+	"bioskuy/api/v1/payment/gateway"
+	"bioskuy/api/v1/payment/mock/notificationmock"
 	"bioskuy/api/v1/payment/mock/repomock"
 	entitySeatBooking "bioskuy/api/v1/seatbooking/entity"
+	dtoWebhook "bioskuy/api/v1/webhook/dto"
+	webhookRepomock "bioskuy/api/v1/webhook/mock/repomock"
 	"bioskuy/exception"
 	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
@@ -25,12 +33,15 @@ type PaymentServiceTestSuite struct {
 	mockDb              *sql.DB
 	mockSql             sqlmock.Sqlmock
 	mockRepo            *repomock.MockPaymentRepository
-	mockRepoSeat        *repomock.MockSeatRepository
 	mockRepoSeatBooking *repomock.MockSeatBookingRepository
+	mockRepoTicket      *repomock.MockTicketRepository
+	mockRepoOutbox      *webhookRepomock.MockOutboxRepository
+	mockRepoNotify      *notificationRepomock.MockNotificationRepository
 	validate            *validator.Validate
 	service             PaymentService
 	ctx                 context.Context
 	ginContext          *gin.Context
+	gateway             *gateway.FakeGateway
 	env                 *helper.Config
 }
 
@@ -40,53 +51,106 @@ func (suite *PaymentServiceTestSuite) SetupTest() {
 	suite.mockDb = db
 	suite.mockSql = mock
 	suite.mockRepo = &repomock.MockPaymentRepository{}
-	suite.mockRepoSeat = &repomock.MockSeatRepository{}
 	suite.mockRepoSeatBooking = &repomock.MockSeatBookingRepository{}
+	suite.mockRepoTicket = &repomock.MockTicketRepository{}
+	suite.mockRepoOutbox = &webhookRepomock.MockOutboxRepository{}
+	suite.mockRepoNotify = &notificationRepomock.MockNotificationRepository{}
 	suite.validate = validator.New()
 	suite.env = &helper.Config{MIDTRANS_SERVER_KEY: "dummy-key"}
-	suite.service = NewPaymentService(suite.mockRepo, suite.mockRepoSeat, suite.mockRepoSeatBooking, suite.validate, suite.mockDb, suite.env)
+	suite.gateway, err = gateway.NewFakeGateway(suite.env.MIDTRANS_SERVER_KEY)
+	assert.NoError(suite.T(), err)
+	suite.service = NewPaymentService(suite.mockRepo, suite.mockRepoSeatBooking, suite.mockRepoTicket, suite.mockRepoOutbox, suite.mockRepoNotify, suite.gateway, suite.validate, suite.mockDb, suite.env)
 	suite.ctx = context.Background()
 	suite.ginContext = &gin.Context{}
 }
 
+type unavailableGateway struct {
+	*gateway.FakeGateway
+}
+
+func (unavailableGateway) CreateCharge(ctx context.Context, request gateway.ChargeRequest) (gateway.Charge, error) {
+	return gateway.Charge{}, errors.New("gateway unavailable")
+}
+
+// countingGateway counts the refunds sent to the provider.
+type countingGateway struct {
+	*gateway.FakeGateway
+	refunds int
+}
+
+func (g *countingGateway) Refund(ctx context.Context, request gateway.RefundRequest) (gateway.Refund, error) {
+	g.refunds++
+	return g.FakeGateway.Refund(ctx, request)
+}
+
 func TestPaymentServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(PaymentServiceTestSuite))
 }
 
 // Create
+func (suite *PaymentServiceTestSuite) pendingSeatBooking() entitySeatBooking.SeatBooking {
+	return entitySeatBooking.SeatBooking{
+		ID:                "booking-id",
+		UserID:            "user-id",
+		SeatBookingStatus: "pending",
+		HoldExpiresAt:     time.Now().UTC().Add(10 * time.Minute),
+		MoviePrice:        10000,
+		Seats:             []entitySeatBooking.SeatDetail{{ID: "detail-1", UnitPrice: 12500}, {ID: "detail-2", UnitPrice: 7500}},
+	}
+}
+
 func (suite *PaymentServiceTestSuite) TestCreate_Success() {
 	request := dto.PaymentRequest{
-		SeatDetailForBookingID: "seat-id",
+		SeatBookingID: "booking-id",
 	}
 	expectedResult := entity.Payment{
-		ID:                     "new-id",
-		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-id",
-		TotalSeat:              1,
-		TotalPrice:             10000,
-	}
-
-	seatBooking := []entitySeatBooking.SeatBooking{
-		{MoviePrice: 10000},
+		ID:            "new-id",
+		UserID:        "user-id",
+		SeatBookingID: "booking-id",
+		TotalSeat:     2,
+		TotalPrice:    20000,
 	}
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepoSeatBooking.On("FindAllPendingByUserID", suite.ctx, mock.Anything, "user-id", suite.ginContext).Return(seatBooking, nil)
-	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Payment"), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.pendingSeatBooking(), nil)
+	suite.mockRepo.On("Save", suite.ctx, mock.Anything, entity.Payment{UserID: "user-id", SeatBookingID: "booking-id", TotalSeat: 2, TotalPrice: 20000}, suite.ginContext).Return(expectedResult, nil)
 	suite.mockSql.ExpectCommit()
 
 	response, err := suite.service.Create(suite.ctx, request, "user-id", suite.ginContext)
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), expectedResult.ID, response.ID)
-	assert.Equal(suite.T(), request.SeatDetailForBookingID, response.SeatDetailForBookingID)
+	assert.Equal(suite.T(), request.SeatBookingID, response.SeatBookingID)
 	assert.Equal(suite.T(), expectedResult.TotalSeat, response.TotalSeat)
 	assert.Equal(suite.T(), expectedResult.TotalPrice, response.TotalPrice)
+	assert.Equal(suite.T(), "/api/v1/payments/fake/new-id", response.URL)
+
+	status, err := suite.gateway.Status(suite.ctx, "new-id")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "20000.00", status.GrossAmount)
+}
+
+func (suite *PaymentServiceTestSuite) TestCreate_GatewayError() {
+	request := dto.PaymentRequest{
+		SeatBookingID: "booking-id",
+	}
+	suite.service = NewPaymentService(suite.mockRepo, suite.mockRepoSeatBooking, suite.mockRepoTicket, suite.mockRepoOutbox, suite.mockRepoNotify, unavailableGateway{suite.gateway}, suite.validate, suite.mockDb, suite.env)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.pendingSeatBooking(), nil)
+	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Payment"), suite.ginContext).Return(entity.Payment{ID: "new-id", TotalPrice: 20000}, nil)
+	suite.mockSql.ExpectRollback()
+
+	response, err := suite.service.Create(suite.ctx, request, "user-id", suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "gateway unavailable")
+	assert.Equal(suite.T(), "", response.ID)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
 }
 
 func (suite *PaymentServiceTestSuite) TestCreate_ValidationError() {
 	request := dto.PaymentRequest{
-		SeatDetailForBookingID: "",
+		SeatBookingID: "",
 	}
 
 	response, err := suite.service.Create(suite.ctx, request, "user-id", suite.ginContext)
@@ -98,16 +162,12 @@ func (suite *PaymentServiceTestSuite) TestCreate_ValidationError() {
 
 func (suite *PaymentServiceTestSuite) TestCreate_SaveError() {
 	request := dto.PaymentRequest{
-		SeatDetailForBookingID: "seat-id",
+		SeatBookingID: "booking-id",
 	}
 	saveError := errors.New("Save Error")
 
-	seatBooking := []entitySeatBooking.SeatBooking{
-		{MoviePrice: 10000},
-	}
-
 	suite.mockSql.ExpectBegin()
-	suite.mockRepoSeatBooking.On("FindAllPendingByUserID", suite.ctx, mock.Anything, "user-id", suite.ginContext).Return(seatBooking, nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.pendingSeatBooking(), nil)
 	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Payment"), suite.ginContext).Return(entity.Payment{}, saveError)
 	suite.mockSql.ExpectRollback()
 
@@ -118,26 +178,58 @@ func (suite *PaymentServiceTestSuite) TestCreate_SaveError() {
 	assert.Equal(suite.T(), saveError, err)
 }
 
+func (suite *PaymentServiceTestSuite) TestCreate_OtherUsersBooking() {
+	request := dto.PaymentRequest{
+		SeatBookingID: "booking-id",
+	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.pendingSeatBooking(), nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Create(suite.ctx, request, "someone-else", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestCreate_HoldExpired() {
+	request := dto.PaymentRequest{
+		SeatBookingID: "booking-id",
+	}
+	seatBooking := suite.pendingSeatBooking()
+	seatBooking.HoldExpiresAt = time.Now().UTC().Add(-time.Minute)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(seatBooking, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Create(suite.ctx, request, "user-id", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ConflictError{}, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // FindByID
 func (suite *PaymentServiceTestSuite) TestFindByID_Success() {
 	expectedResult := entity.Payment{
-		ID:                     "some-id",
-		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-id",
-		TotalSeat:              1,
-		TotalPrice:             10000,
+		ID:            "some-id",
+		UserID:        "user-id",
+		SeatBookingID: "booking-id",
+		TotalSeat:     1,
+		TotalPrice:    10000,
 	}
 
 	suite.mockSql.ExpectBegin()
 	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
 	suite.mockSql.ExpectCommit()
 
-	response, err := suite.service.FindByID(suite.ctx, "some-id", suite.ginContext)
+	response, err := suite.service.FindByID(suite.ctx, "some-id", "user-id", "user", suite.ginContext)
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), expectedResult.ID, response.ID)
 	assert.Equal(suite.T(), expectedResult.UserID, response.UserID)
-	assert.Equal(suite.T(), expectedResult.SeatDetailForBookingID, response.SeatDetailForBookingID)
+	assert.Equal(suite.T(), expectedResult.SeatBookingID, response.SeatBookingID)
 	assert.Equal(suite.T(), expectedResult.TotalSeat, response.TotalSeat)
 	assert.Equal(suite.T(), expectedResult.TotalPrice, response.TotalPrice)
 }
@@ -149,41 +241,54 @@ func (suite *PaymentServiceTestSuite) TestFindByID_NotFoundError() {
 	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(entity.Payment{}, notFoundError)
 	suite.mockSql.ExpectRollback()
 
-	response, err := suite.service.FindByID(suite.ctx, "some-id", suite.ginContext)
+	response, err := suite.service.FindByID(suite.ctx, "some-id", "user-id", "user", suite.ginContext)
 
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "", response.ID)
 	assert.Equal(suite.T(), notFoundError, err)
 }
 
+func (suite *PaymentServiceTestSuite) TestFindByID_OtherUserForbidden() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(entity.Payment{ID: "some-id", UserID: "user-id"}, nil)
+	suite.mockSql.ExpectRollback()
+
+	response, err := suite.service.FindByID(suite.ctx, "some-id", "someone-else", "user", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+	assert.Equal(suite.T(), "", response.ID)
+}
+
 // FindAll
 func (suite *PaymentServiceTestSuite) TestFindAll_Success() {
 	expectedResults := []entity.Payment{
-		{ID: "id1", UserID: "user1", SeatDetailForBookingID: "seat1", TotalSeat: 1, TotalPrice: 10000},
-		{ID: "id2", UserID: "user2", SeatDetailForBookingID: "seat2", TotalSeat: 2, TotalPrice: 20000},
+		{ID: "id1", UserID: "user1", SeatBookingID: "booking1", TotalSeat: 1, TotalPrice: 10000},
+		{ID: "id2", UserID: "user2", SeatBookingID: "booking2", TotalSeat: 2, TotalPrice: 20000},
 	}
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, suite.ginContext).Return(expectedResults, nil)
+	listQuery := helper.ListQuery{Page: 2, Size: 2}
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, listQuery, suite.ginContext).Return(expectedResults, 5, nil)
 	suite.mockSql.ExpectCommit()
 
-	response, err := suite.service.FindAll(suite.ctx, suite.ginContext)
+	response, paging, err := suite.service.FindAll(suite.ctx, listQuery, suite.ginContext)
 
 	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), web.Paging{Page: 2, Size: 2, TotalData: 5, TotalPages: 3}, paging)
 	assert.Len(suite.T(), response, 2)
 	assert.Equal(suite.T(), expectedResults[0].ID, response[0].ID)
 	assert.Equal(suite.T(), expectedResults[0].UserID, response[0].UserID)
-	assert.Equal(suite.T(), expectedResults[0].SeatDetailForBookingID, response[0].SeatDetailForBookingID)
+	assert.Equal(suite.T(), expectedResults[0].SeatBookingID, response[0].SeatBookingID)
 }
 
 func (suite *PaymentServiceTestSuite) TestFindAll_Error() {
 	findAllError := errors.New("Find All Error")
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, suite.ginContext).Return([]entity.Payment{}, findAllError)
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, mock.Anything, suite.ginContext).Return([]entity.Payment{}, 0, findAllError)
 	suite.mockSql.ExpectRollback()
 
-	response, err := suite.service.FindAll(suite.ctx, suite.ginContext)
+	response, _, err := suite.service.FindAll(suite.ctx, helper.ListQuery{Page: 1, Size: 10}, suite.ginContext)
 
 	assert.Error(suite.T(), err)
 	assert.Len(suite.T(), response, 0)
@@ -191,78 +296,556 @@ func (suite *PaymentServiceTestSuite) TestFindAll_Error() {
 }
 
 func (suite *PaymentServiceTestSuite) TestUpdate_Success() {
-	notificationPayload := map[string]interface{}{
-		"order_id":           "some-id",
-		"transaction_status": "settlement",
-	}
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "settlement", 10000)
 	expectedResult := entity.Payment{
-		ID:                     "some-id",
-		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-id",
-		Status:                 "paid",
+		ID:            "some-id",
+		UserID:        "user-id",
+		SeatBookingID: "booking-id",
+		TotalPrice:    10000,
+		Status:        "unpaid",
 	}
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
-	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Payment"), suite.ginContext).Return(expectedResult, nil)
-	suite.mockRepoSeatBooking.On("Update", suite.ctx, mock.Anything, mock.AnythingOfType("entitySeatBooking.SeatBooking"), suite.ginContext).Return(nil, nil)
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.MatchedBy(func(e entity.PaymentEvent) bool {
+		return e.PaymentID == "some-id" && e.TransactionID == notification.TransactionID && e.TransactionStatus == "settlement"
+	}), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "paid" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.paid", dtoWebhook.PaymentData{
+		ID: "some-id", UserID: "user-id", SeatBookingID: "booking-id", Status: "paid", PreviousStatus: "unpaid", TotalPrice: 10000,
+	}, suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool {
+		return n.UserID == "user-id" && n.Kind == entityNotification.KindBookingConfirmed && n.Data.PaymentID == "some-id" && len(n.Data.Seats) == 2 && n.Data.Seats[0] == "A1"
+	}), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("Update", suite.ctx, mock.Anything, entitySeatBooking.SeatBooking{ID: "booking-id", SeatBookingStatus: "success"}, suite.ginContext).Return(entitySeatBooking.SeatBooking{}, nil)
+	suite.mockRepoTicket.On("Issue", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(nil)
 	suite.mockSql.ExpectCommit()
 
-	suite.service.Update(suite.ctx, notificationPayload, suite.ginContext)
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertExpectations(suite.T())
+	suite.mockRepoTicket.AssertExpectations(suite.T())
+	suite.mockRepoOutbox.AssertExpectations(suite.T())
+	suite.mockRepoNotify.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
 }
 
 func (suite *PaymentServiceTestSuite) TestUpdate_DenyStatus() {
-	notificationPayload := map[string]interface{}{
-		"order_id":           "some-id",
-		"transaction_status": "deny",
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "deny", 10000)
+	expectedResult := entity.Payment{
+		ID:            "some-id",
+		SeatBookingID: "booking-id",
+		TotalPrice:    10000,
+		Status:        "unpaid",
 	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "failed" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.failed", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool {
+		return n.Kind == entityNotification.KindBookingCancelled
+	}), suite.ginContext).Return(nil)
+	suite.mockRepoTicket.On("Void", suite.ctx, mock.Anything, "some-id", []string(nil), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("Delete", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return([]entity.FailedPayment{}, nil)
+	suite.mockSql.ExpectCommit()
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertExpectations(suite.T())
+	suite.mockRepoNotify.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_ExpireStatus() {
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "expire", 10000)
 	expectedResult := entity.Payment{
-		ID:                     "some-id",
-		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-id",
-		Status:                 "cancelled",
+		ID:            "some-id",
+		UserID:        "user-id",
+		SeatBookingID: "booking-id",
+		TotalPrice:    10000,
+		Status:        "pending",
 	}
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
-	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Payment"), suite.ginContext).Return(expectedResult, nil)
-	suite.mockRepoSeatBooking.On("Update", suite.ctx, mock.Anything, mock.AnythingOfType("entitySeatBooking.SeatBooking"), suite.ginContext).Return(nil, nil)
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "failed" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.failed", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool {
+		return n.Kind == entityNotification.KindBookingExpired && n.Data.RefundedAmount == 0
+	}), suite.ginContext).Return(nil)
+	suite.mockRepoTicket.On("Void", suite.ctx, mock.Anything, "some-id", []string(nil), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("Delete", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return([]entity.FailedPayment{}, nil)
 	suite.mockSql.ExpectCommit()
 
-	suite.service.Update(suite.ctx, notificationPayload, suite.ginContext)
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepoNotify.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
 }
 
 func (suite *PaymentServiceTestSuite) TestUpdate_PendingStatus() {
-	notificationPayload := map[string]interface{}{
-		"order_id":           "some-id",
-		"transaction_status": "pending",
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "pending", 10000)
+	expectedResult := entity.Payment{
+		ID:         "some-id",
+		TotalPrice: 10000,
+		Status:     "unpaid",
 	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "pending" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.pending", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepoSeatBooking.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepoNotify.AssertNotCalled(suite.T(), "Queue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_DuplicateNotification() {
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "settlement", 10000)
 	expectedResult := entity.Payment{
-		ID:                     "some-id",
-		UserID:                 "user-id",
-		SeatDetailForBookingID: "seat-id",
-		Status:                 "pending",
+		ID:            "some-id",
+		SeatBookingID: "booking-id",
+		TotalPrice:    10000,
+		Status:        "paid",
 	}
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
-	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Payment"), suite.ginContext).Return(expectedResult, nil)
-	suite.mockRepoSeatBooking.On("Update", suite.ctx, mock.Anything, mock.AnythingOfType("entitySeatBooking.SeatBooking"), suite.ginContext).Return(nil, nil)
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(false, nil)
 	suite.mockSql.ExpectCommit()
 
-	suite.service.Update(suite.ctx, notificationPayload, suite.ginContext)
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepoSeatBooking.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
 }
 
-func (suite *PaymentServiceTestSuite) TestUpdate_NotFound() {
-	notificationPayload := map[string]interface{}{
-		"order_id":           "some-id",
-		"transaction_status": "settlement",
+func (suite *PaymentServiceTestSuite) TestUpdate_SettlementAfterFailure() {
+	_, err := suite.gateway.CreateCharge(suite.ctx, gateway.ChargeRequest{OrderID: "some-id", GrossAmount: 10000})
+	assert.NoError(suite.T(), err)
+	notification, err := suite.gateway.Settle("some-id", "settlement")
+	assert.NoError(suite.T(), err)
+	expectedResult := entity.Payment{
+		ID:            "some-id",
+		UserID:        "user-id",
+		SeatBookingID: "booking-id",
+		TotalPrice:    10000,
+		Status:        "failed",
+	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("SaveRefund", suite.ctx, mock.Anything, entity.Refund{PaymentID: "some-id", Amount: 10000, Reason: "paid after the booking expired"}, suite.ginContext).
+		Return(entity.Refund{ID: "refund-id", PaymentID: "some-id", Amount: 10000, Reason: "paid after the booking expired"}, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "refunded" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.refunded", mock.MatchedBy(func(data dtoWebhook.PaymentData) bool {
+		return data.PreviousStatus == "failed" && data.RefundedAmount == 10000
+	}), suite.ginContext).Return(nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool {
+		return n.Kind == entityNotification.KindLatePaymentRefunded && n.Data.RefundedAmount == 10000
+	}), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	err = suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoOutbox.AssertExpectations(suite.T())
+	suite.mockRepoNotify.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepoTicket.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+
+	status, err := suite.gateway.Status(suite.ctx, "some-id")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "refund", status.TransactionStatus)
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_CaptureAndSettlementAfterFailureRefundOnce() {
+	provider := &countingGateway{FakeGateway: suite.gateway}
+	suite.service = NewPaymentService(suite.mockRepo, suite.mockRepoSeatBooking, suite.mockRepoTicket, suite.mockRepoOutbox, suite.mockRepoNotify, provider, suite.validate, suite.mockDb, suite.env)
+
+	_, err := suite.gateway.CreateCharge(suite.ctx, gateway.ChargeRequest{OrderID: "some-id", GrossAmount: 10000})
+	assert.NoError(suite.T(), err)
+	capture, err := suite.gateway.Settle("some-id", "capture")
+	assert.NoError(suite.T(), err)
+	settlement, err := suite.gateway.Settle("some-id", "settlement")
+	assert.NoError(suite.T(), err)
+
+	failed := entity.Payment{ID: "some-id", UserID: "user-id", SeatBookingID: "booking-id", TotalPrice: 10000, Status: "failed"}
+	refunded := failed
+	refunded.Status = "refunded"
+
+	// The row lock makes the settlement read the payment the capture refunded.
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectCommit()
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectCommit()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(failed, nil).Once()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(refunded, nil).Once()
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("SaveRefund", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Refund"), suite.ginContext).
+		Return(entity.Refund{ID: "refund-id", PaymentID: "some-id", Amount: 10000, Reason: "paid after the booking expired"}, nil).Once()
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "refunded" }), suite.ginContext).Return(refunded, nil).Once()
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.refunded", mock.Anything, suite.ginContext).Return(nil).Once()
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.Anything, suite.ginContext).Return(nil).Once()
+
+	assert.NoError(suite.T(), suite.service.Update(suite.ctx, capture, suite.ginContext))
+	assert.NoError(suite.T(), suite.service.Update(suite.ctx, settlement, suite.ginContext))
+
+	assert.Equal(suite.T(), 1, provider.refunds)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoOutbox.AssertExpectations(suite.T())
+	suite.mockRepoTicket.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_CaptureChallenge() {
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "capture", 10000)
+	notification.FraudStatus = "challenge"
+	expectedResult := entity.Payment{
+		ID:         "some-id",
+		TotalPrice: 10000,
+		Status:     "unpaid",
 	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "pending" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.pending", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_RefundReleasesSeats() {
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "refund", 10000)
+	expectedResult := entity.Payment{
+		ID:            "some-id",
+		SeatBookingID: "booking-id",
+		TotalPrice:    10000,
+		Status:        "paid",
+	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "refunded" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.refunded", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool {
+		return n.Kind == entityNotification.KindBookingRefunded && n.Data.RefundedAmount == 10000
+	}), suite.ginContext).Return(nil)
+	suite.mockRepoTicket.On("Void", suite.ctx, mock.Anything, "some-id", []string(nil), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("Delete", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return([]entity.FailedPayment{}, nil)
+	suite.mockSql.ExpectCommit()
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertExpectations(suite.T())
+	suite.mockRepoTicket.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_PartialRefundKeepsSeats() {
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "partial_refund", 10000)
+	expectedResult := entity.Payment{
+		ID:            "some-id",
+		SeatBookingID: "booking-id",
+		TotalPrice:    10000,
+		Status:        "paid",
+	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepo.On("SaveEvent", suite.ctx, mock.Anything, mock.AnythingOfType("entity.PaymentEvent"), suite.ginContext).Return(true, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "partially_refunded" }), suite.ginContext).Return(expectedResult, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.partially_refunded", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepoNotify.AssertNotCalled(suite.T(), "Queue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_NotFound() {
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "settlement", 10000)
 	notFoundError := errors.New("Not Found Error")
 
 	suite.mockSql.ExpectBegin()
-	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(entity.Payment{}, notFoundError)
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(entity.Payment{}, notFoundError)
+	suite.mockSql.ExpectRollback()
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.Equal(suite.T(), notFoundError, err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_InvalidSignature() {
+	sender := notificationmock.NewFakeNotificationSender("attacker-key", nil)
+	notification := sender.Notification("some-id", "settlement", 10000)
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_UnsignedNotification() {
+	notification := dto.PaymentNotificationRequest{
+		OrderID:           "some-id",
+		TransactionStatus: "settlement",
+		StatusCode:        "200",
+		GrossAmount:       "10000.00",
+	}
+
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestUpdate_GrossAmountMismatch() {
+	sender := notificationmock.NewFakeNotificationSender(suite.env.MIDTRANS_SERVER_KEY, nil)
+	notification := sender.Notification("some-id", "settlement", 1)
+	expectedResult := entity.Payment{
+		ID:         "some-id",
+		TotalPrice: 10000,
+		Status:     "unpaid",
+	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(expectedResult, nil)
 	suite.mockSql.ExpectRollback()
 
-	suite.service.Update(suite.ctx, notificationPayload, suite.ginContext)
+	err := suite.service.Update(suite.ctx, notification, suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+// Refund
+func (suite *PaymentServiceTestSuite) paidPayment(showStart time.Time) entity.Payment {
+	_, err := suite.gateway.CreateCharge(suite.ctx, gateway.ChargeRequest{OrderID: "some-id", GrossAmount: 20000})
+	assert.NoError(suite.T(), err)
+	_, err = suite.gateway.Settle("some-id", "settlement")
+	assert.NoError(suite.T(), err)
+
+	return entity.Payment{
+		ID:            "some-id",
+		UserID:        "user-id",
+		SeatBookingID: "booking-id",
+		ShowStart:     showStart,
+		TotalSeat:     2,
+		TotalPrice:    20000,
+		Status:        "paid",
+	}
+}
+
+func (suite *PaymentServiceTestSuite) paidSeatBooking() entitySeatBooking.SeatBooking {
+	return entitySeatBooking.SeatBooking{
+		ID:                "booking-id",
+		UserID:            "user-id",
+		SeatBookingStatus: "success",
+		MoviePrice:        10000,
+		Seats:             []entitySeatBooking.SeatDetail{{ID: "detail-1", SeatID: "seat-1", SeatName: "A1", UnitPrice: 10000}, {ID: "detail-2", SeatID: "seat-2", SeatName: "A2", UnitPrice: 10000}},
+	}
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_PartialByOwner() {
+	payment := suite.paidPayment(time.Now().Add(24 * time.Hour))
+	request := dto.RefundRequest{SeatIDs: []string{"seat-2"}, Reason: "friend cancelled"}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(payment, nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepo.On("SaveRefund", suite.ctx, mock.Anything, entity.Refund{PaymentID: "some-id", Amount: 10000, Reason: "friend cancelled", RefundedBy: "user-id", SeatIDs: []string{"seat-2"}}, suite.ginContext).
+		Return(entity.Refund{ID: "refund-id", PaymentID: "some-id", Amount: 10000, Reason: "friend cancelled"}, nil)
+	suite.mockRepoTicket.On("Void", suite.ctx, mock.Anything, "some-id", []string{"seat-2"}, suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("DeleteSeats", suite.ctx, mock.Anything, "booking-id", []string{"seat-2"}, suite.ginContext).Return(nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "partially_refunded" }), suite.ginContext).Return(payment, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.partially_refunded", mock.MatchedBy(func(data dtoWebhook.PaymentData) bool {
+		return data.PreviousStatus == "paid" && data.RefundedAmount == 10000 && len(data.RefundedSeatIDs) == 1 && data.RefundedSeatIDs[0] == "seat-2"
+	}), suite.ginContext).Return(nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool {
+		return n.Kind == entityNotification.KindBookingRefunded && n.Data.RefundedAmount == 10000 && len(n.Data.Seats) == 1 && n.Data.Seats[0] == "A2"
+	}), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Refund(suite.ctx, "some-id", request, "user-id", "user", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "refund-id", response.ID)
+	assert.Equal(suite.T(), 10000, response.Amount)
+	assert.Equal(suite.T(), "partially_refunded", response.PaymentStatus)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeatBooking.AssertExpectations(suite.T())
+	suite.mockRepoTicket.AssertExpectations(suite.T())
+	suite.mockRepoOutbox.AssertExpectations(suite.T())
+	suite.mockRepoNotify.AssertExpectations(suite.T())
+
+	status, err := suite.gateway.Status(suite.ctx, "some-id")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "partial_refund", status.TransactionStatus)
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_FullByAdminAfterCutoff() {
+	payment := suite.paidPayment(time.Now().Add(10 * time.Minute))
+	request := dto.RefundRequest{Reason: "projector broken"}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(payment, nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepo.On("SaveRefund", suite.ctx, mock.Anything, mock.MatchedBy(func(r entity.Refund) bool { return r.Amount == 20000 && len(r.SeatIDs) == 2 }), suite.ginContext).
+		Return(entity.Refund{ID: "refund-id", PaymentID: "some-id", Amount: 20000, Reason: "projector broken"}, nil)
+	suite.mockRepoTicket.On("Void", suite.ctx, mock.Anything, "some-id", mock.MatchedBy(func(seatIDs []string) bool { return len(seatIDs) == 2 }), suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("Delete", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return([]entity.FailedPayment{}, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(p entity.Payment) bool { return p.Status == "refunded" }), suite.ginContext).Return(payment, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.refunded", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool {
+		return n.Kind == entityNotification.KindBookingRefunded && n.Data.RefundedAmount == 20000 && len(n.Data.Seats) == 2
+	}), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Refund(suite.ctx, "some-id", request, "admin-id", "admin", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 20000, response.Amount)
+	assert.Equal(suite.T(), "refunded", response.PaymentStatus)
+	suite.mockRepoSeatBooking.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_OwnerAfterCutoff() {
+	payment := suite.paidPayment(time.Now().Add(10 * time.Minute))
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(payment, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Refund(suite.ctx, "some-id", dto.RefundRequest{Reason: "late"}, "user-id", "user", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "SaveRefund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_OtherUser() {
+	payment := suite.paidPayment(time.Now().Add(24 * time.Hour))
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(payment, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Refund(suite.ctx, "some-id", dto.RefundRequest{Reason: "mine now"}, "someone-else", "user", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_NotPaid() {
+	payment := suite.paidPayment(time.Now().Add(24 * time.Hour))
+	payment.Status = "unpaid"
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(payment, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Refund(suite.ctx, "some-id", dto.RefundRequest{Reason: "changed mind"}, "user-id", "user", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ConflictError{}, err)
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_SeatNotInPayment() {
+	payment := suite.paidPayment(time.Now().Add(24 * time.Hour))
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(payment, nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Refund(suite.ctx, "some-id", dto.RefundRequest{SeatIDs: []string{"seat-9"}, Reason: "wrong seat"}, "user-id", "user", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ValidationError{}, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "SaveRefund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_GatewayError() {
+	payment := suite.paidPayment(time.Now().Add(24 * time.Hour))
+	payment.ID = "unknown-to-gateway"
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "unknown-to-gateway", suite.ginContext).Return(payment, nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepo.On("SaveRefund", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Refund"), suite.ginContext).Return(entity.Refund{ID: "refund-id", Amount: 20000}, nil)
+	suite.mockRepoTicket.On("Void", suite.ctx, mock.Anything, "unknown-to-gateway", mock.Anything, suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("Delete", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return([]entity.FailedPayment{}, nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Payment"), suite.ginContext).Return(payment, nil)
+	suite.mockRepoOutbox.On("Publish", suite.ctx, mock.Anything, "payment.refunded", mock.AnythingOfType("dto.PaymentData"), suite.ginContext).Return(nil)
+	suite.mockRepoNotify.On("Queue", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Notification"), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Refund(suite.ctx, "unknown-to-gateway", dto.RefundRequest{Reason: "sick"}, "user-id", "user", suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "transaction not found")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PaymentServiceTestSuite) TestRefund_WriteErrorSkipsGateway() {
+	payment := suite.paidPayment(time.Now().Add(24 * time.Hour))
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByIDForUpdate", suite.ctx, mock.Anything, "some-id", suite.ginContext).Return(payment, nil)
+	suite.mockRepoSeatBooking.On("FindByID", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return(suite.paidSeatBooking(), nil)
+	suite.mockRepo.On("SaveRefund", suite.ctx, mock.Anything, mock.AnythingOfType("entity.Refund"), suite.ginContext).Return(entity.Refund{ID: "refund-id", Amount: 20000}, nil)
+	suite.mockRepoTicket.On("Void", suite.ctx, mock.Anything, "some-id", mock.Anything, suite.ginContext).Return(nil)
+	suite.mockRepoSeatBooking.On("Delete", suite.ctx, mock.Anything, "booking-id", suite.ginContext).Return([]entity.FailedPayment{}, errors.New("database error"))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Refund(suite.ctx, "some-id", dto.RefundRequest{Reason: "sick"}, "user-id", "user", suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "database error")
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+
+	status, err := suite.gateway.Status(suite.ctx, "some-id")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "settlement", status.TransactionStatus)
 }
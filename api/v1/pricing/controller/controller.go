@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type PricingController interface {
+	CreateRule(c *gin.Context)
+	FindAllRules(c *gin.Context)
+	DeleteRule(c *gin.Context)
+	CreateHoliday(c *gin.Context)
+	FindAllHolidays(c *gin.Context)
+	DeleteHoliday(c *gin.Context)
+	Quote(c *gin.Context)
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"bioskuy/api/v1/pricing/dto"
+	"bioskuy/api/v1/pricing/service"
+	"bioskuy/exception"
+	"bioskuy/web"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type pricingControllerImpl struct {
+	pricingService service.PricingService
+}
+
+func NewPricingController(pricingService service.PricingService) PricingController {
+	return &pricingControllerImpl{pricingService: pricingService}
+}
+
+func (ctl *pricingControllerImpl) CreateRule(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.CreateRuleRequest{}
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, err := ctl.pricingService.CreateRule(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusCreated, web.FormatResponse{ResponseCode: http.StatusCreated, Data: result})
+}
+
+func (ctl *pricingControllerImpl) FindAllRules(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.pricingService.FindAllRules(ctx, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *pricingControllerImpl) DeleteRule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := ctl.pricingService.DeleteRule(ctx, c.Param("ruleId"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: "OK"})
+}
+
+func (ctl *pricingControllerImpl) CreateHoliday(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.HolidayRequest{}
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, err := ctl.pricingService.CreateHoliday(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusCreated, web.FormatResponse{ResponseCode: http.StatusCreated, Data: result})
+}
+
+func (ctl *pricingControllerImpl) FindAllHolidays(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.pricingService.FindAllHolidays(ctx, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *pricingControllerImpl) DeleteHoliday(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := ctl.pricingService.DeleteHoliday(ctx, c.Param("date"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: "OK"})
+}
+
+func (ctl *pricingControllerImpl) Quote(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.pricingService.Quote(ctx, c.Param("showtimeId"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"bioskuy/api/v1/pricing/dto"
+	"bioskuy/api/v1/pricing/mock/servicemock"
+	"bioskuy/exception"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PricingControllerTestSuite struct {
+	suite.Suite
+	mockService *servicemock.MockPricingService
+	controller  PricingController
+	router      *gin.Engine
+}
+
+func (suite *PricingControllerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockService = new(servicemock.MockPricingService)
+	suite.controller = NewPricingController(suite.mockService)
+	suite.router = gin.New()
+	suite.router.Use(exception.ErrorHandler)
+
+	suite.router.POST("/pricing/rules", suite.controller.CreateRule)
+	suite.router.DELETE("/pricing/rules/:ruleId", suite.controller.DeleteRule)
+	suite.router.GET("/pricing/showtimes/:showtimeId", suite.controller.Quote)
+}
+
+func TestPricingControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(PricingControllerTestSuite))
+}
+
+func (suite *PricingControllerTestSuite) TestCreateRule_Success() {
+	request := dto.CreateRuleRequest{Name: "weekend", AdjustmentPercent: 20}
+	suite.mockService.On("CreateRule", mock.Anything, request, mock.Anything).Return(dto.RuleResponse{ID: "rule-1", Name: "weekend", AdjustmentPercent: 20, Active: true}, nil)
+
+	payload, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/pricing/rules", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"id":"rule-1"`)
+}
+
+func (suite *PricingControllerTestSuite) TestDeleteRule_NotFound() {
+	suite.mockService.On("DeleteRule", mock.Anything, "missing", mock.Anything).Return(errors.New("pricing rule not found")).Run(func(args mock.Arguments) {
+		c := args.Get(2).(*gin.Context)
+		c.Error(exception.NotFoundError{Message: "pricing rule not found"}).SetType(gin.ErrorTypePublic)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/pricing/rules/missing", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *PricingControllerTestSuite) TestQuote_Success() {
+	quote := dto.QuoteResponse{ShowtimeID: "showtime-1", BasePrice: 40000, Prices: map[string]int{"regular": 40000, "vip": 80000}}
+	suite.mockService.On("Quote", mock.Anything, "showtime-1", mock.Anything).Return(quote, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/pricing/showtimes/showtime-1", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"vip":80000`)
+}
@@ -0,0 +1,48 @@
+package dto
+
+type CreateRuleRequest struct {
+	Name              string `json:"name" validate:"required"`
+	DayOfWeek         *int   `json:"day_of_week" validate:"omitempty,min=0,max=6"`
+	StartTime         string `json:"start_time" validate:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime           string `json:"end_time" validate:"required_with=StartTime,omitempty,datetime=15:04"`
+	Holiday           *bool  `json:"holiday"`
+	StudioClass       string `json:"studio_class"`
+	SeatCategory      string `json:"seat_category"`
+	AdjustmentPercent int    `json:"adjustment_percent" validate:"min=-100"`
+	AdjustmentAmount  int    `json:"adjustment_amount"`
+	Priority          int    `json:"priority"`
+}
+
+type RuleResponse struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	DayOfWeek         *int   `json:"day_of_week"`
+	StartTime         string `json:"start_time,omitempty"`
+	EndTime           string `json:"end_time,omitempty"`
+	Holiday           *bool  `json:"holiday"`
+	StudioClass       string `json:"studio_class,omitempty"`
+	SeatCategory      string `json:"seat_category,omitempty"`
+	AdjustmentPercent int    `json:"adjustment_percent"`
+	AdjustmentAmount  int    `json:"adjustment_amount"`
+	Priority          int    `json:"priority"`
+	Active            bool   `json:"active"`
+}
+
+type HolidayRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name string `json:"name" validate:"required"`
+}
+
+type HolidayResponse struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// QuoteResponse is the unit price of each seat category at one showtime.
+type QuoteResponse struct {
+	ShowtimeID  string         `json:"showtime_id"`
+	BasePrice   int            `json:"base_price"`
+	Holiday     bool           `json:"holiday"`
+	StudioClass string         `json:"studio_class"`
+	Prices      map[string]int `json:"prices"`
+}
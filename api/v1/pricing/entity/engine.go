@@ -0,0 +1,63 @@
+package entity
+
+import "sort"
+
+// Matches reports whether every condition set on the rule holds for the slot.
+func (r Rule) Matches(slot Slot) bool {
+	if !r.Active {
+		return false
+	}
+	if r.DayOfWeek != nil && *r.DayOfWeek != int(slot.ShowStart.Weekday()) {
+		return false
+	}
+	if r.StartTime != "" && r.EndTime != "" && !inWindow(slot.ShowStart.Format("15:04"), r.StartTime, r.EndTime) {
+		return false
+	}
+	if r.Holiday != nil && *r.Holiday != slot.Holiday {
+		return false
+	}
+	if r.StudioClass != "" && r.StudioClass != slot.StudioClass {
+		return false
+	}
+	if r.SeatCategory != "" && r.SeatCategory != slot.SeatCategory {
+		return false
+	}
+	return true
+}
+
+// Apply adjusts price by the rule's percentage and then its fixed amount.
+func (r Rule) Apply(price int) int {
+	return price*(100+r.AdjustmentPercent)/100 + r.AdjustmentAmount
+}
+
+// Price runs the matching rules over the slot's base price in ascending
+// priority. Rules of equal priority keep the order they were given in. The
+// result never drops below zero.
+func Price(rules []Rule, slot Slot) int {
+	ordered := make([]Rule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority < ordered[j].Priority
+	})
+
+	price := slot.BasePrice
+	for _, rule := range ordered {
+		if rule.Matches(slot) {
+			price = rule.Apply(price)
+		}
+	}
+
+	if price < 0 {
+		return 0
+	}
+	return price
+}
+
+// inWindow compares zero-padded "15:04" strings, which order like the times
+// they hold.
+func inWindow(at, start, end string) bool {
+	if start <= end {
+		return at >= start && at < end
+	}
+	return at >= start || at < end
+}
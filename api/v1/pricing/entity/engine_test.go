@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int    { return &i }
+func boolPtr(b bool) *bool { return &b }
+
+func TestPrice_AppliesMatchingRulesByPriority(t *testing.T) {
+	// Saturday 13:00
+	slot := Slot{
+		BasePrice:    50000,
+		ShowStart:    time.Date(2024, 7, 27, 13, 0, 0, 0, time.UTC),
+		StudioClass:  "regular",
+		SeatCategory: "vip",
+	}
+	rules := []Rule{
+		{Name: "vip surcharge", SeatCategory: "vip", AdjustmentAmount: 20000, Priority: 2, Active: true},
+		{Name: "weekend", DayOfWeek: intPtr(6), AdjustmentPercent: 20, Priority: 1, Active: true},
+		{Name: "matinee", StartTime: "10:00", EndTime: "12:00", AdjustmentPercent: -30, Active: true},
+		{Name: "premium studio", StudioClass: "premium", AdjustmentAmount: 30000, Active: true},
+		{Name: "disabled", AdjustmentAmount: 99999, Active: false},
+	}
+
+	assert.Equal(t, 80000, Price(rules, slot))
+}
+
+func TestPrice_HolidayAndClamp(t *testing.T) {
+	slot := Slot{BasePrice: 10000, ShowStart: time.Date(2024, 8, 17, 23, 30, 0, 0, time.UTC), Holiday: true}
+	rules := []Rule{
+		{Name: "late night", StartTime: "22:00", EndTime: "02:00", AdjustmentAmount: -15000, Active: true},
+		{Name: "not a holiday", Holiday: boolPtr(false), AdjustmentAmount: 5000, Active: true},
+	}
+
+	assert.Equal(t, 0, Price(rules, slot))
+}
+
+func TestRule_Matches(t *testing.T) {
+	slot := Slot{ShowStart: time.Date(2024, 7, 22, 12, 0, 0, 0, time.UTC), Holiday: true, SeatCategory: "regular"}
+
+	assert.True(t, Rule{Active: true}.Matches(slot))
+	assert.True(t, Rule{Active: true, Holiday: boolPtr(true)}.Matches(slot))
+	assert.True(t, Rule{Active: true, StartTime: "11:00", EndTime: "12:01"}.Matches(slot))
+	assert.False(t, Rule{Active: true, StartTime: "10:00", EndTime: "12:00"}.Matches(slot))
+	assert.False(t, Rule{Active: true, DayOfWeek: intPtr(0)}.Matches(slot))
+	assert.False(t, Rule{Active: true, SeatCategory: "vip"}.Matches(slot))
+}
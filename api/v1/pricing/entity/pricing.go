@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// Rule adjusts the base movie price for the seats it matches. Condition
+// fields left empty (nil or "") match anything.
+type Rule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// DayOfWeek follows time.Weekday: 0 is Sunday.
+	DayOfWeek *int `json:"day_of_week"`
+	// StartTime and EndTime are "15:04" local times bounding the show start,
+	// end exclusive. A window with EndTime before StartTime wraps midnight.
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+	Holiday      *bool  `json:"holiday"`
+	StudioClass  string `json:"studio_class"`
+	SeatCategory string `json:"seat_category"`
+
+	AdjustmentPercent int  `json:"adjustment_percent"`
+	AdjustmentAmount  int  `json:"adjustment_amount"`
+	Priority          int  `json:"priority"`
+	Active            bool `json:"active"`
+}
+
+type Holiday struct {
+	// Date is formatted as time.DateOnly.
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// Slot is everything a price depends on: one seat category at one screening.
+type Slot struct {
+	BasePrice int
+	// ShowStart is in the cinema's timezone.
+	ShowStart    time.Time
+	Holiday      bool
+	StudioClass  string
+	SeatCategory string
+}
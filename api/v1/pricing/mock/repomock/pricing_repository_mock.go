@@ -0,0 +1,49 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/pricing/entity"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPricingRepository struct {
+	mock.Mock
+}
+
+func (m *MockPricingRepository) SaveRule(ctx context.Context, tx *sql.Tx, rule entity.Rule, c *gin.Context) (entity.Rule, error) {
+	args := m.Called(ctx, tx, rule, c)
+	return args.Get(0).(entity.Rule), args.Error(1)
+}
+
+func (m *MockPricingRepository) FindAllRules(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Rule, error) {
+	args := m.Called(ctx, tx, c)
+	return args.Get(0).([]entity.Rule), args.Error(1)
+}
+
+func (m *MockPricingRepository) DeleteRule(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
+
+func (m *MockPricingRepository) SaveHoliday(ctx context.Context, tx *sql.Tx, holiday entity.Holiday, c *gin.Context) (entity.Holiday, error) {
+	args := m.Called(ctx, tx, holiday, c)
+	return args.Get(0).(entity.Holiday), args.Error(1)
+}
+
+func (m *MockPricingRepository) FindAllHolidays(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Holiday, error) {
+	args := m.Called(ctx, tx, c)
+	return args.Get(0).([]entity.Holiday), args.Error(1)
+}
+
+func (m *MockPricingRepository) DeleteHoliday(ctx context.Context, tx *sql.Tx, date string, c *gin.Context) error {
+	args := m.Called(ctx, tx, date, c)
+	return args.Error(0)
+}
+
+func (m *MockPricingRepository) FindSlot(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (entity.Slot, error) {
+	args := m.Called(ctx, tx, showtimeID, c)
+	return args.Get(0).(entity.Slot), args.Error(1)
+}
@@ -0,0 +1,48 @@
+package servicemock
+
+import (
+	"bioskuy/api/v1/pricing/dto"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPricingService struct {
+	mock.Mock
+}
+
+func (m *MockPricingService) CreateRule(ctx context.Context, request dto.CreateRuleRequest, c *gin.Context) (dto.RuleResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.RuleResponse), args.Error(1)
+}
+
+func (m *MockPricingService) FindAllRules(ctx context.Context, c *gin.Context) ([]dto.RuleResponse, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).([]dto.RuleResponse), args.Error(1)
+}
+
+func (m *MockPricingService) DeleteRule(ctx context.Context, id string, c *gin.Context) error {
+	args := m.Called(ctx, id, c)
+	return args.Error(0)
+}
+
+func (m *MockPricingService) CreateHoliday(ctx context.Context, request dto.HolidayRequest, c *gin.Context) (dto.HolidayResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.HolidayResponse), args.Error(1)
+}
+
+func (m *MockPricingService) FindAllHolidays(ctx context.Context, c *gin.Context) ([]dto.HolidayResponse, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).([]dto.HolidayResponse), args.Error(1)
+}
+
+func (m *MockPricingService) DeleteHoliday(ctx context.Context, date string, c *gin.Context) error {
+	args := m.Called(ctx, date, c)
+	return args.Error(0)
+}
+
+func (m *MockPricingService) Quote(ctx context.Context, showtimeID string, c *gin.Context) (dto.QuoteResponse, error) {
+	args := m.Called(ctx, showtimeID, c)
+	return args.Get(0).(dto.QuoteResponse), args.Error(1)
+}
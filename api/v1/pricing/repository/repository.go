@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"bioskuy/api/v1/pricing/entity"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PricingRepository interface {
+	SaveRule(ctx context.Context, tx *sql.Tx, rule entity.Rule, c *gin.Context) (entity.Rule, error)
+	FindAllRules(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Rule, error)
+	DeleteRule(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	SaveHoliday(ctx context.Context, tx *sql.Tx, holiday entity.Holiday, c *gin.Context) (entity.Holiday, error)
+	FindAllHolidays(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Holiday, error)
+	DeleteHoliday(ctx context.Context, tx *sql.Tx, date string, c *gin.Context) error
+	FindSlot(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (entity.Slot, error)
+}
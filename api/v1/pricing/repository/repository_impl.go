@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"bioskuy/api/v1/pricing/entity"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type pricingRepository struct {
+}
+
+func NewPricingRepository() PricingRepository {
+	return &pricingRepository{}
+}
+
+func (r *pricingRepository) SaveRule(ctx context.Context, tx *sql.Tx, rule entity.Rule, c *gin.Context) (entity.Rule, error) {
+	query := `INSERT INTO pricing_rules
+		(name, day_of_week, start_time, end_time, holiday, studio_class, seat_category, adjustment_percent, adjustment_amount, priority)
+		VALUES ($1, $2, NULLIF($3, '')::time, NULLIF($4, '')::time, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10)
+		RETURNING id, active`
+
+	err := tx.QueryRowContext(ctx, query,
+		rule.Name, rule.DayOfWeek, rule.StartTime, rule.EndTime, rule.Holiday, rule.StudioClass, rule.SeatCategory,
+		rule.AdjustmentPercent, rule.AdjustmentAmount, rule.Priority,
+	).Scan(&rule.ID, &rule.Active)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return rule, err
+	}
+
+	return rule, nil
+}
+
+func (r *pricingRepository) FindAllRules(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Rule, error) {
+	query := `SELECT id, name, day_of_week,
+			COALESCE(to_char(start_time, 'HH24:MI'), ''), COALESCE(to_char(end_time, 'HH24:MI'), ''),
+			holiday, COALESCE(studio_class, ''), COALESCE(seat_category, ''),
+			adjustment_percent, adjustment_amount, priority, active
+		FROM pricing_rules
+		ORDER BY priority, created_at, id`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []entity.Rule{}
+	for rows.Next() {
+		rule := entity.Rule{}
+		err := rows.Scan(&rule.ID, &rule.Name, &rule.DayOfWeek,
+			&rule.StartTime, &rule.EndTime,
+			&rule.Holiday, &rule.StudioClass, &rule.SeatCategory,
+			&rule.AdjustmentPercent, &rule.AdjustmentAmount, &rule.Priority, &rule.Active)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *pricingRepository) DeleteRule(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM pricing_rules WHERE id = $1", id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return errors.New("pricing rule not found")
+	}
+
+	return nil
+}
+
+func (r *pricingRepository) SaveHoliday(ctx context.Context, tx *sql.Tx, holiday entity.Holiday, c *gin.Context) (entity.Holiday, error) {
+	query := `INSERT INTO holidays (date, name) VALUES ($1, $2)
+		ON CONFLICT (date) DO UPDATE SET name = EXCLUDED.name`
+
+	_, err := tx.ExecContext(ctx, query, holiday.Date, holiday.Name)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return holiday, err
+	}
+
+	return holiday, nil
+}
+
+func (r *pricingRepository) FindAllHolidays(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Holiday, error) {
+	query := `SELECT to_char(date, 'YYYY-MM-DD'), name FROM holidays ORDER BY date`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []entity.Holiday{}
+	for rows.Next() {
+		holiday := entity.Holiday{}
+		if err := rows.Scan(&holiday.Date, &holiday.Name); err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, rows.Err()
+}
+
+func (r *pricingRepository) DeleteHoliday(ctx context.Context, tx *sql.Tx, date string, c *gin.Context) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM holidays WHERE date = $1", date)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return errors.New("holiday not found")
+	}
+
+	return nil
+}
+
+// FindSlot loads the showtime-wide pricing inputs; the caller fills in
+// SeatCategory per seat. Holidays and ShowStart are taken in the cinema's
+// timezone, so matinee and weekend rules follow the local clock.
+func (r *pricingRepository) FindSlot(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (entity.Slot, error) {
+	query := `SELECT m.price, s.show_start, ci.timezone, st.class,
+			EXISTS (SELECT 1 FROM holidays h WHERE h.date = (s.show_start AT TIME ZONE 'UTC' AT TIME ZONE ci.timezone)::date)
+		FROM showtimes s
+		JOIN movies m ON s.movie_id = m.id
+		JOIN studios st ON s.studio_id = st.id
+		JOIN cinemas ci ON st.cinema_id = ci.id
+		WHERE s.id = $1 AND s.cancelled_at IS NULL`
+
+	slot := entity.Slot{}
+	timezone := ""
+	err := tx.QueryRowContext(ctx, query, showtimeID).Scan(&slot.BasePrice, &slot.ShowStart, &timezone, &slot.StudioClass, &slot.Holiday)
+	if err == sql.ErrNoRows {
+		return slot, errors.New("showtime not found")
+	}
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return slot, err
+	}
+
+	slot.ShowStart = helper.InZone(slot.ShowStart, timezone)
+	return slot, nil
+}
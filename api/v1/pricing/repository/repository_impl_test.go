@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"bioskuy/api/v1/pricing/entity"
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PricingRepositoryTestSuite struct {
+	suite.Suite
+	mockDb  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    PricingRepository
+	ctx     context.Context
+	ginCtx  *gin.Context
+}
+
+func (suite *PricingRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.repo = NewPricingRepository()
+	suite.ctx = context.Background()
+	suite.ginCtx = &gin.Context{}
+}
+
+func (suite *PricingRepositoryTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestPricingRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PricingRepositoryTestSuite))
+}
+
+func (suite *PricingRepositoryTestSuite) TestSaveRule_Success() {
+	saturday := 6
+	rule := entity.Rule{Name: "weekend", DayOfWeek: &saturday, AdjustmentPercent: 20, Priority: 1}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`INSERT INTO pricing_rules`)).
+		WithArgs(rule.Name, rule.DayOfWeek, "", "", rule.Holiday, "", "", 20, 0, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "active"}).AddRow("rule-1", true))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.SaveRule(suite.ctx, tx, rule, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "rule-1", result.ID)
+	assert.True(suite.T(), result.Active)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PricingRepositoryTestSuite) TestFindAllRules_Success() {
+	rows := sqlmock.NewRows([]string{"id", "name", "day_of_week", "start_time", "end_time", "holiday", "studio_class", "seat_category",
+		"adjustment_percent", "adjustment_amount", "priority", "active"}).
+		AddRow("rule-1", "matinee", nil, "10:00", "13:00", nil, "", "", -25, 0, 0, true).
+		AddRow("rule-2", "holiday vip", nil, "", "", true, "", "vip", 0, 15000, 1, true)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM pricing_rules ORDER BY priority, created_at, id`)).WillReturnRows(rows)
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	rules, err := suite.repo.FindAllRules(suite.ctx, tx, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), rules, 2)
+	assert.Nil(suite.T(), rules[0].DayOfWeek)
+	assert.Equal(suite.T(), "10:00", rules[0].StartTime)
+	assert.Nil(suite.T(), rules[0].Holiday)
+	assert.True(suite.T(), *rules[1].Holiday)
+	assert.Equal(suite.T(), "vip", rules[1].SeatCategory)
+}
+
+func (suite *PricingRepositoryTestSuite) TestDeleteRule_NotFound() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`DELETE FROM pricing_rules WHERE id = $1`)).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.DeleteRule(suite.ctx, tx, "missing", suite.ginCtx)
+	assert.EqualError(suite.T(), err, "pricing rule not found")
+}
+
+func (suite *PricingRepositoryTestSuite) TestSaveHoliday_Upserts() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`INSERT INTO holidays (date, name) VALUES ($1, $2) ON CONFLICT (date) DO UPDATE SET name = EXCLUDED.name`)).
+		WithArgs("2024-08-17", "Independence Day").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.SaveHoliday(suite.ctx, tx, entity.Holiday{Date: "2024-08-17", Name: "Independence Day"}, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Independence Day", result.Name)
+}
+
+func (suite *PricingRepositoryTestSuite) TestFindSlot_Success() {
+	showStart := time.Date(2024, 8, 17, 12, 0, 0, 0, time.UTC)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT m.price, s.show_start, ci.timezone, st.class,`)).
+		WithArgs("showtime-1").
+		WillReturnRows(sqlmock.NewRows([]string{"price", "show_start", "timezone", "class", "exists"}).AddRow(50000, showStart, "Asia/Jakarta", "premium", true))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	slot, err := suite.repo.FindSlot(suite.ctx, tx, "showtime-1", suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "premium", slot.StudioClass)
+	assert.True(suite.T(), slot.Holiday)
+	// Rules see the screening at 19:00 Jakarta time, not 12:00 UTC.
+	assert.Equal(suite.T(), 19, slot.ShowStart.Hour())
+	assert.True(suite.T(), showStart.Equal(slot.ShowStart))
+}
+
+func (suite *PricingRepositoryTestSuite) TestFindSlot_NotFound() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT m.price, s.show_start, ci.timezone, st.class,`)).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"price", "show_start", "class", "exists"}))
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.FindSlot(suite.ctx, tx, "missing", suite.ginCtx)
+	assert.EqualError(suite.T(), err, "showtime not found")
+}
@@ -0,0 +1,37 @@
+package route
+
+import (
+	"bioskuy/api/v1/pricing/controller"
+	"bioskuy/api/v1/pricing/repository"
+	"bioskuy/api/v1/pricing/service"
+	"bioskuy/auth"
+	"bioskuy/helper"
+	"bioskuy/middleware"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func PricingRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB, config *helper.Config) {
+
+	authService := auth.NewService(config)
+
+	pricingRepo := repository.NewPricingRepository()
+	pricingService := service.NewPricingService(pricingRepo, validate, db)
+	pricingController := controller.NewPricingController(pricingService)
+
+	v1 := router.Group("/api/v1")
+	{
+		pricing := v1.Group("/pricing")
+		{
+			pricing.POST("/rules", middleware.AuthMiddleware(authService, "admin", "super admin"), pricingController.CreateRule)
+			pricing.GET("/rules", middleware.AuthMiddleware(authService, "admin", "super admin"), pricingController.FindAllRules)
+			pricing.DELETE("/rules/:ruleId", middleware.AuthMiddleware(authService, "admin", "super admin"), pricingController.DeleteRule)
+			pricing.POST("/holidays", middleware.AuthMiddleware(authService, "admin", "super admin"), pricingController.CreateHoliday)
+			pricing.GET("/holidays", pricingController.FindAllHolidays)
+			pricing.DELETE("/holidays/:date", middleware.AuthMiddleware(authService, "admin", "super admin"), pricingController.DeleteHoliday)
+			pricing.GET("/showtimes/:showtimeId", pricingController.Quote)
+		}
+	}
+}
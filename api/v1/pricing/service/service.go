@@ -0,0 +1,18 @@
+package service
+
+import (
+	"bioskuy/api/v1/pricing/dto"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PricingService interface {
+	CreateRule(ctx context.Context, request dto.CreateRuleRequest, c *gin.Context) (dto.RuleResponse, error)
+	FindAllRules(ctx context.Context, c *gin.Context) ([]dto.RuleResponse, error)
+	DeleteRule(ctx context.Context, id string, c *gin.Context) error
+	CreateHoliday(ctx context.Context, request dto.HolidayRequest, c *gin.Context) (dto.HolidayResponse, error)
+	FindAllHolidays(ctx context.Context, c *gin.Context) ([]dto.HolidayResponse, error)
+	DeleteHoliday(ctx context.Context, date string, c *gin.Context) error
+	Quote(ctx context.Context, showtimeID string, c *gin.Context) (dto.QuoteResponse, error)
+}
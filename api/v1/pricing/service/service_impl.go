@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bioskuy/api/v1/pricing/dto"
+	"bioskuy/api/v1/pricing/entity"
+	"bioskuy/api/v1/pricing/repository"
+	entitySeat "bioskuy/api/v1/seat/entity"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type pricingServiceImpl struct {
+	Repo     repository.PricingRepository
+	Validate *validator.Validate
+	DB       *sql.DB
+}
+
+func NewPricingService(repo repository.PricingRepository, validate *validator.Validate, DB *sql.DB) PricingService {
+	return &pricingServiceImpl{Repo: repo, Validate: validate, DB: DB}
+}
+
+func (s *pricingServiceImpl) CreateRule(ctx context.Context, request dto.CreateRuleRequest, c *gin.Context) (dto.RuleResponse, error) {
+	ruleResponse := dto.RuleResponse{}
+
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ruleResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ruleResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	rule := entity.Rule{
+		Name:              request.Name,
+		DayOfWeek:         request.DayOfWeek,
+		StartTime:         request.StartTime,
+		EndTime:           request.EndTime,
+		Holiday:           request.Holiday,
+		StudioClass:       request.StudioClass,
+		SeatCategory:      request.SeatCategory,
+		AdjustmentPercent: request.AdjustmentPercent,
+		AdjustmentAmount:  request.AdjustmentAmount,
+		Priority:          request.Priority,
+	}
+
+	result, err := s.Repo.SaveRule(ctx, tx, rule, c)
+	if err != nil {
+		return ruleResponse, err
+	}
+
+	return toRuleResponse(result), nil
+}
+
+func (s *pricingServiceImpl) FindAllRules(ctx context.Context, c *gin.Context) ([]dto.RuleResponse, error) {
+	ruleResponses := []dto.RuleResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ruleResponses, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	results, err := s.Repo.FindAllRules(ctx, tx, c)
+	if err != nil {
+		return ruleResponses, err
+	}
+
+	for _, result := range results {
+		ruleResponses = append(ruleResponses, toRuleResponse(result))
+	}
+
+	return ruleResponses, nil
+}
+
+func (s *pricingServiceImpl) DeleteRule(ctx context.Context, id string, c *gin.Context) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	err = s.Repo.DeleteRule(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+func (s *pricingServiceImpl) CreateHoliday(ctx context.Context, request dto.HolidayRequest, c *gin.Context) (dto.HolidayResponse, error) {
+	holidayResponse := dto.HolidayResponse{}
+
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return holidayResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return holidayResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	result, err := s.Repo.SaveHoliday(ctx, tx, entity.Holiday{Date: request.Date, Name: request.Name}, c)
+	if err != nil {
+		return holidayResponse, err
+	}
+
+	holidayResponse.Date = result.Date
+	holidayResponse.Name = result.Name
+
+	return holidayResponse, nil
+}
+
+func (s *pricingServiceImpl) FindAllHolidays(ctx context.Context, c *gin.Context) ([]dto.HolidayResponse, error) {
+	holidayResponses := []dto.HolidayResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return holidayResponses, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	results, err := s.Repo.FindAllHolidays(ctx, tx, c)
+	if err != nil {
+		return holidayResponses, err
+	}
+
+	for _, result := range results {
+		holidayResponses = append(holidayResponses, dto.HolidayResponse{Date: result.Date, Name: result.Name})
+	}
+
+	return holidayResponses, nil
+}
+
+func (s *pricingServiceImpl) DeleteHoliday(ctx context.Context, date string, c *gin.Context) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	err = s.Repo.DeleteHoliday(ctx, tx, date, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+func (s *pricingServiceImpl) Quote(ctx context.Context, showtimeID string, c *gin.Context) (dto.QuoteResponse, error) {
+	quoteResponse := dto.QuoteResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return quoteResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	slot, err := s.Repo.FindSlot(ctx, tx, showtimeID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return quoteResponse, err
+	}
+
+	rules, err := s.Repo.FindAllRules(ctx, tx, c)
+	if err != nil {
+		return quoteResponse, err
+	}
+
+	quoteResponse.ShowtimeID = showtimeID
+	quoteResponse.BasePrice = slot.BasePrice
+	quoteResponse.Holiday = slot.Holiday
+	quoteResponse.StudioClass = slot.StudioClass
+	quoteResponse.Prices = map[string]int{}
+	for _, category := range entitySeat.SeatCategories {
+		slot.SeatCategory = category
+		quoteResponse.Prices[category] = entity.Price(rules, slot)
+	}
+
+	return quoteResponse, nil
+}
+
+func toRuleResponse(rule entity.Rule) dto.RuleResponse {
+	return dto.RuleResponse{
+		ID:                rule.ID,
+		Name:              rule.Name,
+		DayOfWeek:         rule.DayOfWeek,
+		StartTime:         rule.StartTime,
+		EndTime:           rule.EndTime,
+		Holiday:           rule.Holiday,
+		StudioClass:       rule.StudioClass,
+		SeatCategory:      rule.SeatCategory,
+		AdjustmentPercent: rule.AdjustmentPercent,
+		AdjustmentAmount:  rule.AdjustmentAmount,
+		Priority:          rule.Priority,
+		Active:            rule.Active,
+	}
+}
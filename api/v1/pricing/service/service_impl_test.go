@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bioskuy/api/v1/pricing/dto"
+	"bioskuy/api/v1/pricing/entity"
+	"bioskuy/api/v1/pricing/mock/repomock"
+	"bioskuy/exception"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PricingServiceTestSuite struct {
+	suite.Suite
+	mockRepo   *repomock.MockPricingRepository
+	mockDb     *sql.DB
+	mockSql    sqlmock.Sqlmock
+	service    PricingService
+	ctx        context.Context
+	ginContext *gin.Context
+}
+
+func (suite *PricingServiceTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.mockRepo = new(repomock.MockPricingRepository)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.service = NewPricingService(suite.mockRepo, validator.New(), db)
+	suite.ctx = context.Background()
+	suite.ginContext, _ = gin.CreateTestContext(httptest.NewRecorder())
+}
+
+func TestPricingServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PricingServiceTestSuite))
+}
+
+func (suite *PricingServiceTestSuite) TestCreateRule_Success() {
+	request := dto.CreateRuleRequest{Name: "matinee", StartTime: "10:00", EndTime: "13:00", AdjustmentPercent: -25}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("SaveRule", suite.ctx, mock.Anything, mock.MatchedBy(func(r entity.Rule) bool {
+		return r.Name == "matinee" && r.StartTime == "10:00" && r.EndTime == "13:00" && r.AdjustmentPercent == -25
+	}), suite.ginContext).Return(entity.Rule{ID: "rule-1", Name: "matinee", StartTime: "10:00", EndTime: "13:00", AdjustmentPercent: -25, Active: true}, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.CreateRule(suite.ctx, request, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "rule-1", response.ID)
+	assert.True(suite.T(), response.Active)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PricingServiceTestSuite) TestCreateRule_ValidationError() {
+	invalid := []dto.CreateRuleRequest{
+		{Name: "half window", StartTime: "10:00"},
+		{Name: "bad time", StartTime: "25:00", EndTime: "26:00"},
+		{Name: "bad day", DayOfWeek: func() *int { d := 7; return &d }()},
+		{Name: "below free", AdjustmentPercent: -101},
+	}
+
+	for _, request := range invalid {
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		_, err := suite.service.CreateRule(suite.ctx, request, ginContext)
+
+		assert.Error(suite.T(), err, request.Name)
+		assert.IsType(suite.T(), exception.ValidationError{}, ginContext.Errors.Last().Err, request.Name)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "SaveRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PricingServiceTestSuite) TestDeleteRule_NotFound() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("DeleteRule", suite.ctx, mock.Anything, "missing", suite.ginContext).Return(errors.New("pricing rule not found"))
+	suite.mockSql.ExpectRollback()
+
+	err := suite.service.DeleteRule(suite.ctx, "missing", suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.NotFoundError{}, suite.ginContext.Errors.Last().Err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PricingServiceTestSuite) TestCreateHoliday_InvalidDate() {
+	_, err := suite.service.CreateHoliday(suite.ctx, dto.HolidayRequest{Date: "17-08-2024", Name: "Independence Day"}, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ValidationError{}, suite.ginContext.Errors.Last().Err)
+}
+
+func (suite *PricingServiceTestSuite) TestQuote_Success() {
+	slot := entity.Slot{BasePrice: 40000, ShowStart: time.Date(2024, 8, 17, 11, 0, 0, 0, time.UTC), Holiday: true, StudioClass: "regular"}
+	holiday := true
+	rules := []entity.Rule{
+		{Name: "matinee", StartTime: "10:00", EndTime: "13:00", AdjustmentPercent: -25, Active: true},
+		{Name: "holiday", Holiday: &holiday, AdjustmentAmount: 5000, Priority: 1, Active: true},
+		{Name: "premium seats", SeatCategory: "premium", AdjustmentAmount: 10000, Priority: 2, Active: true},
+		{Name: "vip seats", SeatCategory: "vip", AdjustmentPercent: 100, Priority: 2, Active: true},
+	}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindSlot", suite.ctx, mock.Anything, "showtime-1", suite.ginContext).Return(slot, nil)
+	suite.mockRepo.On("FindAllRules", suite.ctx, mock.Anything, suite.ginContext).Return(rules, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Quote(suite.ctx, "showtime-1", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 40000, response.BasePrice)
+	assert.True(suite.T(), response.Holiday)
+	assert.Equal(suite.T(), map[string]int{"regular": 35000, "premium": 45000, "vip": 70000}, response.Prices)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *PricingServiceTestSuite) TestQuote_ShowtimeNotFound() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindSlot", suite.ctx, mock.Anything, "missing", suite.ginContext).Return(entity.Slot{}, errors.New("showtime not found"))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Quote(suite.ctx, "missing", suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.NotFoundError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindAllRules", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -6,5 +6,6 @@ import (
 
 type SeatController interface {
 	FindById(c *gin.Context)
-	FindAll(c *gin.Context)
+	SeatMap(c *gin.Context)
+	FindAllByShowtime(c *gin.Context)
 }
@@ -35,20 +35,37 @@ func (controller *seatControllerImpl) FindById(c *gin.Context){
 	}
 }
 
-func (controller *seatControllerImpl) FindAll(c *gin.Context) {
-    ctx := c.Request.Context()
+func (controller *seatControllerImpl) SeatMap(c *gin.Context) {
+	ctx := c.Request.Context()
 	id := c.Param("studioId")
 
-    result, err := controller.seatService.FindAll(ctx, id, c)
-    if err != nil {
-        c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-        return
-    }
+	result, err := controller.seatService.SeatMap(ctx, id, c)
+	if err != nil {
+		return
+	}
+
+	response := web.FormatResponse{
+		ResponseCode: http.StatusOK,
+		Data:         result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (controller *seatControllerImpl) FindAllByShowtime(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("showtimeId")
+
+	result, err := controller.seatService.FindAllByShowtime(ctx, id, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
 
-    response := web.FormatResponse{
-        ResponseCode: http.StatusOK,
-        Data:    result,
-    }
+	response := web.FormatResponse{
+		ResponseCode: http.StatusOK,
+		Data:         result,
+	}
 
-    c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, response)
+}
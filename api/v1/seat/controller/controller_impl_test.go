@@ -77,54 +77,96 @@ func (suite *SeatControllerTestSuite) TestFindById_NotFound() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
-func (suite *SeatControllerTestSuite) TestFindAll_Success() {
+func (suite *SeatControllerTestSuite) TestSeatMap_Success() {
 	gin.SetMode(gin.TestMode)
 
-	expectedSeats := []dto.SeatResponse{
-		{ID: "1", Name: "Seat A", IsAvailable: true, StudioID: "1"},
-		{ID: "2", Name: "Seat B", IsAvailable: false, StudioID: "1"},
+	seatMap := dto.SeatMapResponse{
+		StudioID: "1",
+		Columns:  3,
+		Rows: []dto.SeatMapRow{{
+			Label: "A",
+			Cells: []dto.SeatMapCell{
+				{Type: "seat", Col: 0, Width: 1, Seat: &dto.SeatResponse{ID: "1", Name: "A-1", IsAvailable: true, StudioID: "1", Category: "regular", Kind: "standard"}},
+				{Type: "aisle", Col: 1, Width: 1},
+				{Type: "seat", Col: 2, Width: 1, Seat: &dto.SeatResponse{ID: "2", Name: "A-2", IsAvailable: true, StudioID: "1", Category: "vip", Kind: "standard", Col: 2}},
+			},
+		}},
 	}
-	suite.mockService.On("FindAll", mock.Anything, "1", mock.Anything).Return(expectedSeats, nil)
+	suite.mockService.On("SeatMap", mock.Anything, "1", mock.Anything).Return(seatMap, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	req := httptest.NewRequest("GET", "/studios/1/seats", nil)
+	req := httptest.NewRequest("GET", "/seats/studio/1", nil)
 	c.Request = req
 	c.Params = gin.Params{{Key: "studioId", Value: "1"}}
 
-	suite.controller.FindAll(c)
+	suite.controller.SeatMap(c)
 
 	var response web.FormatResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
 
-	// Extracting Data field from response
 	responseData, err := json.Marshal(response.Data)
 	assert.NoError(suite.T(), err)
 
-	var result []dto.SeatResponse
+	var result dto.SeatMapResponse
 	err = json.Unmarshal(responseData, &result)
 	assert.NoError(suite.T(), err)
-	assert.Equal(suite.T(), expectedSeats, result)
+	assert.Equal(suite.T(), seatMap, result)
+
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *SeatControllerTestSuite) TestSeatMap_StudioNotFound() {
+	gin.SetMode(gin.TestMode)
+
+	suite.mockService.On("SeatMap", mock.Anything, "1", mock.Anything).Return(dto.SeatMapResponse{}, exception.NotFoundError{Message: "studio not found"}).Run(func(args mock.Arguments) {
+		c := args.Get(2).(*gin.Context)
+		c.Error(exception.NotFoundError{Message: "studio not found"}).SetType(gin.ErrorTypePublic)
+	})
+
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.Use(exception.ErrorHandler)
+	router.GET("/seats/studio/:studioId", suite.controller.SeatMap)
+
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/seats/studio/1", nil))
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
 
 	suite.mockService.AssertExpectations(suite.T())
 }
 
-func (suite *SeatControllerTestSuite) TestFindAll_InternalServerError() {
+func (suite *SeatControllerTestSuite) TestFindAllByShowtime_Success() {
 	gin.SetMode(gin.TestMode)
 
-	suite.mockService.On("FindAll", mock.Anything, "1", mock.Anything).Return(nil, exception.InternalServerError{Message: "Database error"})
+	expectedSeats := []dto.SeatResponse{
+		{ID: "1", Name: "Seat A", IsAvailable: true, StudioID: "1"},
+		{ID: "2", Name: "Seat B", IsAvailable: false, StudioID: "1"},
+	}
+	suite.mockService.On("FindAllByShowtime", mock.Anything, "10", mock.Anything).Return(expectedSeats, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	req := httptest.NewRequest("GET", "/studios/1/seats", nil)
+	req := httptest.NewRequest("GET", "/seats/showtime/10", nil)
 	c.Request = req
-	c.Params = gin.Params{{Key: "studioId", Value: "1"}}
+	c.Params = gin.Params{{Key: "showtimeId", Value: "10"}}
 
-	suite.controller.FindAll(c)
+	suite.controller.FindAllByShowtime(c)
 
-	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
+	var response web.FormatResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	responseData, err := json.Marshal(response.Data)
+	assert.NoError(suite.T(), err)
+
+	var result []dto.SeatResponse
+	err = json.Unmarshal(responseData, &result)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expectedSeats, result)
 
 	suite.mockService.AssertExpectations(suite.T())
 }
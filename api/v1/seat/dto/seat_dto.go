@@ -11,4 +11,30 @@ type SeatResponse struct {
 	Name        string `json:"name"`
 	IsAvailable bool   `json:"is-available"`
 	StudioID    string `json:"studio-id"`
+	Category    string `json:"category,omitempty"`
+	Kind        string `json:"kind,omitempty"`
+	Row         int    `json:"row"`
+	Col         int    `json:"col"`
+}
+
+// SeatMapResponse lays a studio's seats out on its grid, front row first, so
+// clients can draw the seat map cell by cell.
+type SeatMapResponse struct {
+	StudioID string       `json:"studio_id"`
+	Columns  int          `json:"columns"`
+	Rows     []SeatMapRow `json:"rows"`
+}
+
+type SeatMapRow struct {
+	Label string        `json:"label"`
+	Cells []SeatMapCell `json:"cells"`
+}
+
+// SeatMapCell is one layout cell. Seat is set on seat, wheelchair and couple
+// cells; aisles and gaps carry only their position.
+type SeatMapCell struct {
+	Type  string        `json:"type"`
+	Col   int           `json:"col"`
+	Width int           `json:"width"`
+	Seat  *SeatResponse `json:"seat,omitempty"`
 }
\ No newline at end of file
@@ -1,8 +1,30 @@
 package entity
 
+// Seat categories pricing rules can target. New seats are regular.
+const (
+	SeatCategoryRegular = "regular"
+	SeatCategoryPremium = "premium"
+	SeatCategoryVIP     = "vip"
+)
+
+// SeatCategories lists every category in ascending order of comfort.
+var SeatCategories = []string{SeatCategoryRegular, SeatCategoryPremium, SeatCategoryVIP}
+
+// Seat kinds, set from the studio layout cell the seat was created from.
+const (
+	SeatKindStandard   = "standard"
+	SeatKindWheelchair = "wheelchair"
+	SeatKindCouple     = "couple"
+)
+
 type Seat struct {
 	ID          string `json:"id" `
 	Name        string `json:"name"`
 	IsAvailable bool   `json:"is-available"`
 	StudioID    string `json:"studio-id"`
-}
\ No newline at end of file
+	Category    string `json:"category"`
+	Kind        string `json:"kind"`
+	// Row and Col place the seat on the studio grid, counted from 0.
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
@@ -23,8 +23,8 @@ func (m *SeatRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c
 	return args.Get(0).(entity.Seat), args.Error(1)
 }
 
-func (m *SeatRepository) FindByIDWithNotAvailable(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Seat, error) {
-	args := m.Called(ctx, tx, id, c)
+func (m *SeatRepository) FindAvailableByShowtime(ctx context.Context, tx *sql.Tx, id string, showtimeID string, c *gin.Context) (entity.Seat, error) {
+	args := m.Called(ctx, tx, id, showtimeID, c)
 	return args.Get(0).(entity.Seat), args.Error(1)
 }
 
@@ -38,7 +38,17 @@ func (m *SeatRepository) FindAll(ctx context.Context, id string, tx *sql.Tx, c *
 	return args.Get(0).([]entity.Seat), args.Error(1)
 }
 
+func (m *SeatRepository) FindAllByShowtime(ctx context.Context, showtimeID string, tx *sql.Tx, c *gin.Context) ([]entity.Seat, error) {
+	args := m.Called(ctx, showtimeID, tx, c)
+	return args.Get(0).([]entity.Seat), args.Error(1)
+}
+
 func (m *SeatRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
 	args := m.Called(ctx, tx, id, c)
 	return args.Error(0)
 }
+
+func (m *SeatRepository) Retire(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
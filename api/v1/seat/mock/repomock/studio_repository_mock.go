@@ -0,0 +1,45 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/studio/entity"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type StudioRepository struct {
+	mock.Mock
+}
+
+func (m *StudioRepository) Save(ctx context.Context, tx *sql.Tx, studio entity.Studio, c *gin.Context) (entity.Studio, error) {
+	args := m.Called(ctx, tx, studio, c)
+	return args.Get(0).(entity.Studio), args.Error(1)
+}
+
+func (m *StudioRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Studio, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).(entity.Studio), args.Error(1)
+}
+
+func (m *StudioRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Studio, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entity.Studio), args.Int(1), args.Error(2)
+}
+
+func (m *StudioRepository) Update(ctx context.Context, tx *sql.Tx, studio entity.Studio, c *gin.Context) (entity.Studio, error) {
+	args := m.Called(ctx, tx, studio, c)
+	return args.Get(0).(entity.Studio), args.Error(1)
+}
+
+func (m *StudioRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
+
+func (m *StudioRepository) HasUpcomingShowtimes(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (bool, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Bool(0), args.Error(1)
+}
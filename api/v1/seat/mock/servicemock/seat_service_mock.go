@@ -17,7 +17,12 @@ func (m *SeatService) FindByID(ctx context.Context, id string, c *gin.Context) (
 	return args.Get(0).(dto.SeatResponse), args.Error(1)
 }
 
-func (m *SeatService) FindAll(ctx context.Context, id string, c *gin.Context) ([]dto.SeatResponse, error) {
-	args := m.Called(ctx, id, c)
+func (m *SeatService) SeatMap(ctx context.Context, studioID string, c *gin.Context) (dto.SeatMapResponse, error) {
+	args := m.Called(ctx, studioID, c)
+	return args.Get(0).(dto.SeatMapResponse), args.Error(1)
+}
+
+func (m *SeatService) FindAllByShowtime(ctx context.Context, showtimeID string, c *gin.Context) ([]dto.SeatResponse, error) {
+	args := m.Called(ctx, showtimeID, c)
 	return args.Get(0).([]dto.SeatResponse), args.Error(1)
 }
@@ -11,8 +11,10 @@ import (
 type SeatRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, user entity.Seat, c *gin.Context) (entity.Seat, error)
 	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Seat, error)
-	FindByIDWithNotAvailable(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Seat, error)
+	FindAvailableByShowtime(ctx context.Context, tx *sql.Tx, id string, showtimeID string, c *gin.Context) (entity.Seat, error)
 	FindAll(ctx context.Context, id string, tx *sql.Tx, c *gin.Context) ([]entity.Seat, error)
+	FindAllByShowtime(ctx context.Context, showtimeID string, tx *sql.Tx, c *gin.Context) ([]entity.Seat, error)
 	Update(ctx context.Context, tx *sql.Tx, seat entity.Seat, c *gin.Context) (entity.Seat, error)
 	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	Retire(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
 }
\ No newline at end of file
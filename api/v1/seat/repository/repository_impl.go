@@ -18,9 +18,9 @@ func NewSeatRepository() SeatRepository {
 }
 
 func (r *seatRepository) Save(ctx context.Context, tx *sql.Tx, seat entity.Seat, c *gin.Context) (entity.Seat, error) {
-	query := "INSERT INTO seats (seat_name, isAvailable, studio_id) VALUES ($1, $2, $3) RETURNING id"
+	query := "INSERT INTO seats (seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
 
-	err := tx.QueryRowContext(ctx, query, seat.Name, seat.IsAvailable, seat.StudioID).Scan(&seat.ID)
+	err := tx.QueryRowContext(ctx, query, seat.Name, seat.IsAvailable, seat.StudioID, seat.Category, seat.Kind, seat.Row, seat.Col).Scan(&seat.ID)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return seat, err
@@ -31,7 +31,7 @@ func (r *seatRepository) Save(ctx context.Context, tx *sql.Tx, seat entity.Seat,
 
 func (r *seatRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Seat, error) {
 
-	query := `SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE id = $1`
+	query := `SELECT id, seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col FROM seats WHERE id = $1`
 
 	seat := entity.Seat{}
 	rows, err := tx.QueryContext(ctx, query, id)
@@ -42,7 +42,7 @@ func (r *seatRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c
 	defer rows.Close()
 
 	if rows.Next() {
-		err := rows.Scan(&seat.ID, &seat.Name, &seat.IsAvailable, &seat.StudioID)
+		err := rows.Scan(&seat.ID, &seat.Name, &seat.IsAvailable, &seat.StudioID, &seat.Category, &seat.Kind, &seat.Row, &seat.Col)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 			return seat, err
@@ -54,12 +54,19 @@ func (r *seatRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c
 	}
 }
 
-func (r *seatRepository) FindByIDWithNotAvailable(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Seat, error){
+// FindAvailableByShowtime returns the seat if it can be booked for the
+// showtime. A seat that exists but is already booked is a ConflictError.
+func (r *seatRepository) FindAvailableByShowtime(ctx context.Context, tx *sql.Tx, id string, showtimeID string, c *gin.Context) (entity.Seat, error){
 
-	query := `SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE id = $1 AND isAvailable = true`
+	query := `SELECT se.id, se.seat_name, se.isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col,
+		EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = sh.id)
+	FROM seats se
+	JOIN showtimes sh ON sh.studio_id = se.studio_id
+	WHERE se.id = $1 AND sh.id = $2 AND se.isAvailable = true AND se.retired_at IS NULL`
 	
 	seat := entity.Seat{}
-	rows, err := tx.QueryContext(ctx, query, id)
+	booked := false
+	rows, err := tx.QueryContext(ctx, query, id, showtimeID)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return  seat, err
@@ -67,23 +74,29 @@ func (r *seatRepository) FindByIDWithNotAvailable(ctx context.Context, tx *sql.T
 	defer rows.Close()
 
 	if rows.Next(){
-		err := rows.Scan(&seat.ID, &seat.Name, &seat.IsAvailable, &seat.StudioID)
+		err := rows.Scan(&seat.ID, &seat.Name, &seat.IsAvailable, &seat.StudioID, &seat.Category, &seat.Kind, &seat.Row, &seat.Col, &booked)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 			return  seat, err
 		}
 
+		if booked {
+			err := exception.ConflictError{Message: "seat " + seat.Name + " is already booked"}
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return seat, err
+		}
+
 		return seat, nil
 	}else{
-		return seat, errors.New("seat not found or have been taken")
+		return seat, errors.New("seat not found")
 	}
 }
 
 func (r *seatRepository) Update(ctx context.Context, tx *sql.Tx, seat entity.Seat, c *gin.Context) (entity.Seat, error){
 
-	query := `UPDATE seats SET isAvailable = $1 WHERE id = $2`
+	query := `UPDATE seats SET seat_name = $1, isAvailable = $2, category = $3, kind = $4, grid_row = $5, grid_col = $6 WHERE id = $7`
 
-	_, err := tx.ExecContext(ctx, query, seat.IsAvailable, seat.ID)
+	_, err := tx.ExecContext(ctx, query, seat.Name, seat.IsAvailable, seat.Category, seat.Kind, seat.Row, seat.Col, seat.ID)
 
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -95,7 +108,7 @@ func (r *seatRepository) Update(ctx context.Context, tx *sql.Tx, seat entity.Sea
 
 func (r *seatRepository) FindAll(ctx context.Context, id string, tx *sql.Tx, c *gin.Context) ([]entity.Seat, error){
 
-	query := `SELECT  id, seat_name, isAvailable, studio_id FROM seats WHERE studio_id = $1`
+	query := `SELECT id, seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col FROM seats WHERE studio_id = $1 AND retired_at IS NULL ORDER BY grid_row, grid_col`
 
 	seats := []entity.Seat{}
 	rows, err := tx.QueryContext(ctx, query, id)
@@ -107,7 +120,7 @@ func (r *seatRepository) FindAll(ctx context.Context, id string, tx *sql.Tx, c *
 
 	for rows.Next() {
 		seat := entity.Seat{}
-		if err := rows.Scan(&seat.ID, &seat.Name, &seat.IsAvailable, &seat.StudioID); err != nil {
+		if err := rows.Scan(&seat.ID, &seat.Name, &seat.IsAvailable, &seat.StudioID, &seat.Category, &seat.Kind, &seat.Row, &seat.Col); err != nil {
 			return nil, err
 		}
 		seats = append(seats, seat)
@@ -115,11 +128,19 @@ func (r *seatRepository) FindAll(ctx context.Context, id string, tx *sql.Tx, c *
 	return seats, nil
 }
 
+// Delete takes every seat out of the studio id, as Retire does for one seat.
 func (r *seatRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
-	query := `DELETE FROM seats WHERE studio_id = $1`
+	query := `DELETE FROM seats se WHERE se.studio_id = $1` + seatWithoutHistory
 
 	_, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	query = `UPDATE seats SET retired_at = (NOW() AT TIME ZONE 'UTC') WHERE studio_id = $1 AND retired_at IS NULL`
 
+	_, err = tx.ExecContext(ctx, query, id)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return err
@@ -127,3 +148,75 @@ func (r *seatRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *g
 
 	return nil
 }
+
+// seatWithoutHistory narrows a delete from seats se to the seats no booking,
+// refund, refund flag or ticket refers to.
+const seatWithoutHistory = `
+	AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id)
+	AND NOT EXISTS (SELECT 1 FROM payment_refund_seats prs WHERE prs.seat_id = se.id)
+	AND NOT EXISTS (SELECT 1 FROM seat_refund_flags srf WHERE srf.seat_id = se.id)
+	AND NOT EXISTS (SELECT 1 FROM tickets t WHERE t.seat_id = se.id)`
+
+// Retire takes a seat out of its studio. A seat nothing refers to is deleted;
+// one with booking, refund or ticket history is kept with retired_at set so that
+// history still resolves, and stops being offered for new bookings.
+func (r *seatRepository) Retire(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	query := `DELETE FROM seats se WHERE se.id = $1` + seatWithoutHistory
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+	if deleted > 0 {
+		return nil
+	}
+
+	query = `UPDATE seats SET retired_at = (NOW() AT TIME ZONE 'UTC') WHERE id = $1 AND retired_at IS NULL`
+
+	_, err = tx.ExecContext(ctx, query, id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+func (r *seatRepository) FindAllByShowtime(ctx context.Context, showtimeID string, tx *sql.Tx, c *gin.Context) ([]entity.Seat, error){
+
+	query := `SELECT se.id, se.seat_name, (se.isAvailable AND sdfb.id IS NULL) AS isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col
+	FROM showtimes sh
+	JOIN seats se ON se.studio_id = sh.studio_id
+	LEFT JOIN seat_detail_for_bookings sdfb ON sdfb.seat_id = se.id AND sdfb.showtime_id = sh.id
+	WHERE sh.id = $1 AND (se.retired_at IS NULL OR sdfb.id IS NOT NULL)
+	ORDER BY se.grid_row, se.grid_col`
+
+	seats := []entity.Seat{}
+	rows, err := tx.QueryContext(ctx, query, showtimeID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return seats, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		seat := entity.Seat{}
+		if err := rows.Scan(&seat.ID, &seat.Name, &seat.IsAvailable, &seat.StudioID, &seat.Category, &seat.Kind, &seat.Row, &seat.Col); err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		seats = append(seats, seat)
+	}
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	return seats, nil
+}
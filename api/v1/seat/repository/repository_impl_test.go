@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bioskuy/api/v1/seat/entity"
+	"bioskuy/exception"
 	"context"
 	"database/sql"
 	"regexp"
@@ -31,15 +32,15 @@ func (suite *SeatRepositoryTestSuite) TearDownTest() {
 }
 
 func (suite *SeatRepositoryTestSuite) TestSave_Success() {
-	seat := entity.Seat{Name: "Test Seat", IsAvailable: true, StudioID: "studio1"}
-	query := regexp.QuoteMeta("INSERT INTO seats (seat_name, isAvailable, studio_id) VALUES ($1, $2, $3) RETURNING id")
+	seat := entity.Seat{Name: "Test Seat", IsAvailable: true, StudioID: "studio1", Category: "regular", Kind: "standard", Row: 0, Col: 1}
+	query := regexp.QuoteMeta("INSERT INTO seats (seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id")
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
 	suite.mockSql.ExpectQuery(query).
-		WithArgs(seat.Name, seat.IsAvailable, seat.StudioID).
+		WithArgs(seat.Name, seat.IsAvailable, seat.StudioID, seat.Category, seat.Kind, seat.Row, seat.Col).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
 
 	ginContext, _ := gin.CreateTestContext(nil)
@@ -56,15 +57,15 @@ func (suite *SeatRepositoryTestSuite) TestSave_Success() {
 }
 
 func (suite *SeatRepositoryTestSuite) TestSave_Error() {
-	seat := entity.Seat{Name: "Test Seat", IsAvailable: true, StudioID: "studio1"}
-	query := regexp.QuoteMeta("INSERT INTO seats (seat_name, isAvailable, studio_id) VALUES ($1, $2, $3) RETURNING id")
+	seat := entity.Seat{Name: "Test Seat", IsAvailable: true, StudioID: "studio1", Category: "regular", Kind: "standard", Row: 0, Col: 1}
+	query := regexp.QuoteMeta("INSERT INTO seats (seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id")
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
 	suite.mockSql.ExpectQuery(query).
-		WithArgs(seat.Name, seat.IsAvailable, seat.StudioID).
+		WithArgs(seat.Name, seat.IsAvailable, seat.StudioID, seat.Category, seat.Kind, seat.Row, seat.Col).
 		WillReturnError(sql.ErrConnDone)
 
 	ginContext, _ := gin.CreateTestContext(nil)
@@ -81,9 +82,9 @@ func (suite *SeatRepositoryTestSuite) TestSave_Error() {
 
 func (suite *SeatRepositoryTestSuite) TestFindByID_Success() {
 	seatID := "1"
-	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE id = $1`)
-	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id"}).
-		AddRow(seatID, "Test Seat", true, "studio1")
+	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col FROM seats WHERE id = $1`)
+	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col"}).
+		AddRow(seatID, "Test Seat", true, "studio1", "regular", "standard", 0, 0)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -107,7 +108,7 @@ func (suite *SeatRepositoryTestSuite) TestFindByID_Success() {
 
 func (suite *SeatRepositoryTestSuite) TestFindByID_Error() {
 	seatID := "1"
-	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE id = $1`)
+	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col FROM seats WHERE id = $1`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -127,24 +128,33 @@ func (suite *SeatRepositoryTestSuite) TestFindByID_Error() {
 	suite.NoError(err)
 }
 
-func (suite *SeatRepositoryTestSuite) TestFindByIDWithNotAvailable_Success() {
+func (suite *SeatRepositoryTestSuite) TestFindAvailableByShowtime_Success() {
 	seatID := "1"
-	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE id = $1 AND isAvailable = true`)
-	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id"}).
-		AddRow(seatID, "Test Seat", true, "studio1")
+	showtimeID := "showtime1"
+	query := regexp.QuoteMeta(`SELECT se.id, se.seat_name, se.isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col,
+		EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = sh.id)
+	FROM seats se
+	JOIN showtimes sh ON sh.studio_id = se.studio_id
+	WHERE se.id = $1 AND sh.id = $2 AND se.isAvailable = true AND se.retired_at IS NULL`)
+	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col", "booked"}).
+		AddRow(seatID, "Test Seat", true, "studio1", "vip", "couple", 2, 4, false)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectQuery(query).WithArgs(seatID).WillReturnRows(rows)
+	suite.mockSql.ExpectQuery(query).WithArgs(seatID, showtimeID).WillReturnRows(rows)
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	seat, err := suite.repo.FindByIDWithNotAvailable(context.Background(), tx, seatID, ginContext)
+	seat, err := suite.repo.FindAvailableByShowtime(context.Background(), tx, seatID, showtimeID, ginContext)
 	suite.NoError(err)
 	suite.Equal(seatID, seat.ID)
 	suite.Equal("Test Seat", seat.Name)
 	suite.Equal(true, seat.IsAvailable)
+	suite.Equal("vip", seat.Category)
+	suite.Equal("couple", seat.Kind)
+	suite.Equal(2, seat.Row)
+	suite.Equal(4, seat.Col)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -154,18 +164,69 @@ func (suite *SeatRepositoryTestSuite) TestFindByIDWithNotAvailable_Success() {
 	suite.NoError(err)
 }
 
-func (suite *SeatRepositoryTestSuite) TestFindByIDWithNotAvailable_Error() {
+func (suite *SeatRepositoryTestSuite) TestFindAvailableByShowtime_NotFound() {
 	seatID := "1"
-	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE id = $1 AND isAvailable = true`)
+	showtimeID := "showtime1"
+	query := regexp.QuoteMeta(`SELECT se.id, se.seat_name, se.isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col,`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectQuery(query).WithArgs(seatID).WillReturnError(sql.ErrNoRows)
+	suite.mockSql.ExpectQuery(query).WithArgs(seatID, showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col", "booked"}))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	_, err = suite.repo.FindAvailableByShowtime(context.Background(), tx, seatID, showtimeID, ginContext)
+	suite.EqualError(err, "seat not found")
+	suite.Empty(ginContext.Errors)
+
+	suite.mockSql.ExpectRollback()
+	err = tx.Rollback()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatRepositoryTestSuite) TestFindAvailableByShowtime_Taken() {
+	seatID := "1"
+	showtimeID := "showtime1"
+	query := regexp.QuoteMeta(`SELECT se.id, se.seat_name, se.isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col,`)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(query).WithArgs(seatID, showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col", "booked"}).
+		AddRow(seatID, "A1", true, "studio1", "regular", "single", 1, 1, true))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	_, err = suite.repo.FindAvailableByShowtime(context.Background(), tx, seatID, showtimeID, ginContext)
+	suite.EqualError(err, "seat A1 is already booked")
+	suite.IsType(exception.ConflictError{}, err)
+	suite.IsType(exception.ConflictError{}, ginContext.Errors.Last().Err)
+
+	suite.mockSql.ExpectRollback()
+	err = tx.Rollback()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatRepositoryTestSuite) TestFindAvailableByShowtime_Error() {
+	seatID := "1"
+	showtimeID := "showtime1"
+	query := regexp.QuoteMeta(`SELECT se.id, se.seat_name, se.isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col,`)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(query).WithArgs(seatID, showtimeID).WillReturnError(sql.ErrNoRows)
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	_, err = suite.repo.FindByIDWithNotAvailable(context.Background(), tx, seatID, ginContext)
+	_, err = suite.repo.FindAvailableByShowtime(context.Background(), tx, seatID, showtimeID, ginContext)
 	suite.Error(err)
 
 	suite.mockSql.ExpectRollback()
@@ -176,15 +237,93 @@ func (suite *SeatRepositoryTestSuite) TestFindByIDWithNotAvailable_Error() {
 	suite.NoError(err)
 }
 
+func (suite *SeatRepositoryTestSuite) TestFindAllByShowtime_Success() {
+	showtimeID := "showtime1"
+	query := regexp.QuoteMeta(`SELECT se.id, se.seat_name, (se.isAvailable AND sdfb.id IS NULL) AS isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col
+	FROM showtimes sh`)
+	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col"}).
+		AddRow("1", "A-1", true, "studio1", "regular", "standard", 0, 0).
+		AddRow("2", "A-2", false, "studio1", "regular", "standard", 0, 1)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(rows)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	seats, err := suite.repo.FindAllByShowtime(context.Background(), showtimeID, tx, ginContext)
+	suite.NoError(err)
+	suite.Len(seats, 2)
+	suite.True(seats[0].IsAvailable)
+	suite.False(seats[1].IsAvailable)
+
+	suite.mockSql.ExpectCommit()
+	err = tx.Commit()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatRepositoryTestSuite) TestFindAllByShowtime_ScanError() {
+	showtimeID := "showtime1"
+	query := regexp.QuoteMeta(`SELECT se.id, se.seat_name, (se.isAvailable AND sdfb.id IS NULL) AS isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col
+	FROM showtimes sh`)
+	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col"}).
+		AddRow("1", "A-1", "not-a-bool", "studio1", "regular", "standard", 0, 0)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(rows)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	seats, err := suite.repo.FindAllByShowtime(context.Background(), showtimeID, tx, ginContext)
+	suite.Error(err)
+	suite.Nil(seats)
+	suite.Len(ginContext.Errors, 1)
+	suite.IsType(exception.InternalServerError{}, ginContext.Errors[0].Err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatRepositoryTestSuite) TestFindAllByShowtime_RowsError() {
+	showtimeID := "showtime1"
+	query := regexp.QuoteMeta(`SELECT se.id, se.seat_name, (se.isAvailable AND sdfb.id IS NULL) AS isAvailable, se.studio_id, se.category, se.kind, se.grid_row, se.grid_col
+	FROM showtimes sh`)
+	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col"}).
+		AddRow("1", "A-1", true, "studio1", "regular", "standard", 0, 0).
+		RowError(0, sql.ErrConnDone)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(rows)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	seats, err := suite.repo.FindAllByShowtime(context.Background(), showtimeID, tx, ginContext)
+	suite.ErrorIs(err, sql.ErrConnDone)
+	suite.Nil(seats)
+	suite.Len(ginContext.Errors, 1)
+	suite.IsType(exception.InternalServerError{}, ginContext.Errors[0].Err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
 func (suite *SeatRepositoryTestSuite) TestUpdate_Success() {
-	seat := entity.Seat{ID: "1", IsAvailable: false}
-	query := regexp.QuoteMeta(`UPDATE seats SET isAvailable = $1 WHERE id = $2`)
+	seat := entity.Seat{ID: "1", Name: "A-1", IsAvailable: false, Category: "premium", Kind: "standard", Row: 0, Col: 0}
+	query := regexp.QuoteMeta(`UPDATE seats SET seat_name = $1, isAvailable = $2, category = $3, kind = $4, grid_row = $5, grid_col = $6 WHERE id = $7`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(query).WithArgs(seat.IsAvailable, seat.ID).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSql.ExpectExec(query).WithArgs(seat.Name, seat.IsAvailable, seat.Category, seat.Kind, seat.Row, seat.Col, seat.ID).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	ginContext, _ := gin.CreateTestContext(nil)
 	updatedSeat, err := suite.repo.Update(context.Background(), tx, seat, ginContext)
@@ -201,14 +340,14 @@ func (suite *SeatRepositoryTestSuite) TestUpdate_Success() {
 }
 
 func (suite *SeatRepositoryTestSuite) TestUpdate_Error() {
-	seat := entity.Seat{ID: "1", IsAvailable: false}
-	query := regexp.QuoteMeta(`UPDATE seats SET isAvailable = $1 WHERE id = $2`)
+	seat := entity.Seat{ID: "1", Name: "A-1", IsAvailable: false, Category: "premium", Kind: "standard", Row: 0, Col: 0}
+	query := regexp.QuoteMeta(`UPDATE seats SET seat_name = $1, isAvailable = $2, category = $3, kind = $4, grid_row = $5, grid_col = $6 WHERE id = $7`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(query).WithArgs(seat.IsAvailable, seat.ID).WillReturnError(sql.ErrConnDone)
+	suite.mockSql.ExpectExec(query).WithArgs(seat.Name, seat.IsAvailable, seat.Category, seat.Kind, seat.Row, seat.Col, seat.ID).WillReturnError(sql.ErrConnDone)
 
 	ginContext, _ := gin.CreateTestContext(nil)
 	_, err = suite.repo.Update(context.Background(), tx, seat, ginContext)
@@ -224,10 +363,10 @@ func (suite *SeatRepositoryTestSuite) TestUpdate_Error() {
 
 func (suite *SeatRepositoryTestSuite) TestFindAll_Success() {
 	studioID := "studio1"
-	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE studio_id = $1`)
-	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id"}).
-		AddRow("1", "Seat 1", true, studioID).
-		AddRow("2", "Seat 2", true, studioID)
+	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col FROM seats WHERE studio_id = $1 AND retired_at IS NULL ORDER BY grid_row, grid_col`)
+	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col"}).
+		AddRow("1", "A-1", true, studioID, "regular", "standard", 0, 0).
+		AddRow("2", "A-2", true, studioID, "vip", "wheelchair", 0, 1)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -250,7 +389,7 @@ func (suite *SeatRepositoryTestSuite) TestFindAll_Success() {
 
 func (suite *SeatRepositoryTestSuite) TestFindAll_Error() {
 	studioID := "studio1"
-	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE studio_id = $1`)
+	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col FROM seats WHERE studio_id = $1 AND retired_at IS NULL ORDER BY grid_row, grid_col`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -271,17 +410,20 @@ func (suite *SeatRepositoryTestSuite) TestFindAll_Error() {
 }
 
 func (suite *SeatRepositoryTestSuite) TestDelete_Success() {
-	seatID := "1"
-	query := regexp.QuoteMeta(`DELETE FROM seats WHERE studio_id = $1`)
+	studioID := "1"
+	deleteQuery := regexp.QuoteMeta(`DELETE FROM seats se WHERE se.studio_id = $1
+	AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id)`)
+	retireQuery := regexp.QuoteMeta(`UPDATE seats SET retired_at = (NOW() AT TIME ZONE 'UTC') WHERE studio_id = $1 AND retired_at IS NULL`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(query).WithArgs(seatID).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSql.ExpectExec(deleteQuery).WithArgs(studioID).WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mockSql.ExpectExec(retireQuery).WithArgs(studioID).WillReturnResult(sqlmock.NewResult(0, 2))
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	err = suite.repo.Delete(context.Background(), tx, seatID, ginContext)
+	err = suite.repo.Delete(context.Background(), tx, studioID, ginContext)
 	suite.NoError(err)
 
 	suite.mockSql.ExpectCommit()
@@ -294,7 +436,7 @@ func (suite *SeatRepositoryTestSuite) TestDelete_Success() {
 
 func (suite *SeatRepositoryTestSuite) TestDelete_Error() {
 	seatID := "1"
-	query := regexp.QuoteMeta(`DELETE FROM seats WHERE studio_id = $1`)
+	query := regexp.QuoteMeta(`DELETE FROM seats se WHERE se.studio_id = $1`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -314,10 +456,79 @@ func (suite *SeatRepositoryTestSuite) TestDelete_Error() {
 	suite.NoError(err)
 }
 
+func (suite *SeatRepositoryTestSuite) TestRetire_Deleted() {
+	seatID := "1"
+	query := regexp.QuoteMeta(`DELETE FROM seats se WHERE se.id = $1
+	AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id)`)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectExec(query).WithArgs(seatID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	err = suite.repo.Retire(context.Background(), tx, seatID, ginContext)
+	suite.NoError(err)
+
+	suite.mockSql.ExpectCommit()
+	err = tx.Commit()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatRepositoryTestSuite) TestRetire_HasHistory() {
+	seatID := "1"
+	deleteQuery := regexp.QuoteMeta(`DELETE FROM seats se WHERE se.id = $1`)
+	retireQuery := regexp.QuoteMeta(`UPDATE seats SET retired_at = (NOW() AT TIME ZONE 'UTC') WHERE id = $1 AND retired_at IS NULL`)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectExec(deleteQuery).WithArgs(seatID).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mockSql.ExpectExec(retireQuery).WithArgs(seatID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	err = suite.repo.Retire(context.Background(), tx, seatID, ginContext)
+	suite.NoError(err)
+
+	suite.mockSql.ExpectCommit()
+	err = tx.Commit()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatRepositoryTestSuite) TestRetire_Error() {
+	seatID := "1"
+	query := regexp.QuoteMeta(`DELETE FROM seats se WHERE se.id = $1`)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectExec(query).WithArgs(seatID).WillReturnError(sql.ErrConnDone)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	err = suite.repo.Retire(context.Background(), tx, seatID, ginContext)
+	suite.Error(err)
+
+	suite.mockSql.ExpectRollback()
+	err = tx.Rollback()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
 func (suite *SeatRepositoryTestSuite) TestFindByID_NotFoundError() {
 	seatID := "1"
-	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id FROM seats WHERE id = $1`)
-	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id"})
+	query := regexp.QuoteMeta(`SELECT id, seat_name, isAvailable, studio_id, category, kind, grid_row, grid_col FROM seats WHERE id = $1`)
+	rows := sqlmock.NewRows([]string{"id", "seat_name", "isAvailable", "studio_id", "category", "kind", "grid_row", "grid_col"})
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -4,6 +4,7 @@ import (
 	"bioskuy/api/v1/seat/controller"
 	"bioskuy/api/v1/seat/repository"
 	"bioskuy/api/v1/seat/service"
+	repoStudio "bioskuy/api/v1/studio/repository"
 	"database/sql"
 
 	"github.com/gin-gonic/gin"
@@ -13,7 +14,8 @@ import (
 func SeatRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB) {
 
 	seatRepo := repository.NewSeatRepository()
-	seatService := service.NewSeatervice(seatRepo, validate, db)
+	studioRepo := repoStudio.NewStudioRepository()
+	seatService := service.NewSeatervice(seatRepo, studioRepo, validate, db)
 	seatController := controller.NewSeatController(seatService)
 
 	v1 := router.Group("/api/v1")
@@ -21,7 +23,8 @@ func SeatRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB) {
 		seats := v1.Group("/seats")
 		{
 			seats.GET("/:seatId", seatController.FindById)
-			seats.GET("/studio/:studioId", seatController.FindAll)
+			seats.GET("/studio/:studioId", seatController.SeatMap)
+			seats.GET("/showtime/:showtimeId", seatController.FindAllByShowtime)
 		}
 	}
 }
@@ -9,5 +9,6 @@ import (
 
 type SeatService interface {
 	FindByID(ctx context.Context, id string, c *gin.Context) (dto.SeatResponse, error)
-	FindAll(ctx context.Context, id string, c *gin.Context) ([]dto.SeatResponse, error)
-}
\ No newline at end of file
+	SeatMap(ctx context.Context, studioID string, c *gin.Context) (dto.SeatMapResponse, error)
+	FindAllByShowtime(ctx context.Context, showtimeID string, c *gin.Context) ([]dto.SeatResponse, error)
+}
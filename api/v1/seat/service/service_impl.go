@@ -2,25 +2,30 @@ package service
 
 import (
 	"bioskuy/api/v1/seat/dto"
+	"bioskuy/api/v1/seat/entity"
 	"bioskuy/api/v1/seat/repository"
+	entityStudio "bioskuy/api/v1/studio/entity"
+	repoStudio "bioskuy/api/v1/studio/repository"
 	"bioskuy/exception"
 	"bioskuy/helper"
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
 type seatService struct {
-	Repo     repository.SeatRepository
-	Validate *validator.Validate
-	DB *sql.DB
+	Repo       repository.SeatRepository
+	RepoStudio repoStudio.StudioRepository
+	Validate   *validator.Validate
+	DB         *sql.DB
 }
 
 
-func NewSeatervice(repo repository.SeatRepository, validate *validator.Validate, DB *sql.DB) SeatService {
-	return &seatService{Repo: repo, Validate: validate, DB: DB}
+func NewSeatervice(repo repository.SeatRepository, RepoStudio repoStudio.StudioRepository, validate *validator.Validate, DB *sql.DB) SeatService {
+	return &seatService{Repo: repo, RepoStudio: RepoStudio, Validate: validate, DB: DB}
 }
 
 func (s *seatService) FindByID(ctx context.Context, id string, c *gin.Context) (dto.SeatResponse, error){
@@ -40,41 +45,143 @@ func (s *seatService) FindByID(ctx context.Context, id string, c *gin.Context) (
 		return  SeatResponse, err
 	}
 
-	SeatResponse.ID = result.ID
-	SeatResponse.Name = result.Name
-	SeatResponse.IsAvailable = result.IsAvailable
-	SeatResponse.StudioID = result.StudioID
+	return toSeatResponse(result), nil
+}
+
+func (s *seatService) SeatMap(ctx context.Context, studioID string, c *gin.Context) (dto.SeatMapResponse, error) {
+	SeatMapResponse := dto.SeatMapResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return SeatMapResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	studio, err := s.RepoStudio.FindByID(ctx, tx, studioID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return SeatMapResponse, err
+	}
+
+	seats, err := s.Repo.FindAll(ctx, studio.ID, tx, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return SeatMapResponse, err
+	}
 
-	return SeatResponse, nil
+	layout := studio.Layout
+	if layout == nil {
+		derived := layoutFromSeats(seats)
+		layout = &derived
+	}
+
+	return buildSeatMap(studio.ID, *layout, seats), nil
 }
 
-func (s *seatService) FindAll(ctx context.Context, id string, c *gin.Context) ([]dto.SeatResponse, error){
-	StudioResponses := []dto.SeatResponse{}
+func (s *seatService) FindAllByShowtime(ctx context.Context, showtimeID string, c *gin.Context) ([]dto.SeatResponse, error){
+	SeatResponses := []dto.SeatResponse{}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  StudioResponses, err
+		return  SeatResponses, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	result, err := s.Repo.FindAll(ctx, id, tx, c)
+	result, err := s.Repo.FindAllByShowtime(ctx, showtimeID, tx, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  StudioResponses, err
+		return  SeatResponses, err
 	}
 
 	for _, seat := range result {
-		SeatResponse := dto.SeatResponse{}
+		SeatResponses = append(SeatResponses, toSeatResponse(seat))
+	}
+
+	return SeatResponses, nil
+}
+
+func toSeatResponse(seat entity.Seat) dto.SeatResponse {
+	return dto.SeatResponse{
+		ID:          seat.ID,
+		Name:        seat.Name,
+		IsAvailable: seat.IsAvailable,
+		StudioID:    seat.StudioID,
+		Category:    seat.Category,
+		Kind:        seat.Kind,
+		Row:         seat.Row,
+		Col:         seat.Col,
+	}
+}
 
-		SeatResponse.ID = seat.ID
-		SeatResponse.Name = seat.Name
-		SeatResponse.IsAvailable = seat.IsAvailable
-		SeatResponse.StudioID = seat.StudioID
+// buildSeatMap walks the layout cell by cell and attaches the seat standing
+// at each bookable position.
+func buildSeatMap(studioID string, layout entityStudio.Layout, seats []entity.Seat) dto.SeatMapResponse {
+	type position struct{ row, col int }
+	byPosition := map[position]entity.Seat{}
+	for _, seat := range seats {
+		byPosition[position{seat.Row, seat.Col}] = seat
+	}
+
+	seatMap := dto.SeatMapResponse{StudioID: studioID, Rows: []dto.SeatMapRow{}}
+	for i, row := range layout.Rows {
+		mapRow := dto.SeatMapRow{Label: layout.Label(i), Cells: []dto.SeatMapCell{}}
+
+		col := 0
+		for _, cell := range row.Cells {
+			mapCell := dto.SeatMapCell{Type: cell.Type, Col: col, Width: cell.Width()}
+			if seat, ok := byPosition[position{i, col}]; ok && cell.Bookable() {
+				response := toSeatResponse(seat)
+				mapCell.Seat = &response
+			}
+			mapRow.Cells = append(mapRow.Cells, mapCell)
+			col += cell.Width()
+		}
+
+		if col > seatMap.Columns {
+			seatMap.Columns = col
+		}
+		seatMap.Rows = append(seatMap.Rows, mapRow)
+	}
+
+	return seatMap
+}
 
-		StudioResponses = append(StudioResponses, SeatResponse)
-		
+// layoutFromSeats rebuilds a plain layout for studios created before layouts
+// were stored: every seat where it stands, gaps everywhere else.
+func layoutFromSeats(seats []entity.Seat) entityStudio.Layout {
+	layout := entityStudio.Layout{}
+	for _, seat := range seats {
+		for len(layout.Rows) <= seat.Row {
+			layout.Rows = append(layout.Rows, entityStudio.LayoutRow{})
+		}
+		row := &layout.Rows[seat.Row]
+
+		if label, _, found := strings.Cut(seat.Name, "-"); found {
+			row.Label = label
+		}
+
+		width := 0
+		for _, cell := range row.Cells {
+			width += cell.Width()
+		}
+		for ; width < seat.Col; width++ {
+			row.Cells = append(row.Cells, entityStudio.LayoutCell{Type: entityStudio.LayoutCellGap})
+		}
+		row.Cells = append(row.Cells, entityStudio.LayoutCell{Type: cellType(seat.Kind), Category: seat.Category})
 	}
 
-	return StudioResponses, nil
+	return layout
+}
+
+func cellType(kind string) string {
+	switch kind {
+	case entity.SeatKindWheelchair:
+		return entityStudio.LayoutCellWheelchair
+	case entity.SeatKindCouple:
+		return entityStudio.LayoutCellCouple
+	default:
+		return entityStudio.LayoutCellSeat
+	}
 }
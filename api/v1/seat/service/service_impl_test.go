@@ -4,6 +4,7 @@ import (
 	"bioskuy/api/v1/seat/dto"
 	"bioskuy/api/v1/seat/entity"
 	"bioskuy/api/v1/seat/mock/repomock"
+	entityStudio "bioskuy/api/v1/studio/entity"
 	"bioskuy/exception"
 	"context"
 	"database/sql"
@@ -21,19 +22,21 @@ import (
 
 type SeatServiceTestSuite struct {
 	suite.Suite
-	service  *seatService
-	mockRepo *repomock.SeatRepository
-	sqlMock  sqlmock.Sqlmock
-	db       *sql.DB
+	service        *seatService
+	mockRepo       *repomock.SeatRepository
+	mockStudioRepo *repomock.StudioRepository
+	sqlMock        sqlmock.Sqlmock
+	db             *sql.DB
 }
 
 func (suite *SeatServiceTestSuite) SetupTest() {
 	suite.mockRepo = new(repomock.SeatRepository)
+	suite.mockStudioRepo = new(repomock.StudioRepository)
 	validate := validator.New()
 	db, sqlMock, _ := sqlmock.New()
 	suite.db = db
 	suite.sqlMock = sqlMock
-	suite.service = &seatService{Repo: suite.mockRepo, Validate: validate, DB: db}
+	suite.service = &seatService{Repo: suite.mockRepo, RepoStudio: suite.mockStudioRepo, Validate: validate, DB: db}
 }
 
 func (suite *SeatServiceTestSuite) TearDownTest() {
@@ -85,65 +88,84 @@ func (suite *SeatServiceTestSuite) TestFindByID_NotFound() {
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
-func (suite *SeatServiceTestSuite) TestFindAll_Success() {
+func (suite *SeatServiceTestSuite) TestSeatMap_FromLayout() {
 	ctx := context.Background()
 	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
-	id := "1"
 
+	layout := entityStudio.Layout{Rows: []entityStudio.LayoutRow{
+		{Cells: []entityStudio.LayoutCell{{Type: "seat"}, {Type: "aisle"}, {Type: "seat", Category: "premium"}}},
+		{Label: "VIP", Category: "vip", Cells: []entityStudio.LayoutCell{{Type: "couple"}, {Type: "wheelchair"}}},
+	}}
 	mockSeats := []entity.Seat{
-		{
-			ID:          "1",
-			Name:        "A1",
-			IsAvailable: true,
-			StudioID:    "1",
-		},
-		{
-			ID:          "2",
-			Name:        "A2",
-			IsAvailable: false,
-			StudioID:    "1",
-		},
+		{ID: "1", Name: "A-1", IsAvailable: true, StudioID: "1", Category: "regular", Kind: "standard", Row: 0, Col: 0},
+		{ID: "2", Name: "A-2", IsAvailable: true, StudioID: "1", Category: "premium", Kind: "standard", Row: 0, Col: 2},
+		{ID: "3", Name: "VIP-1", IsAvailable: true, StudioID: "1", Category: "vip", Kind: "couple", Row: 1, Col: 0},
+		{ID: "4", Name: "VIP-2", IsAvailable: false, StudioID: "1", Category: "vip", Kind: "wheelchair", Row: 1, Col: 2},
 	}
 
 	suite.sqlMock.ExpectBegin()
 	suite.sqlMock.ExpectCommit()
 
-	suite.mockRepo.On("FindAll", ctx, id, mock.Anything, ginCtx).Return(mockSeats, nil).Once()
+	suite.mockStudioRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(entityStudio.Studio{ID: "1", Layout: &layout}, nil).Once()
+	suite.mockRepo.On("FindAll", ctx, "1", mock.Anything, ginCtx).Return(mockSeats, nil).Once()
 
-	result, err := suite.service.FindAll(ctx, id, ginCtx)
+	result, err := suite.service.SeatMap(ctx, "1", ginCtx)
 	assert.NoError(suite.T(), err)
-	assert.Equal(suite.T(), []dto.SeatResponse{
-		{
-			ID:          "1",
-			Name:        "A1",
-			IsAvailable: true,
-			StudioID:    "1",
-		},
-		{
-			ID:          "2",
-			Name:        "A2",
-			IsAvailable: false,
-			StudioID:    "1",
-		},
-	}, result)
+	assert.Equal(suite.T(), "1", result.StudioID)
+	assert.Equal(suite.T(), 3, result.Columns)
+	assert.Len(suite.T(), result.Rows, 2)
+
+	assert.Equal(suite.T(), "A", result.Rows[0].Label)
+	assert.Equal(suite.T(), "aisle", result.Rows[0].Cells[1].Type)
+	assert.Nil(suite.T(), result.Rows[0].Cells[1].Seat)
+	assert.Equal(suite.T(), "2", result.Rows[0].Cells[2].Seat.ID)
+
+	assert.Equal(suite.T(), "VIP", result.Rows[1].Label)
+	assert.Equal(suite.T(), dto.SeatMapCell{Type: "couple", Col: 0, Width: 2, Seat: &dto.SeatResponse{
+		ID: "3", Name: "VIP-1", IsAvailable: true, StudioID: "1", Category: "vip", Kind: "couple", Row: 1, Col: 0,
+	}}, result.Rows[1].Cells[0])
+	assert.Equal(suite.T(), 2, result.Rows[1].Cells[1].Col)
+	assert.False(suite.T(), result.Rows[1].Cells[1].Seat.IsAvailable)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
-func (suite *SeatServiceTestSuite) TestFindAll_NotFound() {
+func (suite *SeatServiceTestSuite) TestSeatMap_WithoutLayout() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	mockSeats := []entity.Seat{
+		{ID: "1", Name: "A-1", IsAvailable: true, StudioID: "1", Row: 0, Col: 0},
+		{ID: "2", Name: "A-2", IsAvailable: true, StudioID: "1", Row: 0, Col: 1},
+		{ID: "3", Name: "B-2", IsAvailable: true, StudioID: "1", Row: 1, Col: 1},
+	}
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	suite.mockStudioRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(entityStudio.Studio{ID: "1"}, nil).Once()
+	suite.mockRepo.On("FindAll", ctx, "1", mock.Anything, ginCtx).Return(mockSeats, nil).Once()
+
+	result, err := suite.service.SeatMap(ctx, "1", ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, result.Columns)
+	assert.Equal(suite.T(), "B", result.Rows[1].Label)
+	assert.Equal(suite.T(), "gap", result.Rows[1].Cells[0].Type)
+	assert.Equal(suite.T(), "3", result.Rows[1].Cells[1].Seat.ID)
+}
+
+func (suite *SeatServiceTestSuite) TestSeatMap_StudioNotFound() {
 	ctx := context.Background()
 	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
-	id := "1"
 
 	suite.sqlMock.ExpectBegin()
 	suite.sqlMock.ExpectRollback()
 
-	suite.mockRepo.On("FindAll", ctx, id, mock.Anything, ginCtx).Return([]entity.Seat{}, exception.NotFoundError{Message: "seats not found"}).Once()
+	suite.mockStudioRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(entityStudio.Studio{}, errors.New("studio not found")).Once()
 
-	result, err := suite.service.FindAll(ctx, id, ginCtx)
+	_, err := suite.service.SeatMap(ctx, "1", ginCtx)
 	assert.Error(suite.T(), err)
-	assert.IsType(suite.T(), exception.NotFoundError{}, err)
-	assert.Equal(suite.T(), []dto.SeatResponse{}, result)
-	suite.mockRepo.AssertExpectations(suite.T())
+	assert.IsType(suite.T(), exception.NotFoundError{}, ginCtx.Errors.Last().Err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindAll", ctx, "1", mock.Anything, ginCtx)
 }
 
 func (suite *SeatServiceTestSuite) TestFindByID_DBBeginError() {
@@ -161,7 +183,7 @@ func (suite *SeatServiceTestSuite) TestFindByID_DBBeginError() {
 	suite.mockRepo.AssertNotCalled(suite.T(), "FindByID", ctx, mock.Anything, id, ginCtx)
 }
 
-func (suite *SeatServiceTestSuite) TestFindAll_DBBeginError() {
+func (suite *SeatServiceTestSuite) TestSeatMap_DBBeginError() {
 	ctx := context.Background()
 	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	id := "1"
@@ -169,11 +191,10 @@ func (suite *SeatServiceTestSuite) TestFindAll_DBBeginError() {
 	// Simulate error when beginning a transaction
 	suite.sqlMock.ExpectBegin().WillReturnError(errors.New("begin error"))
 
-	result, err := suite.service.FindAll(ctx, id, ginCtx)
+	_, err := suite.service.SeatMap(ctx, id, ginCtx)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "begin error", err.Error())
-	assert.Equal(suite.T(), []dto.SeatResponse{}, result)
-	suite.mockRepo.AssertNotCalled(suite.T(), "FindAll", ctx, id, mock.Anything, ginCtx)
+	suite.mockStudioRepo.AssertNotCalled(suite.T(), "FindByID", ctx, mock.Anything, id, ginCtx)
 }
 
 func TestSeatServiceTestSuite(t *testing.T) {
@@ -8,5 +8,6 @@ type ShowtimeController interface {
 	Create(c *gin.Context)
 	FindById(c *gin.Context)
 	FindAll(c *gin.Context)
+	FindMine(c *gin.Context)
 	Delete(c *gin.Context)
 }
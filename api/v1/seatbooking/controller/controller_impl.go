@@ -4,6 +4,7 @@ import (
 	"bioskuy/api/v1/seatbooking/dto"
 	"bioskuy/api/v1/seatbooking/service"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"bioskuy/web"
 	"net/http"
 
@@ -49,7 +50,7 @@ func (controller *seatbookingControllerImpl) FindById(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("seatbookingId")
 
-	result, err := controller.Service.FindByID(ctx, id, c)
+	result, err := controller.Service.FindByID(ctx, id, c.GetString("user_id"), c.GetString("role"), c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return
@@ -62,17 +63,46 @@ func (controller *seatbookingControllerImpl) FindById(c *gin.Context) {
 }
 
 func (controller *seatbookingControllerImpl) FindAll(c *gin.Context) {
+	query, err := helper.ListQueryFrom(c, "status", "user_id", "showtime_id", "movie_id", "cinema_id")
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	query, ok := helper.ScopeToCinema(c, query)
+	if !ok {
+		c.Error(exception.ForbiddenError{Message: "your account is not assigned to a cinema"}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	controller.list(c, query)
+}
+
+// FindMine lists the signed-in user's own bookings, optionally only those for
+// upcoming or past showtimes.
+func (controller *seatbookingControllerImpl) FindMine(c *gin.Context) {
+	query, err := helper.OwnListQuery(c, "status", "movie_id", "cinema_id")
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	controller.list(c, query)
+}
+
+func (controller *seatbookingControllerImpl) list(c *gin.Context, query helper.ListQuery) {
 	ctx := c.Request.Context()
 
-	result, err := controller.Service.FindAll(ctx, c)
+	result, paging, err := controller.Service.FindAll(ctx, query, c)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return
 	}
 
-	response := web.FormatResponse{
+	response := web.FormatResponsePaging{
 		ResponseCode: http.StatusOK,
 		Data:         result,
+		Paging:        paging,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -83,7 +113,7 @@ func (ctl *seatbookingControllerImpl) Delete(c *gin.Context) {
 	response := web.FormatResponse{}
 	id := c.Param("seatbookingId")
 
-	err := ctl.Service.Delete(ctx, id, c)
+	err := ctl.Service.Delete(ctx, id, c.GetString("user_id"), c.GetString("role"), c)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return
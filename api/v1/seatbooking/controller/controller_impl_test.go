@@ -4,6 +4,8 @@ import (
 	"bioskuy/api/v1/seatbooking/dto"
 	"bioskuy/api/v1/seatbooking/mock/servicemock"
 	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"bytes"
 	"context"
 	"errors"
@@ -27,9 +29,18 @@ func (suite *SeatBookingControllerTestSuite) SetupTest() {
 	suite.mockService = new(servicemock.MockSeatBookingService)
 	controller := NewSeatbookingController(suite.mockService)
 	suite.router = gin.Default()
+	suite.router.Use(exception.ErrorHandler)
 	suite.router.POST("/seatbooking", controller.Create)
 	suite.router.GET("/seatbooking/:seatbookingId", controller.FindById)
 	suite.router.GET("/seatbooking", controller.FindAll)
+	suite.router.GET("/me/bookings", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Set("role", "user")
+	}, controller.FindMine)
+	suite.router.GET("/cinema/seatbooking", func(c *gin.Context) {
+		c.Set("role", "branch admin")
+		c.Set("cinema_id", "cinema-1")
+	}, controller.FindAll)
 	suite.router.DELETE("/seatbooking/:seatbookingId", controller.Delete)
 }
 
@@ -37,7 +48,7 @@ func (suite *SeatBookingControllerTestSuite) TestCreate_Success() {
 	mockResponse := dto.CreateSeatBookingResponse{ID: "1"}
 	suite.mockService.On("Create", mock.Anything, mock.Anything, "test_user", mock.Anything).Return(mockResponse, nil)
 
-	reqBody := `{"show_id":"1", "seat_id":"A1", "customer":"John Doe"}`
+	reqBody := `{"showtime_id":"1", "seat_ids":["A1", "A2"]}`
 	req, _ := http.NewRequest(http.MethodPost, "/seatbooking", bytes.NewBufferString(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	req = req.WithContext(context.WithValue(req.Context(), "user_id", "test_user"))
@@ -61,8 +72,8 @@ func (suite *SeatBookingControllerTestSuite) TestCreate_BindError() {
 }
 
 func (suite *SeatBookingControllerTestSuite) TestFindById_Success() {
-	mockResponse := dto.SeatBookingResponse{ID: "1", ShowtimeID: "1", SeatID: "A1", UserID: "John Doe"}
-	suite.mockService.On("FindByID", mock.Anything, "1", mock.Anything).Return(mockResponse, nil)
+	mockResponse := dto.SeatBookingResponse{ID: "1", ShowtimeID: "1", Seats: []dto.SeatDetailResponse{{SeatID: "A1"}}, UserID: "John Doe"}
+	suite.mockService.On("FindByID", mock.Anything, "1", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, nil)
 
 	req, _ := http.NewRequest(http.MethodGet, "/seatbooking/1", nil)
 	resp := httptest.NewRecorder()
@@ -74,7 +85,7 @@ func (suite *SeatBookingControllerTestSuite) TestFindById_Success() {
 }
 
 func (suite *SeatBookingControllerTestSuite) TestFindById_NotFound() {
-	suite.mockService.On("FindByID", mock.Anything, "2", mock.Anything).Return(dto.SeatBookingResponse{}, exception.NotFoundError{Message: "not found"})
+	suite.mockService.On("FindByID", mock.Anything, "2", mock.Anything, mock.Anything, mock.Anything).Return(dto.SeatBookingResponse{}, exception.NotFoundError{Message: "not found"})
 
 	req, _ := http.NewRequest(http.MethodGet, "/seatbooking/2", nil)
 	resp := httptest.NewRecorder()
@@ -86,10 +97,10 @@ func (suite *SeatBookingControllerTestSuite) TestFindById_NotFound() {
 }
 
 func (suite *SeatBookingControllerTestSuite) TestFindAll_Success() {
-	mockResponse := []dto.SeatBookingResponse{{ID: "1", ShowtimeID: "1", SeatID: "A1", UserID: "John Doe"}}
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(mockResponse, nil)
+	mockResponse := []dto.SeatBookingResponse{{ID: "1", ShowtimeID: "1", Seats: []dto.SeatDetailResponse{{SeatID: "A1"}}, UserID: "John Doe"}}
+	suite.mockService.On("FindAll", mock.Anything, helper.ListQuery{Page: 1, Size: 10, Sort: "status", Desc: true}.With("status", "pending"), mock.Anything).Return(mockResponse, web.Paging{Page: 1, Size: 10, TotalData: 1, TotalPages: 1}, nil)
 
-	req, _ := http.NewRequest(http.MethodGet, "/seatbooking", nil)
+	req, _ := http.NewRequest(http.MethodGet, "/seatbooking?status=pending&sort=status&order=desc", nil)
 	resp := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(resp, req)
@@ -99,7 +110,7 @@ func (suite *SeatBookingControllerTestSuite) TestFindAll_Success() {
 }
 
 func (suite *SeatBookingControllerTestSuite) TestFindAll_InternalServerError() {
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(nil, errors.New("internal server error"))
+	suite.mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return([]dto.SeatBookingResponse{}, web.Paging{}, errors.New("internal server error"))
 
 	req, _ := http.NewRequest(http.MethodGet, "/seatbooking", nil)
 	resp := httptest.NewRecorder()
@@ -110,8 +121,58 @@ func (suite *SeatBookingControllerTestSuite) TestFindAll_InternalServerError() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+func (suite *SeatBookingControllerTestSuite) TestFindAll_BranchAdminScopedToCinema() {
+	query := helper.ListQuery{Page: 1, Size: 10}.With("cinema_id", "cinema-1")
+	suite.mockService.On("FindAll", mock.Anything, query, mock.Anything).Return([]dto.SeatBookingResponse{}, web.Paging{Page: 1, Size: 10}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/cinema/seatbooking?cinema_id=cinema-2", nil)
+	resp := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *SeatBookingControllerTestSuite) TestFindAll_BranchAdminWithoutCinemaForbidden() {
+	suite.router.GET("/unassigned/seatbooking", func(c *gin.Context) {
+		c.Set("role", "branch admin")
+	}, NewSeatbookingController(suite.mockService).FindAll)
+
+	req, _ := http.NewRequest(http.MethodGet, "/unassigned/seatbooking", nil)
+	resp := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, resp.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "FindAll", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SeatBookingControllerTestSuite) TestFindMine_Past() {
+	query := helper.ListQuery{Page: 1, Size: 10, Desc: true}.With("when", "past").With("user_id", "user-1")
+	suite.mockService.On("FindAll", mock.Anything, query, mock.Anything).Return([]dto.SeatBookingResponse{}, web.Paging{Page: 1, Size: 10}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/me/bookings?when=past&user_id=someone-else", nil)
+	resp := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *SeatBookingControllerTestSuite) TestFindAll_BadOrder() {
+	req, _ := http.NewRequest(http.MethodGet, "/seatbooking?order=sideways", nil)
+	resp := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "FindAll")
+}
+
 func (suite *SeatBookingControllerTestSuite) TestDelete_Success() {
-	suite.mockService.On("Delete", mock.Anything, "1", mock.Anything).Return(nil)
+	suite.mockService.On("Delete", mock.Anything, "1", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	req, _ := http.NewRequest(http.MethodDelete, "/seatbooking/1", nil)
 	resp := httptest.NewRecorder()
@@ -123,7 +184,7 @@ func (suite *SeatBookingControllerTestSuite) TestDelete_Success() {
 }
 
 func (suite *SeatBookingControllerTestSuite) TestDelete_InternalServerError() {
-	suite.mockService.On("Delete", mock.Anything, "2", mock.Anything).Return(errors.New("internal server error"))
+	suite.mockService.On("Delete", mock.Anything, "2", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("internal server error"))
 
 	req, _ := http.NewRequest(http.MethodDelete, "/seatbooking/2", nil)
 	resp := httptest.NewRecorder()
@@ -1,8 +1,10 @@
 package dto
 
+import "time"
+
 type SeatBookingRequest struct {
-	SeatID     string `json:"seat_id" validate:"required"`
-	ShowtimeID string `json:"showtime_id" validate:"required"`
+	SeatIDs    []string `json:"seat_ids" validate:"required,min=1,unique,dive,required"`
+	ShowtimeID string   `json:"showtime_id" validate:"required"`
 }
 
 type CreateSeatBookingRequest struct {
@@ -13,17 +15,32 @@ type CreateSeatBookingRequest struct {
 }
 
 type CreateSeatBookingResponse struct {
-	ID            string `json:"id"`
-	SeatID        string `json:"seat_id"`
-	SeatBookingID string `json:"seatbooking_id"`
+	ID            string               `json:"id"`
+	ShowtimeID    string               `json:"showtime_id"`
+	Seats         []SeatDetailResponse `json:"seats"`
+	TotalPrice    int                  `json:"total_price"`
+	HoldExpiresAt time.Time            `json:"hold_expires_at"`
+}
+
+type SeatDetailResponse struct {
+	ID              string `json:"id"`
+	SeatID          string `json:"seat_id"`
+	SeatName        string `json:"seat_name"`
+	SeatIsAvailable string `json:"seat_isAvailabe,omitempty"`
+	UnitPrice       int    `json:"unit_price"`
 }
 
+// SeatBookingResponse renders show_start and show_end as wall-clock time in
+// the cinema's timezone, with the UTC instants alongside.
 type SeatBookingResponse struct {
 	ID string `json:"id"`
 
-	ShowtimeID string `json:"showtime_id"`
-	ShowStart  string `json:"show_start"`
-	ShowEnd    string `json:"show_end"`
+	ShowtimeID   string    `json:"showtime_id"`
+	ShowStart    time.Time `json:"show_start"`
+	ShowEnd      time.Time `json:"show_end"`
+	ShowStartUTC time.Time `json:"show_start_utc"`
+	ShowEndUTC   time.Time `json:"show_end_utc"`
+	Timezone     string    `json:"timezone"`
 
 	MovieID          string `json:"movie_id"`
 	MovieTitle       string `json:"movie_title"`
@@ -35,11 +52,10 @@ type SeatBookingResponse struct {
 	StudioID   string `json:"studio_id" validate:"required"`
 	StudioName string `json:"studio_name"`
 
-	SeatID          string `json:"seat_id"`
-	SeatName        string `json:"seat_name"`
-	SeatIsAvailable string `json:"seat_isAvailabe"`
+	Seats []SeatDetailResponse `json:"seats"`
 
-	UserID                 string `json:"user_id"`
-	SeatDetailForBookingID string `json:"seat_detail_for_booking_id"`
-	SeatBookingStatus      string `json:"seat_booking_status"`
+	UserID            string    `json:"user_id"`
+	SeatBookingStatus string    `json:"seat_booking_status"`
+	HoldExpiresAt     time.Time `json:"hold_expires_at"`
+	HoldExpired       bool      `json:"hold_expired"`
 }
@@ -1,11 +1,15 @@
 package entity
 
+import "time"
+
 type SeatBooking struct {
 	ID string `json:"id"`
 
-	ShowtimeID string `json:"showtime_id"`
-	ShowStart  string `json:"show_start"`
-	ShowEnd    string `json:"show_end"`
+	ShowtimeID string    `json:"showtime_id"`
+	ShowStart  time.Time `json:"show_start"`
+	ShowEnd    time.Time `json:"show_end"`
+	// Timezone is the IANA timezone of the cinema.
+	Timezone string `json:"timezone"`
 
 	MovieID          string `json:"movie_id"`
 	MovieTitle       string `json:"movie_title"`
@@ -17,11 +21,27 @@ type SeatBooking struct {
 	StudioID   string `json:"studio_id" validate:"required"`
 	StudioName string `json:"studio_name"`
 
+	Seats []SeatDetail `json:"seats"`
+
+	UserID            string    `json:"user_id"`
+	SeatBookingStatus string    `json:"seat_booking_status"`
+	HoldExpiresAt     time.Time `json:"hold_expires_at"`
+}
+
+// TotalPrice is the sum of the prices the booked seats were reserved at.
+func (s SeatBooking) TotalPrice() int {
+	total := 0
+	for _, seat := range s.Seats {
+		total += seat.UnitPrice
+	}
+	return total
+}
+
+type SeatDetail struct {
+	ID              string `json:"id"`
 	SeatID          string `json:"seat_id"`
 	SeatName        string `json:"seat_name"`
 	SeatIsAvailable string `json:"seat_isAvailabe"`
-
-	UserID                 string `json:"user_id"`
-	SeatDetailForBookingID string `json:"seat_detail_for_booking_id"`
-	SeatBookingStatus      string `json:"seat_booking_status"`
-}
\ No newline at end of file
+	// UnitPrice is what the seat was priced at when it was reserved.
+	UnitPrice int `json:"unit_price"`
+}
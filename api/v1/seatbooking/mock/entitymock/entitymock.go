@@ -12,8 +12,8 @@ var MockSeatBookingEntity = entity.SeatBooking{
 	ID: "booking123",
 
 	ShowtimeID: "showtime456",
-	ShowStart:  "2024-07-10T15:00:00Z",
-	ShowEnd:    "2024-07-10T17:00:00Z",
+	ShowStart:  time.Date(2024, 7, 10, 15, 0, 0, 0, time.UTC),
+	ShowEnd:    time.Date(2024, 7, 10, 17, 0, 0, 0, time.UTC),
 
 	MovieID:          "movie789",
 	MovieTitle:       "Avengers: Endgame",
@@ -25,17 +25,16 @@ var MockSeatBookingEntity = entity.SeatBooking{
 	StudioID:   "studio456",
 	StudioName: "Studio 1",
 
-	SeatID:          "seat123",
-	SeatName:        "A1",
-	SeatIsAvailable: "false",
+	Seats: []entity.SeatDetail{
+		{ID: "seatdetail789", SeatID: "seat123", SeatName: "A1", SeatIsAvailable: "false"},
+	},
 
-	UserID:                 "user123",
-	SeatDetailForBookingID: "seatdetail789",
-	SeatBookingStatus:      "confirmed",
+	UserID:            "user123",
+	SeatBookingStatus: "confirmed",
 }
 
 var MockSeatBookingRequest = dto.SeatBookingRequest{
-	SeatID:     "seat123",
+	SeatIDs:    []string{"seat123"},
 	ShowtimeID: "showtime456",
 }
 
@@ -47,17 +46,19 @@ var MockCreateSeatBookingRequest = dto.CreateSeatBookingRequest{
 }
 
 var MockCreateSeatBookingResponse = dto.CreateSeatBookingResponse{
-	ID:            "booking789",
-	SeatID:        "seat123",
-	SeatBookingID: "seatbooking789",
+	ID:         "booking789",
+	ShowtimeID: "showtime456",
+	Seats: []dto.SeatDetailResponse{
+		{ID: "seatdetail789", SeatID: "seat123", SeatName: "A1"},
+	},
 }
 
 var MockSeatBookingResponse = dto.SeatBookingResponse{
 	ID: "booking789",
 
 	ShowtimeID: "showtime456",
-	ShowStart:  "2024-07-10T15:00:00Z",
-	ShowEnd:    "2024-07-10T17:00:00Z",
+	ShowStart:  time.Date(2024, 7, 10, 15, 0, 0, 0, time.UTC),
+	ShowEnd:    time.Date(2024, 7, 10, 17, 0, 0, 0, time.UTC),
 
 	MovieID:          "movie789",
 	MovieTitle:       "Avengers: Endgame",
@@ -69,13 +70,12 @@ var MockSeatBookingResponse = dto.SeatBookingResponse{
 	StudioID:   "studio456",
 	StudioName: "Studio 1",
 
-	SeatID:          "seat123",
-	SeatName:        "A1",
-	SeatIsAvailable: "false",
+	Seats: []dto.SeatDetailResponse{
+		{ID: "seatdetail789", SeatID: "seat123", SeatName: "A1", SeatIsAvailable: "false"},
+	},
 
-	UserID:                 "user123",
-	SeatDetailForBookingID: "seatdetail789",
-	SeatBookingStatus:      "confirmed",
+	UserID:            "user123",
+	SeatBookingStatus: "confirmed",
 }
 
 var MockShowtimeEntity = eST.Showtime{
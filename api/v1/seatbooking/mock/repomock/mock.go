@@ -1,12 +1,16 @@
 package mock
 
 import (
+	ePay "bioskuy/api/v1/payment/entity"
+	eP "bioskuy/api/v1/pricing/entity"
 	eS "bioskuy/api/v1/seat/entity"
 	"bioskuy/api/v1/seatbooking/entity"
 	eSO "bioskuy/api/v1/showtime/entity"
 	eSt "bioskuy/api/v1/studio/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
@@ -31,13 +35,18 @@ func (m *SeatBookingRepositoryMock) FindAllPendingByUserID(ctx context.Context,
 	return args.Get(0).([]entity.SeatBooking), args.Error(1)
 }
 
-func (m *SeatBookingRepositoryMock) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.SeatBooking, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]entity.SeatBooking), args.Error(1)
+func (m *SeatBookingRepositoryMock) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.SeatBooking, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entity.SeatBooking), args.Int(1), args.Error(2)
 }
 
-func (m *SeatBookingRepositoryMock) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+func (m *SeatBookingRepositoryMock) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) ([]ePay.FailedPayment, error) {
 	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).([]ePay.FailedPayment), args.Error(1)
+}
+
+func (m *SeatBookingRepositoryMock) DeleteSeats(ctx context.Context, tx *sql.Tx, id string, seatIDs []string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, seatIDs, c)
 	return args.Error(0)
 }
 
@@ -46,6 +55,11 @@ func (m *SeatBookingRepositoryMock) Update(ctx context.Context, tx *sql.Tx, paym
 	return args.Get(0).(entity.SeatBooking), args.Error(1)
 }
 
+func (m *SeatBookingRepositoryMock) FindExpiredPending(ctx context.Context, tx *sql.Tx, now time.Time, c *gin.Context) ([]entity.SeatBooking, error) {
+	args := m.Called(ctx, tx, now, c)
+	return args.Get(0).([]entity.SeatBooking), args.Error(1)
+}
+
 type MockShowtimeRepository struct {
 	mock.Mock
 }
@@ -60,9 +74,9 @@ func (m *MockShowtimeRepository) FindByID(ctx context.Context, tx *sql.Tx, id st
 	return args.Get(0).(eSO.Showtime), args.Error(1)
 }
 
-func (m *MockShowtimeRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]eSO.Showtime, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]eSO.Showtime), args.Error(1)
+func (m *MockShowtimeRepository) FindAll(ctx context.Context, tx *sql.Tx, filter eSO.ShowtimeFilter, c *gin.Context) ([]eSO.Showtime, int, error) {
+	args := m.Called(ctx, tx, filter, c)
+	return args.Get(0).([]eSO.Showtime), args.Int(1), args.Error(2)
 }
 
 func (m *MockShowtimeRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
@@ -75,6 +89,41 @@ func (m *MockShowtimeRepository) FindConflictingShowtimes(ctx context.Context, t
 	return args.Error(0)
 }
 
+func (m *MockShowtimeRepository) Update(ctx context.Context, tx *sql.Tx, showtime eSO.Showtime, c *gin.Context) (eSO.Showtime, error) {
+	args := m.Called(ctx, tx, showtime, c)
+	return args.Get(0).(eSO.Showtime), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) Cancel(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) ([]ePay.FailedPayment, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).([]ePay.FailedPayment), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) FindBookedSeats(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) ([]eSO.BookedSeat, error) {
+	args := m.Called(ctx, tx, showtimeID, c)
+	return args.Get(0).([]eSO.BookedSeat), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) MoveBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, seatID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, detailID, seatID, c)
+	return args.Error(0)
+}
+
+func (m *MockShowtimeRepository) ReleaseBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, detailID, c)
+	return args.Error(0)
+}
+
+func (m *MockShowtimeRepository) HasBookings(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (bool, error) {
+	args := m.Called(ctx, tx, showtimeID, c)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) FlagForRefund(ctx context.Context, tx *sql.Tx, flag eSO.RefundFlag, c *gin.Context) (eSO.RefundFlag, error) {
+	args := m.Called(ctx, tx, flag, c)
+	return args.Get(0).(eSO.RefundFlag), args.Error(1)
+}
+
 type SeatRepository struct {
 	mock.Mock
 }
@@ -89,8 +138,8 @@ func (m *SeatRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c
 	return args.Get(0).(eS.Seat), args.Error(1)
 }
 
-func (m *SeatRepository) FindByIDWithNotAvailable(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (eS.Seat, error) {
-	args := m.Called(ctx, tx, id, c)
+func (m *SeatRepository) FindAvailableByShowtime(ctx context.Context, tx *sql.Tx, id string, showtimeID string, c *gin.Context) (eS.Seat, error) {
+	args := m.Called(ctx, tx, id, showtimeID, c)
 	return args.Get(0).(eS.Seat), args.Error(1)
 }
 
@@ -104,7 +153,56 @@ func (m *SeatRepository) FindAll(ctx context.Context, id string, tx *sql.Tx, c *
 	return args.Get(0).([]eS.Seat), args.Error(1)
 }
 
+func (m *SeatRepository) FindAllByShowtime(ctx context.Context, showtimeID string, tx *sql.Tx, c *gin.Context) ([]eS.Seat, error) {
+	args := m.Called(ctx, showtimeID, tx, c)
+	return args.Get(0).([]eS.Seat), args.Error(1)
+}
+
 func (m *SeatRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
 	args := m.Called(ctx, tx, id, c)
 	return args.Error(0)
 }
+
+func (m *SeatRepository) Retire(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
+
+type MockPricingRepository struct {
+	mock.Mock
+}
+
+func (m *MockPricingRepository) SaveRule(ctx context.Context, tx *sql.Tx, rule eP.Rule, c *gin.Context) (eP.Rule, error) {
+	args := m.Called(ctx, tx, rule, c)
+	return args.Get(0).(eP.Rule), args.Error(1)
+}
+
+func (m *MockPricingRepository) FindAllRules(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]eP.Rule, error) {
+	args := m.Called(ctx, tx, c)
+	return args.Get(0).([]eP.Rule), args.Error(1)
+}
+
+func (m *MockPricingRepository) DeleteRule(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
+
+func (m *MockPricingRepository) SaveHoliday(ctx context.Context, tx *sql.Tx, holiday eP.Holiday, c *gin.Context) (eP.Holiday, error) {
+	args := m.Called(ctx, tx, holiday, c)
+	return args.Get(0).(eP.Holiday), args.Error(1)
+}
+
+func (m *MockPricingRepository) FindAllHolidays(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]eP.Holiday, error) {
+	args := m.Called(ctx, tx, c)
+	return args.Get(0).([]eP.Holiday), args.Error(1)
+}
+
+func (m *MockPricingRepository) DeleteHoliday(ctx context.Context, tx *sql.Tx, date string, c *gin.Context) error {
+	args := m.Called(ctx, tx, date, c)
+	return args.Error(0)
+}
+
+func (m *MockPricingRepository) FindSlot(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (eP.Slot, error) {
+	args := m.Called(ctx, tx, showtimeID, c)
+	return args.Get(0).(eP.Slot), args.Error(1)
+}
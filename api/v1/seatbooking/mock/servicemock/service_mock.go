@@ -2,6 +2,8 @@ package servicemock
 
 import (
 	"bioskuy/api/v1/seatbooking/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -17,17 +19,17 @@ func (m *MockSeatBookingService) Create(ctx context.Context, request dto.SeatBoo
 	return args.Get(0).(dto.CreateSeatBookingResponse), args.Error(1)
 }
 
-func (m *MockSeatBookingService) FindByID(ctx context.Context, id string, c *gin.Context) (dto.SeatBookingResponse, error) {
-	args := m.Called(ctx, id, c)
+func (m *MockSeatBookingService) FindByID(ctx context.Context, id string, userID string, role string, c *gin.Context) (dto.SeatBookingResponse, error) {
+	args := m.Called(ctx, id, userID, role, c)
 	return args.Get(0).(dto.SeatBookingResponse), args.Error(1)
 }
 
-func (m *MockSeatBookingService) FindAll(ctx context.Context, c *gin.Context) ([]dto.SeatBookingResponse, error) {
-	args := m.Called(ctx, c)
-	return args.Get(0).([]dto.SeatBookingResponse), args.Error(1)
+func (m *MockSeatBookingService) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.SeatBookingResponse, web.Paging, error) {
+	args := m.Called(ctx, query, c)
+	return args.Get(0).([]dto.SeatBookingResponse), args.Get(1).(web.Paging), args.Error(2)
 }
 
-func (m *MockSeatBookingService) Delete(ctx context.Context, id string, c *gin.Context) error {
-	args := m.Called(ctx, id, c)
+func (m *MockSeatBookingService) Delete(ctx context.Context, id string, userID string, role string, c *gin.Context) error {
+	args := m.Called(ctx, id, userID, role, c)
 	return args.Error(0)
 }
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	entityPayment "bioskuy/api/v1/payment/entity"
 	"bioskuy/api/v1/seatbooking/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,8 +14,10 @@ import (
 type SeatBookingRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, seatbooking entity.SeatBooking, c *gin.Context) (entity.SeatBooking, error)
 	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.SeatBooking, error)
-	FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.SeatBooking, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.SeatBooking, int, error)
 	FindAllPendingByUserID(ctx context.Context, tx *sql.Tx, userID string, c *gin.Context) ([]entity.SeatBooking, error) 
-	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) ([]entityPayment.FailedPayment, error)
+	DeleteSeats(ctx context.Context, tx *sql.Tx, id string, seatIDs []string, c *gin.Context) error
 	Update(ctx context.Context, tx *sql.Tx, payment entity.SeatBooking, c *gin.Context) (entity.SeatBooking, error)
+	FindExpiredPending(ctx context.Context, tx *sql.Tx, now time.Time, c *gin.Context) ([]entity.SeatBooking, error)
 }
@@ -1,13 +1,17 @@
 package repository
 
 import (
+	entityPayment "bioskuy/api/v1/payment/entity"
 	"bioskuy/api/v1/seatbooking/entity"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
 type seatBookingRepository struct {
@@ -19,20 +23,27 @@ func NewSeatBookingRepository() SeatBookingRepository {
 
 func (r *seatBookingRepository) Save(ctx context.Context, tx *sql.Tx, seatbooking entity.SeatBooking, c *gin.Context) (entity.SeatBooking, error){
 
-	queryForSeatBooking := "INSERT INTO seat_bookings (user_id, showtime_id) VALUES ($1, $2) RETURNING id"
+	queryForSeatBooking := "INSERT INTO seat_bookings (user_id, showtime_id, hold_expires_at) VALUES ($1, $2, $3) RETURNING id"
 
-	err := tx.QueryRowContext(ctx, queryForSeatBooking, seatbooking.UserID, seatbooking.ShowtimeID).Scan(&seatbooking.ID)
+	err := tx.QueryRowContext(ctx, queryForSeatBooking, seatbooking.UserID, seatbooking.ShowtimeID, seatbooking.HoldExpiresAt).Scan(&seatbooking.ID)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return seatbooking, err
 	}
 
-	queryForSeatDetailForBooking := "INSERT INTO seat_detail_for_bookings (seat_id, seatBooking_id) VALUES ($1, $2) RETURNING id"
+	queryForSeatDetailForBooking := "INSERT INTO seat_detail_for_bookings (seat_id, seatBooking_id, showtime_id, unit_price) VALUES ($1, $2, $3, $4) RETURNING id"
 
-	err = tx.QueryRowContext(ctx, queryForSeatDetailForBooking, seatbooking.SeatID, seatbooking.ID).Scan(&seatbooking.SeatDetailForBookingID)
-	if err != nil {
-		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return seatbooking, err
+	for i := range seatbooking.Seats {
+		err = tx.QueryRowContext(ctx, queryForSeatDetailForBooking, seatbooking.Seats[i].SeatID, seatbooking.ID, seatbooking.ShowtimeID, seatbooking.Seats[i].UnitPrice).Scan(&seatbooking.Seats[i].ID)
+		if isUniqueViolation(err) {
+			conflict := exception.ConflictError{Message: "seat " + seatbooking.Seats[i].SeatID + " has already been booked for this showtime"}
+			c.Error(conflict).SetType(gin.ErrorTypePublic)
+			return seatbooking, conflict
+		}
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return seatbooking, err
+		}
 	}
 
 	return seatbooking, nil
@@ -41,18 +52,20 @@ func (r *seatBookingRepository) Save(ctx context.Context, tx *sql.Tx, seatbookin
 func (r *seatBookingRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.SeatBooking, error) {
 	query := `
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -70,109 +83,169 @@ func (r *seatBookingRepository) FindByID(ctx context.Context, tx *sql.Tx, id str
 	}
 	defer rows.Close()
 
-	if rows.Next() {
-		err := rows.Scan(
-			&seatBookingResponse.ID, &seatBookingResponse.SeatBookingStatus, &seatBookingResponse.UserID,
-			&seatBookingResponse.ShowtimeID, &seatBookingResponse.StudioID, &seatBookingResponse.MovieID, &seatBookingResponse.ShowStart, &seatBookingResponse.ShowEnd,
-			&seatBookingResponse.StudioName,
-			&seatBookingResponse.MovieTitle, &seatBookingResponse.MovieDescription, &seatBookingResponse.MoviePrice, &seatBookingResponse.MovieDuration, &seatBookingResponse.MovieStatus,
-			&seatBookingResponse.SeatDetailForBookingID, &seatBookingResponse.SeatID,
-			&seatBookingResponse.SeatName, &seatBookingResponse.SeatIsAvailable,
-		)
-		if err != nil {
-			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-			return seatBookingResponse, err
-		}
+	seatBookings, err := scanSeatBookings(rows)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return seatBookingResponse, err
+	}
 
-		return seatBookingResponse, nil
-	} else {
+	if len(seatBookings) == 0 {
 		return seatBookingResponse, errors.New("seatbooking not found")
 	}
+
+	return seatBookings[0], nil
+}
+
+// seatBookingListSpec is what the booking listing can be sorted, filtered
+// and searched by.
+var seatBookingListSpec = helper.ListSpec{
+	Sorts: map[string]string{
+		"show_start":      "s.show_start",
+		"status":          "sb.status",
+		"movie_title":     "m.title",
+		"hold_expires_at": "sb.hold_expires_at",
+	},
+	DefaultSort: "show_start",
+	Key:         "sb.id",
+	Filters: map[string]string{
+		"status":      "sb.status::text",
+		"user_id":     "sb.user_id::text",
+		"showtime_id": "s.id::text",
+		"movie_id":    "m.id::text",
+		"cinema_id":   "st.cinema_id::text",
+	},
+	Scopes: map[string]map[string]string{
+		"when": {
+			"upcoming": "s.show_end > NOW() AT TIME ZONE 'UTC'",
+			"past":     "s.show_end <= NOW() AT TIME ZONE 'UTC'",
+		},
+	},
+	Search: []string{"m.title", "st.name"},
 }
 
-func (r *seatBookingRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.SeatBooking, error) {
+// FindAll pages over bookings rather than over their seat rows, so a page
+// always holds whole bookings.
+func (r *seatBookingRepository) FindAll(ctx context.Context, tx *sql.Tx, listQuery helper.ListQuery, c *gin.Context) ([]entity.SeatBooking, int, error) {
+	clauses, err := listQuery.Clauses(seatBookingListSpec)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	bookings := `
+		FROM
+			seat_bookings sb
+		JOIN
+			showtimes s ON sb.showtime_id = s.id
+		JOIN
+			studios st ON s.studio_id = st.id
+		JOIN
+			movies m ON s.movie_id = m.id
+	`
+
+	total := 0
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*)`+bookings+clauses.Where, clauses.Args...).Scan(&total); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
 	query := `
+		WITH page AS (SELECT sb.id` + bookings + clauses.Where + ` ` + clauses.Page + `)
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
-			seat_bookings sb
+			page
+		JOIN
+			seat_bookings sb ON sb.id = page.id
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
 			seat_detail_for_bookings sdfb ON sb.id = sdfb.seatBooking_id
 		JOIN
 			seats se ON sdfb.seat_id = se.id
+		` + clauses.OrderBy + `, se.seat_name
 	`
-	rows, err := tx.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query, clauses.AllArgs()...)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var seatBookings []entity.SeatBooking
+	seatBookings, err := scanSeatBookings(rows)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
 
+	return seatBookings, total, nil
+}
+
+// Delete releases a booking's seats and fails its open payments, returning
+// the payments it failed so their events can be published.
+func (r *seatBookingRepository) Delete(ctx context.Context, tx *sql.Tx, seatBookingID string, c *gin.Context) ([]entityPayment.FailedPayment, error) {
+
+	failOpenPaymentQuery := `UPDATE payments p SET status = 'failed'
+		FROM payments old
+		WHERE p.id = old.id AND p.seatbooking_id = $1 AND p.status IN ('unpaid', 'pending')
+		RETURNING p.id, p.user_id, p.seatbooking_id, old.status, p.total_price`
+	rows, err := tx.QueryContext(ctx, failOpenPaymentQuery, seatBookingID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	failed := []entityPayment.FailedPayment{}
 	for rows.Next() {
-		var seatBookingResponse entity.SeatBooking
-		err := rows.Scan(
-			&seatBookingResponse.ID, &seatBookingResponse.SeatBookingStatus, &seatBookingResponse.UserID,
-			&seatBookingResponse.ShowtimeID, &seatBookingResponse.StudioID, &seatBookingResponse.MovieID, &seatBookingResponse.ShowStart, &seatBookingResponse.ShowEnd,
-			&seatBookingResponse.StudioName,
-			&seatBookingResponse.MovieTitle, &seatBookingResponse.MovieDescription, &seatBookingResponse.MoviePrice, &seatBookingResponse.MovieDuration, &seatBookingResponse.MovieStatus,
-			&seatBookingResponse.SeatDetailForBookingID, &seatBookingResponse.SeatID,
-			&seatBookingResponse.SeatName, &seatBookingResponse.SeatIsAvailable,
-		)
+		payment := entityPayment.FailedPayment{}
+		err := rows.Scan(&payment.ID, &payment.UserID, &payment.SeatBookingID, &payment.PreviousStatus, &payment.TotalPrice)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 			return nil, err
 		}
-
-		seatBookings = append(seatBookings, seatBookingResponse)
+		failed = append(failed, payment)
 	}
-
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return nil, err
 	}
 
-	return seatBookings, nil
-}
-
-func (r *seatBookingRepository) Delete(ctx context.Context, tx *sql.Tx, seatBookingID string, c *gin.Context) error {
-
-	updateSeatQuery := `UPDATE seats SET isAvailable = true WHERE id IN (SELECT seat_id FROM seat_detail_for_bookings WHERE seatBooking_id = $1)
-	`
-	_, err := tx.ExecContext(ctx, updateSeatQuery, seatBookingID)
-	if err != nil {
-		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return err
-	}
-
 	deleteSeatDetailQuery := `DELETE FROM seat_detail_for_bookings  WHERE seatBooking_id = $1 `
 	_, err = tx.ExecContext(ctx, deleteSeatDetailQuery, seatBookingID)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return err
+		return nil, err
 	}
 
-	updateSeatBookingQuery := `UPDATE seat_bookings SET status = 'active' WHERE id = $1`
-	_, err = tx.ExecContext(ctx, updateSeatBookingQuery, seatBookingID)
+	// The booking row is kept so the payments and events that reference it survive.
+	cancelSeatBookingQuery := `UPDATE seat_bookings SET status = 'cancelled' WHERE id = $1`
+	_, err = tx.ExecContext(ctx, cancelSeatBookingQuery, seatBookingID)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return err
+		return nil, err
 	}
 
-	deleteSeatBookingQuery := `DELETE FROM seat_bookings WHERE id = $1`
-	_, err = tx.ExecContext(ctx, deleteSeatBookingQuery, seatBookingID)
+	return failed, nil
+}
+
+// DeleteSeats releases some of a booking's seats, leaving the booking and its
+// other seats in place.
+func (r *seatBookingRepository) DeleteSeats(ctx context.Context, tx *sql.Tx, seatBookingID string, seatIDs []string, c *gin.Context) error {
+
+	query := `DELETE FROM seat_detail_for_bookings WHERE seatBooking_id = $1 AND seat_id = ANY($2)`
+	_, err := tx.ExecContext(ctx, query, seatBookingID, pq.Array(seatIDs))
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return err
@@ -184,18 +257,20 @@ func (r *seatBookingRepository) Delete(ctx context.Context, tx *sql.Tx, seatBook
 func (r *seatBookingRepository) FindAllPendingByUserID(ctx context.Context, tx *sql.Tx, userID string, c *gin.Context) ([]entity.SeatBooking, error) {
 	query := `
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -211,44 +286,94 @@ func (r *seatBookingRepository) FindAllPendingByUserID(ctx context.Context, tx *
 	}
 	defer rows.Close()
 
+	return scanSeatBookings(rows)
+}
+
+func (r *seatBookingRepository) Update(ctx context.Context, tx *sql.Tx, seatbooking entity.SeatBooking, c *gin.Context) (entity.SeatBooking, error){
+
+	query := `UPDATE seat_bookings SET status = $1 WHERE id = $2`
+
+	_, err := tx.ExecContext(ctx, query, seatbooking.SeatBookingStatus, seatbooking.ID)
+
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return  seatbooking, err
+	}
+
+	return seatbooking, nil
+}
+
+func (r *seatBookingRepository) FindExpiredPending(ctx context.Context, tx *sql.Tx, now time.Time, c *gin.Context) ([]entity.SeatBooking, error) {
+	query := `SELECT id, user_id, showtime_id, status, hold_expires_at FROM seat_bookings WHERE status = 'pending' AND hold_expires_at <= $1 FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.QueryContext(ctx, query, now)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
 	var seatBookings []entity.SeatBooking
 
 	for rows.Next() {
-		var seatBookingResponse entity.SeatBooking
-		err := rows.Scan(
-			&seatBookingResponse.ID, &seatBookingResponse.SeatBookingStatus, &seatBookingResponse.UserID,
-			&seatBookingResponse.ShowtimeID, &seatBookingResponse.StudioID, &seatBookingResponse.MovieID, &seatBookingResponse.ShowStart, &seatBookingResponse.ShowEnd,
-			&seatBookingResponse.StudioName,
-			&seatBookingResponse.MovieTitle, &seatBookingResponse.MovieDescription, &seatBookingResponse.MoviePrice, &seatBookingResponse.MovieDuration, &seatBookingResponse.MovieStatus,
-			&seatBookingResponse.SeatDetailForBookingID, &seatBookingResponse.SeatID,
-			&seatBookingResponse.SeatName, &seatBookingResponse.SeatIsAvailable,
-		)
+		var seatBooking entity.SeatBooking
+		err := rows.Scan(&seatBooking.ID, &seatBooking.UserID, &seatBooking.ShowtimeID, &seatBooking.SeatBookingStatus, &seatBooking.HoldExpiresAt)
 		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 			return nil, err
 		}
 
-		seatBookings = append(seatBookings, seatBookingResponse)
+		seatBookings = append(seatBookings, seatBooking)
 	}
 
 	if err = rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return nil, err
 	}
 
 	return seatBookings, nil
 }
 
-func (r *seatBookingRepository) Update(ctx context.Context, tx *sql.Tx, seatbooking entity.SeatBooking, c *gin.Context) (entity.SeatBooking, error){
+// isUniqueViolation reports whether err comes from the (showtime_id, seat_id)
+// unique constraint, i.e. another transaction booked the seat first.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
 
-	query := `UPDATE seat_bookings SET status = $1 WHERE id = $2`
+// scanSeatBookings folds the one-row-per-seat result of the booking queries
+// into one SeatBooking per booking id, keeping the order the rows came in.
+func scanSeatBookings(rows *sql.Rows) ([]entity.SeatBooking, error) {
+	var seatBookings []entity.SeatBooking
+	index := map[string]int{}
 
-	_, err := tx.ExecContext(ctx, query, seatbooking.SeatBookingStatus, seatbooking.ID)
+	for rows.Next() {
+		var seatBooking entity.SeatBooking
+		var seat entity.SeatDetail
+		err := rows.Scan(
+			&seatBooking.ID, &seatBooking.SeatBookingStatus, &seatBooking.UserID, &seatBooking.HoldExpiresAt,
+			&seatBooking.ShowtimeID, &seatBooking.StudioID, &seatBooking.MovieID, &seatBooking.ShowStart, &seatBooking.ShowEnd,
+			&seatBooking.StudioName, &seatBooking.Timezone,
+			&seatBooking.MovieTitle, &seatBooking.MovieDescription, &seatBooking.MoviePrice, &seatBooking.MovieDuration, &seatBooking.MovieStatus,
+			&seat.ID, &seat.SeatID,
+			&seat.SeatName, &seat.SeatIsAvailable, &seat.UnitPrice,
+		)
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  seatbooking, err
+		i, ok := index[seatBooking.ID]
+		if !ok {
+			i = len(seatBookings)
+			index[seatBooking.ID] = i
+			seatBookings = append(seatBookings, seatBooking)
+		}
+		seatBookings[i].Seats = append(seatBookings[i].Seats, seat)
 	}
 
-	return seatbooking, nil
-}
-
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	return seatBookings, nil
+}
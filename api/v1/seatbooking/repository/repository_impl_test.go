@@ -1,15 +1,20 @@
 package repository
 
 import (
+	entityPayment "bioskuy/api/v1/payment/entity"
 	"bioskuy/api/v1/seatbooking/entity"
+	"bioskuy/exception"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"errors"
 	"regexp"
+	"time"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -33,14 +38,15 @@ func (suite *SeatBookingRepositoryTestSuite) TearDownTest() {
 
 // Test Save Method
 func (suite *SeatBookingRepositoryTestSuite) TestSave_Success() {
-	queryForSeatBooking := regexp.QuoteMeta("INSERT INTO seat_bookings (user_id, showtime_id) VALUES ($1, $2) RETURNING id")
-	queryForSeatDetailForBooking := regexp.QuoteMeta("INSERT INTO seat_detail_for_bookings (seat_id, seatBooking_id) VALUES ($1, $2) RETURNING id")
+	queryForSeatBooking := regexp.QuoteMeta("INSERT INTO seat_bookings (user_id, showtime_id, hold_expires_at) VALUES ($1, $2, $3) RETURNING id")
+	queryForSeatDetailForBooking := regexp.QuoteMeta("INSERT INTO seat_detail_for_bookings (seat_id, seatBooking_id, showtime_id, unit_price) VALUES ($1, $2, $3, $4) RETURNING id")
 
-	seatBooking := entity.SeatBooking{UserID: "user1", ShowtimeID: "showtime1", SeatID: "seat1"}
+	seatBooking := entity.SeatBooking{UserID: "user1", ShowtimeID: "showtime1", Seats: []entity.SeatDetail{{SeatID: "seat1", UnitPrice: 50000}, {SeatID: "seat2", UnitPrice: 75000}}}
 
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(queryForSeatBooking).WithArgs(seatBooking.UserID, seatBooking.ShowtimeID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
-	suite.mockSql.ExpectQuery(queryForSeatDetailForBooking).WithArgs(seatBooking.SeatID, "1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	suite.mockSql.ExpectQuery(queryForSeatBooking).WithArgs(seatBooking.UserID, seatBooking.ShowtimeID, seatBooking.HoldExpiresAt).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	suite.mockSql.ExpectQuery(queryForSeatDetailForBooking).WithArgs("seat1", "1", seatBooking.ShowtimeID, 50000).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("10"))
+	suite.mockSql.ExpectQuery(queryForSeatDetailForBooking).WithArgs("seat2", "1", seatBooking.ShowtimeID, 75000).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("11"))
 
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
@@ -49,7 +55,8 @@ func (suite *SeatBookingRepositoryTestSuite) TestSave_Success() {
 	seatBooking, err = suite.repo.Save(context.Background(), tx, seatBooking, ginContext)
 	suite.NoError(err)
 	suite.Equal("1", seatBooking.ID)
-	suite.Equal("1", seatBooking.SeatDetailForBookingID)
+	suite.Equal("10", seatBooking.Seats[0].ID)
+	suite.Equal("11", seatBooking.Seats[1].ID)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -60,12 +67,12 @@ func (suite *SeatBookingRepositoryTestSuite) TestSave_Success() {
 }
 
 func (suite *SeatBookingRepositoryTestSuite) TestSave_Error() {
-	queryForSeatBooking := regexp.QuoteMeta("INSERT INTO seat_bookings (user_id, showtime_id) VALUES ($1, $2) RETURNING id")
+	queryForSeatBooking := regexp.QuoteMeta("INSERT INTO seat_bookings (user_id, showtime_id, hold_expires_at) VALUES ($1, $2, $3) RETURNING id")
 
-	seatBooking := entity.SeatBooking{UserID: "user1", ShowtimeID: "showtime1", SeatID: "seat1"}
+	seatBooking := entity.SeatBooking{UserID: "user1", ShowtimeID: "showtime1", Seats: []entity.SeatDetail{{SeatID: "seat1"}}}
 
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(queryForSeatBooking).WithArgs(seatBooking.UserID, seatBooking.ShowtimeID).WillReturnError(errors.New("insert error"))
+	suite.mockSql.ExpectQuery(queryForSeatBooking).WithArgs(seatBooking.UserID, seatBooking.ShowtimeID, seatBooking.HoldExpiresAt).WillReturnError(errors.New("insert error"))
 
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
@@ -83,22 +90,70 @@ func (suite *SeatBookingRepositoryTestSuite) TestSave_Error() {
 	suite.NoError(err)
 }
 
+func (suite *SeatBookingRepositoryTestSuite) TestSave_SeatAlreadyBooked() {
+	queryForSeatBooking := regexp.QuoteMeta("INSERT INTO seat_bookings (user_id, showtime_id, hold_expires_at) VALUES ($1, $2, $3) RETURNING id")
+	queryForSeatDetailForBooking := regexp.QuoteMeta("INSERT INTO seat_detail_for_bookings (seat_id, seatBooking_id, showtime_id, unit_price) VALUES ($1, $2, $3, $4) RETURNING id")
+
+	seatBooking := entity.SeatBooking{UserID: "user1", ShowtimeID: "showtime1", Seats: []entity.SeatDetail{{SeatID: "seat1"}}}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(queryForSeatBooking).WithArgs(seatBooking.UserID, seatBooking.ShowtimeID, seatBooking.HoldExpiresAt).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	suite.mockSql.ExpectQuery(queryForSeatDetailForBooking).WithArgs("seat1", "1", seatBooking.ShowtimeID, 0).WillReturnError(&pq.Error{Code: "23505"})
+
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	_, err = suite.repo.Save(context.Background(), tx, seatBooking, ginContext)
+	suite.IsType(exception.ConflictError{}, err)
+	suite.EqualError(err, "seat seat1 has already been booked for this showtime")
+	suite.IsType(exception.ConflictError{}, ginContext.Errors.Last().Err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatBookingRepositoryTestSuite) TestSave_OtherConstraintIsNotConflict() {
+	queryForSeatBooking := regexp.QuoteMeta("INSERT INTO seat_bookings (user_id, showtime_id, hold_expires_at) VALUES ($1, $2, $3) RETURNING id")
+	queryForSeatDetailForBooking := regexp.QuoteMeta("INSERT INTO seat_detail_for_bookings (seat_id, seatBooking_id, showtime_id, unit_price) VALUES ($1, $2, $3, $4) RETURNING id")
+
+	seatBooking := entity.SeatBooking{UserID: "user1", ShowtimeID: "showtime1", Seats: []entity.SeatDetail{{SeatID: "seat1"}}}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(queryForSeatBooking).WithArgs(seatBooking.UserID, seatBooking.ShowtimeID, seatBooking.HoldExpiresAt).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	suite.mockSql.ExpectQuery(queryForSeatDetailForBooking).WithArgs("seat1", "1", seatBooking.ShowtimeID, 0).WillReturnError(&pq.Error{Code: "23503"})
+
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	_, err = suite.repo.Save(context.Background(), tx, seatBooking, ginContext)
+	suite.Error(err)
+	suite.IsType(&pq.Error{}, err)
+	suite.IsType(exception.InternalServerError{}, ginContext.Errors.Last().Err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
 func (suite *SeatBookingRepositoryTestSuite) TestFindByID_Success() {
 	seatBookingID := "1"
 	query := regexp.QuoteMeta(`
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -109,11 +164,13 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindByID_Success() {
 			sb.id = $1
 	`)
 	rows := sqlmock.NewRows([]string{
-		"id", "status", "user_id", "showtime_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name",
+		"id", "status", "user_id", "hold_expires_at", "showtime_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "cinema_timezone",
 		"movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
-		"seat_detail_for_booking_id", "seat_id", "seat_name", "isAvailable",
-	}).AddRow(seatBookingID, "booked", "user1", "showtime1", "studio1", "movie1", "2023-07-10 10:00:00", "2023-07-10 12:00:00", "Studio 1",
-		"Movie 1", "Description", 100, 120, "active", "2", "seat1", "Seat 1", true)
+		"seat_detail_for_booking_id", "seat_id", "seat_name", "isAvailable", "unit_price",
+	}).AddRow(seatBookingID, "booked", "user1", time.Now(), "showtime1", "studio1", "movie1", time.Date(2023, 7, 10, 10, 0, 0, 0, time.UTC), time.Date(2023, 7, 10, 12, 0, 0, 0, time.UTC), "Studio 1", "Asia/Jakarta",
+		"Movie 1", "Description", 100, 120, "active", "2", "seat1", "Seat 1", true, 100).
+		AddRow(seatBookingID, "booked", "user1", time.Now(), "showtime1", "studio1", "movie1", time.Date(2023, 7, 10, 10, 0, 0, 0, time.UTC), time.Date(2023, 7, 10, 12, 0, 0, 0, time.UTC), "Studio 1", "Asia/Jakarta",
+			"Movie 1", "Description", 100, 120, "active", "3", "seat2", "Seat 2", true, 100)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -126,6 +183,8 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindByID_Success() {
 	suite.NoError(err)
 	suite.Equal(seatBookingID, seatBooking.ID)
 	suite.Equal("booked", seatBooking.SeatBookingStatus)
+	suite.Len(seatBooking.Seats, 2)
+	suite.Equal("seat2", seatBooking.Seats[1].SeatID)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -139,18 +198,20 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindByID_Error() {
 	seatBookingID := "1"
 	query := regexp.QuoteMeta(`
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -181,46 +242,66 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindByID_Error() {
 
 func (suite *SeatBookingRepositoryTestSuite) TestFindAll_Success() {
 	query := regexp.QuoteMeta(`
+		WITH page AS (SELECT sb.id
+		FROM
+			seat_bookings sb
+		JOIN
+			showtimes s ON sb.showtime_id = s.id
+		JOIN
+			studios st ON s.studio_id = st.id
+		JOIN
+			movies m ON s.movie_id = m.id
+		WHERE sb.status::text = $1 AND sb.user_id::text = $2 ORDER BY s.show_start DESC, sb.id LIMIT $3 OFFSET $4)
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
-			seat_bookings sb
+			page
+		JOIN
+			seat_bookings sb ON sb.id = page.id
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
 			seat_detail_for_bookings sdfb ON sb.id = sdfb.seatBooking_id
 		JOIN
 			seats se ON sdfb.seat_id = se.id
+		ORDER BY s.show_start DESC, sb.id, se.seat_name
 	`)
 	rows := sqlmock.NewRows([]string{
-		"id", "status", "user_id", "showtime_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name",
+		"id", "status", "user_id", "hold_expires_at", "showtime_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "cinema_timezone",
 		"movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
-		"seat_detail_for_booking_id", "seat_id", "seat_name", "isAvailable",
+		"seat_detail_for_booking_id", "seat_id", "seat_name", "isAvailable", "unit_price",
 	}).
-		AddRow("1", "booked", "user1", "showtime1", "studio1", "movie1", "2023-07-10 10:00:00", "2023-07-10 12:00:00", "Studio 1",
-			"Movie 1", "Description 1", 100, 120, "active", "2", "seat1", "Seat 1", true).
-		AddRow("2", "booked", "user2", "showtime2", "studio2", "movie2", "2023-07-11 14:00:00", "2023-07-11 16:00:00", "Studio 2",
-			"Movie 2", "Description 2", 120, 140, "active", "3", "seat2", "Seat 2", true)
+		AddRow("1", "booked", "user1", time.Now(), "showtime1", "studio1", "movie1", time.Date(2023, 7, 10, 10, 0, 0, 0, time.UTC), time.Date(2023, 7, 10, 12, 0, 0, 0, time.UTC), "Studio 1", "Asia/Jakarta",
+			"Movie 1", "Description 1", 100, 120, "active", "2", "seat1", "Seat 1", true, 100).
+		AddRow("2", "booked", "user1", time.Now(), "showtime2", "studio2", "movie2", time.Date(2023, 7, 11, 14, 0, 0, 0, time.UTC), time.Date(2023, 7, 11, 16, 0, 0, 0, time.UTC), "Studio 2", "Asia/Jakarta",
+			"Movie 2", "Description 2", 120, 140, "active", "3", "seat2", "Seat 2", true, 100)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectQuery(query).WillReturnRows(rows)
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM seat_bookings sb JOIN showtimes s ON sb.showtime_id = s.id JOIN studios st ON s.studio_id = st.id JOIN movies m ON s.movie_id = m.id WHERE sb.status::text = $1 AND sb.user_id::text = $2`)).
+		WithArgs("booked", "user1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(9))
+	suite.mockSql.ExpectQuery(query).WithArgs("booked", "user1", 2, 2).WillReturnRows(rows)
 
+	listQuery := helper.ListQuery{Page: 2, Size: 2, Desc: true}.With("user_id", "user1").With("status", "booked")
 	ginContext, _ := gin.CreateTestContext(nil)
-	seatBookings, err := suite.repo.FindAll(context.Background(), tx, ginContext)
+	seatBookings, total, err := suite.repo.FindAll(context.Background(), tx, listQuery, ginContext)
 	suite.NoError(err)
 	suite.Len(seatBookings, 2)
+	suite.Equal(9, total)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -231,36 +312,17 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindAll_Success() {
 }
 
 func (suite *SeatBookingRepositoryTestSuite) TestFindAll_Error() {
-	query := regexp.QuoteMeta(`
-		SELECT
-			sb.id, sb.status, sb.user_id,
-			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
-			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
-			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
-		FROM
-			seat_bookings sb
-		JOIN
-			showtimes s ON sb.showtime_id = s.id
-		JOIN
-			studios st ON s.studio_id = st.id
-		JOIN
-			movies m ON s.movie_id = m.id
-		JOIN
-			seat_detail_for_bookings sdfb ON sb.id = sdfb.seatBooking_id
-		JOIN
-			seats se ON sdfb.seat_id = se.id
-	`)
+	query := regexp.QuoteMeta(`WITH page AS (SELECT sb.id`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 	suite.mockSql.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	_, err = suite.repo.FindAll(context.Background(), tx, ginContext)
+	_, _, err = suite.repo.FindAll(context.Background(), tx, helper.ListQuery{Page: 1, Size: 10}, ginContext)
 	suite.Error(err)
 
 	suite.mockSql.ExpectRollback()
@@ -271,25 +333,63 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindAll_Error() {
 	suite.NoError(err)
 }
 
+func (suite *SeatBookingRepositoryTestSuite) TestFindAll_Upcoming() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`) + ".*" + regexp.QuoteMeta(`WHERE sb.user_id::text = $1 AND s.show_end > NOW() AT TIME ZONE 'UTC'`)).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`WITH page AS (SELECT sb.id`)).
+		WithArgs("user-1", 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	listQuery := helper.ListQuery{Page: 1, Size: 10}.With("user_id", "user-1").With("when", "upcoming")
+	_, total, err := suite.repo.FindAll(context.Background(), tx, listQuery, ginContext)
+	suite.NoError(err)
+	suite.Equal(0, total)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatBookingRepositoryTestSuite) TestFindAll_UnknownWhen() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	listQuery := helper.ListQuery{Page: 1, Size: 10}.With("when", "someday")
+	_, _, err = suite.repo.FindAll(context.Background(), tx, listQuery, ginContext)
+	suite.EqualError(err, "when must be one of past, upcoming")
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
 func (suite *SeatBookingRepositoryTestSuite) TestDelete_Success() {
 	seatBookingID := "1"
-	updateSeatQuery := regexp.QuoteMeta(`UPDATE seats SET isAvailable = true WHERE id IN (SELECT seat_id FROM seat_detail_for_bookings WHERE seatBooking_id = $1)`)
+	failOpenPaymentQuery := regexp.QuoteMeta(`UPDATE payments p SET status = 'failed' FROM payments old WHERE p.id = old.id AND p.seatbooking_id = $1 AND p.status IN ('unpaid', 'pending') RETURNING p.id, p.user_id, p.seatbooking_id, old.status, p.total_price`)
 	deleteSeatDetailQuery := regexp.QuoteMeta(`DELETE FROM seat_detail_for_bookings WHERE seatBooking_id = $1`)
-	updateSeatBookingQuery := regexp.QuoteMeta(`UPDATE seat_bookings SET status = 'active' WHERE id = $1`)
-	deleteSeatBookingQuery := regexp.QuoteMeta(`DELETE FROM seat_bookings WHERE id = $1`)
+	cancelSeatBookingQuery := regexp.QuoteMeta(`UPDATE seat_bookings SET status = 'cancelled' WHERE id = $1`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(updateSeatQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSql.ExpectQuery(failOpenPaymentQuery).WithArgs(seatBookingID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "seatbooking_id", "status", "total_price"}).AddRow("payment-1", "user-1", seatBookingID, "pending", 100000))
 	suite.mockSql.ExpectExec(deleteSeatDetailQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
-	suite.mockSql.ExpectExec(updateSeatBookingQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
-	suite.mockSql.ExpectExec(deleteSeatBookingQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSql.ExpectExec(cancelSeatBookingQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
+	failed, err := suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
 	suite.NoError(err)
+	suite.Equal([]entityPayment.FailedPayment{
+		{ID: "payment-1", UserID: "user-1", SeatBookingID: seatBookingID, PreviousStatus: "pending", TotalPrice: 100000},
+	}, failed)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -301,19 +401,16 @@ func (suite *SeatBookingRepositoryTestSuite) TestDelete_Success() {
 
 func (suite *SeatBookingRepositoryTestSuite) TestDelete_Error() {
 	seatBookingID := "1"
-	updateSeatQuery := regexp.QuoteMeta(`UPDATE seats SET isAvailable = true WHERE id IN (SELECT seat_id FROM seat_detail_for_bookings WHERE seatBooking_id = $1)`)
-	deleteSeatDetailQuery := regexp.QuoteMeta(`DELETE FROM seat_detail_for_bookings WHERE seatBooking_id = $1`)
-	updateSeatBookingQuery := regexp.QuoteMeta(`UPDATE seat_bookings SET status = 'active' WHERE id = $1`)
-	deleteSeatBookingQuery := regexp.QuoteMeta(`DELETE FROM seat_bookings WHERE id = $1`)
+	failOpenPaymentQuery := regexp.QuoteMeta(`UPDATE payments p SET status = 'failed' FROM payments old WHERE p.id = old.id AND p.seatbooking_id = $1 AND p.status IN ('unpaid', 'pending') RETURNING p.id, p.user_id, p.seatbooking_id, old.status, p.total_price`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(updateSeatQuery).WithArgs(seatBookingID).WillReturnError(sql.ErrConnDone)
+	suite.mockSql.ExpectQuery(failOpenPaymentQuery).WithArgs(seatBookingID).WillReturnError(sql.ErrConnDone)
 	suite.mockSql.ExpectRollback()
 	ginContext, _ := gin.CreateTestContext(nil)
-	err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
+	_, err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
 	suite.Error(err)
 
 	err = tx.Rollback()
@@ -321,26 +418,22 @@ func (suite *SeatBookingRepositoryTestSuite) TestDelete_Error() {
 
 	err = suite.mockSql.ExpectationsWereMet()
 	suite.NoError(err)
-
-	_ = deleteSeatDetailQuery
-	_ = updateSeatBookingQuery
-	_ = deleteSeatBookingQuery
 }
 
 func (suite *SeatBookingRepositoryTestSuite) TestDelete_DeleteSeatDetailQueryError() {
 	seatBookingID := "1"
-	updateSeatQuery := regexp.QuoteMeta(`UPDATE seats SET isAvailable = true WHERE id IN (SELECT seat_id FROM seat_detail_for_bookings WHERE seatBooking_id = $1)`)
+	failOpenPaymentQuery := regexp.QuoteMeta(`UPDATE payments p SET status = 'failed' FROM payments old WHERE p.id = old.id AND p.seatbooking_id = $1 AND p.status IN ('unpaid', 'pending') RETURNING p.id, p.user_id, p.seatbooking_id, old.status, p.total_price`)
 	deleteSeatDetailQuery := regexp.QuoteMeta(`DELETE FROM seat_detail_for_bookings WHERE seatBooking_id = $1`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(updateSeatQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSql.ExpectQuery(failOpenPaymentQuery).WithArgs(seatBookingID).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "seatbooking_id", "status", "total_price"}))
 	suite.mockSql.ExpectExec(deleteSeatDetailQuery).WithArgs(seatBookingID).WillReturnError(sql.ErrConnDone)
 	suite.mockSql.ExpectRollback()
 	ginContext, _ := gin.CreateTestContext(nil)
-	err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
+	_, err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
 	suite.Error(err)
 
 	err = tx.Rollback()
@@ -350,22 +443,22 @@ func (suite *SeatBookingRepositoryTestSuite) TestDelete_DeleteSeatDetailQueryErr
 	suite.NoError(err)
 }
 
-func (suite *SeatBookingRepositoryTestSuite) TestDelete_UpdateSeatBookingQueryError() {
+func (suite *SeatBookingRepositoryTestSuite) TestDelete_CancelSeatBookingQueryError() {
 	seatBookingID := "1"
-	updateSeatQuery := regexp.QuoteMeta(`UPDATE seats SET isAvailable = true WHERE id IN (SELECT seat_id FROM seat_detail_for_bookings WHERE seatBooking_id = $1)`)
+	failOpenPaymentQuery := regexp.QuoteMeta(`UPDATE payments p SET status = 'failed' FROM payments old WHERE p.id = old.id AND p.seatbooking_id = $1 AND p.status IN ('unpaid', 'pending') RETURNING p.id, p.user_id, p.seatbooking_id, old.status, p.total_price`)
 	deleteSeatDetailQuery := regexp.QuoteMeta(`DELETE FROM seat_detail_for_bookings WHERE seatBooking_id = $1`)
-	updateSeatBookingQuery := regexp.QuoteMeta(`UPDATE seat_bookings SET status = 'active' WHERE id = $1`)
+	cancelSeatBookingQuery := regexp.QuoteMeta(`UPDATE seat_bookings SET status = 'cancelled' WHERE id = $1`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(updateSeatQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSql.ExpectQuery(failOpenPaymentQuery).WithArgs(seatBookingID).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "seatbooking_id", "status", "total_price"}))
 	suite.mockSql.ExpectExec(deleteSeatDetailQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
-	suite.mockSql.ExpectExec(updateSeatBookingQuery).WithArgs(seatBookingID).WillReturnError(sql.ErrConnDone)
+	suite.mockSql.ExpectExec(cancelSeatBookingQuery).WithArgs(seatBookingID).WillReturnError(sql.ErrConnDone)
 	suite.mockSql.ExpectRollback()
 	ginContext, _ := gin.CreateTestContext(nil)
-	err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
+	_, err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
 	suite.Error(err)
 
 	err = tx.Rollback()
@@ -375,49 +468,54 @@ func (suite *SeatBookingRepositoryTestSuite) TestDelete_UpdateSeatBookingQueryEr
 	suite.NoError(err)
 }
 
-func (suite *SeatBookingRepositoryTestSuite) TestDelete_DeleteSeatBookingQueryError() {
+func (suite *SeatBookingRepositoryTestSuite) TestDeleteSeats_Success() {
 	seatBookingID := "1"
-	updateSeatQuery := regexp.QuoteMeta(`UPDATE seats SET isAvailable = true WHERE id IN (SELECT seat_id FROM seat_detail_for_bookings WHERE seatBooking_id = $1)`)
-	deleteSeatDetailQuery := regexp.QuoteMeta(`DELETE FROM seat_detail_for_bookings WHERE seatBooking_id = $1`)
-	updateSeatBookingQuery := regexp.QuoteMeta(`UPDATE seat_bookings SET status = 'active' WHERE id = $1`)
-	deleteSeatBookingQuery := regexp.QuoteMeta(`DELETE FROM seat_bookings WHERE id = $1`)
+	query := regexp.QuoteMeta(`DELETE FROM seat_detail_for_bookings WHERE seatBooking_id = $1 AND seat_id = ANY($2)`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectExec(updateSeatQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
-	suite.mockSql.ExpectExec(deleteSeatDetailQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
-	suite.mockSql.ExpectExec(updateSeatBookingQuery).WithArgs(seatBookingID).WillReturnResult(sqlmock.NewResult(1, 1))
-	suite.mockSql.ExpectExec(deleteSeatBookingQuery).WithArgs(seatBookingID).WillReturnError(sql.ErrConnDone)
-	suite.mockSql.ExpectRollback()
-	ginContext, _ := gin.CreateTestContext(nil)
-	err = suite.repo.Delete(context.Background(), tx, seatBookingID, ginContext)
-	suite.Error(err)
+	suite.mockSql.ExpectExec(query).WithArgs(seatBookingID, pq.Array([]string{"seat-2"})).WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = tx.Rollback()
+	ginContext, _ := gin.CreateTestContext(nil)
+	err = suite.repo.DeleteSeats(context.Background(), tx, seatBookingID, []string{"seat-2"}, ginContext)
 	suite.NoError(err)
 
 	err = suite.mockSql.ExpectationsWereMet()
 	suite.NoError(err)
 }
 
+func (suite *SeatBookingRepositoryTestSuite) TestDeleteSeats_Error() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectExec("DELETE FROM seat_detail_for_bookings").WillReturnError(sql.ErrConnDone)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	err = suite.repo.DeleteSeats(context.Background(), tx, "1", []string{"seat-2"}, ginContext)
+	suite.Error(err)
+}
+
 func (suite *SeatBookingRepositoryTestSuite) TestFindByID_NotFoundError() {
 	seatBookingID := "1"
 	query := regexp.QuoteMeta(`
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -428,9 +526,9 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindByID_NotFoundError() {
 			sb.id = $1
 	`)
 	rows := sqlmock.NewRows([]string{
-		"id", "status", "user_id", "showtime_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name",
+		"id", "status", "user_id", "hold_expires_at", "showtime_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "cinema_timezone",
 		"movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
-		"seat_detail_for_booking_id", "seat_id", "seat_name", "isAvailable",
+		"seat_detail_for_booking_id", "seat_id", "seat_name", "isAvailable", "unit_price",
 	})
 
 	suite.mockSql.ExpectBegin()
@@ -454,18 +552,20 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindByID_NotFoundError() {
 func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_Success() {
 	query := regexp.QuoteMeta(`
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -478,19 +578,19 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_Success(
 
 	userID := "user1"
 	rows := sqlmock.NewRows([]string{
-		"id", "status", "user_id",
+		"id", "status", "user_id", "hold_expires_at",
 		"showtime_id", "studio_id", "movie_id", "show_start", "show_end",
-		"studio_name",
+		"studio_name", "cinema_timezone",
 		"movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
 		"seat_detail_for_booking_id", "seat_id",
-		"seat_name", "isAvailable",
+		"seat_name", "isAvailable", "unit_price",
 	}).AddRow(
-		"1", "pending", userID,
-		"showtime1", "studio1", "movie1", "2022-07-20 14:00:00", "2022-07-20 16:00:00",
-		"Studio 1",
+		"1", "pending", userID, time.Now(),
+		"showtime1", "studio1", "movie1", time.Date(2022, 7, 20, 14, 0, 0, 0, time.UTC), time.Date(2022, 7, 20, 16, 0, 0, 0, time.UTC),
+		"Studio 1", "Asia/Jakarta",
 		"Movie 1", "Description", 100, 120, "active",
 		"1", "seat1",
-		"Seat 1", true,
+		"Seat 1", true, 100,
 	)
 
 	suite.mockSql.ExpectBegin()
@@ -504,7 +604,7 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_Success(
 	suite.NoError(err)
 	suite.Len(seatBookings, 1)
 	suite.Equal("1", seatBookings[0].ID)
-	suite.Equal("Seat 1", seatBookings[0].SeatName)
+	suite.Equal("Seat 1", seatBookings[0].Seats[0].SeatName)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -517,18 +617,20 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_Success(
 func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_Error() {
 	query := regexp.QuoteMeta(`
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -562,18 +664,20 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_Error()
 func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_NoRows() {
 	query := regexp.QuoteMeta(`
 		SELECT
-			sb.id, sb.status, sb.user_id,
+			sb.id, sb.status, sb.user_id, sb.hold_expires_at,
 			s.id as showtime_id, s.studio_id, s.movie_id, s.show_start, s.show_end,
-			st.name as studio_name,
+			st.name as studio_name, ci.timezone as cinema_timezone,
 			m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status,
 			sdfb.id as seat_detail_for_booking_id, sdfb.seat_id,
-			se.seat_name, se.isAvailable
+			se.seat_name, se.isAvailable, sdfb.unit_price
 		FROM
 			seat_bookings sb
 		JOIN
 			showtimes s ON sb.showtime_id = s.id
 		JOIN
 			studios st ON s.studio_id = st.id
+		JOIN
+			cinemas ci ON st.cinema_id = ci.id
 		JOIN
 			movies m ON s.movie_id = m.id
 		JOIN
@@ -586,12 +690,12 @@ func (suite *SeatBookingRepositoryTestSuite) TestFindAllPendingByUserID_NoRows()
 
 	userID := "user1"
 	rows := sqlmock.NewRows([]string{
-		"id", "status", "user_id",
+		"id", "status", "user_id", "hold_expires_at",
 		"showtime_id", "studio_id", "movie_id", "show_start", "show_end",
-		"studio_name",
+		"studio_name", "cinema_timezone",
 		"movie_title", "movie_description", "movie_price", "movie_duration", "movie_status",
 		"seat_detail_for_booking_id", "seat_id",
-		"seat_name", "isAvailable",
+		"seat_name", "isAvailable", "unit_price",
 	})
 
 	suite.mockSql.ExpectBegin()
@@ -664,3 +768,47 @@ func (suite *SeatBookingRepositoryTestSuite) TestUpdate_Error() {
 func TestSeatBookingRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(SeatBookingRepositoryTestSuite))
 }
+
+func (suite *SeatBookingRepositoryTestSuite) TestFindExpiredPending_Success() {
+	query := regexp.QuoteMeta(`SELECT id, user_id, showtime_id, status, hold_expires_at FROM seat_bookings WHERE status = 'pending' AND hold_expires_at <= $1 FOR UPDATE SKIP LOCKED`)
+	now := time.Date(2024, 7, 21, 10, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "showtime_id", "status", "hold_expires_at"}).
+		AddRow("1", "user1", "showtime1", "pending", now.Add(-time.Minute)).
+		AddRow("2", "user2", "showtime1", "pending", now.Add(-time.Hour))
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(query).WithArgs(now).WillReturnRows(rows)
+
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	seatBookings, err := suite.repo.FindExpiredPending(context.Background(), tx, now, ginContext)
+	suite.NoError(err)
+	suite.Len(seatBookings, 2)
+	suite.Equal("2", seatBookings[1].ID)
+	suite.Equal(now.Add(-time.Hour), seatBookings[1].HoldExpiresAt)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SeatBookingRepositoryTestSuite) TestFindExpiredPending_Error() {
+	query := regexp.QuoteMeta(`SELECT id, user_id, showtime_id, status, hold_expires_at FROM seat_bookings WHERE status = 'pending' AND hold_expires_at <= $1 FOR UPDATE SKIP LOCKED`)
+	now := time.Date(2024, 7, 21, 10, 0, 0, 0, time.UTC)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(query).WithArgs(now).WillReturnError(sql.ErrConnDone)
+
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	_, err = suite.repo.FindExpiredPending(context.Background(), tx, now, ginContext)
+	suite.Error(err)
+	suite.Len(ginContext.Errors, 1)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
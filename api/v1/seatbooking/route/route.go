@@ -1,11 +1,13 @@
 package movieroute
 
 import (
+	pricingRepo "bioskuy/api/v1/pricing/repository"
 	seatRepo "bioskuy/api/v1/seat/repository"
 	"bioskuy/api/v1/seatbooking/controller"
 	seatbookingRepo "bioskuy/api/v1/seatbooking/repository"
 	"bioskuy/api/v1/seatbooking/service"
 	showtimeRepo "bioskuy/api/v1/showtime/repository"
+	webhookRepo "bioskuy/api/v1/webhook/repository"
 	"bioskuy/auth"
 	"bioskuy/helper"
 	"bioskuy/middleware"
@@ -22,17 +24,24 @@ func SeatBookingRoute(router *gin.Engine, validate *validator.Validate, db *sql.
 	seatbookingRepo := seatbookingRepo.NewSeatBookingRepository()
 	seatRepo := seatRepo.NewSeatRepository()
 	showtimeRepo := showtimeRepo.NewShowtimeRepository()
+	pricingRepo := pricingRepo.NewPricingRepository()
+	outboxRepo := webhookRepo.NewOutboxRepository()
 
-	seatBookingService := service.NewSeatBookingService(seatbookingRepo, showtimeRepo, seatRepo, validate, db)
+	seatBookingService := service.NewSeatBookingService(seatbookingRepo, showtimeRepo, seatRepo, pricingRepo, outboxRepo, validate, db, config)
 	seatBookinngController := controller.NewSeatbookingController(seatBookingService)
 	v1 := router.Group("/api/v1")
 	{
 		showtimeRoutes := v1.Group("/bookings")
 		{
 			showtimeRoutes.POST("/", middleware.AuthMiddleware(authService, "user"), seatBookinngController.Create)
-			showtimeRoutes.GET("/", seatBookinngController.FindAll)
-			showtimeRoutes.GET("/:seatbookingId", seatBookinngController.FindById)
-			showtimeRoutes.DELETE("/:seatbookingId",  middleware.AuthMiddleware(authService, "user"), seatBookinngController.Delete)
+			showtimeRoutes.GET("/", middleware.AuthMiddleware(authService, "admin", "super admin", "branch admin"), seatBookinngController.FindAll)
+			showtimeRoutes.GET("/:seatbookingId", middleware.AuthMiddleware(authService, "user", "admin", "super admin"), seatBookinngController.FindById)
+			showtimeRoutes.DELETE("/:seatbookingId",  middleware.AuthMiddleware(authService, "user", "admin", "super admin"), seatBookinngController.Delete)
+		}
+
+		me := v1.Group("/me")
+		{
+			me.GET("/bookings", middleware.AuthMiddleware(authService, "user"), seatBookinngController.FindMine)
 		}
 	}
 }
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bioskuy/api/v1/seatbooking/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -9,7 +11,7 @@ import (
 
 type SeatBookingService interface {
 	Create(ctx context.Context, request dto.SeatBookingRequest, userid string, c *gin.Context) (dto.CreateSeatBookingResponse, error)
-	FindByID(ctx context.Context, id string, c *gin.Context) (dto.SeatBookingResponse, error)
-	FindAll(ctx context.Context, c *gin.Context) ([]dto.SeatBookingResponse, error)
-	Delete(ctx context.Context, id string, c *gin.Context) error
+	FindByID(ctx context.Context, id string, userID string, role string, c *gin.Context) (dto.SeatBookingResponse, error)
+	FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.SeatBookingResponse, web.Paging, error)
+	Delete(ctx context.Context, id string, userID string, role string, c *gin.Context) error
 }
@@ -1,16 +1,23 @@
 package service
 
 import (
+	entityPricing "bioskuy/api/v1/pricing/entity"
+	RepoPricing "bioskuy/api/v1/pricing/repository"
 	RepoSeat "bioskuy/api/v1/seat/repository"
 	"bioskuy/api/v1/seatbooking/dto"
 	"bioskuy/api/v1/seatbooking/entity"
 	"bioskuy/api/v1/seatbooking/repository"
 	RepoShowtime "bioskuy/api/v1/showtime/repository"
+	dtoWebhook "bioskuy/api/v1/webhook/dto"
+	entityWebhook "bioskuy/api/v1/webhook/entity"
+	RepoWebhook "bioskuy/api/v1/webhook/repository"
 	"bioskuy/exception"
 	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 	"database/sql"
-	"sync"
+	"errors"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -20,19 +27,24 @@ type seatbookingServiceImpl struct {
 	Repo         repository.SeatBookingRepository
 	RepoShowtime RepoShowtime.ShowtimeRepository
 	RepoSeat     RepoSeat.SeatRepository
+	RepoPricing  RepoPricing.PricingRepository
+	RepoOutbox   RepoWebhook.OutboxRepository
 	Validate     *validator.Validate
 	DB           *sql.DB
-	Mutex        sync.Mutex
+	Env          *helper.Config
 }
 
 func NewSeatBookingService(repo repository.SeatBookingRepository, RepoShowtime RepoShowtime.ShowtimeRepository,
-	RepoSeat RepoSeat.SeatRepository, validate *validator.Validate, DB *sql.DB) SeatBookingService {
+	RepoSeat RepoSeat.SeatRepository, RepoPricing RepoPricing.PricingRepository, RepoOutbox RepoWebhook.OutboxRepository, validate *validator.Validate, DB *sql.DB, env *helper.Config) SeatBookingService {
 	return &seatbookingServiceImpl{
 		Repo:         repo,
 		RepoShowtime: RepoShowtime,
 		RepoSeat:     RepoSeat,
+		RepoPricing:  RepoPricing,
+		RepoOutbox:   RepoOutbox,
 		Validate:     validate,
 		DB:           DB,
+		Env:          env,
 	}
 }
 
@@ -49,9 +61,6 @@ func (s *seatbookingServiceImpl) Create(ctx context.Context, request dto.SeatBoo
 		return SeatBookingResponse, err
 	}
 
-	s.Mutex.Lock()
-	defer s.Mutex.Unlock()
-
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -65,41 +74,83 @@ func (s *seatbookingServiceImpl) Create(ctx context.Context, request dto.SeatBoo
 		return SeatBookingResponse, err
 	}
 
-	seat, err := s.RepoSeat.FindByIDWithNotAvailable(ctx, tx, request.SeatID, c)
+	slot, err := s.RepoPricing.FindSlot(ctx, tx, SeatBookingRequest.ShowtimeID, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return SeatBookingResponse, err
 	}
 
+	rules, err := s.RepoPricing.FindAllRules(ctx, tx, c)
+	if err != nil {
+		return SeatBookingResponse, err
+	}
+
 	seatbooking := entity.SeatBooking{
 		UserID:            userid,
 		ShowtimeID:        SeatBookingRequest.ShowtimeID,
 		SeatBookingStatus: SeatBookingRequest.Status,
-		SeatID:            request.SeatID,
+		HoldExpiresAt:     time.Now().UTC().Add(s.Env.HoldDuration()),
 	}
 
+	for _, seatID := range request.SeatIDs {
+		seat, err := s.RepoSeat.FindAvailableByShowtime(ctx, tx, seatID, SeatBookingRequest.ShowtimeID, c)
+		if err != nil {
+			var conflict exception.ConflictError
+			if !errors.As(err, &conflict) {
+				c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			}
+			return SeatBookingResponse, err
+		}
+
+		// The price is fixed here so later rule or movie price changes do not
+		// alter what this booking costs.
+		slot.SeatCategory = seat.Category
+		seatbooking.Seats = append(seatbooking.Seats, entity.SeatDetail{
+			SeatID:    seat.ID,
+			SeatName:  seat.Name,
+			UnitPrice: entityPricing.Price(rules, slot),
+		})
+	}
+
+	// Concurrent requests for the same seat are settled by the unique
+	// (showtime_id, seat_id) constraint; the loser gets a ConflictError.
 	result, err := s.Repo.Save(ctx, tx, seatbooking, c)
 	if err != nil {
-		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		var conflict exception.ConflictError
+		if !errors.As(err, &conflict) {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		}
 		return SeatBookingResponse, err
 	}
 
-	seat.IsAvailable = false
+	seatIDs := []string{}
+	for _, seat := range result.Seats {
+		seatIDs = append(seatIDs, seat.SeatID)
+	}
 
-	_, err = s.RepoSeat.Update(ctx, tx, seat, c)
+	err = s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventSeatBookingCreated, dtoWebhook.SeatBookingData{
+		ID:            result.ID,
+		UserID:        result.UserID,
+		ShowtimeID:    result.ShowtimeID,
+		SeatIDs:       seatIDs,
+		TotalPrice:    result.TotalPrice(),
+		HoldExpiresAt: result.HoldExpiresAt,
+	}, c)
 	if err != nil {
-		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return SeatBookingResponse, err
 	}
 
-	SeatBookingResponse.ID = result.SeatDetailForBookingID
-	SeatBookingResponse.SeatBookingID = result.ID
-	SeatBookingResponse.SeatID = result.SeatID
+	SeatBookingResponse.ID = result.ID
+	SeatBookingResponse.ShowtimeID = result.ShowtimeID
+	SeatBookingResponse.Seats = toSeatDetailResponses(result.Seats)
+	SeatBookingResponse.TotalPrice = result.TotalPrice()
+	SeatBookingResponse.HoldExpiresAt = result.HoldExpiresAt
 
 	return SeatBookingResponse, nil
 }
 
-func (s *seatbookingServiceImpl) FindByID(ctx context.Context, id string, c *gin.Context) (dto.SeatBookingResponse, error) {
+// FindByID returns the booking to its owner, or to any admin.
+func (s *seatbookingServiceImpl) FindByID(ctx context.Context, id string, userID string, role string, c *gin.Context) (dto.SeatBookingResponse, error) {
 	seatBookingResponse := dto.SeatBookingResponse{}
 
 	tx, err := s.DB.Begin()
@@ -115,12 +166,21 @@ func (s *seatbookingServiceImpl) FindByID(ctx context.Context, id string, c *gin
 		return seatBookingResponse, err
 	}
 
+	if role != "admin" && role != "super admin" && result.UserID != userID {
+		err := exception.ForbiddenError{Message: "seat booking belongs to another user"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return seatBookingResponse, err
+	}
+
 	seatBookingResponse.ID = result.ID
 	seatBookingResponse.SeatBookingStatus = result.SeatBookingStatus
 	seatBookingResponse.UserID = result.UserID
 	seatBookingResponse.ShowtimeID = result.ShowtimeID
-	seatBookingResponse.ShowStart = result.ShowStart
-	seatBookingResponse.ShowEnd = result.ShowEnd
+	seatBookingResponse.ShowStart = helper.InZone(result.ShowStart, result.Timezone)
+	seatBookingResponse.ShowEnd = helper.InZone(result.ShowEnd, result.Timezone)
+	seatBookingResponse.ShowStartUTC = result.ShowStart.UTC()
+	seatBookingResponse.ShowEndUTC = result.ShowEnd.UTC()
+	seatBookingResponse.Timezone = helper.Location(result.Timezone).String()
 	seatBookingResponse.StudioID = result.StudioID
 	seatBookingResponse.StudioName = result.StudioName
 	seatBookingResponse.MovieID = result.MovieID
@@ -129,59 +189,61 @@ func (s *seatbookingServiceImpl) FindByID(ctx context.Context, id string, c *gin
 	seatBookingResponse.MoviePrice = result.MoviePrice
 	seatBookingResponse.MovieDuration = result.MovieDuration
 	seatBookingResponse.MovieStatus = result.MovieStatus
-	seatBookingResponse.SeatID = result.SeatID
-	seatBookingResponse.SeatDetailForBookingID = result.SeatDetailForBookingID
-	seatBookingResponse.SeatName = result.SeatName
-	seatBookingResponse.SeatIsAvailable = result.SeatIsAvailable
-	
+	seatBookingResponse.Seats = toSeatDetailResponses(result.Seats)
+	seatBookingResponse.HoldExpiresAt = result.HoldExpiresAt
+	seatBookingResponse.HoldExpired = isHoldExpired(result, time.Now().UTC())
 
 	return seatBookingResponse, nil
 }
 
-func (s *seatbookingServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto.SeatBookingResponse, error) {
+func (s *seatbookingServiceImpl) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.SeatBookingResponse, web.Paging, error) {
 	seatBookingResponses := []dto.SeatBookingResponse{}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return seatBookingResponses, err
+		return seatBookingResponses, web.Paging{}, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	results, err := s.Repo.FindAll(ctx, tx, c)
+	results, total, err := s.Repo.FindAll(ctx, tx, query, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return seatBookingResponses, err
+		return seatBookingResponses, web.Paging{}, err
 	}
 
 	for _, result := range results {
 		seatBookingResponse := dto.SeatBookingResponse{
-			ID:                     result.ID,
-			SeatBookingStatus:      result.SeatBookingStatus,
-			UserID:                 result.UserID,
-			ShowtimeID:             result.ShowtimeID,
-			ShowStart:              result.ShowStart,
-			ShowEnd:                result.ShowEnd,
-			StudioID:               result.StudioID,
-			StudioName:             result.StudioName,
-			MovieID:                result.MovieID,
-			MovieTitle:             result.MovieTitle,
-			MovieDescription:       result.MovieDescription,
-			MoviePrice:             result.MoviePrice,
-			MovieDuration:          result.MovieDuration,
-			MovieStatus:            result.MovieStatus,
-			SeatID:                 result.SeatID,
-			SeatDetailForBookingID: result.SeatDetailForBookingID,
-			SeatName:               result.SeatName,
-			SeatIsAvailable:        result.SeatIsAvailable,
+			ID:                result.ID,
+			SeatBookingStatus: result.SeatBookingStatus,
+			UserID:            result.UserID,
+			ShowtimeID:        result.ShowtimeID,
+			ShowStart:         helper.InZone(result.ShowStart, result.Timezone),
+			ShowEnd:           helper.InZone(result.ShowEnd, result.Timezone),
+			ShowStartUTC:      result.ShowStart.UTC(),
+			ShowEndUTC:        result.ShowEnd.UTC(),
+			Timezone:          helper.Location(result.Timezone).String(),
+			StudioID:          result.StudioID,
+			StudioName:        result.StudioName,
+			MovieID:           result.MovieID,
+			MovieTitle:        result.MovieTitle,
+			MovieDescription:  result.MovieDescription,
+			MoviePrice:        result.MoviePrice,
+			MovieDuration:     result.MovieDuration,
+			MovieStatus:       result.MovieStatus,
+			Seats:             toSeatDetailResponses(result.Seats),
+			HoldExpiresAt:     result.HoldExpiresAt,
+			HoldExpired:       isHoldExpired(result, time.Now().UTC()),
 		}
 		seatBookingResponses = append(seatBookingResponses, seatBookingResponse)
 	}
 
-	return seatBookingResponses, nil
+	return seatBookingResponses, query.Paging(total), nil
 }
 
-func (s *seatbookingServiceImpl) Delete(ctx context.Context, id string, c *gin.Context) error {
+// Delete cancels a pending booking of userID, or of anyone when role is an
+// admin one, releasing its seats and failing its open payments.
+func (s *seatbookingServiceImpl) Delete(ctx context.Context, id string, userID string, role string, c *gin.Context) error {
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -189,11 +251,69 @@ func (s *seatbookingServiceImpl) Delete(ctx context.Context, id string, c *gin.C
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	err = s.Repo.Delete(ctx, tx, id, c)
+	seatbooking, err := s.Repo.FindByID(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	if role != "admin" && role != "super admin" && seatbooking.UserID != userID {
+		err := exception.ForbiddenError{Message: "seat booking belongs to another user"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	if seatbooking.SeatBookingStatus == "success" {
+		err := exception.ConflictError{Message: "paid bookings are cancelled by refunding their payment"}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	failedPayments, err := s.Repo.Delete(ctx, tx, id, c)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return err
 	}
 
-	return nil
+	for _, payment := range failedPayments {
+		err = s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventPaymentFailed, dtoWebhook.FailedPaymentData(payment), c)
+		if err != nil {
+			return err
+		}
+	}
+
+	seatIDs := []string{}
+	for _, seat := range seatbooking.Seats {
+		seatIDs = append(seatIDs, seat.SeatID)
+	}
+
+	return s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventSeatBookingCancelled, dtoWebhook.SeatBookingData{
+		ID:            seatbooking.ID,
+		UserID:        seatbooking.UserID,
+		ShowtimeID:    seatbooking.ShowtimeID,
+		SeatIDs:       seatIDs,
+		TotalPrice:    seatbooking.TotalPrice(),
+		HoldExpiresAt: seatbooking.HoldExpiresAt,
+	}, c)
+}
+
+func toSeatDetailResponses(seats []entity.SeatDetail) []dto.SeatDetailResponse {
+	responses := []dto.SeatDetailResponse{}
+	for _, seat := range seats {
+		responses = append(responses, dto.SeatDetailResponse{
+			ID:              seat.ID,
+			SeatID:          seat.SeatID,
+			SeatName:        seat.SeatName,
+			SeatIsAvailable: seat.SeatIsAvailable,
+			UnitPrice:       seat.UnitPrice,
+		})
+	}
+
+	return responses
+}
+
+// isHoldExpired reports a pending booking whose hold ran out but which the
+// sweeper has not released yet.
+func isHoldExpired(seatbooking entity.SeatBooking, now time.Time) bool {
+	return seatbooking.SeatBookingStatus == "pending" && !seatbooking.HoldExpiresAt.After(now)
 }
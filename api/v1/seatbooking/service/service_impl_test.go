@@ -1,20 +1,34 @@
 package service
 
 import (
+	ePay "bioskuy/api/v1/payment/entity"
+	eP "bioskuy/api/v1/pricing/entity"
+	eS "bioskuy/api/v1/seat/entity"
 	"bioskuy/api/v1/seatbooking/dto"
 	"bioskuy/api/v1/seatbooking/entity"
 	"bioskuy/api/v1/seatbooking/mock/entitymock"
 	mockSB "bioskuy/api/v1/seatbooking/mock/repomock"
+	"bioskuy/api/v1/seatbooking/repository"
+	dtoWebhook "bioskuy/api/v1/webhook/dto"
+	eW "bioskuy/api/v1/webhook/entity"
+	mockWebhook "bioskuy/api/v1/webhook/mock/repomock"
+	"bioskuy/exception"
 	"bioskuy/helper"
 	"context"
 	"database/sql"
-	"sync"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -23,10 +37,11 @@ type SeatBookingServiceTestSuite struct {
 	repoSBMock *mockSB.SeatBookingRepositoryMock
 	repoSTMock *mockSB.MockShowtimeRepository
 	repoS      *mockSB.SeatRepository
+	repoP      *mockSB.MockPricingRepository
+	repoO      *mockWebhook.MockOutboxRepository
 	validate   *validator.Validate
 	mockDb     *sql.DB
 	mockSql    sqlmock.Sqlmock
-	Mutex      sync.Mutex
 	sBSB       SeatBookingService
 	ctx        context.Context
 	ginContext *gin.Context
@@ -39,12 +54,13 @@ func (suite *SeatBookingServiceTestSuite) SetupTest() {
 	suite.repoSBMock = new(mockSB.SeatBookingRepositoryMock)
 	suite.repoSTMock = new(mockSB.MockShowtimeRepository)
 	suite.repoS = new(mockSB.SeatRepository)
+	suite.repoP = new(mockSB.MockPricingRepository)
+	suite.repoO = new(mockWebhook.MockOutboxRepository)
 	suite.validate = &validator.Validate{}
 	suite.validate = validator.New()
 	suite.mockDb = db
 	suite.mockSql = mock
-	suite.Mutex = sync.Mutex{}
-	suite.sBSB = NewSeatBookingService(suite.repoSBMock, suite.repoSTMock, suite.repoS, suite.validate, suite.mockDb)
+	suite.sBSB = NewSeatBookingService(suite.repoSBMock, suite.repoSTMock, suite.repoS, suite.repoP, suite.repoO, suite.validate, suite.mockDb, &helper.Config{SeatHoldDuration: "15"})
 	suite.ctx = context.Background()
 	suite.ginContext = &gin.Context{}
 }
@@ -54,35 +70,101 @@ func TestSeatBookingServiceTestSuite(t *testing.T) {
 }
 
 func (suite *SeatBookingServiceTestSuite) TestCreate_Success() {
-	var SeatBookingResponse dto.CreateSeatBookingResponse
-	var SeatBookingRequest dto.CreateSeatBookingRequest
+	request := dto.SeatBookingRequest{
+		SeatIDs:    []string{"seat123", "seat124"},
+		ShowtimeID: entitymock.MockSeatBookingRequest.ShowtimeID,
+	}
+	secondSeat := eS.Seat{ID: "seat124", Name: "A2", IsAvailable: true, StudioID: "studio456", Category: eS.SeatCategoryVIP}
+	slot := eP.Slot{BasePrice: 50000, ShowStart: time.Date(2024, 7, 27, 19, 0, 0, 0, time.UTC)}
+	rules := []eP.Rule{{Name: "vip", SeatCategory: eS.SeatCategoryVIP, AdjustmentAmount: 25000, Active: true}}
 
-	SeatBookingRequest.UserID = entitymock.MockSeatBookingEntity.ID
-	SeatBookingRequest.ShowtimeID = entitymock.MockSeatBookingRequest.ShowtimeID
+	saved := entity.SeatBooking{
+		ID:         "booking123",
+		UserID:     "user123",
+		ShowtimeID: request.ShowtimeID,
+		Seats: []entity.SeatDetail{
+			{ID: "detail1", SeatID: "seat123", SeatName: "A1", UnitPrice: 50000},
+			{ID: "detail2", SeatID: "seat124", SeatName: "A2", UnitPrice: 75000},
+		},
+	}
+
+	suite.mockSql.ExpectBegin()
+	suite.repoSTMock.On("FindByID", suite.ctx, mock.Anything, request.ShowtimeID, suite.ginContext).Return(entitymock.MockShowtimeEntity, nil)
+	suite.repoP.On("FindSlot", suite.ctx, mock.Anything, request.ShowtimeID, suite.ginContext).Return(slot, nil)
+	suite.repoP.On("FindAllRules", suite.ctx, mock.Anything, suite.ginContext).Return(rules, nil)
+	suite.repoS.On("FindAvailableByShowtime", suite.ctx, mock.Anything, "seat123", request.ShowtimeID, suite.ginContext).Return(entitymock.MockSeatEntity, nil)
+	suite.repoS.On("FindAvailableByShowtime", suite.ctx, mock.Anything, "seat124", request.ShowtimeID, suite.ginContext).Return(secondSeat, nil)
+	suite.repoSBMock.On("Save", suite.ctx, mock.Anything, mock.MatchedBy(func(sb entity.SeatBooking) bool {
+		return len(sb.Seats) == 2 && sb.Seats[0].SeatID == "seat123" && sb.Seats[1].SeatID == "seat124" &&
+			sb.Seats[0].UnitPrice == 50000 && sb.Seats[1].UnitPrice == 75000 &&
+			sb.HoldExpiresAt.After(time.Now().Add(14*time.Minute))
+	}), suite.ginContext).Return(saved, nil)
+	suite.repoO.On("Publish", suite.ctx, mock.Anything, eW.EventSeatBookingCreated, mock.MatchedBy(func(data dtoWebhook.SeatBookingData) bool {
+		return data.ID == "booking123" && len(data.SeatIDs) == 2 && data.TotalPrice == 125000
+	}), suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.sBSB.Create(suite.ctx, request, "user123", suite.ginContext)
 
-	err := suite.validate.Struct(SeatBookingRequest)
 	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "booking123", response.ID)
+	assert.Equal(suite.T(), request.ShowtimeID, response.ShowtimeID)
+	assert.Len(suite.T(), response.Seats, 2)
+	assert.Equal(suite.T(), "detail2", response.Seats[1].ID)
+	assert.Equal(suite.T(), "A2", response.Seats[1].SeatName)
+	assert.Equal(suite.T(), 75000, response.Seats[1].UnitPrice)
+	assert.Equal(suite.T(), 125000, response.TotalPrice)
+	suite.repoO.AssertExpectations(suite.T())
 
-	suite.mockSql.ExpectBegin()
-	tx, err := suite.mockDb.Begin()
+	err = suite.mockSql.ExpectationsWereMet()
 	assert.NoError(suite.T(), err)
-	defer helper.CommitAndRollback(tx, suite.ginContext)
+}
 
-	suite.repoSTMock.On("FindByID", suite.ctx, tx, entitymock.MockSeatBookingRequest.ShowtimeID, suite.ginContext).Return(entitymock.MockShowtimeEntity, nil)
-	suite.repoS.On("FindByIDWithNotAvailable", suite.ctx, tx, entitymock.MockSeatBookingRequest.SeatID, suite.ginContext).Return(entitymock.MockSeatEntity, nil)
-	seatbooking := entitymock.MockSeatBookingEntity
-	suite.repoSBMock.On("Save", suite.ctx, tx, seatbooking, entitymock.MockSeatBookingRequest.SeatID, suite.ginContext).Return(entitymock.MockSeatBookingEntity, nil)
+func (suite *SeatBookingServiceTestSuite) TestCreate_PublishErrorRollsBack() {
+	request := entitymock.MockSeatBookingRequest
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
 
-	updatedSeat := entitymock.MockSeatEntity
-	updatedSeat.IsAvailable = false
-	suite.repoS.On("Update", suite.ctx, tx, updatedSeat, suite.ginContext).Return(updatedSeat, nil)
+	suite.mockSql.ExpectBegin()
+	suite.repoSTMock.On("FindByID", suite.ctx, mock.Anything, request.ShowtimeID, ginContext).Return(entitymock.MockShowtimeEntity, nil)
+	suite.repoP.On("FindSlot", suite.ctx, mock.Anything, request.ShowtimeID, ginContext).Return(eP.Slot{BasePrice: 50000}, nil)
+	suite.repoP.On("FindAllRules", suite.ctx, mock.Anything, ginContext).Return([]eP.Rule{}, nil)
+	suite.repoS.On("FindAvailableByShowtime", suite.ctx, mock.Anything, request.SeatIDs[0], request.ShowtimeID, ginContext).Return(entitymock.MockSeatEntity, nil)
+	suite.repoSBMock.On("Save", suite.ctx, mock.Anything, mock.Anything, ginContext).Return(entity.SeatBooking{ID: "booking123"}, nil)
+	suite.repoO.On("Publish", suite.ctx, mock.Anything, eW.EventSeatBookingCreated, mock.Anything, ginContext).Return(errors.New("outbox error")).Run(func(args mock.Arguments) {
+		c := args.Get(4).(*gin.Context)
+		c.Error(exception.InternalServerError{Message: "outbox error"}).SetType(gin.ErrorTypePublic)
+	})
+	suite.mockSql.ExpectRollback()
 
-	response, err := suite.sBSB.Create(suite.ctx, entitymock.MockSeatBookingRequest, entitymock.MockCreateSeatBookingRequest.UserID, suite.ginContext)
+	_, err := suite.sBSB.Create(suite.ctx, request, "user123", ginContext)
 
-	// Validasi respons
-	assert.Equal(suite.T(), SeatBookingResponse.ID, response.ID)
-	assert.Equal(suite.T(), SeatBookingResponse.SeatID, response.SeatID)
-	assert.Equal(suite.T(), SeatBookingResponse.SeatBookingID, response.SeatBookingID)
+	assert.Error(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SeatBookingServiceTestSuite) TestCreate_SeatTaken() {
+	request := dto.SeatBookingRequest{
+		SeatIDs:    []string{"seat123", "seat124"},
+		ShowtimeID: entitymock.MockSeatBookingRequest.ShowtimeID,
+	}
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	suite.mockSql.ExpectBegin()
+	suite.repoSTMock.On("FindByID", suite.ctx, mock.Anything, request.ShowtimeID, ginContext).Return(entitymock.MockShowtimeEntity, nil)
+	suite.repoP.On("FindSlot", suite.ctx, mock.Anything, request.ShowtimeID, ginContext).Return(eP.Slot{BasePrice: 50000}, nil)
+	suite.repoP.On("FindAllRules", suite.ctx, mock.Anything, ginContext).Return([]eP.Rule{}, nil)
+	suite.repoS.On("FindAvailableByShowtime", suite.ctx, mock.Anything, "seat123", request.ShowtimeID, ginContext).Return(entitymock.MockSeatEntity, nil)
+	suite.repoS.On("FindAvailableByShowtime", suite.ctx, mock.Anything, "seat124", request.ShowtimeID, ginContext).Return(eS.Seat{}, exception.ConflictError{Message: "seat A2 is already booked"}).Run(func(args mock.Arguments) {
+		c := args.Get(4).(*gin.Context)
+		c.Error(exception.ConflictError{Message: "seat A2 is already booked"}).SetType(gin.ErrorTypePublic)
+	})
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.sBSB.Create(suite.ctx, request, "user123", ginContext)
+
+	assert.IsType(suite.T(), exception.ConflictError{}, err)
+	assert.Len(suite.T(), ginContext.Errors, 1)
+	suite.repoSBMock.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
 	err = suite.mockSql.ExpectationsWereMet()
 	assert.NoError(suite.T(), err)
@@ -92,7 +174,17 @@ func (suite *SeatBookingServiceTestSuite) TestCreate_ValidationError() {
 	var invalidRequest = entitymock.MockSeatBookingRequest
 	invalidRequest.ShowtimeID = "" // UserID kosong untuk memicu error validasi
 
-	_, err := suite.sBSB.Create(suite.ctx, invalidRequest, invalidRequest.SeatID, suite.ginContext)
+	_, err := suite.sBSB.Create(suite.ctx, invalidRequest, "user123", suite.ginContext)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SeatBookingServiceTestSuite) TestCreate_DuplicateSeat() {
+	request := dto.SeatBookingRequest{
+		SeatIDs:    []string{"seat123", "seat123"},
+		ShowtimeID: entitymock.MockSeatBookingRequest.ShowtimeID,
+	}
+
+	_, err := suite.sBSB.Create(suite.ctx, request, "user123", suite.ginContext)
 	assert.Error(suite.T(), err)
 }
 
@@ -104,20 +196,56 @@ func (suite *SeatBookingServiceTestSuite) TestFindByID_NotFound() {
 
 	suite.repoSBMock.On("FindByID", suite.ctx, tx, entitymock.MockSeatBookingEntity.ID, suite.ginContext).Return(entity.SeatBooking{}, sql.ErrNoRows)
 
-	response, err := suite.sBSB.FindByID(suite.ctx, entitymock.MockSeatBookingEntity.ID, suite.ginContext)
+	response, err := suite.sBSB.FindByID(suite.ctx, entitymock.MockSeatBookingEntity.ID, "user123", "user", suite.ginContext)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), dto.SeatBookingResponse{}, response)
 }
 
+func (suite *SeatBookingServiceTestSuite) TestFindByID_HoldExpired() {
+	expired := entitymock.MockSeatBookingEntity
+	expired.SeatBookingStatus = "pending"
+	expired.HoldExpiresAt = time.Now().UTC().Add(-time.Minute)
+
+	suite.mockSql.ExpectBegin()
+	suite.repoSBMock.On("FindByID", suite.ctx, mock.Anything, expired.ID, suite.ginContext).Return(expired, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.sBSB.FindByID(suite.ctx, expired.ID, "user123", "user", suite.ginContext)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), response.HoldExpired)
+	assert.Equal(suite.T(), expired.HoldExpiresAt, response.HoldExpiresAt)
+}
+
+func (suite *SeatBookingServiceTestSuite) TestFindByID_OtherUserForbidden() {
+	suite.mockSql.ExpectBegin()
+	suite.repoSBMock.On("FindByID", suite.ctx, mock.Anything, entitymock.MockSeatBookingEntity.ID, suite.ginContext).Return(entitymock.MockSeatBookingEntity, nil)
+	suite.mockSql.ExpectRollback()
+
+	response, err := suite.sBSB.FindByID(suite.ctx, entitymock.MockSeatBookingEntity.ID, "someone-else", "user", suite.ginContext)
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+	assert.Equal(suite.T(), dto.SeatBookingResponse{}, response)
+}
+
+func (suite *SeatBookingServiceTestSuite) TestFindByID_AdminSeesAnyBooking() {
+	suite.mockSql.ExpectBegin()
+	suite.repoSBMock.On("FindByID", suite.ctx, mock.Anything, entitymock.MockSeatBookingEntity.ID, suite.ginContext).Return(entitymock.MockSeatBookingEntity, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.sBSB.FindByID(suite.ctx, entitymock.MockSeatBookingEntity.ID, "admin-1", "admin", suite.ginContext)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "user123", response.UserID)
+}
+
 func (suite *SeatBookingServiceTestSuite) TestFindAll_NoSeatBookings() {
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.mockDb.Begin()
 	assert.NoError(suite.T(), err)
 	defer helper.CommitAndRollback(tx, suite.ginContext)
 
-	suite.repoSBMock.On("FindAll", suite.ctx, tx, suite.ginContext).Return([]entity.SeatBooking{}, sql.ErrNoRows)
+	query := helper.ListQuery{Page: 1, Size: 10}
+	suite.repoSBMock.On("FindAll", suite.ctx, tx, query, suite.ginContext).Return([]entity.SeatBooking{}, 0, sql.ErrNoRows)
 
-	responses, err := suite.sBSB.FindAll(suite.ctx, suite.ginContext)
+	responses, _, err := suite.sBSB.FindAll(suite.ctx, query, suite.ginContext)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), []dto.SeatBookingResponse{}, responses)
 }
@@ -128,7 +256,7 @@ func (suite *SeatBookingServiceTestSuite) TestDelete_Success() {
 	assert.NoError(suite.T(), err)
 	defer helper.CommitAndRollback(tx, suite.ginContext)
 
-	suite.repoSBMock.On("Delete", suite.ctx, tx, entitymock.MockSeatBookingEntity.ID, suite.ginContext).Return(sql.ErrConnDone)
+	suite.repoSBMock.On("Delete", suite.ctx, tx, entitymock.MockSeatBookingEntity.ID, suite.ginContext).Return([]ePay.FailedPayment{}, sql.ErrConnDone)
 
 	assert.NoError(suite.T(), err)
 	assert.Nil(suite.T(), err)
@@ -140,8 +268,108 @@ func (suite *SeatBookingServiceTestSuite) TestDelete_Error() {
 	assert.NoError(suite.T(), err)
 	defer helper.CommitAndRollback(tx, suite.ginContext)
 
-	suite.repoSBMock.On("Delete", suite.ctx, tx, entitymock.MockSeatBookingEntity.ID, suite.ginContext).Return(sql.ErrConnDone)
+	suite.repoSBMock.On("Delete", suite.ctx, tx, entitymock.MockSeatBookingEntity.ID, suite.ginContext).Return([]ePay.FailedPayment{}, sql.ErrConnDone)
 
-	err = suite.sBSB.Delete(suite.ctx, entitymock.MockSeatBookingEntity.ID, suite.ginContext)
+	err = suite.sBSB.Delete(suite.ctx, entitymock.MockSeatBookingEntity.ID, "user123", "user", suite.ginContext)
 	assert.Error(suite.T(), err)
 }
+
+func (suite *SeatBookingServiceTestSuite) TestDelete_PendingBooking() {
+	suite.mockSql.ExpectBegin()
+	holdExpiresAt := time.Date(2024, 7, 21, 10, 15, 0, 0, time.UTC)
+	suite.repoSBMock.On("FindByID", suite.ctx, mock.Anything, "booking123", suite.ginContext).Return(entity.SeatBooking{
+		ID: "booking123", UserID: "user1", ShowtimeID: "showtime1", SeatBookingStatus: "pending", HoldExpiresAt: holdExpiresAt,
+		Seats: []entity.SeatDetail{{SeatID: "seat1", UnitPrice: 50000}},
+	}, nil)
+	suite.repoSBMock.On("Delete", suite.ctx, mock.Anything, "booking123", suite.ginContext).Return([]ePay.FailedPayment{
+		{ID: "payment1", UserID: "user1", SeatBookingID: "booking123", PreviousStatus: "unpaid", TotalPrice: 50000},
+	}, nil)
+	suite.repoO.On("Publish", suite.ctx, mock.Anything, eW.EventPaymentFailed, dtoWebhook.PaymentData{
+		ID: "payment1", UserID: "user1", SeatBookingID: "booking123", Status: "failed", PreviousStatus: "unpaid", TotalPrice: 50000,
+	}, suite.ginContext).Return(nil).Once()
+	suite.repoO.On("Publish", suite.ctx, mock.Anything, eW.EventSeatBookingCancelled, dtoWebhook.SeatBookingData{
+		ID: "booking123", UserID: "user1", ShowtimeID: "showtime1", SeatIDs: []string{"seat1"}, TotalPrice: 50000, HoldExpiresAt: holdExpiresAt,
+	}, suite.ginContext).Return(nil).Once()
+	suite.mockSql.ExpectCommit()
+
+	err := suite.sBSB.Delete(suite.ctx, "booking123", "user1", "user", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.repoSBMock.AssertExpectations(suite.T())
+	suite.repoO.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SeatBookingServiceTestSuite) TestDelete_PaidBooking() {
+	suite.mockSql.ExpectBegin()
+	suite.repoSBMock.On("FindByID", suite.ctx, mock.Anything, "booking123", suite.ginContext).Return(entity.SeatBooking{ID: "booking123", UserID: "user1", SeatBookingStatus: "success"}, nil)
+	suite.mockSql.ExpectRollback()
+
+	err := suite.sBSB.Delete(suite.ctx, "booking123", "user1", "user", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ConflictError{}, err)
+	suite.repoSBMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SeatBookingServiceTestSuite) TestDelete_OtherUsersBooking() {
+	suite.mockSql.ExpectBegin()
+	suite.repoSBMock.On("FindByID", suite.ctx, mock.Anything, "booking123", suite.ginContext).Return(entity.SeatBooking{ID: "booking123", UserID: "user1", SeatBookingStatus: "pending"}, nil)
+	suite.mockSql.ExpectRollback()
+
+	err := suite.sBSB.Delete(suite.ctx, "booking123", "someone-else", "user", suite.ginContext)
+
+	assert.IsType(suite.T(), exception.ForbiddenError{}, err)
+	suite.repoSBMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.repoO.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SeatBookingServiceTestSuite) TestDelete_AdminCancelsAnyBooking() {
+	suite.mockSql.ExpectBegin()
+	suite.repoSBMock.On("FindByID", suite.ctx, mock.Anything, "booking123", suite.ginContext).Return(entity.SeatBooking{ID: "booking123", UserID: "user1", SeatBookingStatus: "pending"}, nil)
+	suite.repoSBMock.On("Delete", suite.ctx, mock.Anything, "booking123", suite.ginContext).Return([]ePay.FailedPayment{}, nil)
+	suite.repoO.On("Publish", suite.ctx, mock.Anything, eW.EventSeatBookingCancelled, mock.Anything, suite.ginContext).Return(nil).Once()
+	suite.mockSql.ExpectCommit()
+
+	err := suite.sBSB.Delete(suite.ctx, "booking123", "admin-1", "admin", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.repoSBMock.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+// The real repository is used so the 409 comes from the unique
+// (showtime_id, seat_id) violation of a booking that committed first.
+func (suite *SeatBookingServiceTestSuite) TestCreate_SeatTakenByConcurrentBooking() {
+	service := NewSeatBookingService(repository.NewSeatBookingRepository(), suite.repoSTMock, suite.repoS, suite.repoP, suite.repoO, suite.validate, suite.mockDb, &helper.Config{})
+	request := entitymock.MockSeatBookingRequest
+
+	suite.repoSTMock.On("FindByID", suite.ctx, mock.Anything, request.ShowtimeID, mock.Anything).Return(entitymock.MockShowtimeEntity, nil)
+	suite.repoP.On("FindSlot", suite.ctx, mock.Anything, request.ShowtimeID, mock.Anything).Return(eP.Slot{BasePrice: 50000}, nil)
+	suite.repoP.On("FindAllRules", suite.ctx, mock.Anything, mock.Anything).Return([]eP.Rule{}, nil)
+	suite.repoS.On("FindAvailableByShowtime", suite.ctx, mock.Anything, request.SeatIDs[0], request.ShowtimeID, mock.Anything).Return(entitymock.MockSeatEntity, nil)
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta("INSERT INTO seat_bookings (user_id, showtime_id, hold_expires_at) VALUES ($1, $2, $3) RETURNING id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("booking2"))
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta("INSERT INTO seat_detail_for_bookings (seat_id, seatBooking_id, showtime_id, unit_price) VALUES ($1, $2, $3, $4) RETURNING id")).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "seat_detail_for_bookings_showtime_id_seat_id_key"})
+	suite.mockSql.ExpectRollback()
+
+	var err error
+	router := gin.New()
+	router.Use(exception.ErrorHandler)
+	router.POST("/bookings", func(c *gin.Context) {
+		_, err = service.Create(c.Request.Context(), request, "user2", c)
+	})
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/bookings", nil).WithContext(suite.ctx)
+	router.ServeHTTP(resp, req)
+
+	assert.IsType(suite.T(), exception.ConflictError{}, err)
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)
+	suite.repoO.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
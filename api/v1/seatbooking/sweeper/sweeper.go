@@ -0,0 +1,147 @@
+package sweeper
+
+import (
+	entityNotification "bioskuy/api/v1/notification/entity"
+	RepoNotification "bioskuy/api/v1/notification/repository"
+	"bioskuy/api/v1/seatbooking/repository"
+	dtoWebhook "bioskuy/api/v1/webhook/dto"
+	entityWebhook "bioskuy/api/v1/webhook/entity"
+	RepoWebhook "bioskuy/api/v1/webhook/repository"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sweeper periodically releases pending seat bookings whose hold has expired,
+// so seats of abandoned checkouts go back on sale, and tells their owners and
+// webhook subscribers.
+type Sweeper struct {
+	Repo             repository.SeatBookingRepository
+	RepoNotification RepoNotification.NotificationRepository
+	RepoOutbox       RepoWebhook.OutboxRepository
+	DB               *sql.DB
+	Interval         time.Duration
+	Now              func() time.Time
+}
+
+func NewSweeper(repo repository.SeatBookingRepository, repoNotification RepoNotification.NotificationRepository, repoOutbox RepoWebhook.OutboxRepository, DB *sql.DB, interval time.Duration) *Sweeper {
+	return &Sweeper{
+		Repo:             repo,
+		RepoNotification: repoNotification,
+		RepoOutbox:       repoOutbox,
+		DB:               DB,
+		Interval:         interval,
+		Now:              func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Start runs Sweep every Interval in its own goroutine until ctx is done.
+func (s *Sweeper) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				released, err := s.Sweep(ctx)
+				if err != nil {
+					slog.Error("seat hold sweep failed", "error", err)
+					continue
+				}
+				if released > 0 {
+					slog.Info("released expired seat holds", "released", released)
+				}
+			}
+		}
+	}()
+}
+
+// Sweep releases every expired pending booking in one transaction, queueing
+// an expiry email and publishing seatbooking.expired for each, along with
+// payment.failed for the open payments it fails, and returns how many were
+// released.
+func (s *Sweeper) Sweep(ctx context.Context) (int, error) {
+	c := &gin.Context{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	expired, err := s.Repo.FindExpiredPending(ctx, tx, s.Now(), c)
+	if err != nil {
+		c.Error(err)
+		return 0, err
+	}
+
+	for _, seatbooking := range expired {
+		// The full booking is read for the email before its seats are released.
+		booking, err := s.Repo.FindByID(ctx, tx, seatbooking.ID, c)
+		if err != nil {
+			c.Error(err)
+			return 0, err
+		}
+
+		seatNames := []string{}
+		seatIDs := []string{}
+		for _, seat := range booking.Seats {
+			seatNames = append(seatNames, seat.SeatName)
+			seatIDs = append(seatIDs, seat.SeatID)
+		}
+
+		err = s.RepoNotification.Queue(ctx, tx, entityNotification.Notification{
+			UserID: booking.UserID,
+			Kind:   entityNotification.KindBookingExpired,
+			Data: entityNotification.Data{
+				SeatBookingID: booking.ID,
+				MovieTitle:    booking.MovieTitle,
+				StudioName:    booking.StudioName,
+				ShowStart:     booking.ShowStart,
+				Timezone:      booking.Timezone,
+				Seats:         seatNames,
+				TotalPrice:    booking.TotalPrice(),
+			},
+		}, c)
+		if err != nil {
+			c.Error(err)
+			return 0, err
+		}
+
+		failedPayments, err := s.Repo.Delete(ctx, tx, seatbooking.ID, c)
+		if err != nil {
+			c.Error(err)
+			return 0, err
+		}
+
+		for _, payment := range failedPayments {
+			err = s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventPaymentFailed, dtoWebhook.FailedPaymentData(payment), c)
+			if err != nil {
+				c.Error(err)
+				return 0, err
+			}
+		}
+
+		err = s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventSeatBookingExpired, dtoWebhook.SeatBookingData{
+			ID:            booking.ID,
+			UserID:        booking.UserID,
+			ShowtimeID:    booking.ShowtimeID,
+			SeatIDs:       seatIDs,
+			TotalPrice:    booking.TotalPrice(),
+			HoldExpiresAt: booking.HoldExpiresAt,
+		}, c)
+		if err != nil {
+			c.Error(err)
+			return 0, err
+		}
+	}
+
+	return len(expired), nil
+}
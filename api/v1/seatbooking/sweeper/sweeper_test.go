@@ -0,0 +1,143 @@
+package sweeper
+
+import (
+	entityNotification "bioskuy/api/v1/notification/entity"
+	mockNotification "bioskuy/api/v1/notification/mock/repomock"
+	entityPayment "bioskuy/api/v1/payment/entity"
+	"bioskuy/api/v1/seatbooking/entity"
+	mockSB "bioskuy/api/v1/seatbooking/mock/repomock"
+	dtoWebhook "bioskuy/api/v1/webhook/dto"
+	entityWebhook "bioskuy/api/v1/webhook/entity"
+	mockWebhook "bioskuy/api/v1/webhook/mock/repomock"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SweeperTestSuite struct {
+	suite.Suite
+	repo       *mockSB.SeatBookingRepositoryMock
+	repoNotify *mockNotification.MockNotificationRepository
+	repoOutbox *mockWebhook.MockOutboxRepository
+	mockDb     *sql.DB
+	mockSql    sqlmock.Sqlmock
+	sweeper    *Sweeper
+	now        time.Time
+}
+
+func (suite *SweeperTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.repo = new(mockSB.SeatBookingRepositoryMock)
+	suite.repoNotify = new(mockNotification.MockNotificationRepository)
+	suite.repoOutbox = new(mockWebhook.MockOutboxRepository)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.now = time.Date(2024, 7, 21, 10, 0, 0, 0, time.UTC)
+	suite.sweeper = NewSweeper(suite.repo, suite.repoNotify, suite.repoOutbox, suite.mockDb, time.Minute)
+	suite.sweeper.Now = func() time.Time { return suite.now }
+}
+
+func (suite *SweeperTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestSweeperTestSuite(t *testing.T) {
+	suite.Run(t, new(SweeperTestSuite))
+}
+
+func (suite *SweeperTestSuite) TestSweep_ReleasesExpiredBookings() {
+	expired := []entity.SeatBooking{{ID: "booking1"}, {ID: "booking2"}}
+
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("FindExpiredPending", mock.Anything, mock.Anything, suite.now, mock.Anything).Return(expired, nil)
+	suite.repo.On("FindByID", mock.Anything, mock.Anything, "booking1", mock.Anything).Return(entity.SeatBooking{
+		ID: "booking1", UserID: "user1", ShowtimeID: "showtime1", MovieTitle: "Dune", HoldExpiresAt: suite.now,
+		Seats: []entity.SeatDetail{{SeatID: "seat1", SeatName: "A1", UnitPrice: 50000}},
+	}, nil)
+	suite.repo.On("FindByID", mock.Anything, mock.Anything, "booking2", mock.Anything).Return(entity.SeatBooking{ID: "booking2", UserID: "user2"}, nil)
+	suite.repoNotify.On("Queue", mock.Anything, mock.Anything, entityNotification.Notification{
+		UserID: "user1",
+		Kind:   entityNotification.KindBookingExpired,
+		Data:   entityNotification.Data{SeatBookingID: "booking1", MovieTitle: "Dune", Seats: []string{"A1"}, TotalPrice: 50000},
+	}, mock.Anything).Return(nil)
+	suite.repoNotify.On("Queue", mock.Anything, mock.Anything, mock.MatchedBy(func(n entityNotification.Notification) bool { return n.UserID == "user2" }), mock.Anything).Return(nil)
+	suite.repo.On("Delete", mock.Anything, mock.Anything, "booking1", mock.Anything).Return([]entityPayment.FailedPayment{
+		{ID: "payment1", UserID: "user1", SeatBookingID: "booking1", PreviousStatus: "pending", TotalPrice: 50000},
+	}, nil)
+	suite.repo.On("Delete", mock.Anything, mock.Anything, "booking2", mock.Anything).Return([]entityPayment.FailedPayment{}, nil)
+	suite.repoOutbox.On("Publish", mock.Anything, mock.Anything, entityWebhook.EventPaymentFailed, dtoWebhook.PaymentData{
+		ID: "payment1", UserID: "user1", SeatBookingID: "booking1", Status: "failed", PreviousStatus: "pending", TotalPrice: 50000,
+	}, mock.Anything).Return(nil).Once()
+	suite.repoOutbox.On("Publish", mock.Anything, mock.Anything, entityWebhook.EventSeatBookingExpired, dtoWebhook.SeatBookingData{
+		ID: "booking1", UserID: "user1", ShowtimeID: "showtime1", SeatIDs: []string{"seat1"}, TotalPrice: 50000, HoldExpiresAt: suite.now,
+	}, mock.Anything).Return(nil).Once()
+	suite.repoOutbox.On("Publish", mock.Anything, mock.Anything, entityWebhook.EventSeatBookingExpired, mock.MatchedBy(func(data dtoWebhook.SeatBookingData) bool { return data.ID == "booking2" }), mock.Anything).Return(nil).Once()
+	suite.mockSql.ExpectCommit()
+
+	released, err := suite.sweeper.Sweep(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, released)
+	suite.repo.AssertExpectations(suite.T())
+	suite.repoNotify.AssertExpectations(suite.T())
+	suite.repoOutbox.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SweeperTestSuite) TestSweep_NothingExpired() {
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("FindExpiredPending", mock.Anything, mock.Anything, suite.now, mock.Anything).Return([]entity.SeatBooking{}, nil)
+	suite.mockSql.ExpectCommit()
+
+	released, err := suite.sweeper.Sweep(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, released)
+	suite.repo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SweeperTestSuite) TestSweep_DeleteErrorRollsBack() {
+	expired := []entity.SeatBooking{{ID: "booking1"}}
+
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("FindExpiredPending", mock.Anything, mock.Anything, suite.now, mock.Anything).Return(expired, nil)
+	suite.repo.On("FindByID", mock.Anything, mock.Anything, "booking1", mock.Anything).Return(entity.SeatBooking{ID: "booking1"}, nil)
+	suite.repoNotify.On("Queue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.repo.On("Delete", mock.Anything, mock.Anything, "booking1", mock.Anything).Return([]entityPayment.FailedPayment{}, errors.New("delete error"))
+	suite.mockSql.ExpectRollback()
+
+	released, err := suite.sweeper.Sweep(context.Background())
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), 0, released)
+	suite.repoOutbox.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SweeperTestSuite) TestSweep_PublishErrorRollsBack() {
+	expired := []entity.SeatBooking{{ID: "booking1"}}
+
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("FindExpiredPending", mock.Anything, mock.Anything, suite.now, mock.Anything).Return(expired, nil)
+	suite.repo.On("FindByID", mock.Anything, mock.Anything, "booking1", mock.Anything).Return(entity.SeatBooking{ID: "booking1"}, nil)
+	suite.repoNotify.On("Queue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.repo.On("Delete", mock.Anything, mock.Anything, "booking1", mock.Anything).Return([]entityPayment.FailedPayment{}, nil)
+	suite.repoOutbox.On("Publish", mock.Anything, mock.Anything, entityWebhook.EventSeatBookingExpired, mock.Anything, mock.Anything).Return(errors.New("outbox error"))
+	suite.mockSql.ExpectRollback()
+
+	released, err := suite.sweeper.Sweep(context.Background())
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), 0, released)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
@@ -6,7 +6,9 @@ import (
 
 type ShowtimeController interface {
 	Create(c *gin.Context)
+	Schedule(c *gin.Context)
 	FindById(c *gin.Context)
 	FindAll(c *gin.Context)
+	Update(c *gin.Context)
 	Delete(c *gin.Context)
 }
@@ -4,8 +4,10 @@ import (
 	"bioskuy/api/v1/showtime/dto"
 	"bioskuy/api/v1/showtime/service"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"bioskuy/web"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -41,6 +43,34 @@ func (ctrl *showtimeControllerImpl) Create(c *gin.Context){
 	c.JSON(http.StatusCreated, response)
 }
 
+func (ctrl *showtimeControllerImpl) Schedule(c *gin.Context){
+	ctx := c.Request.Context()
+	request := dto.ScheduleRequest{}
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, err := ctrl.Service.Schedule(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	status := http.StatusCreated
+	if result.DryRun {
+		status = http.StatusOK
+	}
+
+	response := web.FormatResponse{
+		ResponseCode: status,
+		Data: result,
+	}
+
+	c.JSON(status, response)
+}
+
 func (controller *showtimeControllerImpl) FindById(c *gin.Context){
 
 	response := web.FormatResponse{}
@@ -61,27 +91,80 @@ func (controller *showtimeControllerImpl) FindById(c *gin.Context){
 
 func (controller *showtimeControllerImpl) FindAll(c *gin.Context) {
     ctx := c.Request.Context()
+    request := dto.ShowtimeSearchRequest{}
+
+    err := c.ShouldBindQuery(&request)
+    if err != nil {
+        c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return
+    }
+
+    if request.Page < 1 {
+        request.Page = 1
+    }
+    if request.Size < 1 {
+        request.Size = helper.DefaultPageSize
+    }
+    if request.Size > helper.MaxPageSize {
+        request.Size = helper.MaxPageSize
+    }
 
-    result, err := controller.Service.FindAll(ctx, c)
+    result, paging, err := controller.Service.FindAll(ctx, request, c)
     if err != nil {
         c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return
     }
 
-    response := web.FormatResponse{
+    response := web.FormatResponsePaging{
         ResponseCode: http.StatusOK,
         Data:    result,
+        Paging: web.Paging{
+            Page:       paging.Page,
+            Size:       paging.Size,
+            TotalData:  paging.TotalRows,
+            TotalPages: paging.TotalPages,
+        },
     }
 
     c.JSON(http.StatusOK, response)
 }
 
+func (ctrl *showtimeControllerImpl) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.UpdateShowtimeRequest{}
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+	request.ID = c.Param("showtimeId")
+
+	result, err := ctrl.Service.Update(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	response := web.FormatResponse{
+		ResponseCode: http.StatusOK,
+		Data: result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (ctl *showtimeControllerImpl) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
 	response := web.FormatResponse{}
 	id := c.Param("showtimeId")
 
-	err := ctl.Service.Delete(ctx, id, c)
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		c.Error(exception.ValidationError{Message: "force must be true or false"}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	err = ctl.Service.Delete(ctx, id, force, c)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return
@@ -26,18 +26,31 @@ func (m *MockShowtimeService) Create(ctx context.Context, request dto.ShowtimeRe
 	return args.Get(0).(dto.CreateShowtimesResponseDTO), args.Error(1)
 }
 
+func (m *MockShowtimeService) Schedule(ctx context.Context, request dto.ScheduleRequest, c *gin.Context) (dto.ScheduleResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.ScheduleResponse), args.Error(1)
+}
+
 func (m *MockShowtimeService) FindByID(ctx context.Context, id string, c *gin.Context) (dto.ShowtimesResponse, error) {
 	args := m.Called(ctx, id, c)
 	return args.Get(0).(dto.ShowtimesResponse), args.Error(1)
 }
 
-func (m *MockShowtimeService) FindAll(ctx context.Context, c *gin.Context) ([]dto.ShowtimesResponse, error) {
-	args := m.Called(ctx, c)
-	return args.Get(0).([]dto.ShowtimesResponse), args.Error(1)
+func (m *MockShowtimeService) FindAll(ctx context.Context, request dto.ShowtimeSearchRequest, c *gin.Context) ([]dto.ShowtimesResponse, dto.Paging, error) {
+	args := m.Called(ctx, request, c)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(dto.Paging), args.Error(2)
+	}
+	return args.Get(0).([]dto.ShowtimesResponse), args.Get(1).(dto.Paging), args.Error(2)
+}
+
+func (m *MockShowtimeService) Update(ctx context.Context, request dto.UpdateShowtimeRequest, c *gin.Context) (dto.RescheduleResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.RescheduleResponse), args.Error(1)
 }
 
-func (m *MockShowtimeService) Delete(ctx context.Context, id string, c *gin.Context) error {
-	args := m.Called(ctx, id, c)
+func (m *MockShowtimeService) Delete(ctx context.Context, id string, force bool, c *gin.Context) error {
+	args := m.Called(ctx, id, force, c)
 	return args.Error(0)
 }
 
@@ -87,6 +100,33 @@ func (suite *ShowtimeControllerTestSuite) TestCreate_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+func (suite *ShowtimeControllerTestSuite) TestSchedule_DryRun() {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/showtimes/schedule", suite.controller.Schedule)
+
+	request := dto.ScheduleRequest{
+		MovieID:    "1",
+		StudioIDs:  []string{"1", "2"},
+		DateFrom:   "2024-07-13",
+		DateTo:     "2024-07-14",
+		StartTimes: []string{"13:00"},
+		DryRun:     true,
+	}
+
+	suite.mockService.On("Schedule", mock.Anything, request, mock.Anything).Return(dto.ScheduleResponse{MovieID: "1", DryRun: true}, nil).Once()
+
+	body, _ := json.Marshal(request)
+	req, _ := http.NewRequest(http.MethodPost, "/showtimes/schedule", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
 func (suite *ShowtimeControllerTestSuite) TestFindById_Success() {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -134,7 +174,8 @@ func (suite *ShowtimeControllerTestSuite) TestFindAll_Success() {
 		},
 	}
 
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(showtimeResponses, nil).Once()
+	request := dto.ShowtimeSearchRequest{Page: 1, Size: 10}
+	suite.mockService.On("FindAll", mock.Anything, request, mock.Anything).Return(showtimeResponses, dto.Paging{Page: 1, Size: 10, TotalRows: 1, TotalPages: 1}, nil).Once()
 
 	req, _ := http.NewRequest(http.MethodGet, "/showtimes", nil)
 	w := httptest.NewRecorder()
@@ -145,12 +186,58 @@ func (suite *ShowtimeControllerTestSuite) TestFindAll_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+func (suite *ShowtimeControllerTestSuite) TestFindAll_SizeCapped() {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/showtimes", suite.controller.FindAll)
+
+	request := dto.ShowtimeSearchRequest{Page: 1, Size: helper.MaxPageSize}
+	suite.mockService.On("FindAll", mock.Anything, request, mock.Anything).Return([]dto.ShowtimesResponse{}, dto.Paging{Page: 1, Size: helper.MaxPageSize}, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/showtimes?size=100000", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *ShowtimeControllerTestSuite) TestFindAll_Filters() {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/showtimes", suite.controller.FindAll)
+
+	upcoming := false
+	request := dto.ShowtimeSearchRequest{
+		DateFrom: "2024-07-09",
+		MovieID:  "1",
+		GenreID:  "2",
+		Upcoming: &upcoming,
+		Sort:     "remaining_seats",
+		Order:    "desc",
+		Page:     2,
+		Size:     5,
+	}
+
+	suite.mockService.On("FindAll", mock.Anything, request, mock.Anything).Return([]dto.ShowtimesResponse{}, dto.Paging{Page: 2, Size: 5, TotalRows: 6, TotalPages: 2}, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/showtimes?date_from=2024-07-09&movie_id=1&genre_id=2&upcoming=false&sort=remaining_seats&order=desc&page=2&size=5", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"total-data":6`)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
 func (suite *ShowtimeControllerTestSuite) TestDelete_Success() {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.DELETE("/showtimes/:showtimeId", suite.controller.Delete)
 
-	suite.mockService.On("Delete", mock.Anything, "1", mock.Anything).Return(nil).Once()
+	suite.mockService.On("Delete", mock.Anything, "1", false, mock.Anything).Return(nil).Once()
 
 	req, _ := http.NewRequest(http.MethodDelete, "/showtimes/1", nil)
 	w := httptest.NewRecorder()
@@ -161,6 +248,40 @@ func (suite *ShowtimeControllerTestSuite) TestDelete_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+func (suite *ShowtimeControllerTestSuite) TestDelete_Force() {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.DELETE("/showtimes/:showtimeId", suite.controller.Delete)
+
+	suite.mockService.On("Delete", mock.Anything, "1", true, mock.Anything).Return(nil).Once()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/showtimes/1?force=true", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *ShowtimeControllerTestSuite) TestUpdate_Success() {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.PUT("/showtimes/:showtimeId", suite.controller.Update)
+
+	request := dto.UpdateShowtimeRequest{ID: "1", StudioID: "2"}
+	suite.mockService.On("Update", mock.Anything, request, mock.Anything).Return(dto.RescheduleResponse{Showtime: dto.ShowtimesResponse{ID: "1", StudioID: "2"}}, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodPut, "/showtimes/1", bytes.NewBufferString(`{"studio_id":"2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
 func (suite *ShowtimeControllerTestSuite) TestCreate_ValidationError() {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -240,9 +361,10 @@ func (suite *ShowtimeControllerTestSuite) TestFindById_InternalServerError() {
 func (suite *ShowtimeControllerTestSuite) TestFindAll_InternalServerError() {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(exception.ErrorHandler)
 	router.GET("/showtimes", suite.controller.FindAll)
 
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(nil, exception.InternalServerError{Message: "Internal Server Error"}).Once()
+	suite.mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(nil, dto.Paging{}, exception.InternalServerError{Message: "Internal Server Error"}).Once()
 
 	req, _ := http.NewRequest(http.MethodGet, "/showtimes", nil)
 	w := httptest.NewRecorder()
@@ -258,7 +380,7 @@ func (suite *ShowtimeControllerTestSuite) TestDelete_InternalServerError() {
 	router := gin.Default()
 	router.DELETE("/showtimes/:showtimeId", suite.controller.Delete)
 
-	suite.mockService.On("Delete", mock.Anything, "1", mock.Anything).Return(exception.InternalServerError{Message: "Internal Server Error"}).Once()
+	suite.mockService.On("Delete", mock.Anything, "1", false, mock.Anything).Return(exception.InternalServerError{Message: "Internal Server Error"}).Once()
 
 	req, _ := http.NewRequest(http.MethodDelete, "/showtimes/1", nil)
 	w := httptest.NewRecorder()
@@ -274,7 +396,7 @@ func (suite *ShowtimeControllerTestSuite) TestDelete_NotFoundError() {
 	router := gin.Default()
 	router.DELETE("/showtimes/:showtimeId", suite.controller.Delete)
 
-	suite.mockService.On("Delete", mock.Anything, "1", mock.Anything).Return(exception.NotFoundError{Message: "Showtime Not Found"}).Once()
+	suite.mockService.On("Delete", mock.Anything, "1", false, mock.Anything).Return(exception.NotFoundError{Message: "Showtime Not Found"}).Once()
 
 	req, _ := http.NewRequest(http.MethodDelete, "/showtimes/1", nil)
 	w := httptest.NewRecorder()
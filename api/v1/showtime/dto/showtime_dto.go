@@ -19,12 +19,20 @@ type CreateShowtimeDTO struct {
 }
 
 type CreateShowtimesResponseDTO struct {
+	ID string `json:"id"`
 	MovieID string `json:"movie_id" validate:"required"`
 	StudioID string `json:"studio_id" validate:"required"`
 	ShowStart time.Time `json:"show_start" validate:"required"`
 	ShowEnd time.Time `json:"show_end" validate:"required"`
+	ShowStartUTC time.Time `json:"show_start_utc"`
+	ShowEndUTC time.Time `json:"show_end_utc"`
+	Timezone string `json:"timezone"`
+	TurnaroundMinutes int `json:"turnaround_minutes"`
+	StudioReadyAt time.Time `json:"studio_ready_at"`
 }
 
+// ShowtimesResponse renders show_start, show_end and studio_ready_at as
+// wall-clock time in the cinema's timezone, with the UTC instants alongside.
 type ShowtimesResponse struct {
 	ID               	string `json:"id"`
 	StudioID 			string `json:"studio_id"`
@@ -35,5 +43,114 @@ type ShowtimesResponse struct {
 	MoviePrice       	int    `json:"movie_price"`
 	MovieDuration    	int    `json:"movie_duration"`
 	MovieStatus      	string `json:"movie_status"`
+	ShowStart        	time.Time `json:"show_start"`
+	ShowEnd          	time.Time `json:"show_end"`
+	ShowStartUTC     	time.Time `json:"show_start_utc"`
+	ShowEndUTC       	time.Time `json:"show_end_utc"`
+	Timezone         	string `json:"timezone"`
+	TurnaroundMinutes	int `json:"turnaround_minutes"`
+	StudioReadyAt    	time.Time `json:"studio_ready_at"`
+	RemainingSeats   	int `json:"remaining_seats"`
+	CinemaID         	string `json:"cinema_id"`
+	CinemaName       	string `json:"cinema_name"`
+	City             	string `json:"city"`
+}
+
+// ShowtimeSearchRequest is bound from the GET /showtimes query string. Dates
+// accept "today", "tomorrow" or YYYY-MM-DD, taken as whole days in each
+// cinema's own timezone, or an exact RFC3339 instant. Date is shorthand for
+// the same date_from and date_to.
+type ShowtimeSearchRequest struct {
+	Date     string `form:"date"`
+	DateFrom string `form:"date_from"`
+	DateTo   string `form:"date_to"`
+	MovieID  string `form:"movie_id"`
+	StudioID string `form:"studio_id"`
+	GenreID  string `form:"genre_id"`
+	CinemaID string `form:"cinema_id"`
+	City     string `form:"city"`
+	// Upcoming defaults to true, hiding showtimes that already started.
+	Upcoming *bool  `form:"upcoming"`
+	Sort     string `form:"sort" validate:"omitempty,oneof=show_start movie_title price remaining_seats"`
+	Order    string `form:"order" validate:"omitempty,oneof=asc desc"`
+	Page     int    `form:"page"`
+	Size     int    `form:"size"`
+}
+
+type Paging struct {
+	Page       int `json:"page"`
+	Size       int `json:"size"`
+	TotalRows  int `json:"total_rows"`
+	TotalPages int `json:"total_pages"`
+}
+
+
+// ScheduleRequest generates one showtime per studio, per matching day, per
+// start time. Dates are YYYY-MM-DD (inclusive), start times HH:MM.
+type ScheduleRequest struct {
+	MovieID    string   `json:"movie_id" validate:"required"`
+	StudioIDs  []string `json:"studio_ids" validate:"required,min=1,unique,dive,required"`
+	DateFrom   string   `json:"date_from" validate:"required"`
+	DateTo     string   `json:"date_to" validate:"required"`
+	// Weekdays limits the schedule to these days; empty means every day.
+	Weekdays   []string `json:"weekdays" validate:"omitempty,unique,dive,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	StartTimes []string `json:"start_times" validate:"required,min=1,unique,dive,required"`
+	DryRun     bool     `json:"dry_run"`
+}
+
+const (
+	ScheduleSlotCreated  = "created"
+	ScheduleSlotPlanned  = "planned"
+	ScheduleSlotConflict = "conflict"
+)
+
+type ScheduleSlot struct {
+	ShowtimeID    string    `json:"showtime_id,omitempty"`
+	StudioID      string    `json:"studio_id"`
+	ShowStart     time.Time `json:"show_start"`
+	ShowEnd       time.Time `json:"show_end"`
+	ShowStartUTC  time.Time `json:"show_start_utc"`
+	StudioReadyAt time.Time `json:"studio_ready_at"`
+	Status        string    `json:"status"`
+}
+
+type ScheduleResponse struct {
+	MovieID   string         `json:"movie_id"`
+	DryRun    bool           `json:"dry_run"`
+	Created   int            `json:"created"`
+	Conflicts int            `json:"conflicts"`
+	Slots     []ScheduleSlot `json:"slots"`
+}
+
+// UpdateShowtimeRequest moves a screening to another time and/or studio.
+// Omitted fields keep their current value.
+type UpdateShowtimeRequest struct {
+	ID        string `json:"id"`
+	StudioID  string `json:"studio_id"`
+	ShowStart string `json:"show_start"`
+}
+
+type SeatMove struct {
+	SeatBookingID string `json:"seat_booking_id"`
+	FromSeatID    string `json:"from_seat_id"`
+	FromSeatName  string `json:"from_seat_name"`
+	ToSeatID      string `json:"to_seat_id"`
+	ToSeatName    string `json:"to_seat_name"`
+}
+
+type AffectedSeat struct {
+	SeatBookingID string `json:"seat_booking_id"`
+	UserID        string `json:"user_id"`
+	SeatID        string `json:"seat_id"`
+	SeatName      string `json:"seat_name"`
+}
+
+type RescheduleResponse struct {
+	Showtime   ShowtimesResponse `json:"showtime"`
+	MovedSeats []SeatMove        `json:"moved_seats"`
+	// RefundSeats were sold but have no equivalent seat in the new studio.
+	RefundSeats []AffectedSeat `json:"refund_seats"`
+	// ReleasedSeats were only held by unpaid bookings and have been dropped.
+	ReleasedSeats []AffectedSeat `json:"released_seats"`
 }
 
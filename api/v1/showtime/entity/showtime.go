@@ -14,4 +14,81 @@ type Showtime struct {
 	MoviePrice       int       `json:"movie_price"`
 	MovieDuration    int       `json:"movie_duration"`
 	MovieStatus      string    `json:"movie_status"`
-}
\ No newline at end of file
+	// TurnaroundMinutes is the studio's buffer after this screening ends.
+	TurnaroundMinutes int    `json:"turnaround_minutes"`
+	RemainingSeats    int    `json:"remaining_seats"`
+	CinemaID          string `json:"cinema_id"`
+	CinemaName        string `json:"cinema_name"`
+	City              string `json:"city"`
+	// Timezone is the IANA timezone of the cinema; ShowStart and ShowEnd are
+	// stored in UTC.
+	Timezone string `json:"timezone"`
+}
+
+// ShowtimeFilter narrows FindAll down; zero values are not applied.
+type ShowtimeFilter struct {
+	From     time.Time
+	To       time.Time
+	MovieID  string
+	StudioID string
+	GenreID  string
+	CinemaID string
+	// City matches the cinema's city case-insensitively.
+	City string
+	// DayFrom and DayTo bound the calendar day of show_start, inclusive,
+	// in each cinema's own timezone.
+	DayFrom *LocalDay
+	DayTo   *LocalDay
+	// Sort is one of the ShowtimeSort* keys.
+	Sort   string
+	Desc   bool
+	Limit  int
+	Offset int
+}
+
+// LocalDay is a calendar day in a cinema's timezone: Date (YYYY-MM-DD) when
+// set, otherwise Offset days from the cinema's today.
+type LocalDay struct {
+	Date   string
+	Offset int
+}
+
+const (
+	ShowtimeSortShowStart      = "show_start"
+	ShowtimeSortMovieTitle     = "movie_title"
+	ShowtimeSortPrice          = "price"
+	ShowtimeSortRemainingSeats = "remaining_seats"
+)
+
+// StudioReadyAt is when the studio can host the next screening.
+func (s Showtime) StudioReadyAt() time.Time {
+	return s.ShowEnd.Add(time.Duration(s.TurnaroundMinutes) * time.Minute)
+}
+
+// BookedSeat is a seat held by a pending booking or sold to a paid one.
+type BookedSeat struct {
+	DetailID          string    `json:"detail_id"`
+	SeatBookingID     string    `json:"seat_booking_id"`
+	SeatBookingStatus string    `json:"seat_booking_status"`
+	UserID            string    `json:"user_id"`
+	HoldExpiresAt     time.Time `json:"hold_expires_at"`
+	SeatID            string    `json:"seat_id"`
+	SeatName          string    `json:"seat_name"`
+	SeatCategory      string    `json:"seat_category"`
+	// UnitPrice is what the seat was priced at when it was reserved.
+	UnitPrice int `json:"unit_price"`
+}
+
+// Sold reports whether the seat belongs to a paid booking.
+func (b BookedSeat) Sold() bool {
+	return b.SeatBookingStatus == "success"
+}
+
+// RefundFlag marks a sold seat the customer is owed a refund for.
+type RefundFlag struct {
+	ID            string `json:"id"`
+	ShowtimeID    string `json:"showtime_id"`
+	SeatBookingID string `json:"seat_booking_id"`
+	SeatID        string `json:"seat_id"`
+	Reason        string `json:"reason"`
+}
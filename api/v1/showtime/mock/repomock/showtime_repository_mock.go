@@ -3,6 +3,8 @@ package repomock
 import (
 	dtoGenre "bioskuy/api/v1/genre/dto"
 	entityMovie "bioskuy/api/v1/movies/entity"
+	entityPayment "bioskuy/api/v1/payment/entity"
+	"bioskuy/helper"
 
 	"bioskuy/api/v1/showtime/entity"
 	entityStudio "bioskuy/api/v1/studio/entity"
@@ -27,9 +29,9 @@ func (m *MockShowtimeRepository) FindByID(ctx context.Context, tx *sql.Tx, id st
 	return args.Get(0).(entity.Showtime), args.Error(1)
 }
 
-func (m *MockShowtimeRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Showtime, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]entity.Showtime), args.Error(1)
+func (m *MockShowtimeRepository) FindAll(ctx context.Context, tx *sql.Tx, filter entity.ShowtimeFilter, c *gin.Context) ([]entity.Showtime, int, error) {
+	args := m.Called(ctx, tx, filter, c)
+	return args.Get(0).([]entity.Showtime), args.Int(1), args.Error(2)
 }
 
 func (m *MockShowtimeRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
@@ -42,6 +44,41 @@ func (m *MockShowtimeRepository) FindConflictingShowtimes(ctx context.Context, t
 	return args.Error(0)
 }
 
+func (m *MockShowtimeRepository) Update(ctx context.Context, tx *sql.Tx, showtime entity.Showtime, c *gin.Context) (entity.Showtime, error) {
+	args := m.Called(ctx, tx, showtime, c)
+	return args.Get(0).(entity.Showtime), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) Cancel(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) ([]entityPayment.FailedPayment, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).([]entityPayment.FailedPayment), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) FindBookedSeats(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) ([]entity.BookedSeat, error) {
+	args := m.Called(ctx, tx, showtimeID, c)
+	return args.Get(0).([]entity.BookedSeat), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) MoveBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, seatID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, detailID, seatID, c)
+	return args.Error(0)
+}
+
+func (m *MockShowtimeRepository) ReleaseBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, detailID, c)
+	return args.Error(0)
+}
+
+func (m *MockShowtimeRepository) HasBookings(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (bool, error) {
+	args := m.Called(ctx, tx, showtimeID, c)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockShowtimeRepository) FlagForRefund(ctx context.Context, tx *sql.Tx, flag entity.RefundFlag, c *gin.Context) (entity.RefundFlag, error) {
+	args := m.Called(ctx, tx, flag, c)
+	return args.Get(0).(entity.RefundFlag), args.Error(1)
+}
+
 type MockMovieRepository struct {
 	mock.Mock
 }
@@ -85,9 +122,9 @@ func (m *MockStudioRepository) FindByID(ctx context.Context, tx *sql.Tx, id stri
 	return args.Get(0).(entityStudio.Studio), args.Error(1)
 }
 
-func (m *MockStudioRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entityStudio.Studio, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]entityStudio.Studio), args.Error(1)
+func (m *MockStudioRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entityStudio.Studio, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entityStudio.Studio), args.Int(1), args.Error(2)
 }
 
 func (m *MockStudioRepository) Update(ctx context.Context, tx *sql.Tx, studio entityStudio.Studio, c *gin.Context) (entityStudio.Studio, error) {
@@ -99,3 +136,8 @@ func (m *MockStudioRepository) Delete(ctx context.Context, tx *sql.Tx, id string
 	args := m.Called(ctx, tx, id, c)
 	return args.Error(0)
 }
+
+func (m *MockStudioRepository) HasUpcomingShowtimes(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (bool, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Bool(0), args.Error(1)
+}
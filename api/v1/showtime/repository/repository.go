@@ -1,6 +1,7 @@
 package repository
 
 import (
+	entityPayment "bioskuy/api/v1/payment/entity"
 	"bioskuy/api/v1/showtime/entity"
 	entityStudio "bioskuy/api/v1/studio/entity"
 	"context"
@@ -12,7 +13,14 @@ import (
 type ShowtimeRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, user entity.Showtime, c *gin.Context) (entity.Showtime, error)
 	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Showtime, error)
-	FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Showtime, error)
+	FindAll(ctx context.Context, tx *sql.Tx, filter entity.ShowtimeFilter, c *gin.Context) ([]entity.Showtime, int, error)
+	Update(ctx context.Context, tx *sql.Tx, showtime entity.Showtime, c *gin.Context) (entity.Showtime, error)
 	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	Cancel(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) ([]entityPayment.FailedPayment, error)
 	FindConflictingShowtimes(ctx context.Context, tx *sql.Tx, studio entityStudio.Studio, showtime entity.Showtime, c *gin.Context) error 
+	FindBookedSeats(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) ([]entity.BookedSeat, error)
+	HasBookings(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (bool, error)
+	MoveBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, seatID string, c *gin.Context) error
+	ReleaseBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, c *gin.Context) error
+	FlagForRefund(ctx context.Context, tx *sql.Tx, flag entity.RefundFlag, c *gin.Context) (entity.RefundFlag, error)
 }
@@ -1,17 +1,24 @@
 package repository
 
 import (
+	entityPayment "bioskuy/api/v1/payment/entity"
 	"bioskuy/api/v1/showtime/entity"
 	entityStudio "bioskuy/api/v1/studio/entity"
 	"bioskuy/exception"
+	"bioskuy/logger"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ErrConflictingShowtimes is returned by FindConflictingShowtimes when the
+// studio is already booked for part of the requested slot.
+var ErrConflictingShowtimes = errors.New("conflicting showtimes found")
+
 type showtimeRepository struct {
 }
 
@@ -21,8 +28,7 @@ func NewShowtimeRepository() ShowtimeRepository {
 
 func (r *showtimeRepository) Save(ctx context.Context, tx *sql.Tx, showtime entity.Showtime, c *gin.Context) (entity.Showtime, error){
 
-	fmt.Println(showtime.ShowStart)
-	fmt.Println(showtime.ShowEnd)
+	logger.FromContext(ctx).Debug("saving showtime", "studio_id", showtime.StudioID, "show_start", showtime.ShowStart, "show_end", showtime.ShowEnd)
 
 	query := "INSERT INTO showtimes (movie_id, studio_id, show_start, show_end) VALUES ($1, $2, $3, $4) RETURNING id"
 
@@ -37,11 +43,9 @@ func (r *showtimeRepository) Save(ctx context.Context, tx *sql.Tx, showtime enti
 
 func (r *showtimeRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Showtime, error){
 
-	query := `SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status
-    FROM showtimes s
-    JOIN studios st ON s.studio_id = st.id
-    JOIN movies m ON s.movie_id = m.id
-    WHERE s.id = $1
+	query := `SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status, st.turnaround_minutes as studio_turnaround_minutes, st.cinema_id, ci.name as cinema_name, ci.city as cinema_city, ci.timezone as cinema_timezone, ` + remainingSeatsColumn + `
+    FROM showtimes s ` + showtimeJoins + `
+    WHERE s.id = $1 AND s.cancelled_at IS NULL
     `
 	showtime := entity.Showtime{}
 	rows, err := tx.QueryContext(ctx, query, id)
@@ -55,7 +59,8 @@ func (r *showtimeRepository) FindByID(ctx context.Context, tx *sql.Tx, id string
 		err := rows.Scan(
 			&showtime.ID, &showtime.StudioID, &showtime.MovieID, &showtime.ShowStart, &showtime.ShowEnd,
 			&showtime.StudioName, &showtime.MovieTitle, &showtime.MovieDescription, &showtime.MoviePrice,
-			&showtime.MovieDuration, &showtime.MovieStatus,
+			&showtime.MovieDuration, &showtime.MovieStatus, &showtime.TurnaroundMinutes,
+			&showtime.CinemaID, &showtime.CinemaName, &showtime.City, &showtime.Timezone, &showtime.RemainingSeats,
 		)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -68,20 +73,32 @@ func (r *showtimeRepository) FindByID(ctx context.Context, tx *sql.Tx, id string
 	}
 }
 
-func (r *showtimeRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Showtime, error){
+func (r *showtimeRepository) FindAll(ctx context.Context, tx *sql.Tx, filter entity.ShowtimeFilter, c *gin.Context) ([]entity.Showtime, int, error){
+
+	where, args := showtimeFilterClause(filter)
+
+	total := 0
+	countQuery := `SELECT COUNT(*) FROM showtimes s ` + showtimeJoins + ` ` + where
+	if err := tx.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
 
 	query := `
-    SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status
-    FROM showtimes s
-    JOIN studios st ON s.studio_id = st.id
-    JOIN movies m ON s.movie_id = m.id
-    `
+    SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status, st.turnaround_minutes as studio_turnaround_minutes, st.cinema_id, ci.name as cinema_name, ci.city as cinema_city, ci.timezone as cinema_timezone, ` + remainingSeatsColumn + `
+    FROM showtimes s ` + showtimeJoins + `
+    ` + where + ` ORDER BY ` + showtimeOrderBy(filter)
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
 
 	showtimes := []entity.Showtime{}
-	rows, err := tx.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  showtimes, err
+		return  showtimes, 0, err
 	}
 	defer rows.Close()
 
@@ -90,13 +107,110 @@ func (r *showtimeRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Con
 		if err := rows.Scan(
             &showtime.ID, &showtime.StudioID, &showtime.MovieID, &showtime.ShowStart, &showtime.ShowEnd,
             &showtime.StudioName, &showtime.MovieTitle, &showtime.MovieDescription, &showtime.MoviePrice,
-            &showtime.MovieDuration, &showtime.MovieStatus,
+            &showtime.MovieDuration, &showtime.MovieStatus, &showtime.TurnaroundMinutes,
+			&showtime.CinemaID, &showtime.CinemaName, &showtime.City, &showtime.Timezone, &showtime.RemainingSeats,
         ); err != nil {
-			return nil, err
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, 0, err
 		}
 		showtimes = append(showtimes, showtime)
 	}
-	return showtimes, nil
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+	return showtimes, total, nil
+}
+
+const showtimeJoins = `JOIN studios st ON s.studio_id = st.id
+    JOIN cinemas ci ON st.cinema_id = ci.id
+    JOIN movies m ON s.movie_id = m.id`
+
+// localShowDate is the calendar day a showtime starts on in its cinema's
+// timezone; show_start itself is stored in UTC.
+const localShowDate = `(s.show_start AT TIME ZONE 'UTC' AT TIME ZONE ci.timezone)::date`
+
+// remainingSeatsColumn counts the sellable seats of the showtime's studio that
+// are not taken by a booking for this screening.
+const remainingSeatsColumn = `(SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.isAvailable AND se.retired_at IS NULL AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = s.id)) as remaining_seats`
+
+func showtimeFilterClause(filter entity.ShowtimeFilter) (string, []interface{}) {
+	conditions := []string{"s.cancelled_at IS NULL"}
+	args := []interface{}{}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !filter.From.IsZero() {
+		add("s.show_start >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("s.show_start < $%d", filter.To)
+	}
+	if filter.MovieID != "" {
+		add("s.movie_id = $%d", filter.MovieID)
+	}
+	if filter.StudioID != "" {
+		add("s.studio_id = $%d", filter.StudioID)
+	}
+	if filter.GenreID != "" {
+		add("EXISTS (SELECT 1 FROM genre_to_movies gtm WHERE gtm.movie_id = s.movie_id AND gtm.genre_id = $%d)", filter.GenreID)
+	}
+	if filter.CinemaID != "" {
+		add("st.cinema_id = $%d", filter.CinemaID)
+	}
+	if filter.City != "" {
+		add("LOWER(ci.city) = LOWER($%d)", filter.City)
+	}
+
+	addDay := func(operator string, day *entity.LocalDay) {
+		if day.Date != "" {
+			add(localShowDate+" "+operator+" $%d::date", day.Date)
+			return
+		}
+		add(localShowDate+" "+operator+" (NOW() AT TIME ZONE ci.timezone)::date + $%d::int", day.Offset)
+	}
+	if filter.DayFrom != nil {
+		addDay(">=", filter.DayFrom)
+	}
+	if filter.DayTo != nil {
+		addDay("<=", filter.DayTo)
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func showtimeOrderBy(filter entity.ShowtimeFilter) string {
+	column := "s.show_start"
+	switch filter.Sort {
+	case entity.ShowtimeSortMovieTitle:
+		column = "m.title"
+	case entity.ShowtimeSortPrice:
+		column = "m.price"
+	case entity.ShowtimeSortRemainingSeats:
+		column = "remaining_seats"
+	}
+
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
+	}
+
+	return column + " " + direction + ", s.id"
+}
+
+func (r *showtimeRepository) Update(ctx context.Context, tx *sql.Tx, showtime entity.Showtime, c *gin.Context) (entity.Showtime, error){
+	query := `UPDATE showtimes SET studio_id = $1, show_start = $2, show_end = $3 WHERE id = $4`
+
+	_, err := tx.ExecContext(ctx, query, showtime.StudioID, showtime.ShowStart, showtime.ShowEnd, showtime.ID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return showtime, err
+	}
+
+	return showtime, nil
 }
 
 func (r *showtimeRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error{
@@ -113,17 +227,20 @@ func (r *showtimeRepository) Delete(ctx context.Context, tx *sql.Tx, id string,
 }
 
 func (r *showtimeRepository) FindConflictingShowtimes(ctx context.Context, tx *sql.Tx, studio entityStudio.Studio, showtime entity.Showtime, c *gin.Context) error {
+    // Each screening blocks the studio until its end plus the studio's
+    // turnaround, so two slots conflict when those windows overlap.
     query := `SELECT s.id
               FROM showtimes s
+              JOIN studios st ON s.studio_id = st.id
               WHERE s.studio_id = $1
-              AND (
-                  (s.show_start >= $2 AND s.show_start <= $3) OR
-                  (s.show_end >= $2 AND s.show_end <= $3) OR
-                  (s.show_start <= $2 AND s.show_end >= $2) OR
-                  (s.show_start <= $3 AND s.show_end >= $3)
-              )`
+              AND s.show_start < $3
+              AND s.show_end + st.turnaround_minutes * INTERVAL '1 minute' > $2
+              AND s.cancelled_at IS NULL
+              AND s.id::text <> $4`
     
-    rows, err := tx.QueryContext(ctx, query, studio.ID, showtime.ShowStart, showtime.ShowEnd)
+    // showtime.ID is empty for new showtimes; a rescheduled one must not
+    // conflict with its own current slot.
+    rows, err := tx.QueryContext(ctx, query, studio.ID, showtime.ShowStart, showtime.ShowEnd.Add(studio.Turnaround()), showtime.ID)
     if err != nil {
         c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return err
@@ -131,10 +248,141 @@ func (r *showtimeRepository) FindConflictingShowtimes(ctx context.Context, tx *s
     defer rows.Close()
 
     if rows.Next() {
-        return errors.New("conflicting showtimes found")
+        return ErrConflictingShowtimes
     }
     
     return nil
 }
 
+// FindBookedSeats lists the seats of pending and paid bookings, paid ones first.
+func (r *showtimeRepository) FindBookedSeats(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) ([]entity.BookedSeat, error) {
+	query := `SELECT sdfb.id, sb.id, sb.status, sb.user_id, sb.hold_expires_at, se.id, se.seat_name, se.category, sdfb.unit_price
+              FROM seat_detail_for_bookings sdfb
+              JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id
+              JOIN seats se ON sdfb.seat_id = se.id
+              WHERE sdfb.showtime_id = $1
+              AND sb.status IN ('pending', 'success')
+              ORDER BY sb.status = 'success' DESC, sb.id, se.seat_name`
+
+	seats := []entity.BookedSeat{}
+	rows, err := tx.QueryContext(ctx, query, showtimeID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return seats, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		seat := entity.BookedSeat{}
+		if err := rows.Scan(&seat.DetailID, &seat.SeatBookingID, &seat.SeatBookingStatus, &seat.UserID, &seat.HoldExpiresAt, &seat.SeatID, &seat.SeatName, &seat.SeatCategory, &seat.UnitPrice); err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		seats = append(seats, seat)
+	}
+
+	return seats, nil
+}
+
+// HasBookings reports whether any booking, in whatever status, refers to the
+// showtime, which keeps it from being deleted.
+func (r *showtimeRepository) HasBookings(ctx context.Context, tx *sql.Tx, showtimeID string, c *gin.Context) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM seat_bookings WHERE showtime_id = $1)`
 
+	hasBookings := false
+	err := tx.QueryRowContext(ctx, query, showtimeID).Scan(&hasBookings)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return false, err
+	}
+
+	return hasBookings, nil
+}
+
+// MoveBookedSeat points a booked seat at another seat, keeping its booking.
+func (r *showtimeRepository) MoveBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, seatID string, c *gin.Context) error {
+	query := `UPDATE seat_detail_for_bookings SET seat_id = $1 WHERE id = $2`
+
+	_, err := tx.ExecContext(ctx, query, seatID, detailID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// ReleaseBookedSeat drops a seat from a pending booking's hold.
+func (r *showtimeRepository) ReleaseBookedSeat(ctx context.Context, tx *sql.Tx, detailID string, c *gin.Context) error {
+	query := `DELETE FROM seat_detail_for_bookings WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, detailID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+func (r *showtimeRepository) FlagForRefund(ctx context.Context, tx *sql.Tx, flag entity.RefundFlag, c *gin.Context) (entity.RefundFlag, error) {
+	query := `INSERT INTO seat_refund_flags (showtime_id, seatbooking_id, seat_id, reason) VALUES ($1, $2, $3, $4)
+              ON CONFLICT (seatbooking_id, seat_id) DO UPDATE SET reason = EXCLUDED.reason
+              RETURNING id`
+
+	err := tx.QueryRowContext(ctx, query, flag.ShowtimeID, flag.SeatBookingID, flag.SeatID, flag.Reason).Scan(&flag.ID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return flag, err
+	}
+
+	return flag, nil
+}
+
+// Cancel hides a showtime that still has bookings and releases its pending
+// holds, returning the open payments it failed. Paid bookings are left in
+// place for the refund flow.
+func (r *showtimeRepository) Cancel(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) ([]entityPayment.FailedPayment, error) {
+	failOpenPaymentQuery := `UPDATE payments p SET status = 'failed'
+		FROM payments old
+		WHERE p.id = old.id AND p.status IN ('unpaid', 'pending')
+		AND p.seatbooking_id IN (SELECT id FROM seat_bookings WHERE showtime_id = $1 AND status = 'pending')
+		RETURNING p.id, p.user_id, p.seatbooking_id, old.status, p.total_price`
+
+	rows, err := tx.QueryContext(ctx, failOpenPaymentQuery, id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	failed := []entityPayment.FailedPayment{}
+	for rows.Next() {
+		payment := entityPayment.FailedPayment{}
+		if err := rows.Scan(&payment.ID, &payment.UserID, &payment.SeatBookingID, &payment.PreviousStatus, &payment.TotalPrice); err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		failed = append(failed, payment)
+	}
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+
+	queries := []string{
+		`DELETE FROM seat_detail_for_bookings WHERE seatBooking_id IN (SELECT id FROM seat_bookings WHERE showtime_id = $1 AND status = 'pending')`,
+		`UPDATE seat_bookings SET status = 'cancelled' WHERE showtime_id = $1 AND status = 'pending'`,
+		`UPDATE showtimes SET cancelled_at = NOW() AT TIME ZONE 'UTC' WHERE id = $1`,
+	}
+
+	for _, query := range queries {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+	}
+
+	return failed, nil
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	entityPayment "bioskuy/api/v1/payment/entity"
 	"bioskuy/api/v1/showtime/entity"
 	entityStudio "bioskuy/api/v1/studio/entity"
 
@@ -110,16 +111,22 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindByID_Success() {
 		MoviePrice:       10000,
 		MovieDuration:    120,
 		MovieStatus:      "Active",
+		TurnaroundMinutes: 15,
+		RemainingSeats:    42,
+		CinemaID:          "cinema-1",
+		CinemaName:        "Bioskuy Central",
+		City:              "Jakarta",
+		Timezone:          "Asia/Jakarta",
 	}
 
-	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1`)
+	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status, st.turnaround_minutes as studio_turnaround_minutes, st.cinema_id, ci.name as cinema_name, ci.city as cinema_city, ci.timezone as cinema_timezone, (SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.isAvailable AND se.retired_at IS NULL AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = s.id)) as remaining_seats FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN cinemas ci ON st.cinema_id = ci.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1 AND s.cancelled_at IS NULL`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status"}).
-		AddRow(expectedShowtime.ID, expectedShowtime.StudioID, expectedShowtime.MovieID, expectedShowtime.ShowStart, expectedShowtime.ShowEnd, expectedShowtime.StudioName, expectedShowtime.MovieTitle, expectedShowtime.MovieDescription, expectedShowtime.MoviePrice, expectedShowtime.MovieDuration, expectedShowtime.MovieStatus))
+	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status", "studio_turnaround_minutes", "cinema_id", "cinema_name", "cinema_city", "cinema_timezone", "remaining_seats"}).
+		AddRow(expectedShowtime.ID, expectedShowtime.StudioID, expectedShowtime.MovieID, expectedShowtime.ShowStart, expectedShowtime.ShowEnd, expectedShowtime.StudioName, expectedShowtime.MovieTitle, expectedShowtime.MovieDescription, expectedShowtime.MoviePrice, expectedShowtime.MovieDuration, expectedShowtime.MovieStatus, expectedShowtime.TurnaroundMinutes, expectedShowtime.CinemaID, expectedShowtime.CinemaName, expectedShowtime.City, expectedShowtime.Timezone, expectedShowtime.RemainingSeats))
 
 	ginContext, _ := gin.CreateTestContext(nil)
 	foundShowtime, err := suite.repo.FindByID(context.Background(), tx, showtimeID, ginContext)
@@ -136,7 +143,7 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindByID_Success() {
 
 func (suite *ShowtimeRepositoryTestSuite) TestFindByID_NotFound() {
 	showtimeID := "1"
-	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1`)
+	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status, st.turnaround_minutes as studio_turnaround_minutes, st.cinema_id, ci.name as cinema_name, ci.city as cinema_city, ci.timezone as cinema_timezone, (SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.isAvailable AND se.retired_at IS NULL AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = s.id)) as remaining_seats FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN cinemas ci ON st.cinema_id = ci.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1 AND s.cancelled_at IS NULL`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
@@ -158,14 +165,14 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindByID_NotFound() {
 
 func (suite *ShowtimeRepositoryTestSuite) TestFindByID_ScanError() {
 	showtimeID := "1"
-	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1`)
+	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status, st.turnaround_minutes as studio_turnaround_minutes, st.cinema_id, ci.name as cinema_name, ci.city as cinema_city, ci.timezone as cinema_timezone, (SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.isAvailable AND se.retired_at IS NULL AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = s.id)) as remaining_seats FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN cinemas ci ON st.cinema_id = ci.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1 AND s.cancelled_at IS NULL`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status"}).
-		AddRow("invalid_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status"))
+	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status", "studio_turnaround_minutes", "cinema_id", "cinema_name", "cinema_city", "cinema_timezone", "remaining_seats"}).
+		AddRow("invalid_id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status", "studio_turnaround_minutes", "cinema_id", "cinema_name", "cinema_city", "cinema_timezone", "remaining_seats"))
 
 	ginContext, _ := gin.CreateTestContext(nil)
 	foundShowtime, err := suite.repo.FindByID(context.Background(), tx, showtimeID, ginContext)
@@ -182,13 +189,13 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindByID_ScanError() {
 
 func (suite *ShowtimeRepositoryTestSuite) TestFindByID_ShowtimeNotFound() {
 	showtimeID := "1"
-	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1`)
+	query := regexp.QuoteMeta(`SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status, st.turnaround_minutes as studio_turnaround_minutes, st.cinema_id, ci.name as cinema_name, ci.city as cinema_city, ci.timezone as cinema_timezone, (SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.isAvailable AND se.retired_at IS NULL AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = s.id)) as remaining_seats FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN cinemas ci ON st.cinema_id = ci.id JOIN movies m ON s.movie_id = m.id WHERE s.id = $1 AND s.cancelled_at IS NULL`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status"}))
+	suite.mockSql.ExpectQuery(query).WithArgs(showtimeID).WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status", "studio_turnaround_minutes", "cinema_id", "cinema_name", "cinema_city", "cinema_timezone", "remaining_seats"}))
 
 	ginContext, _ := gin.CreateTestContext(nil)
 	foundShowtime, err := suite.repo.FindByID(context.Background(), tx, showtimeID, ginContext)
@@ -205,27 +212,83 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindByID_ShowtimeNotFound() {
 }
 
 func (suite *ShowtimeRepositoryTestSuite) TestFindAll_Success() {
+	countQuery := regexp.QuoteMeta(`SELECT COUNT(*) FROM showtimes s`)
 	query := regexp.QuoteMeta(`
-        SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status
+        SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status, st.turnaround_minutes as studio_turnaround_minutes, st.cinema_id, ci.name as cinema_name, ci.city as cinema_city, ci.timezone as cinema_timezone, (SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.isAvailable AND se.retired_at IS NULL AND NOT EXISTS (SELECT 1 FROM seat_detail_for_bookings sdfb WHERE sdfb.seat_id = se.id AND sdfb.showtime_id = s.id)) as remaining_seats
         FROM showtimes s
         JOIN studios st ON s.studio_id = st.id
+        JOIN cinemas ci ON st.cinema_id = ci.id
         JOIN movies m ON s.movie_id = m.id
+        WHERE s.cancelled_at IS NULL
+        ORDER BY s.show_start ASC, s.id
     `)
 
-	rows := sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status"}).
-		AddRow("1", "1", "1", time.Now(), time.Now().Add(2*time.Hour), "Studio 1", "Movie 1", "Description 1", 10000, 120, "Active").
-		AddRow("2", "2", "2", time.Now(), time.Now().Add(3*time.Hour), "Studio 2", "Movie 2", "Description 2", 15000, 150, "Active")
+	rows := sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status", "studio_turnaround_minutes", "cinema_id", "cinema_name", "cinema_city", "cinema_timezone", "remaining_seats"}).
+		AddRow("1", "1", "1", time.Now(), time.Now().Add(2*time.Hour), "Studio 1", "Movie 1", "Description 1", 10000, 120, "Active", 15, "cinema-1", "Bioskuy Central", "Jakarta", "Asia/Jakarta", 40).
+		AddRow("2", "2", "2", time.Now(), time.Now().Add(3*time.Hour), "Studio 2", "Movie 2", "Description 2", 15000, 150, "Active", 20, "cinema-1", "Bioskuy Central", "Jakarta", "Asia/Jakarta", 0)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
+	suite.mockSql.ExpectQuery(countQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	suite.mockSql.ExpectQuery(query).WillReturnRows(rows)
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	showtimes, err := suite.repo.FindAll(context.Background(), tx, ginContext)
+	showtimes, total, err := suite.repo.FindAll(context.Background(), tx, entity.ShowtimeFilter{}, ginContext)
 	suite.NoError(err)
 	suite.Len(showtimes, 2)
+	suite.Equal(2, total)
+	suite.Equal(40, showtimes[0].RemainingSeats)
+
+	suite.mockSql.ExpectCommit()
+	err = tx.Commit()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *ShowtimeRepositoryTestSuite) TestFindAll_Filtered() {
+	from := time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	filter := entity.ShowtimeFilter{
+		From:     from,
+		To:       to,
+		MovieID:  "movie-1",
+		StudioID: "studio-1",
+		GenreID:  "genre-1",
+		CinemaID: "cinema-1",
+		City:     "Jakarta",
+		DayFrom:  &entity.LocalDay{},
+		DayTo:    &entity.LocalDay{Date: "2024-07-09"},
+		Sort:     entity.ShowtimeSortRemainingSeats,
+		Desc:     true,
+		Limit:    5,
+		Offset:   10,
+	}
+
+	where := `WHERE s.cancelled_at IS NULL AND s.show_start >= $1 AND s.show_start < $2 AND s.movie_id = $3 AND s.studio_id = $4 AND EXISTS (SELECT 1 FROM genre_to_movies gtm WHERE gtm.movie_id = s.movie_id AND gtm.genre_id = $5) AND st.cinema_id = $6 AND LOWER(ci.city) = LOWER($7) AND (s.show_start AT TIME ZONE 'UTC' AT TIME ZONE ci.timezone)::date >= (NOW() AT TIME ZONE ci.timezone)::date + $8::int AND (s.show_start AT TIME ZONE 'UTC' AT TIME ZONE ci.timezone)::date <= $9::date`
+	countQuery := regexp.QuoteMeta(`SELECT COUNT(*) FROM showtimes s JOIN studios st ON s.studio_id = st.id JOIN cinemas ci ON st.cinema_id = ci.id JOIN movies m ON s.movie_id = m.id ` + where)
+	query := regexp.QuoteMeta(`JOIN movies m ON s.movie_id = m.id ` + where + ` ORDER BY remaining_seats DESC, s.id LIMIT $10 OFFSET $11`)
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(countQuery).
+		WithArgs(from, to, "movie-1", "studio-1", "genre-1", "cinema-1", "Jakarta", 0, "2024-07-09").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+	suite.mockSql.ExpectQuery(query).
+		WithArgs(from, to, "movie-1", "studio-1", "genre-1", "cinema-1", "Jakarta", 0, "2024-07-09", 5, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "studio_id", "movie_id", "show_start", "show_end", "studio_name", "movie_title", "movie_description", "movie_price", "movie_duration", "movie_status", "studio_turnaround_minutes", "cinema_id", "cinema_name", "cinema_city", "cinema_timezone", "remaining_seats"}).
+			AddRow("1", "studio-1", "movie-1", from.Add(10*time.Hour), from.Add(12*time.Hour), "Studio 1", "Movie 1", "Description 1", 10000, 120, "Active", 15, "cinema-1", "Bioskuy Central", "Jakarta", "Asia/Jakarta", 40))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	showtimes, total, err := suite.repo.FindAll(context.Background(), tx, filter, ginContext)
+	suite.NoError(err)
+	suite.Len(showtimes, 1)
+	suite.Equal(11, total)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -236,21 +299,16 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindAll_Success() {
 }
 
 func (suite *ShowtimeRepositoryTestSuite) TestFindAll_Error() {
-	query := regexp.QuoteMeta(`
-        SELECT s.id, s.studio_id, s.movie_id, s.show_start, s.show_end, st.name as studio_name, m.title as movie_title, m.description as movie_description, m.price as movie_price, m.duration as movie_duration, m.status as movie_status
-        FROM showtimes s
-        JOIN studios st ON s.studio_id = st.id
-        JOIN movies m ON s.movie_id = m.id
-    `)
+	countQuery := regexp.QuoteMeta(`SELECT COUNT(*) FROM showtimes s`)
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	suite.mockSql.ExpectQuery(query).WillReturnError(errors.New("query error"))
+	suite.mockSql.ExpectQuery(countQuery).WillReturnError(errors.New("query error"))
 
 	ginContext, _ := gin.CreateTestContext(nil)
-	_, err = suite.repo.FindAll(context.Background(), tx, ginContext)
+	_, _, err = suite.repo.FindAll(context.Background(), tx, entity.ShowtimeFilter{}, ginContext)
 	suite.Error(err)
 
 	suite.mockSql.ExpectRollback()
@@ -306,7 +364,7 @@ func (suite *ShowtimeRepositoryTestSuite) TestDelete_Error() {
 }
 
 func (suite *ShowtimeRepositoryTestSuite) TestFindConflictingShowtimes_Success() {
-	studio := entityStudio.Studio{ID: "1"}
+	studio := entityStudio.Studio{ID: "1", TurnaroundMinutes: 15}
 	showtime := entity.Showtime{
 		ShowStart: time.Now(),
 		ShowEnd:   time.Now().Add(2 * time.Hour),
@@ -314,20 +372,19 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindConflictingShowtimes_Success()
 
 	query := `SELECT s.id
               FROM showtimes s
-              WHERE s.studio_id = \$1
-              AND (
-                  (s.show_start >= \$2 AND s.show_start <= \$3) OR
-                  (s.show_end >= \$2 AND s.show_end <= \$3) OR
-                  (s.show_start <= \$2 AND s.show_end >= \$2) OR
-                  (s.show_start <= \$3 AND s.show_end >= \$3)
-              )`
+              JOIN studios st ON s.studio_id = st.id
+              WHERE s.studio_id = $1
+              AND s.show_start < $3
+              AND s.show_end + st.turnaround_minutes * INTERVAL '1 minute' > $2
+              AND s.cancelled_at IS NULL
+              AND s.id::text <> $4`
 
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
 	suite.mockSql.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(studio.ID, showtime.ShowStart, showtime.ShowEnd).
+		WithArgs(studio.ID, showtime.ShowStart, showtime.ShowEnd.Add(15*time.Minute), showtime.ID).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
 	ginContext, _ := gin.CreateTestContext(nil)
@@ -342,6 +399,157 @@ func (suite *ShowtimeRepositoryTestSuite) TestFindConflictingShowtimes_Success()
 	suite.NoError(err)
 }
 
+func (suite *ShowtimeRepositoryTestSuite) TestFindConflictingShowtimes_WithinTurnaround() {
+	studio := entityStudio.Studio{ID: "1", TurnaroundMinutes: 15}
+	showtime := entity.Showtime{
+		ShowStart: time.Now(),
+		ShowEnd:   time.Now().Add(2 * time.Hour),
+	}
+
+	query := `SELECT s.id
+              FROM showtimes s
+              JOIN studios st ON s.studio_id = st.id
+              WHERE s.studio_id = $1
+              AND s.show_start < $3
+              AND s.show_end + st.turnaround_minutes * INTERVAL '1 minute' > $2
+              AND s.cancelled_at IS NULL
+              AND s.id::text <> $4`
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(studio.ID, showtime.ShowStart, showtime.ShowEnd.Add(15*time.Minute), showtime.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	err = suite.repo.FindConflictingShowtimes(context.Background(), tx, studio, showtime, ginContext)
+	suite.EqualError(err, "conflicting showtimes found")
+
+	suite.mockSql.ExpectRollback()
+	err = tx.Rollback()
+	suite.NoError(err)
+
+	err = suite.mockSql.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *ShowtimeRepositoryTestSuite) TestUpdate_Success() {
+	showtime := entity.Showtime{
+		ID:        "1",
+		StudioID:  "2",
+		ShowStart: time.Now(),
+		ShowEnd:   time.Now().Add(2 * time.Hour),
+	}
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE showtimes SET studio_id = $1, show_start = $2, show_end = $3 WHERE id = $4`)).
+		WithArgs(showtime.StudioID, showtime.ShowStart, showtime.ShowEnd, showtime.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	result, err := suite.repo.Update(context.Background(), tx, showtime, ginContext)
+	suite.NoError(err)
+	suite.Equal(showtime, result)
+
+	suite.mockSql.ExpectCommit()
+	suite.NoError(tx.Commit())
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *ShowtimeRepositoryTestSuite) TestFindBookedSeats_Success() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM seat_detail_for_bookings sdfb JOIN seat_bookings sb ON sdfb.seatBooking_id = sb.id JOIN seats se ON sdfb.seat_id = se.id WHERE sdfb.showtime_id = $1 AND sb.status IN ('pending', 'success')`)).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "id", "status", "user_id", "hold_expires_at", "id", "seat_name", "category", "unit_price"}).
+			AddRow("d1", "b1", "success", "u1", time.Time{}, "s1", "A-1", "regular", 50000).
+			AddRow("d2", "b2", "pending", "u2", time.Date(2024, 7, 21, 10, 15, 0, 0, time.UTC), "s2", "A-2", "vip", 75000))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	seats, err := suite.repo.FindBookedSeats(context.Background(), tx, "1", ginContext)
+	suite.NoError(err)
+	suite.Len(seats, 2)
+	suite.True(seats[0].Sold())
+	suite.False(seats[1].Sold())
+	suite.Equal("vip", seats[1].SeatCategory)
+	suite.Equal(75000, seats[1].UnitPrice)
+
+	suite.mockSql.ExpectCommit()
+	suite.NoError(tx.Commit())
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *ShowtimeRepositoryTestSuite) TestHasBookings() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM seat_bookings WHERE showtime_id = $1)`)).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	hasBookings, err := suite.repo.HasBookings(context.Background(), tx, "1", ginContext)
+	suite.NoError(err)
+	suite.True(hasBookings)
+
+	suite.mockSql.ExpectCommit()
+	suite.NoError(tx.Commit())
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *ShowtimeRepositoryTestSuite) TestFlagForRefund_Success() {
+	flag := entity.RefundFlag{ShowtimeID: "1", SeatBookingID: "b1", SeatID: "s1", Reason: "showtime cancelled"}
+
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`INSERT INTO seat_refund_flags (showtime_id, seatbooking_id, seat_id, reason) VALUES ($1, $2, $3, $4)`)).
+		WithArgs(flag.ShowtimeID, flag.SeatBookingID, flag.SeatID, flag.Reason).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("f1"))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	result, err := suite.repo.FlagForRefund(context.Background(), tx, flag, ginContext)
+	suite.NoError(err)
+	suite.Equal("f1", result.ID)
+
+	suite.mockSql.ExpectCommit()
+	suite.NoError(tx.Commit())
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *ShowtimeRepositoryTestSuite) TestCancel_Success() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`UPDATE payments p SET status = 'failed'`)).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "seatbooking_id", "status", "total_price"}).
+			AddRow("p1", "u2", "b2", "unpaid", 50000))
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`DELETE FROM seat_detail_for_bookings`)).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE seat_bookings SET status = 'cancelled' WHERE showtime_id = $1 AND status = 'pending'`)).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE showtimes SET cancelled_at = NOW() AT TIME ZONE 'UTC' WHERE id = $1`)).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ginContext, _ := gin.CreateTestContext(nil)
+	failed, err := suite.repo.Cancel(context.Background(), tx, "1", ginContext)
+	suite.NoError(err)
+	suite.Equal([]entityPayment.FailedPayment{
+		{ID: "p1", UserID: "u2", SeatBookingID: "b2", PreviousStatus: "unpaid", TotalPrice: 50000},
+	}, failed)
+
+	suite.mockSql.ExpectCommit()
+	suite.NoError(tx.Commit())
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
 func TestShowtimeRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(ShowtimeRepositoryTestSuite))
 }
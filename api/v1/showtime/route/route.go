@@ -2,10 +2,14 @@ package movieroute
 
 import (
 	movieRepo "bioskuy/api/v1/movies/repository"
+	notificationRepo "bioskuy/api/v1/notification/repository"
+	seatRepo "bioskuy/api/v1/seat/repository"
 	"bioskuy/api/v1/showtime/controller"
 	showtimeRepo "bioskuy/api/v1/showtime/repository"
 	"bioskuy/api/v1/showtime/service"
 	studioRepo "bioskuy/api/v1/studio/repository"
+	ticketRepo "bioskuy/api/v1/ticket/repository"
+	webhookRepo "bioskuy/api/v1/webhook/repository"
 	"bioskuy/auth"
 	"bioskuy/helper"
 	"bioskuy/middleware"
@@ -22,16 +26,22 @@ func ShowtimeRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB,
 	showtimeRepo := showtimeRepo.NewShowtimeRepository()
 	studioRepo := studioRepo.NewStudioRepository()
 	movieRepo := movieRepo.NewMovieRepository(db)
-	showService := service.NewGenreToMovieService(showtimeRepo, movieRepo, studioRepo, validate, db)
+	seatRepo := seatRepo.NewSeatRepository()
+	ticketRepo := ticketRepo.NewTicketRepository()
+	outboxRepo := webhookRepo.NewOutboxRepository()
+	notificationRepo := notificationRepo.NewNotificationRepository()
+	showService := service.NewGenreToMovieService(showtimeRepo, movieRepo, studioRepo, seatRepo, ticketRepo, outboxRepo, notificationRepo, validate, db)
 	showController := controller.NewMovieController(showService)
 	v1 := router.Group("/api/v1")
 	{
 		showtimeRoutes := v1.Group("/showtimes")
 		{
-			showtimeRoutes.POST("/", middleware.AuthMiddleware(authService, "admin"), showController.Create)
+			showtimeRoutes.POST("/", middleware.AuthMiddleware(authService, "admin", "branch admin"), showController.Create)
+			showtimeRoutes.POST("/schedule", middleware.AuthMiddleware(authService, "admin", "branch admin"), showController.Schedule)
 			showtimeRoutes.GET("/", showController.FindAll)
 			showtimeRoutes.GET("/:showtimeId", showController.FindById)
-			showtimeRoutes.DELETE("/:showtimeId", middleware.AuthMiddleware(authService, "admin"), showController.Delete)
+			showtimeRoutes.PUT("/:showtimeId", middleware.AuthMiddleware(authService, "admin", "branch admin"), showController.Update)
+			showtimeRoutes.DELETE("/:showtimeId", middleware.AuthMiddleware(authService, "admin", "branch admin"), showController.Delete)
 		}
 	}
 }
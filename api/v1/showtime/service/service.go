@@ -9,7 +9,9 @@ import (
 
 type ShowtimeService interface {
 	Create(ctx context.Context, request dto.ShowtimeRequest, c *gin.Context) (dto.CreateShowtimesResponseDTO, error) 
+	Schedule(ctx context.Context, request dto.ScheduleRequest, c *gin.Context) (dto.ScheduleResponse, error)
 	FindByID(ctx context.Context, id string, c *gin.Context) (dto.ShowtimesResponse, error)
-	FindAll(ctx context.Context, c *gin.Context) ([]dto.ShowtimesResponse, error)
-	Delete(ctx context.Context, id string, c *gin.Context) error
+	FindAll(ctx context.Context, request dto.ShowtimeSearchRequest, c *gin.Context) ([]dto.ShowtimesResponse, dto.Paging, error)
+	Update(ctx context.Context, request dto.UpdateShowtimeRequest, c *gin.Context) (dto.RescheduleResponse, error)
+	Delete(ctx context.Context, id string, force bool, c *gin.Context) error
 }
@@ -2,15 +2,29 @@ package service
 
 import (
 	RepoMovie "bioskuy/api/v1/movies/repository"
+	entityNotification "bioskuy/api/v1/notification/entity"
+	RepoNotification "bioskuy/api/v1/notification/repository"
+	entityPayment "bioskuy/api/v1/payment/entity"
+	entitySeat "bioskuy/api/v1/seat/entity"
+	RepoSeat "bioskuy/api/v1/seat/repository"
 	"bioskuy/api/v1/showtime/dto"
 	"bioskuy/api/v1/showtime/entity"
 	"bioskuy/api/v1/showtime/repository"
+	entityStudio "bioskuy/api/v1/studio/entity"
 	RepoStudio "bioskuy/api/v1/studio/repository"
+	RepoTicket "bioskuy/api/v1/ticket/repository"
+	dtoWebhook "bioskuy/api/v1/webhook/dto"
+	entityWebhook "bioskuy/api/v1/webhook/entity"
+	RepoWebhook "bioskuy/api/v1/webhook/repository"
 	"bioskuy/exception"
 	"bioskuy/helper"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,15 +35,23 @@ type showtimesServiceImpl struct {
 	Repo repository.ShowtimeRepository
 	RepoMovie RepoMovie.MovieRepository
 	RepoStudio RepoStudio.StudioRepository
+	RepoSeat RepoSeat.SeatRepository
+	RepoTicket RepoTicket.TicketRepository
+	RepoOutbox RepoWebhook.OutboxRepository
+	RepoNotification RepoNotification.NotificationRepository
 	Validate *validator.Validate
 	DB *sql.DB
 }
 
-func NewGenreToMovieService(repo repository.ShowtimeRepository, RepoMovie RepoMovie.MovieRepository, RepoStudio RepoStudio.StudioRepository, validate *validator.Validate, DB *sql.DB) ShowtimeService {
+func NewGenreToMovieService(repo repository.ShowtimeRepository, RepoMovie RepoMovie.MovieRepository, RepoStudio RepoStudio.StudioRepository, RepoSeat RepoSeat.SeatRepository, RepoTicket RepoTicket.TicketRepository, RepoOutbox RepoWebhook.OutboxRepository, RepoNotification RepoNotification.NotificationRepository, validate *validator.Validate, DB *sql.DB) ShowtimeService {
 	return &showtimesServiceImpl{
 		Repo: repo,
 		RepoMovie: RepoMovie,
 		RepoStudio: RepoStudio,
+		RepoSeat: RepoSeat,
+		RepoTicket: RepoTicket,
+		RepoOutbox: RepoOutbox,
+		RepoNotification: RepoNotification,
 		Validate: validate,
 		DB: DB,
 	}
@@ -41,7 +63,6 @@ func (s *showtimesServiceImpl) Create(ctx context.Context, request dto.ShowtimeR
 	
 	ShowtimeRequest.MovieID = request.MovieID
 	ShowtimeRequest.StudioID = request.StudioID
-	ShowtimeRequest.ShowStart = helper.StringToDate(request.ShowStart, c)
 
 	err := s.Validate.Struct(request)
 	if err != nil {
@@ -68,7 +89,21 @@ func (s *showtimesServiceImpl) Create(ctx context.Context, request dto.ShowtimeR
 		return ShowtimeResponse, err
 	}
 
-	duration := time.Duration(movie.Duration) * time.Hour
+	if !helper.CanManageCinema(c, studio.CinemaID) {
+		err := errors.New("you can only manage showtimes of your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ShowtimeResponse, err
+	}
+
+	// A show_start without an offset is wall-clock time at the studio's cinema.
+	ShowtimeRequest.ShowStart, err = helper.ParseLocalTime(request.ShowStart, helper.Location(studio.Timezone))
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ShowtimeResponse, err
+	}
+
+	// Movie durations are stored in minutes.
+	duration := time.Duration(movie.Duration) * time.Minute
 
 	ShowtimeRequest.ShowEnd = ShowtimeRequest.ShowStart.Add(duration)
 
@@ -79,8 +114,6 @@ func (s *showtimesServiceImpl) Create(ctx context.Context, request dto.ShowtimeR
 		ShowEnd: ShowtimeRequest.ShowEnd ,
 	}
 
-	fmt.Println(showtime)
-
 	err = s.Repo.FindConflictingShowtimes(ctx, tx, studio, showtime, c)
 	if err != nil {
 		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -93,14 +126,192 @@ func (s *showtimesServiceImpl) Create(ctx context.Context, request dto.ShowtimeR
 		return ShowtimeResponse, err
 	}
 
+	result.TurnaroundMinutes = studio.TurnaroundMinutes
+
+	ShowtimeResponse.ID = result.ID
 	ShowtimeResponse.MovieID = result.MovieID
 	ShowtimeResponse.StudioID = result.StudioID
-	ShowtimeResponse.ShowStart = result.ShowStart
-	ShowtimeResponse.ShowEnd = result.ShowEnd
+	ShowtimeResponse.ShowStart = helper.InZone(result.ShowStart, studio.Timezone)
+	ShowtimeResponse.ShowEnd = helper.InZone(result.ShowEnd, studio.Timezone)
+	ShowtimeResponse.ShowStartUTC = result.ShowStart.UTC()
+	ShowtimeResponse.ShowEndUTC = result.ShowEnd.UTC()
+	ShowtimeResponse.Timezone = helper.Location(studio.Timezone).String()
+	ShowtimeResponse.TurnaroundMinutes = result.TurnaroundMinutes
+	ShowtimeResponse.StudioReadyAt = helper.InZone(result.StudioReadyAt(), studio.Timezone)
 
 	return ShowtimeResponse, nil
 }
 
+// maxScheduleSlots bounds a single Schedule call so a typo in the date range
+// cannot generate years of showtimes.
+const maxScheduleSlots = 1000
+
+func (s *showtimesServiceImpl) Schedule(ctx context.Context, request dto.ScheduleRequest, c *gin.Context) (dto.ScheduleResponse, error) {
+	ScheduleResponse := dto.ScheduleResponse{MovieID: request.MovieID, DryRun: request.DryRun}
+
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ScheduleResponse, err
+	}
+
+	starts, err := scheduleStarts(request)
+	if err == nil && len(starts)*len(request.StudioIDs) > maxScheduleSlots {
+		err = fmt.Errorf("schedule would create more than %d showtimes", maxScheduleSlots)
+	}
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ScheduleResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ScheduleResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	movie, err := s.RepoMovie.GetByID(request.MovieID)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: "Movie Not Found"}).SetType(gin.ErrorTypePublic)
+		return ScheduleResponse, err
+	}
+	duration := time.Duration(movie.Duration) * time.Minute
+
+	studios := []entityStudio.Studio{}
+	for _, studioID := range request.StudioIDs {
+		studio, err := s.RepoStudio.FindByID(ctx, tx, studioID, c)
+		if err != nil {
+			c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return ScheduleResponse, err
+		}
+		if !helper.CanManageCinema(c, studio.CinemaID) {
+			err := errors.New("you can only manage showtimes of your own cinema")
+			c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return ScheduleResponse, err
+		}
+		studios = append(studios, studio)
+	}
+
+	// Slots of this batch are checked against each other here, since a dry
+	// run never writes them for FindConflictingShowtimes to see.
+	accepted := map[string][]entity.Showtime{}
+
+	for _, wallClock := range starts {
+		for _, studio := range studios {
+			start := atLocation(wallClock, helper.Location(studio.Timezone))
+			showtime := entity.Showtime{
+				StudioID:          studio.ID,
+				MovieID:           request.MovieID,
+				ShowStart:         start,
+				ShowEnd:           start.Add(duration),
+				TurnaroundMinutes: studio.TurnaroundMinutes,
+			}
+			slot := dto.ScheduleSlot{
+				StudioID:      showtime.StudioID,
+				ShowStart:     helper.InZone(showtime.ShowStart, studio.Timezone),
+				ShowEnd:       helper.InZone(showtime.ShowEnd, studio.Timezone),
+				ShowStartUTC:  showtime.ShowStart,
+				StudioReadyAt: helper.InZone(showtime.StudioReadyAt(), studio.Timezone),
+			}
+
+			conflict := overlapsAny(showtime, accepted[studio.ID])
+			if !conflict {
+				err = s.Repo.FindConflictingShowtimes(ctx, tx, studio, showtime, c)
+				if errors.Is(err, repository.ErrConflictingShowtimes) {
+					conflict = true
+				} else if err != nil {
+					return ScheduleResponse, err
+				}
+			}
+
+			switch {
+			case conflict:
+				slot.Status = dto.ScheduleSlotConflict
+				ScheduleResponse.Conflicts++
+			case request.DryRun:
+				slot.Status = dto.ScheduleSlotPlanned
+				accepted[studio.ID] = append(accepted[studio.ID], showtime)
+			default:
+				result, err := s.Repo.Save(ctx, tx, showtime, c)
+				if err != nil {
+					return ScheduleResponse, err
+				}
+				slot.ShowtimeID = result.ID
+				slot.Status = dto.ScheduleSlotCreated
+				ScheduleResponse.Created++
+				accepted[studio.ID] = append(accepted[studio.ID], showtime)
+			}
+
+			ScheduleResponse.Slots = append(ScheduleResponse.Slots, slot)
+		}
+	}
+
+	return ScheduleResponse, nil
+}
+
+// scheduleStarts expands the request's date range, weekdays and daily start
+// times into the start of every slot, in chronological order. The starts are
+// wall-clock times; Schedule reads them in each studio's timezone.
+func scheduleStarts(request dto.ScheduleRequest) ([]time.Time, error) {
+	from, err := time.Parse(time.DateOnly, request.DateFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date_from: %w", err)
+	}
+	to, err := time.Parse(time.DateOnly, request.DateTo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date_to: %w", err)
+	}
+	if to.Before(from) {
+		return nil, errors.New("date_to must not be before date_from")
+	}
+
+	offsets := []time.Duration{}
+	for _, value := range request.StartTimes {
+		startTime, err := time.Parse("15:04", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time %q: %w", value, err)
+		}
+		offsets = append(offsets, time.Duration(startTime.Hour())*time.Hour+time.Duration(startTime.Minute())*time.Minute)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	weekdays := map[time.Weekday]bool{}
+	for _, weekday := range request.Weekdays {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(day.String(), weekday) {
+				weekdays[day] = true
+			}
+		}
+	}
+
+	starts := []time.Time{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if len(weekdays) > 0 && !weekdays[date.Weekday()] {
+			continue
+		}
+		for _, offset := range offsets {
+			starts = append(starts, date.Add(offset))
+		}
+	}
+
+	return starts, nil
+}
+
+// atLocation reads the wall-clock time of t in loc and returns it in UTC.
+func atLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).UTC()
+}
+
+func overlapsAny(showtime entity.Showtime, others []entity.Showtime) bool {
+	for _, other := range others {
+		if showtime.ShowStart.Before(other.StudioReadyAt()) && other.ShowStart.Before(showtime.StudioReadyAt()) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *showtimesServiceImpl) FindByID(ctx context.Context, id string, c *gin.Context) (dto.ShowtimesResponse, error){
 	ShowtimeResponse := dto.ShowtimesResponse{}
 
@@ -117,77 +328,530 @@ func (s *showtimesServiceImpl) FindByID(ctx context.Context, id string, c *gin.C
 		return  ShowtimeResponse, err
 	}
 
-	ShowtimeResponse.ID = result.ID
-	ShowtimeResponse.StudioID = result.StudioID
-	ShowtimeResponse.MovieID = result.MovieID
-	ShowtimeResponse.StudioName = result.StudioName
-	ShowtimeResponse.MovieTitle = result.MovieTitle
-	ShowtimeResponse.MovieDescription = result.MovieDescription
-	ShowtimeResponse.MoviePrice = result.MoviePrice
-	ShowtimeResponse.MovieDuration = result.MovieDuration
-	ShowtimeResponse.MovieStatus = result.MovieStatus
-
-	return ShowtimeResponse, nil
+	return toShowtimesResponse(result), nil
 }
 
-func (s *showtimesServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto.ShowtimesResponse, error){
+func (s *showtimesServiceImpl) FindAll(ctx context.Context, request dto.ShowtimeSearchRequest, c *gin.Context) ([]dto.ShowtimesResponse, dto.Paging, error){
 	ShowtimeResponses := []dto.ShowtimesResponse{}
+	paging := dto.Paging{}
+
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ShowtimeResponses, paging, err
+	}
+
+	filter, err := showtimeFilterFor(request, time.Now().UTC())
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ShowtimeResponses, paging, err
+	}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  ShowtimeResponses, err
+		return  ShowtimeResponses, paging, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	results, err := s.Repo.FindAll(ctx, tx, c)
+	results, total, err := s.Repo.FindAll(ctx, tx, filter, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  ShowtimeResponses, err
+		return  ShowtimeResponses, paging, err
 	}
 
 	for _, result := range results {
-		ShowtimeResponse := dto.ShowtimesResponse{
-			ID: result.ID,
-			StudioID: result.StudioID,
-			MovieID: result.MovieID,
-			StudioName: result.StudioName,
-			MovieTitle: result.MovieTitle,
-			MovieDescription: result.MovieDescription,
-			MoviePrice: result.MoviePrice,
-			MovieDuration: result.MovieDuration,
-			MovieStatus: result.MovieStatus,
+		ShowtimeResponses = append(ShowtimeResponses, toShowtimesResponse(result))
+	}
+
+	paging.Page = request.Page
+	paging.Size = request.Size
+	paging.TotalRows = total
+	paging.TotalPages = int(math.Ceil(float64(total) / float64(request.Size)))
+
+	return ShowtimeResponses, paging, nil
+}
+
+// showtimeFilterFor turns the query string into a repository filter. Unless
+// upcoming=false is passed, showtimes that started before now are left out.
+func showtimeFilterFor(request dto.ShowtimeSearchRequest, now time.Time) (entity.ShowtimeFilter, error) {
+	filter := entity.ShowtimeFilter{
+		MovieID:  request.MovieID,
+		StudioID: request.StudioID,
+		GenreID:  request.GenreID,
+		CinemaID: request.CinemaID,
+		City:     request.City,
+		Sort:     request.Sort,
+		Desc:     request.Order == "desc",
+		Limit:    request.Size,
+		Offset:   (request.Page - 1) * request.Size,
+	}
+
+	dateFrom, dateTo := request.DateFrom, request.DateTo
+	if request.Date != "" {
+		dateFrom, dateTo = request.Date, request.Date
+	}
+
+	if dateFrom != "" {
+		day, from, err := parseSearchDate(dateFrom)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_from: %w", err)
+		}
+		filter.DayFrom, filter.From = day, from
+	}
+
+	if dateTo != "" {
+		day, to, err := parseSearchDate(dateTo)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_to: %w", err)
 		}
-		ShowtimeResponses = append(ShowtimeResponses, ShowtimeResponse)
+		filter.DayTo, filter.To = day, to
 	}
 
-	return ShowtimeResponses, nil
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errors.New("date_from must be before date_to")
+	}
+	if filter.DayFrom != nil && filter.DayTo != nil && filter.DayFrom.Date != "" && filter.DayTo.Date != "" && filter.DayFrom.Date > filter.DayTo.Date {
+		return filter, errors.New("date_from must not be after date_to")
+	}
+
+	if (request.Upcoming == nil || *request.Upcoming) && filter.From.Before(now) {
+		filter.From = now
+	}
+
+	return filter, nil
 }
 
-func (s *showtimesServiceImpl) Delete(ctx context.Context, id string, c *gin.Context) error{
-	showtime := entity.Showtime{}
+// parseSearchDate reads "today", "tomorrow" and YYYY-MM-DD as a calendar day
+// in each cinema's timezone, and an RFC3339 value as an exact instant.
+func parseSearchDate(value string) (*entity.LocalDay, time.Time, error) {
+	switch strings.ToLower(value) {
+	case "today":
+		return &entity.LocalDay{}, time.Time{}, nil
+	case "tomorrow":
+		return &entity.LocalDay{Offset: 1}, time.Time{}, nil
+	}
+
+	if _, err := time.Parse(time.DateOnly, value); err == nil {
+		return &entity.LocalDay{Date: value}, time.Time{}, nil
+	}
+
+	result, err := time.Parse(time.RFC3339, value)
+	return nil, result, err
+}
+
+func (s *showtimesServiceImpl) Update(ctx context.Context, request dto.UpdateShowtimeRequest, c *gin.Context) (dto.RescheduleResponse, error) {
+	RescheduleResponse := dto.RescheduleResponse{}
+
+	if request.StudioID == "" && request.ShowStart == "" {
+		err := errors.New("studio_id or show_start is required")
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return RescheduleResponse, err
+	}
+
+	now := time.Now().UTC()
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return err
+		return RescheduleResponse, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	resultUser, err := s.Repo.FindByID(ctx, tx, id, c)
+	current, err := s.Repo.FindByID(ctx, tx, request.ID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return RescheduleResponse, err
+	}
+
+	if !helper.CanManageCinema(c, current.CinemaID) {
+		err := errors.New("you can only manage showtimes of your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return RescheduleResponse, err
+	}
+
+	if !current.ShowStart.After(now) {
+		err := errors.New("showtime has already started")
+		c.Error(exception.ConflictError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return RescheduleResponse, err
+	}
+
+	showtime := current
+	if request.StudioID != "" {
+		showtime.StudioID = request.StudioID
+	}
+
+	studio, err := s.RepoStudio.FindByID(ctx, tx, showtime.StudioID, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return RescheduleResponse, err
+	}
+	if !helper.CanManageCinema(c, studio.CinemaID) {
+		err := errors.New("you can only manage showtimes of your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return RescheduleResponse, err
+	}
+	showtime.StudioName = studio.Name
+	showtime.TurnaroundMinutes = studio.TurnaroundMinutes
+	showtime.CinemaID = studio.CinemaID
+	showtime.Timezone = studio.Timezone
+
+	// A show_start without an offset is wall-clock time at the new studio.
+	if request.ShowStart != "" {
+		showtime.ShowStart, err = helper.ParseLocalTime(request.ShowStart, helper.Location(studio.Timezone))
+		if err == nil && !showtime.ShowStart.After(now) {
+			err = errors.New("show_start must be in the future")
+		}
+		if err != nil {
+			c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return RescheduleResponse, err
+		}
+	}
+	showtime.ShowEnd = showtime.ShowStart.Add(time.Duration(current.MovieDuration) * time.Minute)
+
+	err = s.Repo.FindConflictingShowtimes(ctx, tx, studio, showtime, c)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflictingShowtimes) {
+			c.Error(exception.ConflictError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		}
+		return RescheduleResponse, err
+	}
+
+	result, err := s.Repo.Update(ctx, tx, showtime, c)
+	if err != nil {
+		return RescheduleResponse, err
+	}
+
+	if result.StudioID != current.StudioID || !result.ShowStart.Equal(current.ShowStart) {
+		booked, err := s.Repo.FindBookedSeats(ctx, tx, result.ID, c)
+		if err != nil {
+			return RescheduleResponse, err
+		}
+
+		// Seat ids belong to a studio, so bookings only need to move when the
+		// screening changes studio.
+		if result.StudioID != current.StudioID {
+			err = s.migrateBookedSeats(ctx, tx, result, booked, &RescheduleResponse, c)
+			if err != nil {
+				return RescheduleResponse, err
+			}
+		}
+
+		refunded := map[string]bool{}
+		for _, seat := range RescheduleResponse.RefundSeats {
+			refunded[seat.SeatID] = true
+		}
+
+		err = s.notifyTicketHolders(ctx, tx, result, entityNotification.KindShowtimeRescheduled, booked, refunded, c)
+		if err != nil {
+			return RescheduleResponse, err
+		}
+	}
+
+	RescheduleResponse.Showtime = toShowtimesResponse(result)
+
+	return RescheduleResponse, nil
+}
+
+// migrateBookedSeats moves every booked seat to the seat with the same name and
+// category in the showtime's new studio, so nobody changes seat class at the
+// price they paid. Paid bookings are served first; sold seats left
+// without a match are flagged for refund with their tickets voided, and
+// unpaid holds are released.
+func (s *showtimesServiceImpl) migrateBookedSeats(ctx context.Context, tx *sql.Tx, showtime entity.Showtime, booked []entity.BookedSeat, response *dto.RescheduleResponse, c *gin.Context) error {
+	seats, err := s.RepoSeat.FindAllByShowtime(ctx, showtime.ID, tx, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return err
 	}
 
-	showtime.ID = resultUser.ID
+	type seatKey struct{ name, category string }
+
+	free := map[seatKey]entitySeat.Seat{}
+	for _, seat := range seats {
+		if seat.IsAvailable {
+			free[seatKey{seat.Name, seat.Category}] = seat
+		}
+	}
+
+	for _, bookedSeat := range booked {
+		affected := dto.AffectedSeat{
+			SeatBookingID: bookedSeat.SeatBookingID,
+			UserID:        bookedSeat.UserID,
+			SeatID:        bookedSeat.SeatID,
+			SeatName:      bookedSeat.SeatName,
+		}
 
-	err = s.Repo.Delete(ctx, tx, id, c)
+		key := seatKey{bookedSeat.SeatName, bookedSeat.SeatCategory}
+		if seat, ok := free[key]; ok {
+			err = s.Repo.MoveBookedSeat(ctx, tx, bookedSeat.DetailID, seat.ID, c)
+			if err != nil {
+				return err
+			}
+			delete(free, key)
+
+			// A ticket is signed over its seat, so the old one is voided and
+			// a new one issued for the seat the booking moved to.
+			if bookedSeat.Sold() {
+				err = s.RepoTicket.Move(ctx, tx, showtime.ID, bookedSeat.SeatID, seat.ID, c)
+				if err != nil {
+					return err
+				}
+			}
+
+			response.MovedSeats = append(response.MovedSeats, dto.SeatMove{
+				SeatBookingID: bookedSeat.SeatBookingID,
+				FromSeatID:    bookedSeat.SeatID,
+				FromSeatName:  bookedSeat.SeatName,
+				ToSeatID:      seat.ID,
+				ToSeatName:    seat.Name,
+			})
+			continue
+		}
+
+		if bookedSeat.Sold() {
+			_, err = s.Repo.FlagForRefund(ctx, tx, entity.RefundFlag{
+				ShowtimeID:    showtime.ID,
+				SeatBookingID: bookedSeat.SeatBookingID,
+				SeatID:        bookedSeat.SeatID,
+				Reason:        "no equivalent seat after reschedule",
+			}, c)
+			if err != nil {
+				return err
+			}
+
+			err = s.RepoTicket.VoidByShowtime(ctx, tx, showtime.ID, []string{bookedSeat.SeatID}, c)
+			if err != nil {
+				return err
+			}
+			response.RefundSeats = append(response.RefundSeats, affected)
+			continue
+		}
+
+		err = s.Repo.ReleaseBookedSeat(ctx, tx, bookedSeat.DetailID, c)
+		if err != nil {
+			return err
+		}
+		response.ReleasedSeats = append(response.ReleasedSeats, affected)
+	}
+
+	return nil
+}
+
+// Delete removes a showtime nobody has ever booked. Any other showtime is
+// cancelled instead, since its bookings keep referring to it; one with sold
+// tickets only when force is set, and their seats are flagged for refund and
+// their tickets voided.
+func (s *showtimesServiceImpl) Delete(ctx context.Context, id string, force bool, c *gin.Context) error{
+	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return err
 	}
+	defer helper.CommitAndRollback(tx, c)
+
+	showtime, err := s.Repo.FindByID(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	if !helper.CanManageCinema(c, showtime.CinemaID) {
+		err := errors.New("you can only manage showtimes of your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	booked, err := s.Repo.FindBookedSeats(ctx, tx, showtime.ID, c)
+	if err != nil {
+		return err
+	}
+
+	sold := []entity.BookedSeat{}
+	for _, bookedSeat := range booked {
+		if bookedSeat.Sold() {
+			sold = append(sold, bookedSeat)
+		}
+	}
+
+	if len(sold) > 0 && !force {
+		err := fmt.Errorf("showtime has %d sold tickets, delete with force=true to cancel it and flag them for refund", len(sold))
+		c.Error(exception.ConflictError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	event := dtoWebhook.ShowtimeData{
+		ID:                    showtime.ID,
+		MovieID:               showtime.MovieID,
+		StudioID:              showtime.StudioID,
+		CinemaID:              showtime.CinemaID,
+		ShowStart:             showtime.ShowStart.UTC(),
+		SeatsFlaggedForRefund: len(sold),
+	}
+
+	if len(booked) == 0 {
+		hasBookings, err := s.Repo.HasBookings(ctx, tx, showtime.ID, c)
+		if err != nil {
+			return err
+		}
+
+		if !hasBookings {
+			err = s.Repo.Delete(ctx, tx, showtime.ID, c)
+			if err != nil {
+				c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+				return err
+			}
+
+			return s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventShowtimeDeleted, event, c)
+		}
+	}
+
+	for _, bookedSeat := range sold {
+		_, err = s.Repo.FlagForRefund(ctx, tx, entity.RefundFlag{
+			ShowtimeID:    showtime.ID,
+			SeatBookingID: bookedSeat.SeatBookingID,
+			SeatID:        bookedSeat.SeatID,
+			Reason:        "showtime cancelled",
+		}, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.RepoTicket.VoidByShowtime(ctx, tx, showtime.ID, nil, c)
+	if err != nil {
+		return err
+	}
+
+	failedPayments, err := s.Repo.Cancel(ctx, tx, showtime.ID, c)
+	if err != nil {
+		return err
+	}
+
+	err = s.publishReleasedHolds(ctx, tx, showtime, booked, failedPayments, c)
+	if err != nil {
+		return err
+	}
+
+	err = s.notifyTicketHolders(ctx, tx, showtime, entityNotification.KindShowtimeCancelled, sold, nil, c)
+	if err != nil {
+		return err
+	}
+
+	return s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventShowtimeCancelled, event, c)
+}
+
+// publishReleasedHolds publishes payment.failed for each payment failed by
+// cancelling the showtime, and seatbooking.cancelled for every pending booking
+// among booked.
+func (s *showtimesServiceImpl) publishReleasedHolds(ctx context.Context, tx *sql.Tx, showtime entity.Showtime, booked []entity.BookedSeat, failedPayments []entityPayment.FailedPayment, c *gin.Context) error {
+	for _, payment := range failedPayments {
+		err := s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventPaymentFailed, dtoWebhook.FailedPaymentData(payment), c)
+		if err != nil {
+			return err
+		}
+	}
+
+	order := []string{}
+	bookings := map[string]*dtoWebhook.SeatBookingData{}
+
+	for _, bookedSeat := range booked {
+		if bookedSeat.Sold() {
+			continue
+		}
+
+		booking, ok := bookings[bookedSeat.SeatBookingID]
+		if !ok {
+			booking = &dtoWebhook.SeatBookingData{
+				ID:            bookedSeat.SeatBookingID,
+				UserID:        bookedSeat.UserID,
+				ShowtimeID:    showtime.ID,
+				SeatIDs:       []string{},
+				HoldExpiresAt: bookedSeat.HoldExpiresAt,
+			}
+			bookings[bookedSeat.SeatBookingID] = booking
+			order = append(order, bookedSeat.SeatBookingID)
+		}
+
+		booking.SeatIDs = append(booking.SeatIDs, bookedSeat.SeatID)
+		booking.TotalPrice += bookedSeat.UnitPrice
+	}
+
+	for _, seatBookingID := range order {
+		err := s.RepoOutbox.Publish(ctx, tx, entityWebhook.EventSeatBookingCancelled, *bookings[seatBookingID], c)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// notifyTicketHolders queues a notification of kind for every paid booking
+// among booked, listing its seats other than those in dropped.
+func (s *showtimesServiceImpl) notifyTicketHolders(ctx context.Context, tx *sql.Tx, showtime entity.Showtime, kind string, booked []entity.BookedSeat, dropped map[string]bool, c *gin.Context) error {
+	order := []string{}
+	notifications := map[string]*entityNotification.Notification{}
+
+	for _, bookedSeat := range booked {
+		if !bookedSeat.Sold() {
+			continue
+		}
+
+		notification, ok := notifications[bookedSeat.SeatBookingID]
+		if !ok {
+			notification = &entityNotification.Notification{
+				UserID: bookedSeat.UserID,
+				Kind:   kind,
+				Data: entityNotification.Data{
+					SeatBookingID: bookedSeat.SeatBookingID,
+					MovieTitle:    showtime.MovieTitle,
+					StudioName:    showtime.StudioName,
+					ShowStart:     showtime.ShowStart,
+					Timezone:      showtime.Timezone,
+					Seats:         []string{},
+				},
+			}
+			notifications[bookedSeat.SeatBookingID] = notification
+			order = append(order, bookedSeat.SeatBookingID)
+		}
+
+		if !dropped[bookedSeat.SeatID] {
+			notification.Data.Seats = append(notification.Data.Seats, bookedSeat.SeatName)
+		}
+	}
+
+	for _, seatBookingID := range order {
+		err := s.RepoNotification.Queue(ctx, tx, *notifications[seatBookingID], c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toShowtimesResponse(result entity.Showtime) dto.ShowtimesResponse {
+	return dto.ShowtimesResponse{
+		ID: result.ID,
+		StudioID: result.StudioID,
+		MovieID: result.MovieID,
+		StudioName: result.StudioName,
+		MovieTitle: result.MovieTitle,
+		MovieDescription: result.MovieDescription,
+		MoviePrice: result.MoviePrice,
+		MovieDuration: result.MovieDuration,
+		MovieStatus: result.MovieStatus,
+		ShowStart: helper.InZone(result.ShowStart, result.Timezone),
+		ShowEnd: helper.InZone(result.ShowEnd, result.Timezone),
+		ShowStartUTC: result.ShowStart.UTC(),
+		ShowEndUTC: result.ShowEnd.UTC(),
+		Timezone: helper.Location(result.Timezone).String(),
+		TurnaroundMinutes: result.TurnaroundMinutes,
+		StudioReadyAt: helper.InZone(result.StudioReadyAt(), result.Timezone),
+		RemainingSeats: result.RemainingSeats,
+		CinemaID: result.CinemaID,
+		CinemaName: result.CinemaName,
+		City: result.City,
+	}
+}
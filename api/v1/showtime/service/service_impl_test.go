@@ -4,10 +4,20 @@ import (
 	"bioskuy/api/v1/movies/entity"
 	entityMovie "bioskuy/api/v1/movies/entity"
 	movieMock "bioskuy/api/v1/movies/mock/repomock"
+	notificationEntity "bioskuy/api/v1/notification/entity"
+	notificationMock "bioskuy/api/v1/notification/mock/repomock"
+	entityPayment "bioskuy/api/v1/payment/entity"
+	seatEntity "bioskuy/api/v1/seat/entity"
+	seatMock "bioskuy/api/v1/seat/mock/repomock"
 	"bioskuy/api/v1/showtime/dto"
 	ShowtimeEntity "bioskuy/api/v1/showtime/entity"
 	showTimeMock "bioskuy/api/v1/showtime/mock/repomock"
+	showTimeRepo "bioskuy/api/v1/showtime/repository"
 	entityStudio "bioskuy/api/v1/studio/entity"
+	ticketMock "bioskuy/api/v1/ticket/mock/repomock"
+	webhookDto "bioskuy/api/v1/webhook/dto"
+	webhookEntity "bioskuy/api/v1/webhook/entity"
+	webhookMock "bioskuy/api/v1/webhook/mock/repomock"
 	"context"
 	"database/sql"
 	"errors"
@@ -25,13 +35,17 @@ import (
 
 type ShowtimeServiceTestSuite struct {
 	suite.Suite
-	service        *showtimesServiceImpl
-	mockRepo       *showTimeMock.MockShowtimeRepository
-	mockRepoMovie  *movieMock.MockMovieRepository
-	mockRepoStudio *showTimeMock.MockStudioRepository
-	sqlMock        sqlmock.Sqlmock
-	validator      *validator.Validate
-	db             *sql.DB
+	service              *showtimesServiceImpl
+	mockRepo             *showTimeMock.MockShowtimeRepository
+	mockRepoMovie        *movieMock.MockMovieRepository
+	mockRepoStudio       *showTimeMock.MockStudioRepository
+	mockRepoSeat         *seatMock.SeatRepository
+	mockRepoTicket       *ticketMock.MockTicketRepository
+	mockRepoOutbox       *webhookMock.MockOutboxRepository
+	mockRepoNotification *notificationMock.MockNotificationRepository
+	sqlMock              sqlmock.Sqlmock
+	validator            *validator.Validate
+	db                   *sql.DB
 }
 
 func (suite *ShowtimeServiceTestSuite) SetupTest() {
@@ -43,12 +57,20 @@ func (suite *ShowtimeServiceTestSuite) SetupTest() {
 	suite.mockRepo = &showTimeMock.MockShowtimeRepository{}
 	suite.mockRepoMovie = &movieMock.MockMovieRepository{}
 	suite.mockRepoStudio = &showTimeMock.MockStudioRepository{}
+	suite.mockRepoSeat = &seatMock.SeatRepository{}
+	suite.mockRepoTicket = &ticketMock.MockTicketRepository{}
+	suite.mockRepoOutbox = &webhookMock.MockOutboxRepository{}
+	suite.mockRepoNotification = &notificationMock.MockNotificationRepository{}
 	suite.validator = validator.New()
 
 	suite.service = &showtimesServiceImpl{
 		suite.mockRepo,
 		suite.mockRepoMovie,
 		suite.mockRepoStudio,
+		suite.mockRepoSeat,
+		suite.mockRepoTicket,
+		suite.mockRepoOutbox,
+		suite.mockRepoNotification,
 		suite.validator,
 		suite.db,
 	}
@@ -71,12 +93,13 @@ func (suite *ShowtimeServiceTestSuite) TestCreate_Success() {
 
 	movie := entityMovie.Movie{
 		ID:       "1",
-		Duration: 2, // 2 hours
+		Duration: 120, // minutes
 	}
 	suite.mockRepoMovie.On("GetByID", request.MovieID).Return(movie, nil).Once()
 
 	studio := entityStudio.Studio{
-		ID: "1",
+		ID:                "1",
+		TurnaroundMinutes: 15,
 	}
 	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, request.StudioID, ginCtx).Return(studio, nil).Once()
 
@@ -93,6 +116,9 @@ func (suite *ShowtimeServiceTestSuite) TestCreate_Success() {
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), request.MovieID, result.MovieID)
 	assert.Equal(suite.T(), request.StudioID, result.StudioID)
+	assert.Equal(suite.T(), showtime.ShowEnd, result.ShowEnd)
+	assert.Equal(suite.T(), 15, result.TurnaroundMinutes)
+	assert.Equal(suite.T(), time.Date(2024, 7, 9, 12, 15, 0, 0, time.UTC), result.StudioReadyAt)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 	suite.mockRepoMovie.AssertExpectations(suite.T())
@@ -100,6 +126,35 @@ func (suite *ShowtimeServiceTestSuite) TestCreate_Success() {
 	suite.sqlMock.ExpectationsWereMet()
 }
 
+func (suite *ShowtimeServiceTestSuite) TestCreate_WallClockInCinemaTimezone() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.ShowtimeRequest{MovieID: "1", StudioID: "1", ShowStart: "2024-07-09T19:00"}
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepoMovie.On("GetByID", "1").Return(entityMovie.Movie{ID: "1", Duration: 120}, nil).Once()
+	studio := entityStudio.Studio{ID: "1", Timezone: "Asia/Jakarta"}
+	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(studio, nil).Once()
+
+	// 19:00 in Jakarta (UTC+7) is stored as 12:00 UTC.
+	showtime := ShowtimeEntity.Showtime{
+		MovieID:   "1",
+		StudioID:  "1",
+		ShowStart: time.Date(2024, 7, 9, 12, 0, 0, 0, time.UTC),
+		ShowEnd:   time.Date(2024, 7, 9, 14, 0, 0, 0, time.UTC),
+	}
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, studio, showtime, ginCtx).Return(nil).Once()
+	suite.mockRepo.On("Save", ctx, mock.Anything, showtime, ginCtx).Return(showtime, nil).Once()
+	suite.sqlMock.ExpectCommit()
+
+	result, err := suite.service.Create(ctx, request, ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Asia/Jakarta", result.Timezone)
+	assert.Equal(suite.T(), "2024-07-09T19:00:00+07:00", result.ShowStart.Format(time.RFC3339))
+	assert.Equal(suite.T(), "2024-07-09T12:00:00Z", result.ShowStartUTC.Format(time.RFC3339))
+	assert.Equal(suite.T(), "2024-07-09T21:00:00+07:00", result.ShowEnd.Format(time.RFC3339))
+}
+
 func (suite *ShowtimeServiceTestSuite) TestFindByID_Success() {
 	ctx := context.Background()
 	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
@@ -124,22 +179,107 @@ func (suite *ShowtimeServiceTestSuite) TestFindByID_Success() {
 func (suite *ShowtimeServiceTestSuite) TestFindAll_Success() {
 	ctx := context.Background()
 	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.ShowtimeSearchRequest{Page: 1, Size: 10}
 
 	suite.sqlMock.ExpectBegin()
 	showtimes := []ShowtimeEntity.Showtime{
-		{ID: "1", StudioID: "1", MovieID: "1"},
+		{ID: "1", StudioID: "1", MovieID: "1", RemainingSeats: 12},
 	}
-	suite.mockRepo.On("FindAll", ctx, mock.Anything, ginCtx).Return(showtimes, nil).Once()
+	suite.mockRepo.On("FindAll", ctx, mock.Anything, mock.AnythingOfType("entity.ShowtimeFilter"), ginCtx).Return(showtimes, 11, nil).Once()
 	suite.sqlMock.ExpectCommit()
 
-	result, err := suite.service.FindAll(ctx, ginCtx)
+	result, paging, err := suite.service.FindAll(ctx, request, ginCtx)
 	assert.NoError(suite.T(), err)
 	assert.NotEmpty(suite.T(), result)
 	assert.Equal(suite.T(), showtimes[0].ID, result[0].ID)
+	assert.Equal(suite.T(), 12, result[0].RemainingSeats)
+	assert.Equal(suite.T(), dto.Paging{Page: 1, Size: 10, TotalRows: 11, TotalPages: 2}, paging)
 	suite.mockRepo.AssertExpectations(suite.T())
 	suite.sqlMock.ExpectationsWereMet()
 }
 
+func (suite *ShowtimeServiceTestSuite) TestFindAll_InvalidDate() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.ShowtimeSearchRequest{DateFrom: "09-07-2024", Page: 1, Size: 10}
+
+	result, _, err := suite.service.FindAll(ctx, request, ginCtx)
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), result)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestFindAll_InvalidSort() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.ShowtimeSearchRequest{Sort: "id; DROP TABLE showtimes", Page: 1, Size: 10}
+
+	_, _, err := suite.service.FindAll(ctx, request, ginCtx)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestShowtimeFilterFor_UpcomingByDefault() {
+	now := time.Date(2024, 7, 9, 8, 30, 0, 0, time.UTC)
+
+	filter, err := showtimeFilterFor(dto.ShowtimeSearchRequest{Page: 2, Size: 5}, now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), now, filter.From)
+	assert.True(suite.T(), filter.To.IsZero())
+	assert.Equal(suite.T(), 5, filter.Limit)
+	assert.Equal(suite.T(), 5, filter.Offset)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestShowtimeFilterFor_DateRange() {
+	now := time.Date(2024, 7, 9, 8, 30, 0, 0, time.UTC)
+	upcoming := false
+	request := dto.ShowtimeSearchRequest{
+		DateFrom: "2024-07-01",
+		DateTo:   "2024-07-09",
+		GenreID:  "genre-1",
+		City:     "Bandung",
+		Upcoming: &upcoming,
+		Sort:     "price",
+		Order:    "desc",
+		Page:     1,
+		Size:     10,
+	}
+
+	filter, err := showtimeFilterFor(request, now)
+	assert.NoError(suite.T(), err)
+	// Plain dates are whole days in each cinema's timezone.
+	assert.True(suite.T(), filter.From.IsZero())
+	assert.True(suite.T(), filter.To.IsZero())
+	assert.Equal(suite.T(), &ShowtimeEntity.LocalDay{Date: "2024-07-01"}, filter.DayFrom)
+	assert.Equal(suite.T(), &ShowtimeEntity.LocalDay{Date: "2024-07-09"}, filter.DayTo)
+	assert.Equal(suite.T(), "genre-1", filter.GenreID)
+	assert.Equal(suite.T(), "Bandung", filter.City)
+	assert.Equal(suite.T(), ShowtimeEntity.ShowtimeSortPrice, filter.Sort)
+	assert.True(suite.T(), filter.Desc)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestShowtimeFilterFor_Today() {
+	now := time.Date(2024, 7, 9, 20, 30, 0, 0, time.UTC)
+
+	filter, err := showtimeFilterFor(dto.ShowtimeSearchRequest{Date: "today", Page: 1, Size: 10}, now)
+	assert.NoError(suite.T(), err)
+	// "today" is resolved per cinema by the repository, not against UTC here.
+	assert.Equal(suite.T(), &ShowtimeEntity.LocalDay{}, filter.DayFrom)
+	assert.Equal(suite.T(), &ShowtimeEntity.LocalDay{}, filter.DayTo)
+	assert.Equal(suite.T(), now, filter.From)
+
+	filter, err = showtimeFilterFor(dto.ShowtimeSearchRequest{DateFrom: "tomorrow", Page: 1, Size: 10}, now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &ShowtimeEntity.LocalDay{Offset: 1}, filter.DayFrom)
+	assert.Nil(suite.T(), filter.DayTo)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestShowtimeFilterFor_FromAfterTo() {
+	request := dto.ShowtimeSearchRequest{DateFrom: "2024-07-10T00:00:00Z", DateTo: "2024-07-09T00:00:00Z", Page: 1, Size: 10}
+
+	_, err := showtimeFilterFor(request, time.Now())
+	assert.Error(suite.T(), err)
+}
+
 func (suite *ShowtimeServiceTestSuite) TestDelete_Success() {
 	ctx := context.Background()
 	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
@@ -148,12 +288,18 @@ func (suite *ShowtimeServiceTestSuite) TestDelete_Success() {
 	suite.sqlMock.ExpectBegin()
 	showtime := ShowtimeEntity.Showtime{ID: id}
 	suite.mockRepo.On("FindByID", ctx, mock.Anything, id, ginCtx).Return(showtime, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, id, ginCtx).Return([]ShowtimeEntity.BookedSeat{}, nil).Once()
+	suite.mockRepo.On("HasBookings", ctx, mock.Anything, id, ginCtx).Return(false, nil).Once()
 	suite.mockRepo.On("Delete", ctx, mock.Anything, id, ginCtx).Return(nil).Once()
+	suite.mockRepoOutbox.On("Publish", ctx, mock.Anything, webhookEntity.EventShowtimeDeleted, mock.MatchedBy(func(data webhookDto.ShowtimeData) bool {
+		return data.ID == id && data.SeatsFlaggedForRefund == 0
+	}), ginCtx).Return(nil).Once()
 	suite.sqlMock.ExpectCommit()
 
-	err := suite.service.Delete(ctx, id, ginCtx)
+	err := suite.service.Delete(ctx, id, false, ginCtx)
 	assert.NoError(suite.T(), err)
 	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoOutbox.AssertExpectations(suite.T())
 	suite.sqlMock.ExpectationsWereMet()
 }
 
@@ -233,7 +379,7 @@ func (suite *ShowtimeServiceTestSuite) TestFindAll_DBError() {
 
 	suite.sqlMock.ExpectBegin().WillReturnError(errors.New("db error"))
 
-	result, err := suite.service.FindAll(ctx, ginCtx)
+	result, _, err := suite.service.FindAll(ctx, dto.ShowtimeSearchRequest{Page: 1, Size: 10}, ginCtx)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "db error", err.Error())
 	assert.Nil(suite.T(), result)
@@ -249,7 +395,7 @@ func (suite *ShowtimeServiceTestSuite) TestDelete_NotFoundError() {
 	suite.mockRepo.On("FindByID", ctx, mock.Anything, id, ginCtx).Return(ShowtimeEntity.Showtime{}, errors.New("not found")).Once()
 	suite.sqlMock.ExpectRollback()
 
-	err := suite.service.Delete(ctx, id, ginCtx)
+	err := suite.service.Delete(ctx, id, false, ginCtx)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "not found", err.Error())
 	suite.mockRepo.AssertExpectations(suite.T())
@@ -263,16 +409,375 @@ func (suite *ShowtimeServiceTestSuite) TestDelete_DBError() {
 
 	suite.sqlMock.ExpectBegin()
 	suite.mockRepo.On("FindByID", ctx, mock.Anything, id, ginCtx).Return(ShowtimeEntity.Showtime{ID: id}, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, id, ginCtx).Return([]ShowtimeEntity.BookedSeat{}, nil).Once()
+	suite.mockRepo.On("HasBookings", ctx, mock.Anything, id, ginCtx).Return(false, nil).Once()
 	suite.mockRepo.On("Delete", ctx, mock.Anything, id, ginCtx).Return(errors.New("db error")).Once()
 	suite.sqlMock.ExpectRollback()
 
-	err := suite.service.Delete(ctx, id, ginCtx)
+	err := suite.service.Delete(ctx, id, false, ginCtx)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "db error", err.Error())
 	suite.mockRepo.AssertExpectations(suite.T())
 	suite.sqlMock.ExpectationsWereMet()
 }
 
+func (suite *ShowtimeServiceTestSuite) TestSchedule_CreatesAndReportsConflicts() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.ScheduleRequest{
+		MovieID:    "1",
+		StudioIDs:  []string{"1"},
+		DateFrom:   "2024-07-08",
+		DateTo:     "2024-07-14",
+		Weekdays:   []string{"saturday", "sunday"},
+		StartTimes: []string{"19:00", "13:00"},
+	}
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepoMovie.On("GetByID", "1").Return(entityMovie.Movie{ID: "1", Duration: 120}, nil).Once()
+	studio := entityStudio.Studio{ID: "1", TurnaroundMinutes: 15}
+	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(studio, nil).Once()
+
+	saturdayEvening := time.Date(2024, 7, 13, 19, 0, 0, 0, time.UTC)
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, studio, mock.MatchedBy(func(showtime ShowtimeEntity.Showtime) bool {
+		return showtime.ShowStart.Equal(saturdayEvening)
+	}), ginCtx).Return(showTimeRepo.ErrConflictingShowtimes).Once()
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, studio, mock.Anything, ginCtx).Return(nil).Times(3)
+	suite.mockRepo.On("Save", ctx, mock.Anything, mock.Anything, ginCtx).Return(ShowtimeEntity.Showtime{ID: "new"}, nil).Times(3)
+	suite.sqlMock.ExpectCommit()
+
+	result, err := suite.service.Schedule(ctx, request, ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, result.Created)
+	assert.Equal(suite.T(), 1, result.Conflicts)
+	assert.Len(suite.T(), result.Slots, 4)
+	assert.Equal(suite.T(), time.Date(2024, 7, 13, 13, 0, 0, 0, time.UTC), result.Slots[0].ShowStart)
+	assert.Equal(suite.T(), time.Date(2024, 7, 13, 15, 15, 0, 0, time.UTC), result.Slots[0].StudioReadyAt)
+	assert.Equal(suite.T(), dto.ScheduleSlotCreated, result.Slots[0].Status)
+	assert.Equal(suite.T(), dto.ScheduleSlotConflict, result.Slots[1].Status)
+	assert.Empty(suite.T(), result.Slots[1].ShowtimeID)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ShowtimeServiceTestSuite) TestSchedule_DryRunDoesNotSave() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.ScheduleRequest{
+		MovieID:    "1",
+		StudioIDs:  []string{"1"},
+		DateFrom:   "2024-07-13",
+		DateTo:     "2024-07-13",
+		StartTimes: []string{"10:00", "12:00"},
+		DryRun:     true,
+	}
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepoMovie.On("GetByID", "1").Return(entityMovie.Movie{ID: "1", Duration: 120}, nil).Once()
+	studio := entityStudio.Studio{ID: "1", TurnaroundMinutes: 15}
+	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(studio, nil).Once()
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, studio, mock.Anything, ginCtx).Return(nil).Once()
+	suite.sqlMock.ExpectCommit()
+
+	result, err := suite.service.Schedule(ctx, request, ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.DryRun)
+	assert.Equal(suite.T(), 0, result.Created)
+	// The 12:00 slot starts before the 10:00 screening's turnaround is over.
+	assert.Equal(suite.T(), 1, result.Conflicts)
+	assert.Equal(suite.T(), dto.ScheduleSlotPlanned, result.Slots[0].Status)
+	assert.Equal(suite.T(), dto.ScheduleSlotConflict, result.Slots[1].Status)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestSchedule_InvalidRange() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.ScheduleRequest{
+		MovieID:    "1",
+		StudioIDs:  []string{"1"},
+		DateFrom:   "2024-07-14",
+		DateTo:     "2024-07-13",
+		StartTimes: []string{"10:00"},
+	}
+
+	_, err := suite.service.Schedule(ctx, request, ginCtx)
+	assert.Error(suite.T(), err)
+	suite.mockRepoMovie.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestScheduleStarts_Weekdays() {
+	starts, err := scheduleStarts(dto.ScheduleRequest{
+		DateFrom:   "2024-07-08",
+		DateTo:     "2024-07-21",
+		Weekdays:   []string{"friday"},
+		StartTimes: []string{"21:30"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []time.Time{
+		time.Date(2024, 7, 12, 21, 30, 0, 0, time.UTC),
+		time.Date(2024, 7, 19, 21, 30, 0, 0, time.UTC),
+	}, starts)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestDelete_SoldTicketsBlocked() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	id := "1"
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, id, ginCtx).Return(ShowtimeEntity.Showtime{ID: id}, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, id, ginCtx).Return([]ShowtimeEntity.BookedSeat{
+		{DetailID: "d1", SeatBookingID: "b1", SeatBookingStatus: "success", SeatID: "s1", SeatName: "A-1"},
+	}, nil).Once()
+	suite.sqlMock.ExpectRollback()
+
+	err := suite.service.Delete(ctx, id, false, ginCtx)
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "1 sold tickets")
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Cancel", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestDelete_ForceCancelsAndFlagsRefunds() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	id := "1"
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, id, ginCtx).Return(ShowtimeEntity.Showtime{ID: id}, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, id, ginCtx).Return([]ShowtimeEntity.BookedSeat{
+		{DetailID: "d1", SeatBookingID: "b1", SeatBookingStatus: "success", SeatID: "s1", SeatName: "A-1"},
+		{DetailID: "d2", SeatBookingID: "b2", SeatBookingStatus: "pending", UserID: "u2", SeatID: "s2", SeatName: "A-2", UnitPrice: 50000},
+	}, nil).Once()
+	flag := ShowtimeEntity.RefundFlag{ShowtimeID: id, SeatBookingID: "b1", SeatID: "s1", Reason: "showtime cancelled"}
+	suite.mockRepo.On("FlagForRefund", ctx, mock.Anything, flag, ginCtx).Return(flag, nil).Once()
+	suite.mockRepoTicket.On("VoidByShowtime", ctx, mock.Anything, id, []string(nil), ginCtx).Return(nil).Once()
+	suite.mockRepo.On("Cancel", ctx, mock.Anything, id, ginCtx).Return([]entityPayment.FailedPayment{
+		{ID: "p2", UserID: "u2", SeatBookingID: "b2", PreviousStatus: "pending", TotalPrice: 50000},
+	}, nil).Once()
+	suite.mockRepoOutbox.On("Publish", ctx, mock.Anything, webhookEntity.EventPaymentFailed, webhookDto.PaymentData{
+		ID: "p2", UserID: "u2", SeatBookingID: "b2", Status: "failed", PreviousStatus: "pending", TotalPrice: 50000,
+	}, ginCtx).Return(nil).Once()
+	suite.mockRepoOutbox.On("Publish", ctx, mock.Anything, webhookEntity.EventSeatBookingCancelled, webhookDto.SeatBookingData{
+		ID: "b2", UserID: "u2", ShowtimeID: id, SeatIDs: []string{"s2"}, TotalPrice: 50000,
+	}, ginCtx).Return(nil).Once()
+	suite.mockRepoNotification.On("Queue", ctx, mock.Anything, mock.MatchedBy(func(notification notificationEntity.Notification) bool {
+		return notification.Kind == notificationEntity.KindShowtimeCancelled && notification.Data.SeatBookingID == "b1" && len(notification.Data.Seats) == 1
+	}), ginCtx).Return(nil).Once()
+	suite.mockRepoOutbox.On("Publish", ctx, mock.Anything, webhookEntity.EventShowtimeCancelled, mock.MatchedBy(func(data webhookDto.ShowtimeData) bool {
+		return data.ID == id && data.SeatsFlaggedForRefund == 1
+	}), ginCtx).Return(nil).Once()
+	suite.sqlMock.ExpectCommit()
+
+	err := suite.service.Delete(ctx, id, true, ginCtx)
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoOutbox.AssertExpectations(suite.T())
+	suite.mockRepoTicket.AssertExpectations(suite.T())
+	suite.mockRepoNotification.AssertExpectations(suite.T())
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestDelete_PastBookingsCancels() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	id := "1"
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, id, ginCtx).Return(ShowtimeEntity.Showtime{ID: id}, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, id, ginCtx).Return([]ShowtimeEntity.BookedSeat{}, nil).Once()
+	suite.mockRepo.On("HasBookings", ctx, mock.Anything, id, ginCtx).Return(true, nil).Once()
+	suite.mockRepoTicket.On("VoidByShowtime", ctx, mock.Anything, id, []string(nil), ginCtx).Return(nil).Once()
+	suite.mockRepo.On("Cancel", ctx, mock.Anything, id, ginCtx).Return([]entityPayment.FailedPayment{}, nil).Once()
+	suite.mockRepoOutbox.On("Publish", ctx, mock.Anything, webhookEntity.EventShowtimeCancelled, mock.MatchedBy(func(data webhookDto.ShowtimeData) bool {
+		return data.ID == id && data.SeatsFlaggedForRefund == 0
+	}), ginCtx).Return(nil).Once()
+	suite.sqlMock.ExpectCommit()
+
+	err := suite.service.Delete(ctx, id, false, ginCtx)
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepoNotification.AssertNotCalled(suite.T(), "Queue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestUpdate_MovesStudioAndMigratesSeats() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Minute)
+	request := dto.UpdateShowtimeRequest{ID: "1", StudioID: "2"}
+
+	current := ShowtimeEntity.Showtime{ID: "1", StudioID: "1", MovieID: "1", ShowStart: start, ShowEnd: start.Add(2 * time.Hour), MovieDuration: 120}
+	studio := entityStudio.Studio{ID: "2", Name: "Studio 2", TurnaroundMinutes: 10}
+	moved := current
+	moved.StudioID = "2"
+	moved.StudioName = "Studio 2"
+	moved.TurnaroundMinutes = 10
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(current, nil).Once()
+	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, "2", ginCtx).Return(studio, nil).Once()
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, studio, moved, ginCtx).Return(nil).Once()
+	suite.mockRepo.On("Update", ctx, mock.Anything, moved, ginCtx).Return(moved, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, "1", ginCtx).Return([]ShowtimeEntity.BookedSeat{
+		{DetailID: "d1", SeatBookingID: "b1", SeatBookingStatus: "success", UserID: "u1", SeatID: "old-a1", SeatName: "A-1"},
+		{DetailID: "d2", SeatBookingID: "b1", SeatBookingStatus: "success", UserID: "u1", SeatID: "old-z9", SeatName: "Z-9"},
+		{DetailID: "d3", SeatBookingID: "b2", SeatBookingStatus: "pending", UserID: "u2", SeatID: "old-a1b", SeatName: "A-1"},
+	}, nil).Once()
+	suite.mockRepoSeat.On("FindAllByShowtime", ctx, "1", mock.Anything, ginCtx).Return([]seatEntity.Seat{
+		{ID: "new-a1", Name: "A-1", IsAvailable: true, StudioID: "2"},
+		{ID: "new-a2", Name: "A-2", IsAvailable: true, StudioID: "2"},
+	}, nil).Once()
+	suite.mockRepo.On("MoveBookedSeat", ctx, mock.Anything, "d1", "new-a1", ginCtx).Return(nil).Once()
+	suite.mockRepoTicket.On("Move", ctx, mock.Anything, "1", "old-a1", "new-a1", ginCtx).Return(nil).Once()
+	flag := ShowtimeEntity.RefundFlag{ShowtimeID: "1", SeatBookingID: "b1", SeatID: "old-z9", Reason: "no equivalent seat after reschedule"}
+	suite.mockRepo.On("FlagForRefund", ctx, mock.Anything, flag, ginCtx).Return(flag, nil).Once()
+	suite.mockRepoTicket.On("VoidByShowtime", ctx, mock.Anything, "1", []string{"old-z9"}, ginCtx).Return(nil).Once()
+	suite.mockRepo.On("ReleaseBookedSeat", ctx, mock.Anything, "d3", ginCtx).Return(nil).Once()
+	suite.mockRepoNotification.On("Queue", ctx, mock.Anything, notificationEntity.Notification{
+		UserID: "u1",
+		Kind:   notificationEntity.KindShowtimeRescheduled,
+		Data: notificationEntity.Data{
+			SeatBookingID: "b1",
+			StudioName:    "Studio 2",
+			ShowStart:     start,
+			Seats:         []string{"A-1"},
+		},
+	}, ginCtx).Return(nil).Once()
+	suite.sqlMock.ExpectCommit()
+
+	result, err := suite.service.Update(ctx, request, ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2", result.Showtime.StudioID)
+	assert.Equal(suite.T(), []dto.SeatMove{{SeatBookingID: "b1", FromSeatID: "old-a1", FromSeatName: "A-1", ToSeatID: "new-a1", ToSeatName: "A-1"}}, result.MovedSeats)
+	assert.Equal(suite.T(), []dto.AffectedSeat{{SeatBookingID: "b1", UserID: "u1", SeatID: "old-z9", SeatName: "Z-9"}}, result.RefundSeats)
+	assert.Equal(suite.T(), []dto.AffectedSeat{{SeatBookingID: "b2", UserID: "u2", SeatID: "old-a1b", SeatName: "A-1"}}, result.ReleasedSeats)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepoSeat.AssertExpectations(suite.T())
+	suite.mockRepoTicket.AssertExpectations(suite.T())
+	suite.mockRepoTicket.AssertNotCalled(suite.T(), "Move", mock.Anything, mock.Anything, "1", "old-a1b", mock.Anything, mock.Anything)
+	suite.mockRepoNotification.AssertExpectations(suite.T())
+}
+
+func (suite *ShowtimeServiceTestSuite) TestUpdate_MigrationKeepsSeatCategory() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Minute)
+	request := dto.UpdateShowtimeRequest{ID: "1", StudioID: "2"}
+
+	current := ShowtimeEntity.Showtime{ID: "1", StudioID: "1", MovieID: "1", ShowStart: start, ShowEnd: start.Add(2 * time.Hour), MovieDuration: 120}
+	studio := entityStudio.Studio{ID: "2", Name: "Studio 2"}
+	moved := current
+	moved.StudioID = "2"
+	moved.StudioName = "Studio 2"
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(current, nil).Once()
+	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, "2", ginCtx).Return(studio, nil).Once()
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, studio, moved, ginCtx).Return(nil).Once()
+	suite.mockRepo.On("Update", ctx, mock.Anything, moved, ginCtx).Return(moved, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, "1", ginCtx).Return([]ShowtimeEntity.BookedSeat{
+		{DetailID: "d1", SeatBookingID: "b1", SeatBookingStatus: "success", UserID: "u1", SeatID: "old-a1", SeatName: "A-1", SeatCategory: "regular"},
+	}, nil).Once()
+	suite.mockRepoSeat.On("FindAllByShowtime", ctx, "1", mock.Anything, ginCtx).Return([]seatEntity.Seat{
+		{ID: "new-a1", Name: "A-1", IsAvailable: true, StudioID: "2", Category: "vip"},
+	}, nil).Once()
+	flag := ShowtimeEntity.RefundFlag{ShowtimeID: "1", SeatBookingID: "b1", SeatID: "old-a1", Reason: "no equivalent seat after reschedule"}
+	suite.mockRepo.On("FlagForRefund", ctx, mock.Anything, flag, ginCtx).Return(flag, nil).Once()
+	suite.mockRepoTicket.On("VoidByShowtime", ctx, mock.Anything, "1", []string{"old-a1"}, ginCtx).Return(nil).Once()
+	suite.mockRepoNotification.On("Queue", ctx, mock.Anything, mock.MatchedBy(func(notification notificationEntity.Notification) bool {
+		return notification.Data.SeatBookingID == "b1" && len(notification.Data.Seats) == 0
+	}), ginCtx).Return(nil).Once()
+	suite.sqlMock.ExpectCommit()
+
+	result, err := suite.service.Update(ctx, request, ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), result.MovedSeats)
+	assert.Len(suite.T(), result.RefundSeats, 1)
+	suite.mockRepo.AssertNotCalled(suite.T(), "MoveBookedSeat", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestUpdate_TimeOnlyKeepsSeats() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Minute)
+	newStart := start.Add(3 * time.Hour)
+	request := dto.UpdateShowtimeRequest{ID: "1", ShowStart: newStart.Format(time.RFC3339)}
+
+	current := ShowtimeEntity.Showtime{ID: "1", StudioID: "1", ShowStart: start, ShowEnd: start.Add(90 * time.Minute), MovieDuration: 90}
+	studio := entityStudio.Studio{ID: "1", TurnaroundMinutes: 15}
+	moved := current
+	moved.ShowStart = newStart
+	moved.ShowEnd = newStart.Add(90 * time.Minute)
+	moved.TurnaroundMinutes = 15
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(current, nil).Once()
+	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(studio, nil).Once()
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, studio, moved, ginCtx).Return(nil).Once()
+	suite.mockRepo.On("Update", ctx, mock.Anything, moved, ginCtx).Return(moved, nil).Once()
+	suite.mockRepo.On("FindBookedSeats", ctx, mock.Anything, "1", ginCtx).Return([]ShowtimeEntity.BookedSeat{
+		{DetailID: "d1", SeatBookingID: "b1", SeatBookingStatus: "success", UserID: "u1", SeatID: "a1", SeatName: "A-1"},
+	}, nil).Once()
+	suite.mockRepoNotification.On("Queue", ctx, mock.Anything, mock.MatchedBy(func(notification notificationEntity.Notification) bool {
+		return notification.Kind == notificationEntity.KindShowtimeRescheduled && notification.Data.ShowStart.Equal(newStart) && len(notification.Data.Seats) == 1
+	}), ginCtx).Return(nil).Once()
+	suite.sqlMock.ExpectCommit()
+
+	result, err := suite.service.Update(ctx, request, ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), newStart, result.Showtime.ShowStart)
+	assert.Equal(suite.T(), newStart.Add(105*time.Minute), result.Showtime.StudioReadyAt)
+	assert.Empty(suite.T(), result.MovedSeats)
+	suite.mockRepo.AssertNotCalled(suite.T(), "MoveBookedSeat", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRepoNotification.AssertExpectations(suite.T())
+}
+
+func (suite *ShowtimeServiceTestSuite) TestUpdate_Conflict() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	start := time.Now().UTC().Add(48 * time.Hour)
+	request := dto.UpdateShowtimeRequest{ID: "1", StudioID: "2"}
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(ShowtimeEntity.Showtime{ID: "1", StudioID: "1", ShowStart: start}, nil).Once()
+	suite.mockRepoStudio.On("FindByID", ctx, mock.Anything, "2", ginCtx).Return(entityStudio.Studio{ID: "2"}, nil).Once()
+	suite.mockRepo.On("FindConflictingShowtimes", ctx, mock.Anything, mock.Anything, mock.Anything, ginCtx).Return(showTimeRepo.ErrConflictingShowtimes).Once()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.service.Update(ctx, request, ginCtx)
+	assert.ErrorIs(suite.T(), err, showTimeRepo.ErrConflictingShowtimes)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ShowtimeServiceTestSuite) TestUpdate_AlreadyStarted() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	request := dto.UpdateShowtimeRequest{ID: "1", StudioID: "2"}
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(ShowtimeEntity.Showtime{ID: "1", ShowStart: time.Now().Add(-time.Minute)}, nil).Once()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.service.Update(ctx, request, ginCtx)
+	assert.EqualError(suite.T(), err, "showtime has already started")
+}
+
+func (suite *ShowtimeServiceTestSuite) TestDelete_OtherCinemaForbidden() {
+	ctx := context.Background()
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Set("role", "branch admin")
+	ginCtx.Set("cinema_id", "cinema-2")
+
+	suite.sqlMock.ExpectBegin()
+	suite.mockRepo.On("FindByID", ctx, mock.Anything, "1", ginCtx).Return(ShowtimeEntity.Showtime{ID: "1", CinemaID: "cinema-1"}, nil).Once()
+	suite.sqlMock.ExpectRollback()
+
+	err := suite.service.Delete(ctx, "1", false, ginCtx)
+	assert.EqualError(suite.T(), err, "you can only manage showtimes of your own cinema")
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindBookedSeats", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestShowtimeServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(ShowtimeServiceTestSuite))
 }
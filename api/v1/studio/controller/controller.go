@@ -9,5 +9,6 @@ type StudioController interface {
 	FindById(c *gin.Context)
 	FindAll(c *gin.Context)
 	Update(c *gin.Context)
+	UpdateLayout(c *gin.Context)
 	Delete(c *gin.Context)
 }
@@ -4,6 +4,7 @@ import (
 	"bioskuy/api/v1/studio/dto"
 	"bioskuy/api/v1/studio/service"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"bioskuy/web"
 	"net/http"
 
@@ -63,15 +64,22 @@ func (controller *studioControllerImpl) FindById(c *gin.Context){
 func (controller *studioControllerImpl) FindAll(c *gin.Context) {
     ctx := c.Request.Context()
 
-    result, err := controller.studioService.FindAll(ctx, c)
+    query, err := helper.ListQueryFrom(c, "cinema_id", "class")
+    if err != nil {
+        c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return
+    }
+
+    result, paging, err := controller.studioService.FindAll(ctx, query, c)
     if err != nil {
         c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return
     }
 
-    response := web.FormatResponse{
+    response := web.FormatResponsePaging{
         ResponseCode: http.StatusOK,
         Data:    result,
+        Paging:   paging,
     }
 
     c.JSON(http.StatusOK, response)
@@ -105,6 +113,31 @@ func (ctl *studioControllerImpl) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (ctl *studioControllerImpl) UpdateLayout(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.UpdateLayoutRequest{}
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	request.ID = c.Param("studioId")
+
+	result, err := ctl.studioService.UpdateLayout(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	response := web.FormatResponse{
+		ResponseCode: http.StatusOK,
+		Data:         result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (ctl *studioControllerImpl) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
 	response := web.FormatResponse{}
@@ -139,7 +139,7 @@ func (suite *StudioControllerTestSuite) TestFindAll_Success() {
 		{ID: "id2", Name: "Studio 2"},
 	}
 
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(studioResponses, nil)
+	suite.mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(studioResponses, web.Paging{Page: 1, Size: 10, TotalData: 2, TotalPages: 1}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/studios", nil)
 	w := httptest.NewRecorder()
@@ -156,7 +156,7 @@ func (suite *StudioControllerTestSuite) TestFindAll_Success() {
 func (suite *StudioControllerTestSuite) TestFindAll_ServiceError() {
 	serviceError := exception.InternalServerError{Message: "internal error"}
 
-	suite.mockService.On("FindAll", mock.Anything, mock.Anything).Return(nil, serviceError)
+	suite.mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(nil, web.Paging{}, serviceError)
 
 	req := httptest.NewRequest(http.MethodGet, "/studios", nil)
 	w := httptest.NewRecorder()
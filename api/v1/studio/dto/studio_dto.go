@@ -1,18 +1,44 @@
 package dto
 
+import "bioskuy/api/v1/studio/entity"
+
 type CreateStudioRequest struct {
-	Name       string `json:"name" validate:"required"`
-	Capacity   int    `json:"capacity" validate:"required"`
-	MaxRowSeat int    `json:"max-row-seat" validate:"required"`
+	Name string `json:"name" validate:"required"`
+	// Capacity and MaxRowSeat lay the studio out as a plain grid when no
+	// Layout is given.
+	Capacity   int `json:"capacity" validate:"required_without=Layout"`
+	MaxRowSeat int `json:"max-row-seat" validate:"required_without=Layout"`
+	// TurnaroundMinutes defaults to entity.DefaultTurnaroundMinutes when omitted.
+	TurnaroundMinutes *int           `json:"turnaround_minutes" validate:"omitempty,min=0"`
+	Class             string         `json:"class"`
+	Layout            *entity.Layout `json:"layout"`
+	CinemaID          string         `json:"cinema_id" validate:"required,uuid"`
 }
 
 type UpdateStudioRequest struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	TurnaroundMinutes *int   `json:"turnaround_minutes" validate:"omitempty,min=0"`
+	Class             string `json:"class"`
+	// Capacity re-lays the studio out as a plain grid of MaxRowSeat seats per
+	// row, adding or retiring seats. MaxRowSeat defaults to the width of the
+	// current first row.
+	Capacity   int `json:"capacity" validate:"omitempty,min=1"`
+	MaxRowSeat int `json:"max-row-seat" validate:"omitempty,min=1"`
+}
+
+type UpdateLayoutRequest struct {
+	ID     string        `json:"id"`
+	Layout entity.Layout `json:"layout"`
 }
 
 type StudioResponse struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Capacity int    `json:"capacity"`
-}
\ No newline at end of file
+	ID                string         `json:"id"`
+	Name              string         `json:"name"`
+	Capacity          int            `json:"capacity"`
+	TurnaroundMinutes int            `json:"turnaround_minutes"`
+	Class             string         `json:"class"`
+	Layout            *entity.Layout `json:"layout,omitempty"`
+	CinemaID          string         `json:"cinema_id"`
+	Timezone          string         `json:"timezone"`
+}
@@ -0,0 +1,242 @@
+package entity
+
+import (
+	eS "bioskuy/api/v1/seat/entity"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Layout cell types. Seat, wheelchair and couple cells are bookable; aisles
+// and gaps only take up room on the seat map.
+const (
+	LayoutCellSeat       = "seat"
+	LayoutCellWheelchair = "wheelchair"
+	LayoutCellCouple     = "couple"
+	LayoutCellAisle      = "aisle"
+	LayoutCellGap        = "gap"
+)
+
+const (
+	MaxLayoutRows    = 100
+	MaxLayoutColumns = 100
+)
+
+// Layout is the seat grid of a studio, front row first. It is stored as JSON
+// on the studio and drives which seats exist and how the seat map is drawn.
+type Layout struct {
+	Rows []LayoutRow `json:"rows"`
+}
+
+type LayoutRow struct {
+	// Label names the row's seats ("A" gives A-1, A-2, ...). Rows without a
+	// label are lettered by position: A..Z, AA, AB, ...
+	Label string `json:"label,omitempty"`
+	// Category applies to every seat in the row that does not set its own.
+	Category string       `json:"category,omitempty"`
+	Cells    []LayoutCell `json:"cells"`
+}
+
+// LayoutCell is written either as an object or as just its type:
+// "seat" is the same as {"type": "seat"}.
+type LayoutCell struct {
+	Type     string `json:"type"`
+	Category string `json:"category,omitempty"`
+}
+
+func (cell *LayoutCell) UnmarshalJSON(data []byte) error {
+	var cellType string
+	if err := json.Unmarshal(data, &cellType); err == nil {
+		*cell = LayoutCell{Type: cellType}
+		return nil
+	}
+
+	type plain LayoutCell
+	return json.Unmarshal(data, (*plain)(cell))
+}
+
+// Bookable reports whether the cell becomes a seat.
+func (cell LayoutCell) Bookable() bool {
+	return cell.Type == LayoutCellSeat || cell.Type == LayoutCellWheelchair || cell.Type == LayoutCellCouple
+}
+
+// Width is the number of grid columns the cell spans. Couple seats are sold
+// as one seat but take the room of two.
+func (cell LayoutCell) Width() int {
+	if cell.Type == LayoutCellCouple {
+		return 2
+	}
+	return 1
+}
+
+// LayoutSeat is one bookable seat described by a layout.
+type LayoutSeat struct {
+	Name     string
+	Row      int
+	Col      int
+	Kind     string
+	Category string
+}
+
+// RowLabel letters rows like spreadsheet columns: 0 is A, 25 is Z, 26 is AA.
+func RowLabel(row int) string {
+	label := ""
+	for row >= 0 {
+		label = string(rune('A'+row%26)) + label
+		row = row/26 - 1
+	}
+	return label
+}
+
+// GridLayout is the plain layout of a studio created from a capacity and a
+// seats-per-row count: full rows of regular seats with a shorter last row.
+func GridLayout(capacity int, maxRowSeat int) Layout {
+	layout := Layout{}
+	for capacity > 0 {
+		cells := []LayoutCell{}
+		for i := 0; i < maxRowSeat && capacity > 0; i++ {
+			cells = append(cells, LayoutCell{Type: LayoutCellSeat})
+			capacity--
+		}
+		layout.Rows = append(layout.Rows, LayoutRow{Cells: cells})
+	}
+	return layout
+}
+
+// IsGrid reports whether the layout is one GridLayout would build: unlabelled,
+// uncategorised rows of regular seats, all as wide as the first but the last.
+func (l Layout) IsGrid() bool {
+	if len(l.Rows) == 0 {
+		return false
+	}
+
+	width := len(l.Rows[0].Cells)
+	for i, row := range l.Rows {
+		if row.Label != "" || row.Category != "" || len(row.Cells) == 0 || len(row.Cells) > width {
+			return false
+		}
+		if i < len(l.Rows)-1 && len(row.Cells) != width {
+			return false
+		}
+		for _, cell := range row.Cells {
+			if cell != (LayoutCell{Type: LayoutCellSeat}) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Validate checks the layout can be turned into uniquely named seats.
+func (l Layout) Validate() error {
+	if len(l.Rows) == 0 {
+		return errors.New("layout must have at least one row")
+	}
+	if len(l.Rows) > MaxLayoutRows {
+		return fmt.Errorf("layout has %d rows, the maximum is %d", len(l.Rows), MaxLayoutRows)
+	}
+
+	labels := map[string]bool{}
+	seats := 0
+	for i, row := range l.Rows {
+		label := l.Label(i)
+		if strings.ContainsAny(label, "- ") {
+			return fmt.Errorf("row label %q may not contain spaces or dashes", label)
+		}
+		if labels[label] {
+			return fmt.Errorf("row label %q is used more than once", label)
+		}
+		labels[label] = true
+
+		if row.Category != "" && !validCategory(row.Category) {
+			return fmt.Errorf("row %s: unknown seat category %q", label, row.Category)
+		}
+
+		width := 0
+		for _, cell := range row.Cells {
+			switch cell.Type {
+			case LayoutCellSeat, LayoutCellWheelchair, LayoutCellCouple:
+				seats++
+			case LayoutCellAisle, LayoutCellGap:
+			default:
+				return fmt.Errorf("row %s: unknown cell type %q", label, cell.Type)
+			}
+			if cell.Category != "" && !validCategory(cell.Category) {
+				return fmt.Errorf("row %s: unknown seat category %q", label, cell.Category)
+			}
+			width += cell.Width()
+		}
+		if width > MaxLayoutColumns {
+			return fmt.Errorf("row %s is %d columns wide, the maximum is %d", label, width, MaxLayoutColumns)
+		}
+	}
+
+	if seats == 0 {
+		return errors.New("layout must have at least one seat")
+	}
+
+	return nil
+}
+
+// Seats lists the bookable seats of the layout, numbered from 1 per row left
+// to right, skipping aisles and gaps.
+func (l Layout) Seats() []LayoutSeat {
+	seats := []LayoutSeat{}
+	for i, row := range l.Rows {
+		label := l.Label(i)
+		number, col := 0, 0
+		for _, cell := range row.Cells {
+			if cell.Bookable() {
+				number++
+				seats = append(seats, LayoutSeat{
+					Name:     fmt.Sprintf("%s-%d", label, number),
+					Row:      i,
+					Col:      col,
+					Kind:     seatKind(cell.Type),
+					Category: firstNonEmpty(cell.Category, row.Category, eS.SeatCategoryRegular),
+				})
+			}
+			col += cell.Width()
+		}
+	}
+	return seats
+}
+
+// Label is the label of row i, defaulted from its position.
+func (l Layout) Label(i int) string {
+	if l.Rows[i].Label != "" {
+		return l.Rows[i].Label
+	}
+	return RowLabel(i)
+}
+
+func seatKind(cellType string) string {
+	switch cellType {
+	case LayoutCellWheelchair:
+		return eS.SeatKindWheelchair
+	case LayoutCellCouple:
+		return eS.SeatKindCouple
+	default:
+		return eS.SeatKindStandard
+	}
+}
+
+func validCategory(category string) bool {
+	for _, known := range eS.SeatCategories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
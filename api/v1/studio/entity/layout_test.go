@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowLabel(t *testing.T) {
+	assert.Equal(t, "A", RowLabel(0))
+	assert.Equal(t, "Z", RowLabel(25))
+	assert.Equal(t, "AA", RowLabel(26))
+	assert.Equal(t, "AB", RowLabel(27))
+	assert.Equal(t, "BA", RowLabel(52))
+}
+
+func TestGridLayout(t *testing.T) {
+	layout := GridLayout(25, 10)
+
+	assert.Len(t, layout.Rows, 3)
+	assert.Len(t, layout.Rows[2].Cells, 5)
+	assert.NoError(t, layout.Validate())
+	assert.Len(t, layout.Seats(), 25)
+}
+
+func TestLayout_UnmarshalShorthand(t *testing.T) {
+	var layout Layout
+	err := json.Unmarshal([]byte(`{"rows":[{"cells":["seat","aisle",{"type":"couple","category":"vip"}]}]}`), &layout)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []LayoutCell{{Type: "seat"}, {Type: "aisle"}, {Type: "couple", Category: "vip"}}, layout.Rows[0].Cells)
+}
+
+func TestLayout_Seats(t *testing.T) {
+	layout := Layout{Rows: []LayoutRow{
+		{Category: "premium", Cells: []LayoutCell{{Type: "seat"}, {Type: "aisle"}, {Type: "seat", Category: "vip"}}},
+		{Cells: []LayoutCell{{Type: "gap"}, {Type: "couple"}, {Type: "wheelchair"}}},
+	}}
+
+	assert.Equal(t, []LayoutSeat{
+		{Name: "A-1", Row: 0, Col: 0, Kind: "standard", Category: "premium"},
+		{Name: "A-2", Row: 0, Col: 2, Kind: "standard", Category: "vip"},
+		{Name: "B-1", Row: 1, Col: 1, Kind: "couple", Category: "regular"},
+		{Name: "B-2", Row: 1, Col: 3, Kind: "wheelchair", Category: "regular"},
+	}, layout.Seats())
+}
+
+func TestLayout_Validate(t *testing.T) {
+	seat := []LayoutCell{{Type: "seat"}}
+
+	tooWide := make([]LayoutCell, MaxLayoutColumns+1)
+	for i := range tooWide {
+		tooWide[i] = LayoutCell{Type: "seat"}
+	}
+
+	tests := []struct {
+		name    string
+		layout  Layout
+		valid   bool
+		message string
+	}{
+		{"valid", Layout{Rows: []LayoutRow{{Cells: seat}}}, true, ""},
+		{"no rows", Layout{}, false, ""},
+		{"no seats", Layout{Rows: []LayoutRow{{Cells: []LayoutCell{{Type: "aisle"}}}}}, false, ""},
+		{"unknown cell", Layout{Rows: []LayoutRow{{Cells: []LayoutCell{{Type: "sofa"}}}}}, false, ""},
+		{"unknown category", Layout{Rows: []LayoutRow{{Category: "gold", Cells: seat}}}, false, ""},
+		{"dash in label", Layout{Rows: []LayoutRow{{Label: "A-B", Cells: seat}}}, false, ""},
+		{"duplicate label", Layout{Rows: []LayoutRow{{Cells: seat}, {Label: "A", Cells: seat}}}, false, ""},
+		{"too wide", Layout{Rows: []LayoutRow{{Cells: tooWide}}}, false, "row A is 101 columns wide, the maximum is 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.layout.Validate()
+			assert.Equal(t, tt.valid, err == nil, err)
+			if tt.message != "" {
+				assert.EqualError(t, err, tt.message)
+			}
+		})
+	}
+}
+
+func TestLayout_IsGrid(t *testing.T) {
+	seat := LayoutCell{Type: "seat"}
+
+	tests := []struct {
+		name   string
+		layout Layout
+		grid   bool
+	}{
+		{"grid", GridLayout(5, 2), true},
+		{"full rows", GridLayout(4, 2), true},
+		{"no rows", Layout{}, false},
+		{"short middle row", Layout{Rows: []LayoutRow{{Cells: []LayoutCell{seat, seat}}, {Cells: []LayoutCell{seat}}, {Cells: []LayoutCell{seat, seat}}}}, false},
+		{"aisle", Layout{Rows: []LayoutRow{{Cells: []LayoutCell{seat, {Type: "aisle"}, seat}}}}, false},
+		{"row category", Layout{Rows: []LayoutRow{{Category: "vip", Cells: []LayoutCell{seat}}}}, false},
+		{"row label", Layout{Rows: []LayoutRow{{Label: "K", Cells: []LayoutCell{seat}}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.grid, tt.layout.IsGrid())
+		})
+	}
+}
@@ -1,7 +1,28 @@
 package entity
 
+import "time"
+
+// DefaultTurnaroundMinutes is the buffer a new studio gets between screenings.
+const DefaultTurnaroundMinutes = 15
+
+// DefaultClass is the studio class pricing rules see when none is given.
+const DefaultClass = "regular"
+
 type Studio struct {
-	ID       string `json:"id" `
-	Name     string `json:"name"`
-	Capacity int    `json:"capacity"`
-}
\ No newline at end of file
+	ID                string `json:"id" `
+	Name              string `json:"name"`
+	Capacity          int    `json:"capacity"`
+	TurnaroundMinutes int    `json:"turnaround_minutes"`
+	Class             string `json:"class"`
+	// Layout is nil for studios created before layouts existed.
+	Layout   *Layout `json:"layout"`
+	CinemaID string  `json:"cinema_id"`
+	// Timezone is the IANA timezone of the studio's cinema.
+	Timezone string `json:"timezone"`
+}
+
+// Turnaround is the time the studio needs after a screening before the next one
+// can start.
+func (s Studio) Turnaround() time.Duration {
+	return time.Duration(s.TurnaroundMinutes) * time.Minute
+}
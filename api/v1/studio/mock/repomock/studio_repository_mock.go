@@ -3,6 +3,7 @@ package repomock
 import (
 	eS "bioskuy/api/v1/seat/entity"
 	"bioskuy/api/v1/studio/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 
@@ -29,9 +30,9 @@ func (m *MockStudioRepository) Update(ctx context.Context, tx *sql.Tx, studio en
 	return args.Get(0).(entity.Studio), args.Error(1)
 }
 
-func (m *MockStudioRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Studio, error) {
-	args := m.Called(ctx, tx, c)
-	return args.Get(0).([]entity.Studio), args.Error(1)
+func (m *MockStudioRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Studio, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entity.Studio), args.Int(1), args.Error(2)
 }
 
 func (m *MockStudioRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
@@ -39,6 +40,11 @@ func (m *MockStudioRepository) Delete(ctx context.Context, tx *sql.Tx, id string
 	return args.Error(0)
 }
 
+func (m *MockStudioRepository) HasUpcomingShowtimes(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (bool, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Bool(0), args.Error(1)
+}
+
 type MockSeatRepository struct {
 	mock.Mock
 }
@@ -53,13 +59,13 @@ func (m *MockSeatRepository) FindByID(ctx context.Context, tx *sql.Tx, id string
 	return args.Get(0).(eS.Seat), args.Error(1)
 }
 
-func (m *MockSeatRepository) FindByIDWithNotAvailable(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (eS.Seat, error) {
-	args := m.Called(ctx, tx, id, c)
+func (m *MockSeatRepository) FindAvailableByShowtime(ctx context.Context, tx *sql.Tx, id string, showtimeID string, c *gin.Context) (eS.Seat, error) {
+	args := m.Called(ctx, tx, id, showtimeID, c)
 	return args.Get(0).(eS.Seat), args.Error(1)
 }
 
 func (m *MockSeatRepository) Update(ctx context.Context, tx *sql.Tx, seat eS.Seat, c *gin.Context) (eS.Seat, error) {
-	args := m.Called(ctx, tx, c)
+	args := m.Called(ctx, tx, seat, c)
 	return args.Get(0).(eS.Seat), args.Error(1)
 }
 
@@ -68,7 +74,17 @@ func (m *MockSeatRepository) FindAll(ctx context.Context, id string, tx *sql.Tx,
 	return args.Get(0).([]eS.Seat), args.Error(1)
 }
 
+func (m *MockSeatRepository) FindAllByShowtime(ctx context.Context, showtimeID string, tx *sql.Tx, c *gin.Context) ([]eS.Seat, error) {
+	args := m.Called(ctx, showtimeID, tx, c)
+	return args.Get(0).([]eS.Seat), args.Error(1)
+}
+
 func (m *MockSeatRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
 	args := m.Called(ctx, tx, id, c)
 	return args.Error(0)
 }
+
+func (m *MockSeatRepository) Retire(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
@@ -2,6 +2,8 @@ package servicemock
 
 import (
 	"bioskuy/api/v1/studio/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -27,9 +29,14 @@ func (m *MockStudioService) Update(ctx context.Context, request dto.UpdateStudio
 	return args.Get(0).(dto.StudioResponse), args.Error(1)
 }
 
-func (m *MockStudioService) FindAll(ctx context.Context, c *gin.Context) ([]dto.StudioResponse, error) {
-	args := m.Called(ctx, c)
-	return args.Get(0).([]dto.StudioResponse), args.Error(1)
+func (m *MockStudioService) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.StudioResponse, web.Paging, error) {
+	args := m.Called(ctx, query, c)
+	return args.Get(0).([]dto.StudioResponse), args.Get(1).(web.Paging), args.Error(2)
+}
+
+func (m *MockStudioService) UpdateLayout(ctx context.Context, request dto.UpdateLayoutRequest, c *gin.Context) (dto.StudioResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.StudioResponse), args.Error(1)
 }
 
 func (m *MockStudioService) Delete(ctx context.Context, id string, c *gin.Context) error {
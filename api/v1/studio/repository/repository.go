@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bioskuy/api/v1/studio/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 
@@ -11,7 +12,8 @@ import (
 type StudioRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, user entity.Studio, c *gin.Context) (entity.Studio, error)
 	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Studio, error)
-	FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Studio, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Studio, int, error)
 	Update(ctx context.Context, tx *sql.Tx, studio entity.Studio, c *gin.Context) (entity.Studio, error)
 	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	HasUpcomingShowtimes(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (bool, error)
 }
\ No newline at end of file
@@ -3,12 +3,14 @@ package repository
 import (
 	"bioskuy/api/v1/studio/entity"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"fmt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
 type studioRepository struct {
@@ -19,9 +21,15 @@ func NewStudioRepository() StudioRepository {
 }
 
 func (r *studioRepository) Save(ctx context.Context, tx *sql.Tx, studio entity.Studio, c *gin.Context) (entity.Studio, error){
-	query := "INSERT INTO studios (name, capacity) VALUES ($1, $2) RETURNING id"
+	query := "INSERT INTO studios (name, capacity, turnaround_minutes, class, layout, cinema_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
 
-	err := tx.QueryRowContext(ctx, query, studio.Name, studio.Capacity).Scan(&studio.ID)
+	layout, err := marshalLayout(studio.Layout)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return studio, err
+	}
+
+	err = tx.QueryRowContext(ctx, query, studio.Name, studio.Capacity, studio.TurnaroundMinutes, studio.Class, layout, studio.CinemaID).Scan(&studio.ID)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return studio, err
@@ -32,9 +40,7 @@ func (r *studioRepository) Save(ctx context.Context, tx *sql.Tx, studio entity.S
 
 func (r *studioRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Studio, error){
 
-	fmt.Println(id)
-
-	query := `SELECT id, name, capacity FROM studios WHERE id = $1`
+	query := `SELECT ` + studioColumns + ` WHERE s.id = $1`
 	
 	studio := entity.Studio{}
 	rows, err := tx.QueryContext(ctx, query, id)
@@ -45,7 +51,7 @@ func (r *studioRepository) FindByID(ctx context.Context, tx *sql.Tx, id string,
 	defer rows.Close()
 
 	if rows.Next(){
-		err := rows.Scan(&studio.ID, &studio.Name, &studio.Capacity)
+		studio, err = scanStudio(rows)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 			return  studio, err
@@ -57,33 +63,62 @@ func (r *studioRepository) FindByID(ctx context.Context, tx *sql.Tx, id string,
 	}
 }
 
-func (r *studioRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Studio, error){
+// studioListSpec is what the studio listing can be sorted, filtered and
+// searched by.
+var studioListSpec = helper.ListSpec{
+	Sorts:       map[string]string{"name": "s.name", "capacity": "s.capacity", "class": "s.class", "city": "ci.city"},
+	DefaultSort: "name",
+	Key:         "s.id",
+	Filters:     map[string]string{"cinema_id": "s.cinema_id::text", "class": "s.class"},
+	Search:      []string{"s.name", "ci.name", "ci.city"},
+}
+
+func (r *studioRepository) FindAll(ctx context.Context, tx *sql.Tx, listQuery helper.ListQuery, c *gin.Context) ([]entity.Studio, int, error){
 
-	query := `SELECT id, name, capacity FROM studios`
+	clauses, err := listQuery.Clauses(studioListSpec)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	total := 0
+	countQuery := `SELECT COUNT(*) FROM studios s JOIN cinemas ci ON s.cinema_id = ci.id ` + clauses.Where
+	if err := tx.QueryRowContext(ctx, countQuery, clauses.Args...).Scan(&total); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + studioColumns + ` ` + clauses.Where + ` ` + clauses.Page
 
 	studios := []entity.Studio{}
-	rows, err := tx.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query, clauses.AllArgs()...)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  studios, err
+		return  studios, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		studio := entity.Studio{}
-		if err := rows.Scan(&studio.ID, &studio.Name, &studio.Capacity); err != nil {
-			return nil, err
+		studio, err := scanStudio(rows)
+		if err != nil {
+			return nil, 0, err
 		}
 		studios = append(studios, studio)
 	}
-	return studios, nil
+	return studios, total, nil
 }
 
 func (r *studioRepository) Update(ctx context.Context, tx *sql.Tx, studio entity.Studio, c *gin.Context) (entity.Studio, error){
 
-	query := `UPDATE studios SET name = $1 WHERE id = $2`
+	query := `UPDATE studios SET name = $1, turnaround_minutes = $2, class = $3, capacity = $4, layout = $5 WHERE id = $6`
 
-	_, err := tx.ExecContext(ctx, query, studio.Name, studio.ID)
+	layout, err := marshalLayout(studio.Layout)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return studio, err
+	}
+
+	_, err = tx.ExecContext(ctx, query, studio.Name, studio.TurnaroundMinutes, studio.Class, studio.Capacity, layout, studio.ID)
 
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -97,6 +132,11 @@ func (r *studioRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c
 	query := `DELETE FROM studios WHERE id = $1`
 
 	_, err := tx.ExecContext(ctx, query, id)
+	if isForeignKeyViolation(err) {
+		conflict := exception.ConflictError{Message: "studio has showtime history and cannot be deleted"}
+		c.Error(conflict).SetType(gin.ErrorTypePublic)
+		return conflict
+	}
 
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -105,3 +145,55 @@ func (r *studioRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c
 
 	return nil
 }
+
+// HasUpcomingShowtimes reports whether the studio has a showtime that has not
+// finished yet and was not cancelled.
+func (r *studioRepository) HasUpcomingShowtimes(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM showtimes WHERE studio_id = $1 AND cancelled_at IS NULL AND show_end > NOW() AT TIME ZONE 'UTC')`
+
+	var upcoming bool
+	err := tx.QueryRowContext(ctx, query, id).Scan(&upcoming)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return false, err
+	}
+
+	return upcoming, nil
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// marshalLayout encodes the layout for the JSONB column; a nil layout is NULL.
+func marshalLayout(layout *entity.Layout) ([]byte, error) {
+	if layout == nil {
+		return nil, nil
+	}
+	return json.Marshal(layout)
+}
+
+// studioColumns selects what scanStudio reads, including the timezone of the
+// studio's cinema.
+const studioColumns = `s.id, s.name, s.capacity, s.turnaround_minutes, s.class, s.layout, s.cinema_id, ci.timezone
+	FROM studios s JOIN cinemas ci ON s.cinema_id = ci.id`
+
+func scanStudio(rows *sql.Rows) (entity.Studio, error) {
+	studio := entity.Studio{}
+	var layout []byte
+
+	err := rows.Scan(&studio.ID, &studio.Name, &studio.Capacity, &studio.TurnaroundMinutes, &studio.Class, &layout, &studio.CinemaID, &studio.Timezone)
+	if err != nil {
+		return studio, err
+	}
+
+	if layout != nil {
+		studio.Layout = &entity.Layout{}
+		if err := json.Unmarshal(layout, studio.Layout); err != nil {
+			return studio, err
+		}
+	}
+
+	return studio, nil
+}
@@ -2,13 +2,17 @@ package repository
 
 import (
 	"bioskuy/api/v1/studio/entity"
+	"bioskuy/exception"
+	"bioskuy/helper"
 	"database/sql"
 	"errors"
+	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/net/context"
@@ -24,9 +28,12 @@ type StudioRepositoryTestSuite struct {
 }
 
 var mockingStudio = entity.Studio{
-	ID:       "1231cmf1m",
-	Name:     "Studio 1",
-	Capacity: 100,
+	ID:                "1231cmf1m",
+	Name:              "Studio 1",
+	Capacity:          100,
+	TurnaroundMinutes: 15,
+	Class:             "regular",
+	CinemaID:          "7d3f6a8e-1c2b-4f5a-9e8d-2b1c0a9f8e7d",
 }
 
 func (suite *StudioRepositoryTestSuite) SetupTest() {
@@ -45,8 +52,8 @@ func TestStudioRepositoryTestSuite(t *testing.T) {
 
 func (suite *StudioRepositoryTestSuite) TestSave_Success() {
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(`INSERT INTO studios \(name, capacity\) VALUES \(\$1, \$2\) RETURNING id`).
-		WithArgs(mockingStudio.Name, mockingStudio.Capacity).
+	suite.mockSql.ExpectQuery(`INSERT INTO studios \(name, capacity, turnaround_minutes, class, layout, cinema_id\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\) RETURNING id`).
+		WithArgs(mockingStudio.Name, mockingStudio.Capacity, mockingStudio.TurnaroundMinutes, mockingStudio.Class, sqlmock.AnyArg(), mockingStudio.CinemaID).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockingStudio.ID))
 	suite.mockSql.ExpectCommit()
 
@@ -62,8 +69,8 @@ func (suite *StudioRepositoryTestSuite) TestSave_Success() {
 
 func (suite *StudioRepositoryTestSuite) TestSave_Failed() {
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(`INSERT INTO studios \(name, capacity\) VALUES \(\$1, \$2\) RETURNING id`).
-		WithArgs(mockingStudio.Name, mockingStudio.Capacity).
+	suite.mockSql.ExpectQuery(`INSERT INTO studios \(name, capacity, turnaround_minutes, class, layout, cinema_id\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\) RETURNING id`).
+		WithArgs(mockingStudio.Name, mockingStudio.Capacity, mockingStudio.TurnaroundMinutes, mockingStudio.Class, sqlmock.AnyArg(), mockingStudio.CinemaID).
 		WillReturnError(errors.New("Insert Studio Failed"))
 	suite.mockSql.ExpectRollback()
 
@@ -77,9 +84,9 @@ func (suite *StudioRepositoryTestSuite) TestSave_Failed() {
 
 func (suite *StudioRepositoryTestSuite) TestFindByID_Success() {
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(`SELECT id, name, capacity FROM studios WHERE id = \$1`).
+	suite.mockSql.ExpectQuery(`SELECT s.id, s.name, s.capacity, s.turnaround_minutes, s.class, s.layout, s.cinema_id, ci.timezone FROM studios s JOIN cinemas ci ON s.cinema_id = ci.id WHERE s.id = \$1`).
 		WithArgs(mockingStudio.ID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "capacity"}).AddRow(mockingStudio.ID, mockingStudio.Name, mockingStudio.Capacity))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "capacity", "turnaround_minutes", "class", "layout", "cinema_id", "timezone"}).AddRow(mockingStudio.ID, mockingStudio.Name, mockingStudio.Capacity, mockingStudio.TurnaroundMinutes, mockingStudio.Class, nil, mockingStudio.CinemaID, "Asia/Jakarta"))
 	suite.mockSql.ExpectCommit()
 
 	tx, err := suite.mockDb.Begin()
@@ -90,12 +97,36 @@ func (suite *StudioRepositoryTestSuite) TestFindByID_Success() {
 	assert.Equal(suite.T(), mockingStudio.ID, result.ID)
 	assert.Equal(suite.T(), mockingStudio.Name, result.Name)
 	assert.Equal(suite.T(), mockingStudio.Capacity, result.Capacity)
+	assert.Equal(suite.T(), mockingStudio.TurnaroundMinutes, result.TurnaroundMinutes)
+	assert.NoError(suite.T(), tx.Commit())
+}
+
+func (suite *StudioRepositoryTestSuite) TestFindByID_WithLayout() {
+	layout := `{"rows":[{"label":"A","category":"vip","cells":["seat","aisle",{"type":"couple"}]}]}`
+
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(`SELECT s.id, s.name, s.capacity, s.turnaround_minutes, s.class, s.layout, s.cinema_id, ci.timezone FROM studios s JOIN cinemas ci ON s.cinema_id = ci.id WHERE s.id = \$1`).
+		WithArgs(mockingStudio.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "capacity", "turnaround_minutes", "class", "layout", "cinema_id", "timezone"}).AddRow(mockingStudio.ID, mockingStudio.Name, 2, 15, "premium", []byte(layout), mockingStudio.CinemaID, "Asia/Jakarta"))
+	suite.mockSql.ExpectCommit()
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.FindByID(suite.ctx, tx, mockingStudio.ID, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "premium", result.Class)
+	assert.Equal(suite.T(), &entity.Layout{Rows: []entity.LayoutRow{{
+		Label:    "A",
+		Category: "vip",
+		Cells:    []entity.LayoutCell{{Type: "seat"}, {Type: "aisle"}, {Type: "couple"}},
+	}}}, result.Layout)
 	assert.NoError(suite.T(), tx.Commit())
 }
 
 func (suite *StudioRepositoryTestSuite) TestFindByID_NotFound() {
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(`SELECT id, name, capacity FROM studios WHERE id = \$1`).
+	suite.mockSql.ExpectQuery(`SELECT s.id, s.name, s.capacity, s.turnaround_minutes, s.class, s.layout, s.cinema_id, ci.timezone FROM studios s JOIN cinemas ci ON s.cinema_id = ci.id WHERE s.id = \$1`).
 		WithArgs(mockingStudio.ID).
 		WillReturnError(sql.ErrNoRows)
 	suite.mockSql.ExpectRollback()
@@ -111,25 +142,31 @@ func (suite *StudioRepositoryTestSuite) TestFindByID_NotFound() {
 
 func (suite *StudioRepositoryTestSuite) TestFindAll_Success() {
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectQuery(`SELECT id, name, capacity FROM studios`).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "capacity"}).
-			AddRow(mockingStudio.ID, mockingStudio.Name, mockingStudio.Capacity).
-			AddRow(uuid.New(), "Studio 2", 200))
+	suite.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM studios s JOIN cinemas ci ON s.cinema_id = ci.id WHERE s.cinema_id::text = \$1 AND \(s.name ILIKE \$2 OR ci.name ILIKE \$2 OR ci.city ILIKE \$2\)`).
+		WithArgs(mockingStudio.CinemaID, "%bandung%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	suite.mockSql.ExpectQuery(`SELECT s.id, s.name, s.capacity, s.turnaround_minutes, s.class, s.layout, s.cinema_id, ci.timezone FROM studios s JOIN cinemas ci ON s.cinema_id = ci.id WHERE .* ORDER BY s.capacity DESC, s.id LIMIT \$3 OFFSET \$4`).
+		WithArgs(mockingStudio.CinemaID, "%bandung%", 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "capacity", "turnaround_minutes", "class", "layout", "cinema_id", "timezone"}).
+			AddRow(mockingStudio.ID, mockingStudio.Name, mockingStudio.Capacity, mockingStudio.TurnaroundMinutes, mockingStudio.Class, nil, mockingStudio.CinemaID, "Asia/Jakarta").
+			AddRow(uuid.New(), "Studio 2", 200, 20, "premium", nil, mockingStudio.CinemaID, "Asia/Jakarta"))
 	suite.mockSql.ExpectCommit()
 
 	tx, err := suite.mockDb.Begin()
 	assert.NoError(suite.T(), err)
 
-	result, err := suite.repo.FindAll(suite.ctx, tx, suite.ginCtx)
+	query := helper.ListQuery{Page: 1, Size: 10, Sort: "capacity", Desc: true, Search: "bandung"}.With("cinema_id", mockingStudio.CinemaID)
+	result, total, err := suite.repo.FindAll(suite.ctx, tx, query, suite.ginCtx)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), 2, total)
 	assert.NoError(suite.T(), tx.Commit())
 }
 
 func (suite *StudioRepositoryTestSuite) TestUpdate_Success() {
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectExec(`UPDATE studios SET name = \$1, capacity = \$2 WHERE id = \$3`).
-		WithArgs(mockingStudio.Name, mockingStudio.Capacity, mockingStudio.ID).
+	suite.mockSql.ExpectExec(`UPDATE studios SET name = \$1, turnaround_minutes = \$2, class = \$3, capacity = \$4, layout = \$5 WHERE id = \$6`).
+		WithArgs(mockingStudio.Name, mockingStudio.TurnaroundMinutes, mockingStudio.Class, mockingStudio.Capacity, sqlmock.AnyArg(), mockingStudio.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mockSql.ExpectCommit()
 
@@ -144,8 +181,8 @@ func (suite *StudioRepositoryTestSuite) TestUpdate_Success() {
 
 func (suite *StudioRepositoryTestSuite) TestUpdate_Failed() {
 	suite.mockSql.ExpectBegin()
-	suite.mockSql.ExpectExec(`UPDATE studios SET name = \$1, capacity = \$2 WHERE id = \$3`).
-		WithArgs(mockingStudio.Name, mockingStudio.Capacity, mockingStudio.ID).
+	suite.mockSql.ExpectExec(`UPDATE studios SET name = \$1, turnaround_minutes = \$2, class = \$3, capacity = \$4, layout = \$5 WHERE id = \$6`).
+		WithArgs(mockingStudio.Name, mockingStudio.TurnaroundMinutes, mockingStudio.Class, mockingStudio.Capacity, sqlmock.AnyArg(), mockingStudio.ID).
 		WillReturnError(errors.New("Update Studio Failed"))
 	suite.mockSql.ExpectRollback()
 
@@ -172,6 +209,37 @@ func (suite *StudioRepositoryTestSuite) TestDelete_Success() {
 	assert.NoError(suite.T(), tx.Commit())
 }
 
+func (suite *StudioRepositoryTestSuite) TestDelete_HasShowtimes() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectExec(`DELETE FROM studios WHERE id = \$1`).
+		WithArgs(mockingStudio.ID).
+		WillReturnError(&pq.Error{Code: "23503"})
+	suite.mockSql.ExpectRollback()
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.Delete(suite.ctx, tx, mockingStudio.ID, suite.ginCtx)
+	assert.IsType(suite.T(), exception.ConflictError{}, err)
+	assert.NoError(suite.T(), tx.Rollback())
+}
+
+func (suite *StudioRepositoryTestSuite) TestHasUpcomingShowtimes() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM showtimes WHERE studio_id = $1 AND cancelled_at IS NULL AND show_end > NOW() AT TIME ZONE 'UTC')`)).
+		WithArgs(mockingStudio.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	suite.mockSql.ExpectCommit()
+
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+
+	upcoming, err := suite.repo.HasUpcomingShowtimes(suite.ctx, tx, mockingStudio.ID, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), upcoming)
+	assert.NoError(suite.T(), tx.Commit())
+}
+
 func (suite *StudioRepositoryTestSuite) TestDelete_Failed() {
 	suite.mockSql.ExpectBegin()
 	suite.mockSql.ExpectExec(`DELETE FROM studios WHERE id = \$1`).
@@ -1,6 +1,7 @@
 package route
 
 import (
+	repoCinema "bioskuy/api/v1/cinema/repository"
 	repoSeat "bioskuy/api/v1/seat/repository"
 	"bioskuy/api/v1/studio/controller"
 	"bioskuy/api/v1/studio/repository"
@@ -20,19 +21,22 @@ func StudioRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB, c
 
 	seatRepo := repoSeat.NewSeatRepository()
 
+	cinemaRepo := repoCinema.NewCinemaRepository()
+
 	studioRepo := repository.NewStudioRepository()
-	studioService := service.NewStudioService(studioRepo, validate, db, seatRepo)
+	studioService := service.NewStudioService(studioRepo, validate, db, seatRepo, cinemaRepo)
 	studioController := controller.NewStudioController(studioService)
 
 	v1 := router.Group("/api/v1")
 	{
 		studios := v1.Group("/studios")
 		{
-			studios.POST("/", middleware.AuthMiddleware(authService, "admin"), studioController.Create)
+			studios.POST("/", middleware.AuthMiddleware(authService, "admin", "branch admin"), studioController.Create)
 			studios.GET("/:studioId", studioController.FindById)
 			studios.GET("/", studioController.FindAll)
-			studios.PUT("/:studioId", middleware.AuthMiddleware(authService, "admin"), studioController.Update)
-			studios.DELETE("/:studioId", middleware.AuthMiddleware(authService, "admin"), studioController.Delete)
+			studios.PUT("/:studioId", middleware.AuthMiddleware(authService, "admin", "branch admin"), studioController.Update)
+			studios.PUT("/:studioId/layout", middleware.AuthMiddleware(authService, "admin", "branch admin"), studioController.UpdateLayout)
+			studios.DELETE("/:studioId", middleware.AuthMiddleware(authService, "admin", "branch admin"), studioController.Delete)
 		}
 	}
 }
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bioskuy/api/v1/studio/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +12,8 @@ import (
 type StudioService interface {
 	Create(ctx context.Context, request dto.CreateStudioRequest, c *gin.Context) (dto.StudioResponse, error)
 	FindByID(ctx context.Context, id string, c *gin.Context) (dto.StudioResponse, error)
-	FindAll(ctx context.Context, c *gin.Context) ([]dto.StudioResponse, error)
+	FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.StudioResponse, web.Paging, error)
 	Update(ctx context.Context, request dto.UpdateStudioRequest, c *gin.Context) (dto.StudioResponse, error)
+	UpdateLayout(ctx context.Context, request dto.UpdateLayoutRequest, c *gin.Context) (dto.StudioResponse, error)
 	Delete(ctx context.Context, id string, c *gin.Context) error
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package service
 
 import (
+	repoCinema "bioskuy/api/v1/cinema/repository"
 	entitySeat "bioskuy/api/v1/seat/entity"
 	repoSeat "bioskuy/api/v1/seat/repository"
 	"bioskuy/api/v1/studio/dto"
@@ -8,10 +9,10 @@ import (
 	"bioskuy/api/v1/studio/repository"
 	"bioskuy/exception"
 	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -20,13 +21,14 @@ import (
 type studioService struct {
 	RepoStudio     repository.StudioRepository
 	RepoSeat     repoSeat.SeatRepository
+	RepoCinema   repoCinema.CinemaRepository
 	Validate *validator.Validate
 	DB *sql.DB
 }
 
 
-func NewStudioService(RepoStudio repository.StudioRepository, validate *validator.Validate, DB *sql.DB, RepoSeat repoSeat.SeatRepository) StudioService {
-	return &studioService{RepoStudio: RepoStudio, Validate: validate, DB: DB, RepoSeat: RepoSeat}
+func NewStudioService(RepoStudio repository.StudioRepository, validate *validator.Validate, DB *sql.DB, RepoSeat repoSeat.SeatRepository, RepoCinema repoCinema.CinemaRepository) StudioService {
+	return &studioService{RepoStudio: RepoStudio, Validate: validate, DB: DB, RepoSeat: RepoSeat, RepoCinema: RepoCinema}
 }
 
 func (s *studioService) Create(ctx context.Context, request dto.CreateStudioRequest, c *gin.Context) (dto.StudioResponse, error) {
@@ -38,15 +40,20 @@ func (s *studioService) Create(ctx context.Context, request dto.CreateStudioRequ
         return StudioResponse, err
     }
 
-    if request.MaxRowSeat == 0 {
-        err := errors.New("maxRowSeat tidak boleh nol")
-        c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-        return StudioResponse, err
+    layout := request.Layout
+    if layout == nil {
+        if request.MaxRowSeat == 0 {
+            err := errors.New("maxRowSeat tidak boleh nol")
+            c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+            return StudioResponse, err
+        }
+
+        grid := entity.GridLayout(request.Capacity, request.MaxRowSeat)
+        layout = &grid
     }
 
-    numRows := (request.Capacity + request.MaxRowSeat - 1) / request.MaxRowSeat
-    if numRows > 26 {
-        err := errors.New("jumlah baris melebihi batas maksimal (26)")
+    err = layout.Validate()
+    if err != nil {
         c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return StudioResponse, err
     }
@@ -58,9 +65,34 @@ func (s *studioService) Create(ctx context.Context, request dto.CreateStudioRequ
     }
     defer helper.CommitAndRollback(tx, c)
 
+    if !helper.CanManageCinema(c, request.CinemaID) {
+        err := errors.New("you can only manage studios of your own cinema")
+        c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return StudioResponse, err
+    }
+
+    cinema, err := s.RepoCinema.FindByID(ctx, tx, request.CinemaID, c)
+    if err != nil {
+        c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return StudioResponse, err
+    }
+
+    layoutSeats := layout.Seats()
+
     studio := entity.Studio{
-        Name:     request.Name,
-        Capacity: request.Capacity,
+        Name:              request.Name,
+        Capacity:          len(layoutSeats),
+        TurnaroundMinutes: entity.DefaultTurnaroundMinutes,
+        Class:             entity.DefaultClass,
+        Layout:            layout,
+        CinemaID:          request.CinemaID,
+        Timezone:          cinema.Timezone,
+    }
+    if request.TurnaroundMinutes != nil {
+        studio.TurnaroundMinutes = *request.TurnaroundMinutes
+    }
+    if request.Class != "" {
+        studio.Class = request.Class
     }
 
     result, err := s.RepoStudio.Save(ctx, tx, studio, c)
@@ -69,31 +101,22 @@ func (s *studioService) Create(ctx context.Context, request dto.CreateStudioRequ
         return StudioResponse, err
     }
 
-    for row := 0; row < numRows; row++ {
-        for seatNum := 1; seatNum <= request.MaxRowSeat; seatNum++ {
-            actualSeatNum := row*request.MaxRowSeat + seatNum
-            if actualSeatNum > request.Capacity {
-                break
-            }
-            seatName := string(rune('A'+row)) + "-" + fmt.Sprintf("%d", seatNum)
-
-            seat := entitySeat.Seat{
-                Name:       seatName,
-                IsAvailable: true,
-                StudioID:   result.ID,
-            }
-
-            _, err := s.RepoSeat.Save(ctx, tx, seat, c)
-            if err != nil {
-                c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-                return StudioResponse, err
-            }
+    for _, layoutSeat := range layoutSeats {
+        _, err := s.RepoSeat.Save(ctx, tx, toSeat(result.ID, layoutSeat), c)
+        if err != nil {
+            c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+            return StudioResponse, err
         }
     }
 
     StudioResponse.ID = result.ID
     StudioResponse.Name = result.Name
     StudioResponse.Capacity = result.Capacity
+    StudioResponse.TurnaroundMinutes = result.TurnaroundMinutes
+    StudioResponse.Class = result.Class
+    StudioResponse.Layout = result.Layout
+    StudioResponse.CinemaID = result.CinemaID
+    StudioResponse.Timezone = result.Timezone
 
     return StudioResponse, nil
 }
@@ -118,24 +141,29 @@ func (s *studioService) FindByID(ctx context.Context, id string, c *gin.Context)
 	StudioResponse.ID = result.ID
 	StudioResponse.Name = result.Name
 	StudioResponse.Capacity = result.Capacity
+	StudioResponse.TurnaroundMinutes = result.TurnaroundMinutes
+	StudioResponse.Class = result.Class
+	StudioResponse.Layout = result.Layout
+	StudioResponse.CinemaID = result.CinemaID
+	StudioResponse.Timezone = result.Timezone
 
 	return StudioResponse, nil
 }
 
-func (s *studioService) FindAll(ctx context.Context, c *gin.Context) ([]dto.StudioResponse, error){
+func (s *studioService) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.StudioResponse, web.Paging, error){
 	StudioResponses := []dto.StudioResponse{}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  StudioResponses, err
+		return  StudioResponses, web.Paging{}, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	result, err := s.RepoStudio.FindAll(ctx, tx, c)
+	result, total, err := s.RepoStudio.FindAll(ctx, tx, query, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  StudioResponses, err
+		return  StudioResponses, web.Paging{}, err
 	}
 
 	for _, studio := range result {
@@ -144,12 +172,16 @@ func (s *studioService) FindAll(ctx context.Context, c *gin.Context) ([]dto.Stud
 		StudioResponse.ID = studio.ID
 		StudioResponse.Name = studio.Name
 		StudioResponse.Capacity = studio.Capacity
+		StudioResponse.TurnaroundMinutes = studio.TurnaroundMinutes
+		StudioResponse.Class = studio.Class
+		StudioResponse.CinemaID = studio.CinemaID
+		StudioResponse.Timezone = studio.Timezone
 
 		StudioResponses = append(StudioResponses, StudioResponse)
 		
 	}
 
-	return StudioResponses, nil
+	return StudioResponses, query.Paging(total), nil
 }
 
 func (s *studioService) Update(ctx context.Context, request dto.UpdateStudioRequest, c *gin.Context) (dto.StudioResponse, error){
@@ -168,6 +200,12 @@ func (s *studioService) Update(ctx context.Context, request dto.UpdateStudioRequ
 		return  StudioResponse, err
 	}
 
+    if !helper.CanManageCinema(c, resultStudio.CinemaID) {
+        err := errors.New("you can only manage studios of your own cinema")
+        c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return StudioResponse, err
+    }
+
     tx, err := s.DB.Begin()
     if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -178,11 +216,55 @@ func (s *studioService) Update(ctx context.Context, request dto.UpdateStudioRequ
     studio.ID = resultStudio.ID
 	studio.Name = resultStudio.Name
 	studio.Capacity = resultStudio.Capacity
+	studio.TurnaroundMinutes = resultStudio.TurnaroundMinutes
+	studio.Class = resultStudio.Class
+	studio.Layout = resultStudio.Layout
+	studio.CinemaID = resultStudio.CinemaID
+	studio.Timezone = resultStudio.Timezone
 
 	if request.Name != "" {
 		studio.Name = request.Name
 	}
 
+	if request.Class != "" {
+		studio.Class = request.Class
+	}
+
+	if request.TurnaroundMinutes != nil {
+		studio.TurnaroundMinutes = *request.TurnaroundMinutes
+	}
+
+	if request.Capacity != 0 {
+		if studio.Layout != nil && !studio.Layout.IsGrid() {
+			err := errors.New("studio has a custom layout; change its capacity by updating the layout")
+			c.Error(exception.ConflictError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return StudioResponse, err
+		}
+
+		maxRowSeat := request.MaxRowSeat
+		if maxRowSeat == 0 && studio.Layout != nil {
+			maxRowSeat = len(studio.Layout.Rows[0].Cells)
+		}
+		if maxRowSeat == 0 {
+			err := errors.New("max-row-seat is required to change the capacity of a studio without a layout")
+			c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return StudioResponse, err
+		}
+
+		layout := entity.GridLayout(request.Capacity, maxRowSeat)
+		err = layout.Validate()
+		if err != nil {
+			c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return StudioResponse, err
+		}
+
+		studio.Capacity, err = s.syncSeats(ctx, tx, studio.ID, layout, c)
+		if err != nil {
+			return StudioResponse, err
+		}
+		studio.Layout = &layout
+	}
+
     result, err := s.RepoStudio.Update(ctx, tx, studio, c)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -190,12 +272,73 @@ func (s *studioService) Update(ctx context.Context, request dto.UpdateStudioRequ
 	}
 
     StudioResponse.ID = result.ID
-	StudioResponse.Name = resultStudio.Name
-	StudioResponse.Capacity = resultStudio.Capacity
+	StudioResponse.Name = result.Name
+	StudioResponse.Capacity = result.Capacity
+	StudioResponse.TurnaroundMinutes = result.TurnaroundMinutes
+	StudioResponse.Class = result.Class
+	StudioResponse.Layout = result.Layout
+	StudioResponse.CinemaID = result.CinemaID
+	StudioResponse.Timezone = result.Timezone
 
     return StudioResponse, nil
 }
 
+// UpdateLayout replaces a studio's layout and brings its seats in line with
+// it; see syncSeats.
+func (s *studioService) UpdateLayout(ctx context.Context, request dto.UpdateLayoutRequest, c *gin.Context) (dto.StudioResponse, error) {
+	StudioResponse := dto.StudioResponse{}
+
+	err := request.Layout.Validate()
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return StudioResponse, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return StudioResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	studio, err := s.RepoStudio.FindByID(ctx, tx, request.ID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return StudioResponse, err
+	}
+
+	if !helper.CanManageCinema(c, studio.CinemaID) {
+		err := errors.New("you can only manage studios of your own cinema")
+		c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return StudioResponse, err
+	}
+
+	capacity, err := s.syncSeats(ctx, tx, studio.ID, request.Layout, c)
+	if err != nil {
+		return StudioResponse, err
+	}
+
+	studio.Layout = &request.Layout
+	studio.Capacity = capacity
+
+	result, err := s.RepoStudio.Update(ctx, tx, studio, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return StudioResponse, err
+	}
+
+	StudioResponse.ID = result.ID
+	StudioResponse.Name = result.Name
+	StudioResponse.Capacity = result.Capacity
+	StudioResponse.TurnaroundMinutes = result.TurnaroundMinutes
+	StudioResponse.Class = result.Class
+	StudioResponse.Layout = result.Layout
+	StudioResponse.CinemaID = result.CinemaID
+	StudioResponse.Timezone = result.Timezone
+
+	return StudioResponse, nil
+}
+
 func (s *studioService) Delete(ctx context.Context, id string, c *gin.Context) error {
     tx, err := s.DB.Begin()
     if err != nil {
@@ -210,17 +353,94 @@ func (s *studioService) Delete(ctx context.Context, id string, c *gin.Context) e
         return err
     }
 
+    if !helper.CanManageCinema(c, studio.CinemaID) {
+        err := errors.New("you can only manage studios of your own cinema")
+        c.Error(exception.ForbiddenError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return err
+    }
+
+    upcoming, err := s.RepoStudio.HasUpcomingShowtimes(ctx, tx, studio.ID, c)
+    if err != nil {
+        return err
+    }
+    if upcoming {
+        err := errors.New("studio has upcoming showtimes and cannot be deleted")
+        c.Error(exception.ConflictError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+        return err
+    }
+
+    // Seats with booking history are retired rather than deleted, and they
+    // and any past showtimes then keep the studio itself from being deleted,
+    // which the repository reports as a conflict.
     err = s.RepoSeat.Delete(ctx, tx, studio.ID, c)
     if err != nil {
-        c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return err
     }
 
     err = s.RepoStudio.Delete(ctx, tx, studio.ID, c)
     if err != nil {
-        c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
         return err
     }
 
     return nil
 }
+
+// syncSeats brings a studio's seats in line with a layout, matching them by
+// name: seats still in the layout are moved and re-categorised in place, new
+// ones are created and the rest retired, which keeps seats with booking
+// history around for that history. It returns the new seat count.
+func (s *studioService) syncSeats(ctx context.Context, tx *sql.Tx, studioID string, layout entity.Layout, c *gin.Context) (int, error) {
+	existing, err := s.RepoSeat.FindAll(ctx, studioID, tx, c)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return 0, err
+	}
+
+	byName := map[string]entitySeat.Seat{}
+	for _, seat := range existing {
+		byName[seat.Name] = seat
+	}
+
+	layoutSeats := layout.Seats()
+	for _, layoutSeat := range layoutSeats {
+		seat := toSeat(studioID, layoutSeat)
+
+		current, ok := byName[layoutSeat.Name]
+		if !ok {
+			_, err = s.RepoSeat.Save(ctx, tx, seat, c)
+		} else {
+			seat.ID = current.ID
+			seat.IsAvailable = current.IsAvailable
+			_, err = s.RepoSeat.Update(ctx, tx, seat, c)
+			delete(byName, layoutSeat.Name)
+		}
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return 0, err
+		}
+	}
+
+	for _, seat := range existing {
+		if _, dropped := byName[seat.Name]; !dropped {
+			continue
+		}
+		err = s.RepoSeat.Retire(ctx, tx, seat.ID, c)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(layoutSeats), nil
+}
+
+func toSeat(studioID string, layoutSeat entity.LayoutSeat) entitySeat.Seat {
+	return entitySeat.Seat{
+		Name:        layoutSeat.Name,
+		IsAvailable: true,
+		StudioID:    studioID,
+		Category:    layoutSeat.Category,
+		Kind:        layoutSeat.Kind,
+		Row:         layoutSeat.Row,
+		Col:         layoutSeat.Col,
+	}
+}
@@ -1,12 +1,15 @@
 package service_test
 
 import (
+	eC "bioskuy/api/v1/cinema/entity"
+	cinemarepomock "bioskuy/api/v1/cinema/mock/repomock"
 	eS "bioskuy/api/v1/seat/entity"
 	"bioskuy/api/v1/studio/dto"
 	"bioskuy/api/v1/studio/entity"
 	"bioskuy/api/v1/studio/mock/repomock"
 	"bioskuy/api/v1/studio/service"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"fmt"
@@ -24,6 +27,7 @@ type StudioServiceTestSuite struct {
 	suite.Suite
 	mockStudioRepo *repomock.MockStudioRepository
 	mockSeatRepo   *repomock.MockSeatRepository
+	mockCinemaRepo *cinemarepomock.MockCinemaRepository
 	mockValidator  *validator.Validate
 	mockDB         *sql.DB
 	sqlMock        sqlmock.Sqlmock
@@ -35,13 +39,16 @@ func (suite *StudioServiceTestSuite) SetupTest() {
 	var err error
 	suite.mockStudioRepo = new(repomock.MockStudioRepository)
 	suite.mockSeatRepo = new(repomock.MockSeatRepository)
+	suite.mockCinemaRepo = new(cinemarepomock.MockCinemaRepository)
 	suite.mockValidator = validator.New()
 	suite.mockDB, suite.sqlMock, err = sqlmock.New()
 	assert.NoError(suite.T(), err)
-	suite.studioService = service.NewStudioService(suite.mockStudioRepo, suite.mockValidator, suite.mockDB, suite.mockSeatRepo)
+	suite.studioService = service.NewStudioService(suite.mockStudioRepo, suite.mockValidator, suite.mockDB, suite.mockSeatRepo, suite.mockCinemaRepo)
 	suite.ctx = context.Background()
 }
 
+const cinemaID = "7d3f6a8e-1c2b-4f5a-9e8d-2b1c0a9f8e7d"
+
 func (suite *StudioServiceTestSuite) TearDownTest() {
 	suite.mockDB.Close()
 }
@@ -53,6 +60,7 @@ func (suite *StudioServiceTestSuite) TestCreate_Success() {
 		Name:       "Studio 1",
 		Capacity:   50,
 		MaxRowSeat: 10,
+		CinemaID:   cinemaID,
 	}
 
 	studioEntity := entity.Studio{
@@ -61,6 +69,7 @@ func (suite *StudioServiceTestSuite) TestCreate_Success() {
 		Capacity: 50,
 	}
 
+	suite.mockCinemaRepo.On("FindByID", mock.Anything, mock.Anything, cinemaID, mock.Anything).Return(eC.Cinema{ID: cinemaID}, nil).Once()
 	suite.mockStudioRepo.On("Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(studioEntity, nil).Once()
 
 	for row := 0; row < 5; row++ {
@@ -70,6 +79,10 @@ func (suite *StudioServiceTestSuite) TestCreate_Success() {
 				Name:        seatName,
 				IsAvailable: true,
 				StudioID:    "new-id",
+				Category:    "regular",
+				Kind:        "standard",
+				Row:         row,
+				Col:         seatNum - 1,
 			}
 			suite.mockSeatRepo.On("Save", mock.Anything, mock.Anything, seat, mock.Anything).Return(seat, nil).Once()
 		}
@@ -104,8 +117,10 @@ func (suite *StudioServiceTestSuite) TestCreate_RepoError() {
 		Name:       "Studio 1",
 		Capacity:   50,
 		MaxRowSeat: 10,
+		CinemaID:   cinemaID,
 	}
 
+	suite.mockCinemaRepo.On("FindByID", mock.Anything, mock.Anything, cinemaID, mock.Anything).Return(eC.Cinema{ID: cinemaID}, nil).Once()
 	suite.mockStudioRepo.On("Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(entity.Studio{}, exception.InternalServerError{Message: "error"}).Once()
 
 	suite.sqlMock.ExpectBegin()
@@ -117,6 +132,102 @@ func (suite *StudioServiceTestSuite) TestCreate_RepoError() {
 	assert.Equal(suite.T(), dto.StudioResponse{}, response)
 }
 
+func (suite *StudioServiceTestSuite) TestCreate_WithLayout() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	layout := entity.Layout{Rows: []entity.LayoutRow{
+		{Cells: []entity.LayoutCell{{Type: "seat"}, {Type: "aisle"}, {Type: "wheelchair"}}},
+		{Label: "VIP", Category: "vip", Cells: []entity.LayoutCell{{Type: "couple"}}},
+	}}
+	request := dto.CreateStudioRequest{
+		Name:     "Studio 1",
+		Class:    "imax",
+		Layout:   &layout,
+		CinemaID: cinemaID,
+	}
+
+	suite.mockCinemaRepo.On("FindByID", mock.Anything, mock.Anything, cinemaID, mock.Anything).Return(eC.Cinema{ID: cinemaID}, nil).Once()
+	suite.mockStudioRepo.On("Save", mock.Anything, mock.Anything, mock.MatchedBy(func(studio entity.Studio) bool {
+		return studio.Capacity == 3 && studio.Class == "imax" && studio.Layout == &layout && studio.CinemaID == cinemaID
+	}), mock.Anything).Return(entity.Studio{ID: "new-id", Name: "Studio 1", Capacity: 3, Class: "imax", Layout: &layout}, nil).Once()
+
+	seats := []eS.Seat{
+		{Name: "A-1", IsAvailable: true, StudioID: "new-id", Category: "regular", Kind: "standard", Row: 0, Col: 0},
+		{Name: "A-2", IsAvailable: true, StudioID: "new-id", Category: "regular", Kind: "wheelchair", Row: 0, Col: 2},
+		{Name: "VIP-1", IsAvailable: true, StudioID: "new-id", Category: "vip", Kind: "couple", Row: 1, Col: 0},
+	}
+	for _, seat := range seats {
+		suite.mockSeatRepo.On("Save", mock.Anything, mock.Anything, seat, mock.Anything).Return(seat, nil).Once()
+	}
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	response, err := suite.studioService.Create(suite.ctx, request, ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, response.Capacity)
+	assert.Equal(suite.T(), "imax", response.Class)
+	assert.Equal(suite.T(), &layout, response.Layout)
+	suite.mockSeatRepo.AssertExpectations(suite.T())
+}
+
+func (suite *StudioServiceTestSuite) TestCreate_InvalidLayout() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	request := dto.CreateStudioRequest{
+		Name:     "Studio 1",
+		CinemaID: cinemaID,
+		Layout:   &entity.Layout{Rows: []entity.LayoutRow{{Cells: []entity.LayoutCell{{Type: "aisle"}}}}},
+	}
+
+	_, err := suite.studioService.Create(suite.ctx, request, ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ValidationError{}, ginCtx.Errors.Last().Err)
+	suite.mockStudioRepo.AssertNotCalled(suite.T(), "Save")
+}
+
+func (suite *StudioServiceTestSuite) TestCreate_CinemaNotFound() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	request := dto.CreateStudioRequest{
+		Name:       "Studio 1",
+		Capacity:   50,
+		MaxRowSeat: 10,
+		CinemaID:   cinemaID,
+	}
+
+	suite.mockCinemaRepo.On("FindByID", mock.Anything, mock.Anything, cinemaID, mock.Anything).Return(eC.Cinema{}, fmt.Errorf("cinema not found")).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.studioService.Create(suite.ctx, request, ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.NotFoundError{}, ginCtx.Errors.Last().Err)
+	suite.mockStudioRepo.AssertNotCalled(suite.T(), "Save")
+}
+
+func (suite *StudioServiceTestSuite) TestCreate_OtherCinemaForbidden() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	ginCtx.Set("role", "branch admin")
+	ginCtx.Set("cinema_id", "another-cinema")
+	request := dto.CreateStudioRequest{
+		Name:       "Studio 1",
+		Capacity:   50,
+		MaxRowSeat: 10,
+		CinemaID:   cinemaID,
+	}
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.studioService.Create(suite.ctx, request, ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ForbiddenError{}, ginCtx.Errors.Last().Err)
+	suite.mockCinemaRepo.AssertNotCalled(suite.T(), "FindByID")
+}
+
 // FindByID
 func (suite *StudioServiceTestSuite) TestFindByID_Success() {
 	ginCtx, _ := gin.CreateTestContext(nil)
@@ -159,26 +270,29 @@ func (suite *StudioServiceTestSuite) TestFindAll_Success() {
 		{ID: "id2", Name: "Studio 2", Capacity: 60},
 	}
 
-	suite.mockStudioRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(studios, nil).Once()
+	query := helper.ListQuery{Page: 1, Size: 2}.With("class", "regular")
+	suite.mockStudioRepo.On("FindAll", mock.Anything, mock.Anything, query, mock.Anything).Return(studios, 3, nil).Once()
 
 	suite.sqlMock.ExpectBegin()
 	suite.sqlMock.ExpectCommit()
 
-	response, err := suite.studioService.FindAll(suite.ctx, ginCtx)
+	response, paging, err := suite.studioService.FindAll(suite.ctx, query, ginCtx)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), response, 2)
+	assert.Equal(suite.T(), 3, paging.TotalData)
+	assert.Equal(suite.T(), 2, paging.TotalPages)
 }
 
 func (suite *StudioServiceTestSuite) TestFindAll_ServiceError() {
 	ginCtx, _ := gin.CreateTestContext(nil)
 
-	suite.mockStudioRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(nil, exception.InternalServerError{Message: "error"}).Once()
+	suite.mockStudioRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, 0, exception.InternalServerError{Message: "error"}).Once()
 
 	suite.sqlMock.ExpectBegin()
 	suite.sqlMock.ExpectRollback()
 
-	response, err := suite.studioService.FindAll(suite.ctx, ginCtx)
+	response, _, err := suite.studioService.FindAll(suite.ctx, helper.ListQuery{Page: 1, Size: 10}, ginCtx)
 
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), response)
@@ -210,6 +324,124 @@ func (suite *StudioServiceTestSuite) TestUpdate_Success() {
 	assert.Equal(suite.T(), dto.StudioResponse{ID: "some-id", Name: "Updated Studio", Capacity: 50}, response)
 }
 
+func (suite *StudioServiceTestSuite) TestUpdate_TurnaroundMinutes() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	turnaround := 30
+	request := dto.UpdateStudioRequest{
+		ID:                "some-id",
+		TurnaroundMinutes: &turnaround,
+	}
+
+	studioEntity := entity.Studio{
+		ID:                "some-id",
+		Name:              "Studio 1",
+		Capacity:          50,
+		TurnaroundMinutes: 15,
+	}
+	updatedStudio := studioEntity
+	updatedStudio.TurnaroundMinutes = 30
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+	suite.mockStudioRepo.On("Update", mock.Anything, mock.Anything, updatedStudio, mock.Anything).Return(updatedStudio, nil).Once()
+
+	// FindByID and Update each run in their own transaction.
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	response, err := suite.studioService.Update(suite.ctx, request, ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), dto.StudioResponse{ID: "some-id", Name: "Studio 1", Capacity: 50, TurnaroundMinutes: 30}, response)
+}
+
+func (suite *StudioServiceTestSuite) TestUpdate_Capacity() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	request := dto.UpdateStudioRequest{
+		ID:       "some-id",
+		Capacity: 3,
+	}
+
+	layout := entity.GridLayout(4, 2)
+	studioEntity := entity.Studio{ID: "some-id", Name: "Studio 1", Capacity: 4, Layout: &layout}
+	existing := []eS.Seat{
+		{ID: "s1", Name: "A-1", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard"},
+		{ID: "s2", Name: "A-2", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard", Col: 1},
+		{ID: "s3", Name: "B-1", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard", Row: 1},
+		{ID: "s4", Name: "B-2", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard", Row: 1, Col: 1},
+	}
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+	suite.mockSeatRepo.On("FindAll", mock.Anything, "some-id", mock.Anything, mock.Anything).Return(existing, nil).Once()
+	for _, seat := range existing[:3] {
+		suite.mockSeatRepo.On("Update", mock.Anything, mock.Anything, seat, mock.Anything).Return(seat, nil).Once()
+	}
+	suite.mockSeatRepo.On("Retire", mock.Anything, mock.Anything, "s4", mock.Anything).Return(nil).Once()
+	suite.mockStudioRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(studio entity.Studio) bool {
+		return studio.Capacity == 3 && len(studio.Layout.Rows) == 2 && len(studio.Layout.Rows[1].Cells) == 1
+	}), mock.Anything).Return(entity.Studio{ID: "some-id", Name: "Studio 1", Capacity: 3}, nil).Once()
+
+	// FindByID and Update each run in their own transaction.
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	response, err := suite.studioService.Update(suite.ctx, request, ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, response.Capacity)
+	suite.mockSeatRepo.AssertExpectations(suite.T())
+	suite.mockStudioRepo.AssertExpectations(suite.T())
+}
+
+func (suite *StudioServiceTestSuite) TestUpdate_CapacityWithoutLayout() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	request := dto.UpdateStudioRequest{
+		ID:       "some-id",
+		Capacity: 60,
+	}
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(entity.Studio{ID: "some-id", Capacity: 50}, nil).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.studioService.Update(suite.ctx, request, ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ValidationError{}, ginCtx.Errors.Last().Err)
+	suite.mockSeatRepo.AssertNotCalled(suite.T(), "FindAll")
+}
+
+func (suite *StudioServiceTestSuite) TestUpdate_CapacityWithCustomLayout() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	request := dto.UpdateStudioRequest{
+		ID:       "some-id",
+		Capacity: 60,
+	}
+
+	layout := entity.Layout{Rows: []entity.LayoutRow{
+		{Category: "vip", Cells: []entity.LayoutCell{{Type: "seat"}, {Type: "aisle"}, {Type: "seat"}}},
+	}}
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(entity.Studio{ID: "some-id", Capacity: 2, Layout: &layout}, nil).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.studioService.Update(suite.ctx, request, ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ConflictError{}, ginCtx.Errors.Last().Err)
+	suite.mockSeatRepo.AssertNotCalled(suite.T(), "FindAll")
+	suite.mockStudioRepo.AssertNotCalled(suite.T(), "Update")
+}
+
 func (suite *StudioServiceTestSuite) TestUpdate_ValidationError() {
 	ginCtx, _ := gin.CreateTestContext(nil)
 	request := dto.UpdateStudioRequest{
@@ -252,6 +484,7 @@ func (suite *StudioServiceTestSuite) TestDelete_Success() {
 	}
 
 	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+	suite.mockStudioRepo.On("HasUpcomingShowtimes", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(false, nil).Once()
 	suite.mockSeatRepo.On("Delete", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(nil).Once()
 	suite.mockStudioRepo.On("Delete", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(nil).Once()
 
@@ -263,6 +496,24 @@ func (suite *StudioServiceTestSuite) TestDelete_Success() {
 	assert.NoError(suite.T(), err)
 }
 
+func (suite *StudioServiceTestSuite) TestDelete_OtherCinemaForbidden() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	ginCtx.Set("role", "branch admin")
+	ginCtx.Set("cinema_id", "another-cinema")
+	studioEntity := entity.Studio{ID: "some-id", Name: "Studio 1", CinemaID: cinemaID}
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	err := suite.studioService.Delete(suite.ctx, "some-id", ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ForbiddenError{}, ginCtx.Errors.Last().Err)
+	suite.mockStudioRepo.AssertNotCalled(suite.T(), "Delete")
+}
+
 func (suite *StudioServiceTestSuite) TestDelete_StudioNotFound() {
 	ginCtx, _ := gin.CreateTestContext(nil)
 
@@ -285,6 +536,7 @@ func (suite *StudioServiceTestSuite) TestDelete_SeatDeleteError() {
 	}
 
 	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+	suite.mockStudioRepo.On("HasUpcomingShowtimes", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(false, nil).Once()
 	suite.mockSeatRepo.On("Delete", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(exception.InternalServerError{Message: "error"}).Once()
 
 	suite.sqlMock.ExpectBegin()
@@ -304,6 +556,7 @@ func (suite *StudioServiceTestSuite) TestDelete_StudioDeleteError() {
 	}
 
 	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+	suite.mockStudioRepo.On("HasUpcomingShowtimes", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(false, nil).Once()
 	suite.mockSeatRepo.On("Delete", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(nil).Once()
 	suite.mockStudioRepo.On("Delete", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(exception.InternalServerError{Message: "error"}).Once()
 
@@ -315,6 +568,113 @@ func (suite *StudioServiceTestSuite) TestDelete_StudioDeleteError() {
 	assert.Error(suite.T(), err)
 }
 
+func (suite *StudioServiceTestSuite) TestDelete_UpcomingShowtimes() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	studioEntity := entity.Studio{
+		ID:       "some-id",
+		Name:     "Studio 1",
+		Capacity: 50,
+	}
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+	suite.mockStudioRepo.On("HasUpcomingShowtimes", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(true, nil).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	err := suite.studioService.Delete(suite.ctx, "some-id", ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.ConflictError{}, ginCtx.Errors.Last().Err)
+	suite.mockSeatRepo.AssertNotCalled(suite.T(), "Delete")
+	suite.mockStudioRepo.AssertNotCalled(suite.T(), "Delete")
+}
+
+// UpdateLayout
+func (suite *StudioServiceTestSuite) TestUpdateLayout_Success() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	layout := entity.Layout{Rows: []entity.LayoutRow{
+		{Category: "premium", Cells: []entity.LayoutCell{{Type: "seat"}, {Type: "seat"}, {Type: "seat"}}},
+	}}
+	request := dto.UpdateLayoutRequest{ID: "some-id", Layout: layout}
+
+	studioEntity := entity.Studio{ID: "some-id", Name: "Studio 1", Capacity: 3}
+	existing := []eS.Seat{
+		{ID: "s1", Name: "A-1", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard"},
+		{ID: "s2", Name: "A-2", IsAvailable: false, StudioID: "some-id", Category: "regular", Kind: "standard", Col: 1},
+		{ID: "s3", Name: "B-1", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard", Row: 1},
+	}
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(studioEntity, nil).Once()
+	suite.mockSeatRepo.On("FindAll", mock.Anything, "some-id", mock.Anything, mock.Anything).Return(existing, nil).Once()
+	moved := []eS.Seat{
+		{ID: "s1", Name: "A-1", IsAvailable: true, StudioID: "some-id", Category: "premium", Kind: "standard"},
+		{ID: "s2", Name: "A-2", IsAvailable: false, StudioID: "some-id", Category: "premium", Kind: "standard", Col: 1},
+	}
+	for _, seat := range moved {
+		suite.mockSeatRepo.On("Update", mock.Anything, mock.Anything, seat, mock.Anything).Return(seat, nil).Once()
+	}
+	added := eS.Seat{Name: "A-3", IsAvailable: true, StudioID: "some-id", Category: "premium", Kind: "standard", Col: 2}
+	suite.mockSeatRepo.On("Save", mock.Anything, mock.Anything, added, mock.Anything).Return(added, nil).Once()
+	suite.mockSeatRepo.On("Retire", mock.Anything, mock.Anything, "s3", mock.Anything).Return(nil).Once()
+	suite.mockStudioRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(studio entity.Studio) bool {
+		return studio.Capacity == 3 && studio.Layout != nil
+	}), mock.Anything).Return(entity.Studio{ID: "some-id", Name: "Studio 1", Capacity: 3, Layout: &layout}, nil).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	response, err := suite.studioService.UpdateLayout(suite.ctx, request, ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, response.Capacity)
+	assert.Equal(suite.T(), &layout, response.Layout)
+	suite.mockSeatRepo.AssertExpectations(suite.T())
+	suite.mockStudioRepo.AssertExpectations(suite.T())
+}
+
+func (suite *StudioServiceTestSuite) TestUpdateLayout_RetireError() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	request := dto.UpdateLayoutRequest{ID: "some-id", Layout: entity.Layout{Rows: []entity.LayoutRow{
+		{Cells: []entity.LayoutCell{{Type: "seat"}}},
+	}}}
+
+	existing := []eS.Seat{
+		{ID: "s1", Name: "A-1", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard"},
+		{ID: "s2", Name: "A-2", IsAvailable: true, StudioID: "some-id", Category: "regular", Kind: "standard", Col: 1},
+	}
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(entity.Studio{ID: "some-id"}, nil).Once()
+	suite.mockSeatRepo.On("FindAll", mock.Anything, "some-id", mock.Anything, mock.Anything).Return(existing, nil).Once()
+	suite.mockSeatRepo.On("Update", mock.Anything, mock.Anything, existing[0], mock.Anything).Return(existing[0], nil).Once()
+	suite.mockSeatRepo.On("Retire", mock.Anything, mock.Anything, "s2", mock.Anything).Return(exception.InternalServerError{Message: "error"}).Run(func(args mock.Arguments) {
+		args.Get(3).(*gin.Context).Error(exception.InternalServerError{Message: "error"})
+	}).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.studioService.UpdateLayout(suite.ctx, request, ginCtx)
+
+	assert.Error(suite.T(), err)
+	suite.mockStudioRepo.AssertNotCalled(suite.T(), "Update")
+}
+
+func (suite *StudioServiceTestSuite) TestUpdateLayout_NotFoundError() {
+	ginCtx, _ := gin.CreateTestContext(nil)
+	request := dto.UpdateLayoutRequest{ID: "some-id", Layout: entity.GridLayout(10, 5)}
+
+	suite.mockStudioRepo.On("FindByID", mock.Anything, mock.Anything, "some-id", mock.Anything).Return(entity.Studio{}, exception.NotFoundError{Message: "studio not found"}).Once()
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	_, err := suite.studioService.UpdateLayout(suite.ctx, request, ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.NotFoundError{}, ginCtx.Errors.Last().Err)
+}
+
 func TestStudioServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(StudioServiceTestSuite))
 }
@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type TicketController interface {
+	FindMine(c *gin.Context)
+	FindById(c *gin.Context)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"bioskuy/api/v1/ticket/dto"
+	"bioskuy/api/v1/ticket/render"
+	"bioskuy/api/v1/ticket/service"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ticketControllerImpl struct {
+	ticketService service.TicketService
+}
+
+func NewTicketController(ticketService service.TicketService) TicketController {
+	return &ticketControllerImpl{ticketService: ticketService}
+}
+
+// FindMine lists the signed-in user's tickets, optionally only those for
+// upcoming or past showtimes.
+func (ctl *ticketControllerImpl) FindMine(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := helper.OwnListQuery(c, "payment_id", "showtime_id", "status")
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, paging, err := ctl.ticketService.FindAll(ctx, query, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponsePaging{ResponseCode: http.StatusOK, Data: result, Paging: paging})
+}
+
+// FindById returns one of the user's tickets as JSON, or with format=png or
+// format=pdf as its QR code or a printable PDF.
+func (ctl *ticketControllerImpl) FindById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.ticketService.FindByID(ctx, c.Param("ticketId"), c.GetString("user_id"), c)
+	if err != nil {
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+	case "png":
+		qr, err := render.QRCode(result)
+		if err != nil {
+			renderError(c, result, err)
+			return
+		}
+		c.Data(http.StatusOK, "image/png", qr)
+	case "pdf":
+		pdf, err := render.PDF(result)
+		if err != nil {
+			renderError(c, result, err)
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="ticket-`+result.ID+`.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", pdf)
+	default:
+		c.Error(exception.ValidationError{Message: "format must be json, png or pdf"}).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// renderError reports a ticket that cannot be rendered. Only a ticket without
+// a token, i.e. one that is not valid, is a conflict; anything else is a
+// failure on our side.
+func renderError(c *gin.Context, result dto.TicketResponse, err error) {
+	if errors.Is(err, render.ErrNoToken) {
+		c.Error(exception.ConflictError{Message: "ticket is " + result.Status}).SetType(gin.ErrorTypePublic)
+		return
+	}
+	c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+}
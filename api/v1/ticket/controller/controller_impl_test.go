@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"bioskuy/api/v1/ticket/dto"
+	"bioskuy/api/v1/ticket/mock/servicemock"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TicketControllerTestSuite struct {
+	suite.Suite
+	mockService *servicemock.MockTicketService
+	controller  TicketController
+	router      *gin.Engine
+}
+
+var ticket = dto.TicketResponse{
+	ID:         "ticket-1",
+	SeatName:   "A1",
+	Status:     "valid",
+	ShowStart:  time.Date(2024, 8, 2, 19, 30, 0, 0, time.UTC),
+	Timezone:   "Asia/Jakarta",
+	MovieTitle: "Movie",
+	Token:      "payload.signature",
+}
+
+func (suite *TicketControllerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockService = new(servicemock.MockTicketService)
+	suite.controller = NewTicketController(suite.mockService)
+	suite.router = gin.New()
+	suite.router.Use(exception.ErrorHandler)
+
+	signedIn := func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Set("role", "user")
+	}
+	suite.router.GET("/me/tickets", signedIn, suite.controller.FindMine)
+	suite.router.GET("/me/tickets/:ticketId", signedIn, suite.controller.FindById)
+}
+
+func TestTicketControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TicketControllerTestSuite))
+}
+
+func (suite *TicketControllerTestSuite) TestFindMine_Upcoming() {
+	query := helper.ListQuery{Page: 1, Size: 10}.With("when", "upcoming").With("user_id", "user-1")
+	suite.mockService.On("FindAll", mock.Anything, query, mock.Anything).Return([]dto.TicketResponse{ticket}, web.Paging{Page: 1, Size: 10, TotalData: 1, TotalPages: 1}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/tickets?when=upcoming", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"id":"ticket-1"`)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *TicketControllerTestSuite) TestFindById_JSON() {
+	suite.mockService.On("FindByID", mock.Anything, "ticket-1", "user-1", mock.Anything).Return(ticket, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/tickets/ticket-1", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"token":"payload.signature"`)
+}
+
+func (suite *TicketControllerTestSuite) TestFindById_PNG() {
+	suite.mockService.On("FindByID", mock.Anything, "ticket-1", "user-1", mock.Anything).Return(ticket, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/tickets/ticket-1?format=png", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "image/png", w.Header().Get("Content-Type"))
+	assert.True(suite.T(), bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")))
+}
+
+func (suite *TicketControllerTestSuite) TestFindById_PDF() {
+	suite.mockService.On("FindByID", mock.Anything, "ticket-1", "user-1", mock.Anything).Return(ticket, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/tickets/ticket-1?format=pdf", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), `filename="ticket-ticket-1.pdf"`)
+}
+
+func (suite *TicketControllerTestSuite) TestFindById_VoidedPDF() {
+	voided := ticket
+	voided.Status = "voided"
+	voided.Token = ""
+	suite.mockService.On("FindByID", mock.Anything, "ticket-1", "user-1", mock.Anything).Return(voided, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/tickets/ticket-1?format=pdf", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *TicketControllerTestSuite) TestFindById_RenderFailure() {
+	oversized := ticket
+	oversized.Token = strings.Repeat("x", 8000)
+	suite.mockService.On("FindByID", mock.Anything, "ticket-1", "user-1", mock.Anything).Return(oversized, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/tickets/ticket-1?format=png", nil)
+	w := httptest.NewRecorder()
+
+	// The error handler writes the 500 and then panics for the recovery
+	// middleware to log.
+	assert.Panics(suite.T(), func() { suite.router.ServeHTTP(w, req) })
+	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
+}
+
+func (suite *TicketControllerTestSuite) TestFindById_NotFound() {
+	suite.mockService.On("FindByID", mock.Anything, "ticket-2", "user-1", mock.Anything).Return(dto.TicketResponse{}, errors.New("ticket not found")).Run(func(args mock.Arguments) {
+		c := args.Get(3).(*gin.Context)
+		c.Error(exception.NotFoundError{Message: "ticket not found"}).SetType(gin.ErrorTypePublic)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me/tickets/ticket-2?format=png", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
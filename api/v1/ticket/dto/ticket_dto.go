@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// TicketResponse shows show times in the cinema's timezone. Token is the
+// signed payload encoded in the ticket's QR code; voided tickets have none.
+type TicketResponse struct {
+	ID         string    `json:"id"`
+	PaymentID  string    `json:"payment_id"`
+	ShowtimeID string    `json:"showtime_id"`
+	SeatID     string    `json:"seat_id"`
+	SeatName   string    `json:"seat_name"`
+	Status     string    `json:"status"`
+	IssuedAt   time.Time `json:"issued_at"`
+	ShowStart  time.Time `json:"show_start"`
+	ShowEnd    time.Time `json:"show_end"`
+	Timezone   string    `json:"timezone"`
+	MovieTitle string    `json:"movie_title"`
+	StudioName string    `json:"studio_name"`
+	CinemaName string    `json:"cinema_name"`
+	Token      string    `json:"token,omitempty"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+const (
+	TicketStatusValid  = "valid"
+	TicketStatusVoided = "voided"
+	// TicketStatusCancelled is a ticket whose showtime has been cancelled.
+	TicketStatusCancelled = "cancelled"
+)
+
+// Ticket admits one seat of a paid booking to its showtime.
+type Ticket struct {
+	ID         string    `json:"id"`
+	PaymentID  string    `json:"payment_id"`
+	ShowtimeID string    `json:"showtime_id"`
+	SeatID     string    `json:"seat_id"`
+	SeatName   string    `json:"seat_name"`
+	UserID     string    `json:"user_id"`
+	IssuedAt   time.Time `json:"issued_at"`
+	// VoidedAt is set once the seat has been refunded.
+	VoidedAt *time.Time `json:"voided_at"`
+
+	ShowStart time.Time `json:"show_start"`
+	ShowEnd   time.Time `json:"show_end"`
+	// ShowtimeCancelledAt is set once the showtime has been cancelled.
+	ShowtimeCancelledAt *time.Time `json:"showtime_cancelled_at"`
+	// Timezone is the IANA timezone of the cinema.
+	Timezone string `json:"timezone"`
+
+	MovieTitle string `json:"movie_title"`
+	StudioID   string `json:"studio_id"`
+	StudioName string `json:"studio_name"`
+	CinemaID   string `json:"cinema_id"`
+	CinemaName string `json:"cinema_name"`
+}
+
+func (t Ticket) Status() string {
+	if t.VoidedAt != nil {
+		return TicketStatusVoided
+	}
+	if t.ShowtimeCancelledAt != nil {
+		return TicketStatusCancelled
+	}
+	return TicketStatusValid
+}
@@ -0,0 +1,45 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/ticket/entity"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTicketRepository struct {
+	mock.Mock
+}
+
+func (m *MockTicketRepository) Issue(ctx context.Context, tx *sql.Tx, paymentID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, paymentID, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) Void(ctx context.Context, tx *sql.Tx, paymentID string, seatIDs []string, c *gin.Context) error {
+	args := m.Called(ctx, tx, paymentID, seatIDs, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) Move(ctx context.Context, tx *sql.Tx, showtimeID string, fromSeatID string, toSeatID string, c *gin.Context) error {
+	args := m.Called(ctx, tx, showtimeID, fromSeatID, toSeatID, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) VoidByShowtime(ctx context.Context, tx *sql.Tx, showtimeID string, seatIDs []string, c *gin.Context) error {
+	args := m.Called(ctx, tx, showtimeID, seatIDs, c)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Ticket, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).(entity.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Ticket, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entity.Ticket), args.Int(1), args.Error(2)
+}
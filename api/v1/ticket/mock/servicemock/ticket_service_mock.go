@@ -0,0 +1,25 @@
+package servicemock
+
+import (
+	"bioskuy/api/v1/ticket/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTicketService struct {
+	mock.Mock
+}
+
+func (m *MockTicketService) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.TicketResponse, web.Paging, error) {
+	args := m.Called(ctx, query, c)
+	return args.Get(0).([]dto.TicketResponse), args.Get(1).(web.Paging), args.Error(2)
+}
+
+func (m *MockTicketService) FindByID(ctx context.Context, id string, userID string, c *gin.Context) (dto.TicketResponse, error) {
+	args := m.Called(ctx, id, userID, c)
+	return args.Get(0).(dto.TicketResponse), args.Error(1)
+}
@@ -0,0 +1,73 @@
+// Package render turns a ticket into what the customer shows at the door: a
+// QR code of its signed token, or a printable PDF holding that QR code.
+package render
+
+import (
+	"bioskuy/api/v1/ticket/dto"
+	"bytes"
+	"errors"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// QRSize is the width and height in pixels of a rendered QR code.
+const QRSize = 512
+
+var ErrNoToken = errors.New("ticket has no token to render")
+
+// QRCode renders the ticket's token as a PNG QR code.
+func QRCode(ticket dto.TicketResponse) ([]byte, error) {
+	if ticket.Token == "" {
+		return nil, ErrNoToken
+	}
+	return qrcode.Encode(ticket.Token, qrcode.Medium, QRSize)
+}
+
+// PDF renders an A6 ticket with the showtime, seat and QR code.
+func PDF(ticket dto.TicketResponse) ([]byte, error) {
+	qr, err := QRCode(ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A6", "")
+	pdf.SetTitle("Ticket "+ticket.ID, true)
+	pdf.SetCreationDate(ticket.IssuedAt)
+	pdf.SetMargins(8, 8, 8)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	width, _ := pdf.GetPageSize()
+	inner := width - 16
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.MultiCell(inner, 7, tr(ticket.MovieTitle), "", "C", false)
+	pdf.Ln(2)
+
+	pdf.SetFont("Helvetica", "", 10)
+	lines := []string{
+		ticket.CinemaName + " - " + ticket.StudioName,
+		ticket.ShowStart.Format("Mon, 02 Jan 2006 15:04") + " (" + ticket.Timezone + ")",
+		"Seat " + ticket.SeatName,
+	}
+	for _, line := range lines {
+		pdf.CellFormat(inner, 6, tr(line), "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	size := 70.0
+	pdf.ImageOptions("qr", (width-size)/2, pdf.GetY(), size, size, true, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Courier", "", 7)
+	pdf.CellFormat(inner, 4, ticket.ID, "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"bioskuy/api/v1/ticket/dto"
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ticket = dto.TicketResponse{
+	ID:         "ticket-1",
+	SeatName:   "A1",
+	Status:     "valid",
+	IssuedAt:   time.Date(2024, 8, 1, 3, 0, 0, 0, time.UTC),
+	ShowStart:  time.Date(2024, 8, 2, 19, 30, 0, 0, time.FixedZone("WIB", 7*60*60)),
+	Timezone:   "Asia/Jakarta",
+	MovieTitle: "Pengabdi Setan – Communion",
+	StudioName: "Studio 1",
+	CinemaName: "Bioskuy Jakarta",
+	Token:      "payload.signature",
+}
+
+func TestQRCode(t *testing.T) {
+	qr, err := QRCode(ticket)
+	assert.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(qr))
+	assert.NoError(t, err)
+	assert.Equal(t, QRSize, img.Bounds().Dx())
+}
+
+func TestPDF(t *testing.T) {
+	pdf, err := PDF(ticket)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+}
+
+func TestVoidedTicketIsNotRendered(t *testing.T) {
+	voided := ticket
+	voided.Status = "voided"
+	voided.Token = ""
+
+	_, err := QRCode(voided)
+	assert.ErrorIs(t, err, ErrNoToken)
+
+	_, err = PDF(voided)
+	assert.ErrorIs(t, err, ErrNoToken)
+}
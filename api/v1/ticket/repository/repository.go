@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"bioskuy/api/v1/ticket/entity"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketRepository interface {
+	Issue(ctx context.Context, tx *sql.Tx, paymentID string, c *gin.Context) error
+	Void(ctx context.Context, tx *sql.Tx, paymentID string, seatIDs []string, c *gin.Context) error
+	Move(ctx context.Context, tx *sql.Tx, showtimeID string, fromSeatID string, toSeatID string, c *gin.Context) error
+	VoidByShowtime(ctx context.Context, tx *sql.Tx, showtimeID string, seatIDs []string, c *gin.Context) error
+	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Ticket, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Ticket, int, error)
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"bioskuy/api/v1/ticket/entity"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type ticketRepository struct {
+}
+
+func NewTicketRepository() TicketRepository {
+	return &ticketRepository{}
+}
+
+const ticketColumns = `t.id, t.payment_id, t.showtime_id, t.seat_id, se.seat_name, t.user_id, t.issued_at, t.voided_at,
+		sh.show_start, sh.show_end, sh.cancelled_at, ci.timezone, m.title, st.id, st.name, ci.id, ci.name`
+
+const ticketJoins = `
+		FROM tickets t
+		JOIN seats se ON t.seat_id = se.id
+		JOIN showtimes sh ON t.showtime_id = sh.id
+		JOIN movies m ON sh.movie_id = m.id
+		JOIN studios st ON sh.studio_id = st.id
+		JOIN cinemas ci ON st.cinema_id = ci.id`
+
+// Issue creates a ticket for every seat still booked under the payment.
+// Seats that already have one are skipped, so issuing twice is harmless.
+func (r *ticketRepository) Issue(ctx context.Context, tx *sql.Tx, paymentID string, c *gin.Context) error {
+	query := `INSERT INTO tickets (payment_id, showtime_id, seat_id, user_id)
+		SELECT p.id, sdfb.showtime_id, sdfb.seat_id, p.user_id
+		FROM payments p
+		JOIN seat_detail_for_bookings sdfb ON sdfb.seatBooking_id = p.seatbooking_id
+		WHERE p.id = $1
+		ON CONFLICT (payment_id, seat_id) DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, paymentID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// Void voids the payment's tickets for the given seats, or all of them when
+// seatIDs is empty.
+func (r *ticketRepository) Void(ctx context.Context, tx *sql.Tx, paymentID string, seatIDs []string, c *gin.Context) error {
+	query := `UPDATE tickets SET voided_at = (NOW() AT TIME ZONE 'UTC')
+		WHERE payment_id = $1 AND voided_at IS NULL AND (cardinality($2::text[]) = 0 OR seat_id::text = ANY($2))`
+
+	if seatIDs == nil {
+		seatIDs = []string{}
+	}
+
+	_, err := tx.ExecContext(ctx, query, paymentID, pq.Array(seatIDs))
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// Move voids the valid ticket for fromSeatID at the showtime and issues a new
+// one for toSeatID under the same payment, so a rescheduled seat gets a ticket
+// whose signed payload matches its new seat.
+func (r *ticketRepository) Move(ctx context.Context, tx *sql.Tx, showtimeID string, fromSeatID string, toSeatID string, c *gin.Context) error {
+	query := `WITH voided AS (
+			UPDATE tickets SET voided_at = (NOW() AT TIME ZONE 'UTC')
+			WHERE showtime_id = $1 AND seat_id = $2 AND voided_at IS NULL
+			RETURNING payment_id, user_id
+		)
+		INSERT INTO tickets (payment_id, showtime_id, seat_id, user_id)
+		SELECT payment_id, $1, $3, user_id FROM voided
+		ON CONFLICT (payment_id, seat_id) DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, showtimeID, fromSeatID, toSeatID)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// VoidByShowtime voids the showtime's tickets for the given seats, or all of
+// them when seatIDs is empty.
+func (r *ticketRepository) VoidByShowtime(ctx context.Context, tx *sql.Tx, showtimeID string, seatIDs []string, c *gin.Context) error {
+	query := `UPDATE tickets SET voided_at = (NOW() AT TIME ZONE 'UTC')
+		WHERE showtime_id = $1 AND voided_at IS NULL AND (cardinality($2::text[]) = 0 OR seat_id::text = ANY($2))`
+
+	if seatIDs == nil {
+		seatIDs = []string{}
+	}
+
+	_, err := tx.ExecContext(ctx, query, showtimeID, pq.Array(seatIDs))
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ticketRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Ticket, error) {
+	query := `SELECT ` + ticketColumns + ticketJoins + `
+		WHERE t.id::text = $1`
+
+	ticket, err := scanTicket(tx.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ticket, errors.New("ticket not found")
+	}
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ticket, err
+	}
+
+	return ticket, nil
+}
+
+// ticketListSpec is what the ticket listing can be sorted, filtered and
+// searched by.
+var ticketListSpec = helper.ListSpec{
+	Sorts: map[string]string{
+		"show_start":  "sh.show_start",
+		"issued_at":   "t.issued_at",
+		"movie_title": "m.title",
+	},
+	DefaultSort: "show_start",
+	Key:         "t.id",
+	Filters: map[string]string{
+		"user_id":     "t.user_id::text",
+		"payment_id":  "t.payment_id::text",
+		"showtime_id": "t.showtime_id::text",
+	},
+	Scopes: map[string]map[string]string{
+		"when": {
+			"upcoming": "sh.show_end > NOW() AT TIME ZONE 'UTC'",
+			"past":     "sh.show_end <= NOW() AT TIME ZONE 'UTC'",
+		},
+		"status": {
+			"valid":     "t.voided_at IS NULL AND sh.cancelled_at IS NULL",
+			"voided":    "t.voided_at IS NOT NULL",
+			"cancelled": "t.voided_at IS NULL AND sh.cancelled_at IS NOT NULL",
+		},
+	},
+	Search: []string{"m.title", "ci.name"},
+}
+
+func (r *ticketRepository) FindAll(ctx context.Context, tx *sql.Tx, listQuery helper.ListQuery, c *gin.Context) ([]entity.Ticket, int, error) {
+	clauses, err := listQuery.Clauses(ticketListSpec)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	total := 0
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*)`+ticketJoins+` `+clauses.Where, clauses.Args...).Scan(&total); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + ticketColumns + ticketJoins + `
+		` + clauses.Where + ` ` + clauses.Page
+
+	rows, err := tx.QueryContext(ctx, query, clauses.AllArgs()...)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tickets := []entity.Ticket{}
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, 0, err
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	return tickets, total, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTicket(row scanner) (entity.Ticket, error) {
+	ticket := entity.Ticket{}
+	var voidedAt, cancelledAt sql.NullTime
+
+	err := row.Scan(
+		&ticket.ID, &ticket.PaymentID, &ticket.ShowtimeID, &ticket.SeatID, &ticket.SeatName, &ticket.UserID, &ticket.IssuedAt, &voidedAt,
+		&ticket.ShowStart, &ticket.ShowEnd, &cancelledAt, &ticket.Timezone, &ticket.MovieTitle, &ticket.StudioID, &ticket.StudioName, &ticket.CinemaID, &ticket.CinemaName,
+	)
+	if voidedAt.Valid {
+		ticket.VoidedAt = &voidedAt.Time
+	}
+	if cancelledAt.Valid {
+		ticket.ShowtimeCancelledAt = &cancelledAt.Time
+	}
+
+	return ticket, err
+}
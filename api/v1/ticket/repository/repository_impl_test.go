@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TicketRepositoryTestSuite struct {
+	suite.Suite
+	mockDb  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    TicketRepository
+	ctx     context.Context
+	ginCtx  *gin.Context
+}
+
+var ticketRows = []string{
+	"id", "payment_id", "showtime_id", "seat_id", "seat_name", "user_id", "issued_at", "voided_at",
+	"show_start", "show_end", "cancelled_at", "timezone", "title", "studio_id", "studio_name", "cinema_id", "cinema_name",
+}
+
+func (suite *TicketRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.repo = NewTicketRepository()
+	suite.ctx = context.Background()
+	suite.ginCtx = &gin.Context{}
+}
+
+func (suite *TicketRepositoryTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestTicketRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TicketRepositoryTestSuite))
+}
+
+func (suite *TicketRepositoryTestSuite) begin() *sql.Tx {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+	return tx
+}
+
+func (suite *TicketRepositoryTestSuite) TestIssue_Success() {
+	tx := suite.begin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`INSERT INTO tickets (payment_id, showtime_id, seat_id, user_id)`) + `.*` + regexp.QuoteMeta(`ON CONFLICT (payment_id, seat_id) DO NOTHING`)).
+		WithArgs("payment-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.Issue(suite.ctx, tx, "payment-1", suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *TicketRepositoryTestSuite) TestIssue_Error() {
+	tx := suite.begin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`INSERT INTO tickets`)).WillReturnError(errors.New("insert error"))
+
+	err := suite.repo.Issue(suite.ctx, tx, "payment-1", suite.ginCtx)
+	assert.EqualError(suite.T(), err, "insert error")
+}
+
+func (suite *TicketRepositoryTestSuite) TestVoid_Seats() {
+	tx := suite.begin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE tickets SET voided_at = (NOW() AT TIME ZONE 'UTC')`)).
+		WithArgs("payment-1", pq.Array([]string{"seat-2"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Void(suite.ctx, tx, "payment-1", []string{"seat-2"}, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *TicketRepositoryTestSuite) TestVoid_AllSeats() {
+	tx := suite.begin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE tickets SET voided_at`)).
+		WithArgs("payment-1", pq.Array([]string{})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.Void(suite.ctx, tx, "payment-1", nil, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *TicketRepositoryTestSuite) TestMove_ReissuesForNewSeat() {
+	tx := suite.begin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`WITH voided AS (`)+`.*`+regexp.QuoteMeta(`INSERT INTO tickets (payment_id, showtime_id, seat_id, user_id)`)).
+		WithArgs("showtime-1", "seat-old", "seat-new").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Move(suite.ctx, tx, "showtime-1", "seat-old", "seat-new", suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *TicketRepositoryTestSuite) TestVoidByShowtime_AllSeats() {
+	tx := suite.begin()
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE tickets SET voided_at`)+`.*`+regexp.QuoteMeta(`WHERE showtime_id = $1`)).
+		WithArgs("showtime-1", pq.Array([]string{})).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := suite.repo.VoidByShowtime(suite.ctx, tx, "showtime-1", nil, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *TicketRepositoryTestSuite) TestFindByID_Success() {
+	showStart := time.Date(2024, 8, 2, 12, 30, 0, 0, time.UTC)
+	voidedAt := showStart.Add(-time.Hour)
+
+	tx := suite.begin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM tickets t`) + `.*` + regexp.QuoteMeta(`WHERE t.id::text = $1`)).
+		WithArgs("ticket-1").
+		WillReturnRows(sqlmock.NewRows(ticketRows).AddRow(
+			"ticket-1", "payment-1", "showtime-1", "seat-1", "A1", "user-1", showStart.Add(-24*time.Hour), voidedAt,
+			showStart, showStart.Add(2*time.Hour), nil, "Asia/Jakarta", "Movie", "studio-1", "Studio 1", "cinema-1", "Bioskuy Dago",
+		))
+
+	ticket, err := suite.repo.FindByID(suite.ctx, tx, "ticket-1", suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "A1", ticket.SeatName)
+	assert.Equal(suite.T(), "Bioskuy Dago", ticket.CinemaName)
+	assert.Equal(suite.T(), &voidedAt, ticket.VoidedAt)
+	assert.Equal(suite.T(), "voided", ticket.Status())
+}
+
+func (suite *TicketRepositoryTestSuite) TestFindByID_NotFound() {
+	tx := suite.begin()
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM tickets t`)).WithArgs("missing").WillReturnError(sql.ErrNoRows)
+
+	_, err := suite.repo.FindByID(suite.ctx, tx, "missing", suite.ginCtx)
+	assert.EqualError(suite.T(), err, "ticket not found")
+}
+
+func (suite *TicketRepositoryTestSuite) TestFindAll_UpcomingForUser() {
+	showStart := time.Now().UTC().Add(24 * time.Hour)
+
+	tx := suite.begin()
+	where := `WHERE t.user_id::text = $1 AND sh.show_end > NOW() AT TIME ZONE 'UTC'`
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`) + `.*` + regexp.QuoteMeta(where)).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(where+` ORDER BY sh.show_start ASC, t.id LIMIT $2 OFFSET $3`)).
+		WithArgs("user-1", 10, 0).
+		WillReturnRows(sqlmock.NewRows(ticketRows).AddRow(
+			"ticket-1", "payment-1", "showtime-1", "seat-1", "A1", "user-1", time.Now().UTC(), nil,
+			showStart, showStart.Add(2*time.Hour), nil, "Asia/Jakarta", "Movie", "studio-1", "Studio 1", "cinema-1", "Bioskuy Dago",
+		))
+
+	query := helper.ListQuery{Page: 1, Size: 10}.With("user_id", "user-1").With("when", "upcoming")
+	tickets, total, err := suite.repo.FindAll(suite.ctx, tx, query, suite.ginCtx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	assert.Len(suite.T(), tickets, 1)
+	assert.Equal(suite.T(), "valid", tickets[0].Status())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *TicketRepositoryTestSuite) TestFindAll_UnknownStatus() {
+	tx := suite.begin()
+
+	query := helper.ListQuery{Page: 1, Size: 10}.With("status", "used")
+	_, _, err := suite.repo.FindAll(suite.ctx, tx, query, suite.ginCtx)
+	assert.EqualError(suite.T(), err, "status must be one of cancelled, valid, voided")
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"bioskuy/api/v1/ticket/controller"
+	"bioskuy/api/v1/ticket/repository"
+	"bioskuy/api/v1/ticket/service"
+	"bioskuy/auth"
+	"bioskuy/helper"
+	"bioskuy/middleware"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TicketRoute(router *gin.Engine, db *sql.DB, config *helper.Config) {
+
+	authService := auth.NewService(config)
+
+	ticketRepo := repository.NewTicketRepository()
+	ticketService := service.NewTicketService(ticketRepo, db, config)
+	ticketController := controller.NewTicketController(ticketService)
+
+	v1 := router.Group("/api/v1")
+	{
+		me := v1.Group("/me")
+		{
+			me.GET("/tickets", middleware.AuthMiddleware(authService, "user"), ticketController.FindMine)
+			me.GET("/tickets/:ticketId", middleware.AuthMiddleware(authService, "user"), ticketController.FindById)
+		}
+	}
+}
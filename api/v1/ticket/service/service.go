@@ -0,0 +1,15 @@
+package service
+
+import (
+	"bioskuy/api/v1/ticket/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketService interface {
+	FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.TicketResponse, web.Paging, error)
+	FindByID(ctx context.Context, id string, userID string, c *gin.Context) (dto.TicketResponse, error)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bioskuy/api/v1/ticket/dto"
+	"bioskuy/api/v1/ticket/entity"
+	"bioskuy/api/v1/ticket/repository"
+	"bioskuy/api/v1/ticket/token"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ticketServiceImpl struct {
+	Repo repository.TicketRepository
+	DB   *sql.DB
+	Env  *helper.Config
+}
+
+func NewTicketService(repo repository.TicketRepository, DB *sql.DB, env *helper.Config) TicketService {
+	return &ticketServiceImpl{Repo: repo, DB: DB, Env: env}
+}
+
+func (s *ticketServiceImpl) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.TicketResponse, web.Paging, error) {
+	ticketResponses := []dto.TicketResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ticketResponses, web.Paging{}, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	results, total, err := s.Repo.FindAll(ctx, tx, query, c)
+	if err != nil {
+		return ticketResponses, web.Paging{}, err
+	}
+
+	for _, result := range results {
+		ticketResponse, err := s.toTicketResponse(result)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return []dto.TicketResponse{}, web.Paging{}, err
+		}
+		ticketResponses = append(ticketResponses, ticketResponse)
+	}
+
+	return ticketResponses, query.Paging(total), nil
+}
+
+// FindByID returns one of the user's tickets. Another user's ticket is
+// reported as not found rather than forbidden, so ticket IDs cannot be probed.
+func (s *ticketServiceImpl) FindByID(ctx context.Context, id string, userID string, c *gin.Context) (dto.TicketResponse, error) {
+	ticketResponse := dto.TicketResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ticketResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	result, err := s.Repo.FindByID(ctx, tx, id, c)
+	if err == nil && result.UserID != userID {
+		err = errors.New("ticket not found")
+	}
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ticketResponse, err
+	}
+
+	ticketResponse, err = s.toTicketResponse(result)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return ticketResponse, err
+	}
+
+	return ticketResponse, nil
+}
+
+func (s *ticketServiceImpl) toTicketResponse(ticket entity.Ticket) (dto.TicketResponse, error) {
+	ticketResponse := dto.TicketResponse{
+		ID:         ticket.ID,
+		PaymentID:  ticket.PaymentID,
+		ShowtimeID: ticket.ShowtimeID,
+		SeatID:     ticket.SeatID,
+		SeatName:   ticket.SeatName,
+		Status:     ticket.Status(),
+		IssuedAt:   ticket.IssuedAt.UTC(),
+		ShowStart:  helper.InZone(ticket.ShowStart, ticket.Timezone),
+		ShowEnd:    helper.InZone(ticket.ShowEnd, ticket.Timezone),
+		Timezone:   helper.Location(ticket.Timezone).String(),
+		MovieTitle: ticket.MovieTitle,
+		StudioName: ticket.StudioName,
+		CinemaName: ticket.CinemaName,
+	}
+
+	if ticket.Status() != entity.TicketStatusValid {
+		return ticketResponse, nil
+	}
+
+	signed, err := token.Sign(s.Env.SecretKey, token.Payload{
+		TicketID:   ticket.ID,
+		PaymentID:  ticket.PaymentID,
+		ShowtimeID: ticket.ShowtimeID,
+		SeatID:     ticket.SeatID,
+	})
+	if err != nil {
+		return ticketResponse, err
+	}
+	ticketResponse.Token = signed
+
+	return ticketResponse, nil
+}
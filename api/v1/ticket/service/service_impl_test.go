@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bioskuy/api/v1/ticket/entity"
+	"bioskuy/api/v1/ticket/mock/repomock"
+	"bioskuy/api/v1/ticket/token"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TicketServiceTestSuite struct {
+	suite.Suite
+	mockRepo   *repomock.MockTicketRepository
+	mockDb     *sql.DB
+	mockSql    sqlmock.Sqlmock
+	service    TicketService
+	ctx        context.Context
+	ginContext *gin.Context
+}
+
+var showStart = time.Date(2024, 8, 2, 12, 30, 0, 0, time.UTC)
+
+var ticket = entity.Ticket{
+	ID:         "ticket-1",
+	PaymentID:  "payment-1",
+	ShowtimeID: "showtime-1",
+	SeatID:     "seat-1",
+	SeatName:   "A1",
+	UserID:     "user-1",
+	ShowStart:  showStart,
+	ShowEnd:    showStart.Add(2 * time.Hour),
+	Timezone:   "Asia/Jakarta",
+}
+
+func (suite *TicketServiceTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.mockRepo = new(repomock.MockTicketRepository)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.service = NewTicketService(suite.mockRepo, db, &helper.Config{SecretKey: "secret"})
+	suite.ctx = context.Background()
+	suite.ginContext, _ = gin.CreateTestContext(httptest.NewRecorder())
+}
+
+func TestTicketServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TicketServiceTestSuite))
+}
+
+func (suite *TicketServiceTestSuite) TestFindByID_Success() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "ticket-1", suite.ginContext).Return(ticket, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.FindByID(suite.ctx, "ticket-1", "user-1", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "valid", response.Status)
+	assert.Equal(suite.T(), "19:30", response.ShowStart.Format("15:04"))
+
+	payload, err := token.Verify("secret", response.Token)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), token.Payload{TicketID: "ticket-1", PaymentID: "payment-1", ShowtimeID: "showtime-1", SeatID: "seat-1"}, payload)
+}
+
+func (suite *TicketServiceTestSuite) TestFindByID_OtherUsersTicket() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "ticket-1", suite.ginContext).Return(ticket, nil)
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.FindByID(suite.ctx, "ticket-1", "user-2", suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "ticket not found")
+	assert.IsType(suite.T(), exception.NotFoundError{}, suite.ginContext.Errors.Last().Err)
+}
+
+func (suite *TicketServiceTestSuite) TestFindByID_VoidedHasNoToken() {
+	voidedAt := showStart.Add(-time.Hour)
+	voided := ticket
+	voided.VoidedAt = &voidedAt
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "ticket-1", suite.ginContext).Return(voided, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.FindByID(suite.ctx, "ticket-1", "user-1", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "voided", response.Status)
+	assert.Empty(suite.T(), response.Token)
+}
+
+func (suite *TicketServiceTestSuite) TestFindAll_Success() {
+	query := helper.ListQuery{Page: 1, Size: 10}.With("user_id", "user-1")
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, query, suite.ginContext).Return([]entity.Ticket{ticket}, 1, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, paging, err := suite.service.FindAll(suite.ctx, query, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), response, 1)
+	assert.NotEmpty(suite.T(), response[0].Token)
+	assert.Equal(suite.T(), web.Paging{Page: 1, Size: 10, TotalData: 1, TotalPages: 1}, paging)
+}
+
+func (suite *TicketServiceTestSuite) TestFindAll_Error() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, mock.Anything, suite.ginContext).Return([]entity.Ticket{}, 0, errors.New("query error"))
+	suite.mockSql.ExpectRollback()
+
+	response, _, err := suite.service.FindAll(suite.ctx, helper.ListQuery{Page: 1, Size: 10}, suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "query error")
+	assert.Empty(suite.T(), response)
+}
@@ -0,0 +1,44 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var payload = Payload{TicketID: "ticket-1", PaymentID: "payment-1", ShowtimeID: "showtime-1", SeatID: "seat-1"}
+
+func TestSignAndVerify(t *testing.T) {
+	signed, err := Sign("secret", payload)
+	assert.NoError(t, err)
+
+	got, err := Verify("secret", signed)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, got)
+}
+
+func TestVerify_WrongSecret(t *testing.T) {
+	signed, _ := Sign("secret", payload)
+
+	_, err := Verify("other-secret", signed)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerify_TamperedPayload(t *testing.T) {
+	signed, _ := Sign("secret", payload)
+	forged, _ := Sign("secret", Payload{TicketID: "ticket-2", PaymentID: "payment-1", ShowtimeID: "showtime-1", SeatID: "seat-1"})
+
+	body, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(signed, ".")
+
+	_, err := Verify("secret", body+"."+signature)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	for _, token := range []string{"", "no-dot", "!!.!!", "e30.e30"} {
+		_, err := Verify("secret", token)
+		assert.ErrorIs(t, err, ErrInvalidToken, token)
+	}
+}
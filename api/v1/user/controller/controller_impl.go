@@ -5,6 +5,7 @@ import (
 	"bioskuy/api/v1/user/service"
 	"bioskuy/auth"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"bioskuy/web"
 	"net/http"
 
@@ -78,15 +79,22 @@ func (ctl *userController) GetUserByID(c *gin.Context) {
 func (ctl *userController) GetAllUsers(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	result, err := ctl.userService.FindAll(ctx, c)
+	query, err := helper.ListQueryFrom(c, "role", "cinema_id")
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, paging, err := ctl.userService.FindAll(ctx, query, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 		return
 	} 
 
-	response := web.FormatResponse{
+	response := web.FormatResponsePaging{
 		ResponseCode: http.StatusOK,
 		Data: result,
+		Paging: paging,
 	}
 
 		c.JSON(http.StatusOK, response)
@@ -8,14 +8,18 @@ type CreateUserRequest struct {
 
 type UpdateUserRequest struct {
 	ID   string `json:"id"`
-	Role string `json:"role" validate:"required"`
+	Role string `json:"role" validate:"required,oneof=user admin 'super admin' 'branch admin' staff"`
+	// CinemaID is required for branch admins and staff and ignored for other
+	// roles.
+	CinemaID string `json:"cinema_id" validate:"omitempty,uuid"`
 }
 
 type UserResponse struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	CinemaID string `json:"cinema_id,omitempty"`
 }
 
 type UserResponseLoginAndRegister struct {
@@ -1,9 +1,21 @@
 package entity
 
+const (
+	RoleUser       = "user"
+	RoleAdmin      = "admin"
+	RoleSuperAdmin = "super admin"
+	// RoleBranchAdmin manages the studios and showtimes of CinemaID only.
+	RoleBranchAdmin = "branch admin"
+	// RoleStaff checks tickets in at the doors of CinemaID only.
+	RoleStaff = "staff"
+)
+
 type User struct {
 	ID    string `json:"id" `
 	Name  string `json:"name"`
 	Email string `json:"email"`
 	Token string `json:"token"`
 	Role  string `json:"role"`
-}
\ No newline at end of file
+	// CinemaID is set for branch admins and staff only.
+	CinemaID string `json:"cinema_id"`
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bioskuy/api/v1/user/entity"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 
@@ -12,7 +13,7 @@ type UserRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, user entity.User, c *gin.Context) (entity.User, error)
 	FindByEmail(ctx context.Context, tx *sql.Tx, email string, c *gin.Context) (entity.User, error)
 	FindByID(ctx context.Context, tx *sql.Tx, email string, c *gin.Context) (entity.User, error)
-	FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.User, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.User, int, error)
 	UpdateToken(ctx context.Context, tx *sql.Tx, user entity.User, c *gin.Context) (entity.User, error)
 	Update(ctx context.Context, tx *sql.Tx, user entity.User, c *gin.Context) (entity.User, error)
 	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
@@ -3,6 +3,7 @@ package repository
 import (
 	"bioskuy/api/v1/user/entity"
 	"bioskuy/exception"
+	"bioskuy/helper"
 	"context"
 	"database/sql"
 	"errors"
@@ -32,7 +33,7 @@ func (r *userRepository) Save(ctx context.Context, tx *sql.Tx, user entity.User,
 
 func (r *userRepository) FindByEmail(ctx context.Context, tx *sql.Tx, email string, c *gin.Context) (entity.User, error){
 
-	query := `SELECT id, name, email, token, role FROM users WHERE email = $1`
+	query := `SELECT id, name, email, token, role, COALESCE(cinema_id::text, '') FROM users WHERE email = $1`
 	
 	user := entity.User{}
 	rows, err := tx.QueryContext(ctx, query, email)
@@ -43,7 +44,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, tx *sql.Tx, email stri
 	defer rows.Close()
 
 	if rows.Next(){
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Token, &user.Role,)
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Token, &user.Role, &user.CinemaID)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 			return  user, err
@@ -57,7 +58,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, tx *sql.Tx, email stri
 
 func (r *userRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.User, error){
 
-	query := `SELECT id, name, email, token, role FROM users WHERE id = $1`
+	query := `SELECT id, name, email, token, role, COALESCE(cinema_id::text, '') FROM users WHERE id = $1`
 	
 	user := entity.User{}
 	rows, err := tx.QueryContext(ctx, query, id)
@@ -68,7 +69,7 @@ func (r *userRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c
 	defer rows.Close()
 
 	if rows.Next(){
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Token, &user.Role,)
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Token, &user.Role, &user.CinemaID)
 		if err != nil {
 			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
 			return  user, err
@@ -80,26 +81,48 @@ func (r *userRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c
 	}
 }
 
-func (r *userRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.User, error){
+// userListSpec is what the user listing can be sorted, filtered and
+// searched by.
+var userListSpec = helper.ListSpec{
+	Sorts:       map[string]string{"name": "name", "email": "email", "role": "role"},
+	DefaultSort: "name",
+	Key:         "id",
+	Filters:     map[string]string{"role": "role::text", "cinema_id": "cinema_id::text"},
+	Search:      []string{"name", "email"},
+}
+
+func (r *userRepository) FindAll(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.User, int, error){
+
+	clauses, err := query.Clauses(userListSpec)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	total := 0
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM users `+clauses.Where, clauses.Args...).Scan(&total); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
 
-	query := `SELECT id, name, email, token, role FROM users`
+	listQuery := `SELECT id, name, email, token, role, COALESCE(cinema_id::text, '') FROM users ` + clauses.Where + ` ` + clauses.Page
 
 	users := []entity.User{}
-	rows, err := tx.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, listQuery, clauses.AllArgs()...)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return  users, err
+		return  users, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		user := entity.User{}
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Token,&user.Role); err != nil {
-			return nil, err
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Token, &user.Role, &user.CinemaID); err != nil {
+			return nil, 0, err
 		}
 		users = append(users, user)
 	}
-	return users, nil
+	return users, total, nil
 }
 
 func (r *userRepository) UpdateToken(ctx context.Context, tx *sql.Tx, user entity.User, c *gin.Context) (entity.User, error){
@@ -118,9 +141,9 @@ func (r *userRepository) UpdateToken(ctx context.Context, tx *sql.Tx, user entit
 
 func (r *userRepository) Update(ctx context.Context, tx *sql.Tx, user entity.User, c *gin.Context) (entity.User, error){
 
-	query := `UPDATE users SET role = $1 WHERE id = $2`
+	query := `UPDATE users SET role = $1, cinema_id = NULLIF($2, '')::uuid WHERE id = $3`
 
-	_, err := tx.ExecContext(ctx, query, user.Role, user.ID)
+	_, err := tx.ExecContext(ctx, query, user.Role, user.CinemaID, user.ID)
 
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"bioskuy/api/v1/user/entity"
+	"bioskuy/exception"
+	"bioskuy/helper"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
@@ -95,9 +97,9 @@ func (suite *UserRepositoryTestSuite) TestFindByEmail_Success() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE email = \$1`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"}).
-		AddRow(1, "John Doe", "john@example.com", "token123", "user")
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE email = \$1`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"}).
+		AddRow(1, "John Doe", "john@example.com", "token123", "user", "")
 	suite.mockSql.ExpectQuery(query).WithArgs("john@example.com").WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -118,7 +120,7 @@ func (suite *UserRepositoryTestSuite) TestFindByEmail_Error() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE email = \$1`
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE email = \$1`
 	suite.mockSql.ExpectQuery(query).WithArgs("john@example.com").WillReturnError(errors.New("Query Error"))
 
 	ctx := context.Background()
@@ -138,7 +140,7 @@ func (suite *UserRepositoryTestSuite) TestFindByEmail_ErrorOnQuery() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE email = \$1`
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE email = \$1`
 	suite.mockSql.ExpectQuery(query).WithArgs("john@example.com").WillReturnError(errors.New("Query Error"))
 
 	ctx := context.Background()
@@ -158,9 +160,9 @@ func (suite *UserRepositoryTestSuite) TestFindByEmail_ErrorOnScan() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE email = \$1`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"}).
-		AddRow(1, nil, "john@example.com", "token123", "user") // nil will cause scan error
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE email = \$1`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"}).
+		AddRow(1, nil, "john@example.com", "token123", "user", "") // nil will cause scan error
 	suite.mockSql.ExpectQuery(query).WithArgs("john@example.com").WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -180,8 +182,8 @@ func (suite *UserRepositoryTestSuite) TestFindByEmail_UserNotFound() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE email = \$1`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"})
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE email = \$1`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"})
 	suite.mockSql.ExpectQuery(query).WithArgs("nonexistent@example.com").WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -202,9 +204,9 @@ func (suite *UserRepositoryTestSuite) TestFindByID_Success() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE id = \$1`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"}).
-		AddRow(1, "John Doe", "john@example.com", "token123", "user")
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE id = \$1`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"}).
+		AddRow(1, "John Doe", "john@example.com", "token123", "user", "")
 	suite.mockSql.ExpectQuery(query).WithArgs("1").WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -225,7 +227,7 @@ func (suite *UserRepositoryTestSuite) TestFindByID_ErrorOnQuery() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE id = \$1`
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE id = \$1`
 	suite.mockSql.ExpectQuery(query).WithArgs("1").WillReturnError(errors.New("Query Error"))
 
 	ctx := context.Background()
@@ -245,9 +247,9 @@ func (suite *UserRepositoryTestSuite) TestFindByID_ErrorOnScan() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE id = \$1`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"}).
-		AddRow(1, nil, "john@example.com", "token123", "user") // nil will cause scan error
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE id = \$1`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"}).
+		AddRow(1, nil, "john@example.com", "token123", "user", "") // nil will cause scan error
 	suite.mockSql.ExpectQuery(query).WithArgs("1").WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -267,8 +269,8 @@ func (suite *UserRepositoryTestSuite) TestFindByID_UserNotFound() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users WHERE id = \$1`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"})
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users WHERE id = \$1`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"})
 	suite.mockSql.ExpectQuery(query).WithArgs("nonexistent-id").WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -289,17 +291,20 @@ func (suite *UserRepositoryTestSuite) TestFindAll_Success() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"}).
-		AddRow("1", "John Doe", "john@example.com", "token123", "user").
-		AddRow("2", "Jane Doe", "jane@example.com", "token456", "admin")
-	suite.mockSql.ExpectQuery(query).WillReturnRows(rows)
+	suite.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM users$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users ORDER BY name ASC, id LIMIT \$1 OFFSET \$2`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"}).
+		AddRow("1", "John Doe", "john@example.com", "token123", "user", "").
+		AddRow("2", "Jane Doe", "jane@example.com", "token456", "admin", "")
+	suite.mockSql.ExpectQuery(query).WithArgs(10, 10).WillReturnRows(rows)
 
 	ctx := context.Background()
 	c, _ := gin.CreateTestContext(nil)
-	result, err := suite.repo.FindAll(ctx, tx, c)
+	result, total, err := suite.repo.FindAll(ctx, tx, helper.ListQuery{Page: 2, Size: 10}, c)
 	suite.NoError(err)
 	suite.Len(result, 2)
+	suite.Equal(12, total)
 
 	suite.mockSql.ExpectCommit()
 	err = tx.Commit()
@@ -308,17 +313,54 @@ func (suite *UserRepositoryTestSuite) TestFindAll_Success() {
 	suite.NoError(suite.mockSql.ExpectationsWereMet())
 }
 
+func (suite *UserRepositoryTestSuite) TestFindAll_FilteredAndSearched() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	where := `WHERE role::text = \$1 AND \(name ILIKE \$2 OR email ILIKE \$2\)`
+	suite.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM users `+where).
+		WithArgs("admin", `%50\%%`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mockSql.ExpectQuery(`FROM users `+where+` ORDER BY email DESC, id LIMIT \$3 OFFSET \$4`).
+		WithArgs("admin", `%50\%%`, 5, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"}).
+			AddRow("2", "Jane 50%", "jane@example.com", "token456", "admin", ""))
+
+	query := helper.ListQuery{Page: 1, Size: 5, Sort: "email", Desc: true, Search: "50%"}.With("role", "admin")
+	c, _ := gin.CreateTestContext(nil)
+	result, total, err := suite.repo.FindAll(context.Background(), tx, query, c)
+	suite.NoError(err)
+	suite.Equal(1, total)
+	suite.Equal("Jane 50%", result[0].Name)
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestFindAll_UnknownSort() {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.db.Begin()
+	suite.NoError(err)
+
+	c, _ := gin.CreateTestContext(nil)
+	_, _, err = suite.repo.FindAll(context.Background(), tx, helper.ListQuery{Page: 1, Size: 10, Sort: "token"}, c)
+	suite.EqualError(err, "cannot sort by token")
+	suite.IsType(exception.ValidationError{}, c.Errors.Last().Err)
+	suite.NoError(suite.mockSql.ExpectationsWereMet())
+}
+
 func (suite *UserRepositoryTestSuite) TestFindAll_ErrorOnQuery() {
 	suite.mockSql.ExpectBegin()
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users`
+	suite.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users`
 	suite.mockSql.ExpectQuery(query).WillReturnError(errors.New("Query Error"))
 
 	ctx := context.Background()
 	c, _ := gin.CreateTestContext(nil)
-	_, err = suite.repo.FindAll(ctx, tx, c)
+	_, _, err = suite.repo.FindAll(ctx, tx, helper.ListQuery{Page: 1, Size: 10}, c)
 	suite.Error(err)
 
 	suite.mockSql.ExpectRollback()
@@ -333,14 +375,16 @@ func (suite *UserRepositoryTestSuite) TestFindAll_ErrorOnScan() {
 	tx, err := suite.db.Begin()
 	suite.NoError(err)
 
-	query := `SELECT id, name, email, token, role FROM users`
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role"}).
-		AddRow(1, nil, "john@example.com", "token123", "user") // nil akan menyebabkan error pada Scan
+	suite.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	query := `SELECT id, name, email, token, role, COALESCE\(cinema_id::text, ''\) FROM users`
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "token", "role", "cinema_id"}).
+		AddRow(1, nil, "john@example.com", "token123", "user", "") // nil akan menyebabkan error pada Scan
 	suite.mockSql.ExpectQuery(query).WillReturnRows(rows)
 
 	ctx := context.Background()
 	c, _ := gin.CreateTestContext(nil)
-	_, err = suite.repo.FindAll(ctx, tx, c)
+	_, _, err = suite.repo.FindAll(ctx, tx, helper.ListQuery{Page: 1, Size: 10}, c)
 	suite.Error(err)
 
 	suite.mockSql.ExpectRollback()
@@ -360,9 +404,9 @@ func (suite *UserRepositoryTestSuite) TestUpdate_Success() {
 		Role: "admin",
 	}
 
-	query := `UPDATE users SET role = \$1 WHERE id = \$2`
+	query := `UPDATE users SET role = \$1, cinema_id = NULLIF\(\$2, ''\)::uuid WHERE id = \$3`
 	suite.mockSql.ExpectExec(query).
-		WithArgs(user.Role, user.ID).
+		WithArgs(user.Role, user.CinemaID, user.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	ctx := context.Background()
@@ -388,9 +432,9 @@ func (suite *UserRepositoryTestSuite) TestUpdate_Error() {
 		Role: "admin",
 	}
 
-	query := `UPDATE users SET role = \$1 WHERE id = \$2`
+	query := `UPDATE users SET role = \$1, cinema_id = NULLIF\(\$2, ''\)::uuid WHERE id = \$3`
 	suite.mockSql.ExpectExec(query).
-		WithArgs(user.Role, user.ID).
+		WithArgs(user.Role, user.CinemaID, user.ID).
 		WillReturnError(errors.New("Update Error"))
 
 	ctx := context.Background()
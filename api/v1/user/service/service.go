@@ -2,6 +2,8 @@ package service
 
 import (
 	"bioskuy/api/v1/user/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
 	"context"
 
 	"github.com/gin-gonic/gin"
@@ -12,7 +14,7 @@ type UserService interface {
 	Login(ctx context.Context, request dto.CreateUserRequest, c *gin.Context) (dto.UserResponseLoginAndRegister, error)
 	FindByEmail(ctx context.Context, email string, c *gin.Context) (dto.UserResponse, error)
 	FindByID(ctx context.Context, id string, c *gin.Context) (dto.UserResponse, error)
-	FindAll(ctx context.Context, c *gin.Context) ([]dto.UserResponse, error)
+	FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.UserResponse, web.Paging, error)
 	Update(ctx context.Context, request dto.UpdateUserRequest, c *gin.Context) (dto.UserResponse, error)
 	Delete(ctx context.Context, id string, c *gin.Context) error
 }
\ No newline at end of file
@@ -7,9 +7,11 @@ import (
 	"bioskuy/auth"
 	"bioskuy/exception"
 	"bioskuy/helper"
+	"bioskuy/logger"
+	"bioskuy/web"
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -66,7 +68,7 @@ func (s *userService) Login(ctx context.Context, request dto.CreateUserRequest,
 
 	}
 
-	fmt.Println(result)
+	logger.FromContext(ctx).Info("user signed in", "user_id", result.ID, "email", result.Email)
 
 	Token, err := s.Jwt.GenerateToken(result, c)
 	if err != nil {
@@ -109,6 +111,7 @@ func (s *userService) FindByEmail(ctx context.Context, email string, c *gin.Cont
 	UserResponse.Name = result.Name
 	UserResponse.Email = result.Email
 	UserResponse.Role = result.Role
+	UserResponse.CinemaID = result.CinemaID
 
 	return UserResponse, nil
 }
@@ -134,24 +137,25 @@ func (s *userService) FindByID(ctx context.Context, id string, c *gin.Context) (
 	UserResponse.Name = result.Name
 	UserResponse.Email = result.Email
 	UserResponse.Role = result.Role
+	UserResponse.CinemaID = result.CinemaID
 
 	return UserResponse, nil
 }
 
-func (s *userService) FindAll(ctx context.Context, c *gin.Context) ([]dto.UserResponse, error) {
+func (s *userService) FindAll(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.UserResponse, web.Paging, error) {
 	UserResponses := []dto.UserResponse{}
 
 	tx, err := s.DB.Begin()
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return UserResponses, err
+		return UserResponses, web.Paging{}, err
 	}
 	defer helper.CommitAndRollback(tx, c)
 
-	result, err := s.Repo.FindAll(ctx, tx, c)
+	result, total, err := s.Repo.FindAll(ctx, tx, query, c)
 	if err != nil {
 		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return UserResponses, err
+		return UserResponses, web.Paging{}, err
 	}
 
 	for _, category := range result {
@@ -161,12 +165,13 @@ func (s *userService) FindAll(ctx context.Context, c *gin.Context) ([]dto.UserRe
 		UserResponse.Name = category.Name
 		UserResponse.Email = category.Email
 		UserResponse.Role = category.Role
+		UserResponse.CinemaID = category.CinemaID
 
 		UserResponses = append(UserResponses, UserResponse)
 
 	}
 
-	return UserResponses, nil
+	return UserResponses, query.Paging(total), nil
 }
 
 func (s *userService) Update(ctx context.Context, request dto.UpdateUserRequest, c *gin.Context) (dto.UserResponse, error) {
@@ -199,6 +204,15 @@ func (s *userService) Update(ctx context.Context, request dto.UpdateUserRequest,
 		user.Role = request.Role
 	}
 
+	if user.Role == entity.RoleBranchAdmin || user.Role == entity.RoleStaff {
+		if request.CinemaID == "" {
+			err := errors.New("cinema_id is required for a " + user.Role)
+			c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return UserResponse, err
+		}
+		user.CinemaID = request.CinemaID
+	}
+
 	result, err := s.Repo.Update(ctx, tx, user, c)
 	if err != nil {
 		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
@@ -209,6 +223,7 @@ func (s *userService) Update(ctx context.Context, request dto.UpdateUserRequest,
 	UserResponse.Name = resultCustomer.Name
 	UserResponse.Email = resultCustomer.Email
 	UserResponse.Role = result.Role
+	UserResponse.CinemaID = result.CinemaID
 
 	return UserResponse, nil
 }
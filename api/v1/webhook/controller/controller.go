@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController interface {
+	Create(c *gin.Context)
+	FindAll(c *gin.Context)
+	FindByID(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	FindDeliveries(c *gin.Context)
+}
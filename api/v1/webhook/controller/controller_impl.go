@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"bioskuy/api/v1/webhook/dto"
+	"bioskuy/api/v1/webhook/service"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type webhookControllerImpl struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) WebhookController {
+	return &webhookControllerImpl{webhookService: webhookService}
+}
+
+func (ctl *webhookControllerImpl) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.CreateSubscriptionRequest{}
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, err := ctl.webhookService.Create(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusCreated, web.FormatResponse{ResponseCode: http.StatusCreated, Data: result})
+}
+
+func (ctl *webhookControllerImpl) FindAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.webhookService.FindAll(ctx, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *webhookControllerImpl) FindByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := ctl.webhookService.FindByID(ctx, c.Param("subscriptionId"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *webhookControllerImpl) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	request := dto.UpdateSubscriptionRequest{}
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+	request.ID = c.Param("subscriptionId")
+
+	result, err := ctl.webhookService.Update(ctx, request, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: result})
+}
+
+func (ctl *webhookControllerImpl) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := ctl.webhookService.Delete(ctx, c.Param("subscriptionId"), c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponse{ResponseCode: http.StatusOK, Data: "OK"})
+}
+
+// FindDeliveries lists deliveries, newest first by default. They can be
+// filtered by subscription_id, event_type, event_id and
+// status=pending|delivered|failed.
+func (ctl *webhookControllerImpl) FindDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := helper.ListQueryFrom(c, "subscription_id", "event_type", "event_id", "status")
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return
+	}
+	if c.Query("order") == "" {
+		query.Desc = true
+	}
+
+	result, paging, err := ctl.webhookService.FindDeliveries(ctx, query, c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, web.FormatResponsePaging{ResponseCode: http.StatusOK, Data: result, Paging: paging})
+}
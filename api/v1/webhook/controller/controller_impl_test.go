@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"bioskuy/api/v1/webhook/dto"
+	"bioskuy/api/v1/webhook/mock/servicemock"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookControllerTestSuite struct {
+	suite.Suite
+	mockService *servicemock.MockWebhookService
+	controller  WebhookController
+	router      *gin.Engine
+}
+
+func (suite *WebhookControllerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockService = new(servicemock.MockWebhookService)
+	suite.controller = NewWebhookController(suite.mockService)
+	suite.router = gin.New()
+	suite.router.Use(exception.ErrorHandler)
+
+	suite.router.POST("/webhooks", suite.controller.Create)
+	suite.router.GET("/webhooks/deliveries", suite.controller.FindDeliveries)
+	suite.router.PUT("/webhooks/:subscriptionId", suite.controller.Update)
+	suite.router.DELETE("/webhooks/:subscriptionId", suite.controller.Delete)
+}
+
+func TestWebhookControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookControllerTestSuite))
+}
+
+func (suite *WebhookControllerTestSuite) TestCreate_Success() {
+	request := dto.CreateSubscriptionRequest{URL: "https://crm.example.com/hooks", EventTypes: []string{"payment.paid"}}
+	suite.mockService.On("Create", mock.Anything, request, mock.Anything).Return(dto.SubscriptionResponse{ID: "subscription-1", Secret: "generated"}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"secret":"generated"`)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookControllerTestSuite) TestCreate_BindError() {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString("{"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookControllerTestSuite) TestUpdate_UsesPathID() {
+	suite.mockService.On("Update", mock.Anything, mock.MatchedBy(func(r dto.UpdateSubscriptionRequest) bool {
+		return r.ID == "subscription-1" && r.Active != nil && !*r.Active
+	}), mock.Anything).Return(dto.SubscriptionResponse{ID: "subscription-1"}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/webhooks/subscription-1", bytes.NewBufferString(`{"active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookControllerTestSuite) TestDelete_NotFound() {
+	suite.mockService.On("Delete", mock.Anything, "missing", mock.Anything).Return(errors.New("webhook subscription not found")).Run(func(args mock.Arguments) {
+		c := args.Get(2).(*gin.Context)
+		c.Error(exception.NotFoundError{Message: "webhook subscription not found"}).SetType(gin.ErrorTypePublic)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/webhooks/missing", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *WebhookControllerTestSuite) TestFindDeliveries_NewestFirstByDefault() {
+	suite.mockService.On("FindDeliveries", mock.Anything, mock.MatchedBy(func(q helper.ListQuery) bool {
+		return q.Desc && q.Filters["status"] == "failed"
+	}), mock.Anything).Return([]dto.DeliveryResponse{{ID: "delivery-1", Status: "failed"}}, web.Paging{Page: 1, Size: 10, TotalData: 1, TotalPages: 1}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?status=failed", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"total-data":1`)
+	suite.mockService.AssertExpectations(suite.T())
+}
@@ -0,0 +1,220 @@
+package dispatcher
+
+import (
+	"bioskuy/api/v1/webhook/dto"
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/api/v1/webhook/repository"
+	"bioskuy/helper"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SignatureHeader = "X-Bioskuy-Signature"
+	EventHeader     = "X-Bioskuy-Event"
+	DeliveryHeader  = "X-Bioskuy-Delivery"
+)
+
+const (
+	batchSize   = 50
+	firstRetry  = 30 * time.Second
+	maxRetry    = 6 * time.Hour
+	sendTimeout = 10 * time.Second
+	// claimLease covers a whole batch, whose deliveries are sent one after
+	// another, plus a send's worth of time to record the outcomes.
+	claimLease = (batchSize + 1) * sendTimeout
+)
+
+// Dispatcher periodically POSTs due outbox deliveries to their subscribers.
+// Failed deliveries are retried with exponential backoff until MaxAttempts.
+type Dispatcher struct {
+	Repo        repository.OutboxRepository
+	DB          *sql.DB
+	Client      *http.Client
+	Interval    time.Duration
+	MaxAttempts int
+	Now         func() time.Time
+}
+
+func NewDispatcher(repo repository.OutboxRepository, DB *sql.DB, interval time.Duration, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		Repo:        repo,
+		DB:          DB,
+		Client:      &http.Client{Timeout: sendTimeout},
+		Interval:    interval,
+		MaxAttempts: maxAttempts,
+		Now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Start runs Dispatch every Interval in its own goroutine until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				delivered, err := d.Dispatch(ctx)
+				if err != nil {
+					slog.Error("webhook dispatch failed", "error", err)
+					continue
+				}
+				if delivered > 0 {
+					slog.Info("delivered webhook events", "delivered", delivered)
+				}
+			}
+		}
+	}()
+}
+
+// Dispatch sends one batch of due deliveries and returns how many were
+// accepted. Deliveries are claimed in their own transaction before sending,
+// so a slow subscriber holds no locks and other dispatchers skip them.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	now := d.Now()
+
+	deliveries, err := d.claim(ctx, now)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	errs := map[string]error{}
+	for _, delivery := range deliveries {
+		errs[delivery.ID] = d.send(ctx, delivery)
+	}
+
+	return d.record(ctx, deliveries, errs)
+}
+
+func (d *Dispatcher) claim(ctx context.Context, now time.Time) ([]entity.Delivery, error) {
+	c := &gin.Context{}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	// The claim lasts as long as the batch may take to send, after which a
+	// delivery whose outcome was never recorded becomes due again.
+	deliveries, err := d.Repo.ClaimDue(ctx, tx, now, now.Add(claimLease), batchSize, c)
+	if err != nil {
+		c.Error(err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (d *Dispatcher) record(ctx context.Context, deliveries []entity.Delivery, errs map[string]error) (int, error) {
+	c := &gin.Context{}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		sendErr := errs[delivery.ID]
+
+		switch {
+		case sendErr == nil:
+			err = d.Repo.MarkDelivered(ctx, tx, delivery.ID, c)
+			delivered++
+		case delivery.Attempts >= d.MaxAttempts:
+			err = d.Repo.Abandon(ctx, tx, delivery.ID, sendErr.Error(), c)
+		default:
+			err = d.Repo.Reschedule(ctx, tx, delivery.ID, sendErr.Error(), d.Now().Add(Backoff(delivery.Attempts)), c)
+		}
+		if err != nil {
+			c.Error(err)
+			return 0, err
+		}
+	}
+
+	return delivered, nil
+}
+
+// send POSTs the event to the subscriber, signed with the time it is sent at
+// so late sends in a slow batch are not taken for replays. Any 2xx response
+// counts as delivered.
+func (d *Dispatcher) send(ctx context.Context, delivery entity.Delivery) error {
+	body, err := json.Marshal(dto.EventMessage{
+		ID:        delivery.Event.ID,
+		Type:      delivery.Event.Type,
+		CreatedAt: delivery.Event.CreatedAt,
+		Data:      delivery.Event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.Event.Type)
+	request.Header.Set(DeliveryHeader, delivery.ID)
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, d.Now(), body))
+
+	response, err := d.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("subscriber responded %s", response.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the signature header of a delivery body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" under secret>".
+// Subscribers recompute it to check the event came from us, and reject old
+// timestamps to stop replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(t + "."))
+	h.Write(body)
+
+	return "t=" + t + ",v1=" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Backoff is how long to wait after the given number of failed attempts:
+// 30 seconds doubling each time, capped at 6 hours.
+func Backoff(attempts int) time.Duration {
+	wait := firstRetry
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= maxRetry {
+			return maxRetry
+		}
+	}
+
+	return wait
+}
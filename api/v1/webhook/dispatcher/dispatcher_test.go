@@ -0,0 +1,202 @@
+package dispatcher
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/api/v1/webhook/mock/repomock"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DispatcherTestSuite struct {
+	suite.Suite
+	repo       *repomock.MockOutboxRepository
+	mockDb     *sql.DB
+	mockSql    sqlmock.Sqlmock
+	dispatcher *Dispatcher
+	now        time.Time
+}
+
+func (suite *DispatcherTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.repo = new(repomock.MockOutboxRepository)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.now = time.Date(2024, 8, 2, 9, 0, 0, 0, time.UTC)
+	suite.dispatcher = NewDispatcher(suite.repo, suite.mockDb, time.Second, 3)
+	suite.dispatcher.Now = func() time.Time { return suite.now }
+}
+
+func (suite *DispatcherTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestDispatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(DispatcherTestSuite))
+}
+
+func (suite *DispatcherTestSuite) delivery(url string, attempts int) entity.Delivery {
+	return entity.Delivery{
+		ID:       "delivery-1",
+		URL:      url,
+		Secret:   "subscriber-secret",
+		Attempts: attempts,
+		Event: entity.Event{
+			ID:        "event-1",
+			Type:      entity.EventPaymentPaid,
+			Payload:   json.RawMessage(`{"id":"payment-1","status":"paid"}`),
+			CreatedAt: suite.now.Add(-time.Minute),
+		},
+	}
+}
+
+func (suite *DispatcherTestSuite) expectClaim(deliveries []entity.Delivery) {
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("ClaimDue", mock.Anything, mock.Anything, suite.now, suite.now.Add(claimLease), batchSize, mock.Anything).Return(deliveries, nil)
+	suite.mockSql.ExpectCommit()
+}
+
+func (suite *DispatcherTestSuite) TestDispatch_DeliversSignedEvent() {
+	var body []byte
+	var header http.Header
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer subscriber.Close()
+
+	suite.expectClaim([]entity.Delivery{suite.delivery(subscriber.URL, 1)})
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("MarkDelivered", mock.Anything, mock.Anything, "delivery-1", mock.Anything).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	delivered, err := suite.dispatcher.Dispatch(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, delivered)
+	assert.JSONEq(suite.T(), `{"id":"event-1","type":"payment.paid","created_at":"2024-08-02T08:59:00Z","data":{"id":"payment-1","status":"paid"}}`, string(body))
+	assert.Equal(suite.T(), "payment.paid", header.Get(EventHeader))
+	assert.Equal(suite.T(), "delivery-1", header.Get(DeliveryHeader))
+	assert.Equal(suite.T(), Sign("subscriber-secret", suite.now, body), header.Get(SignatureHeader))
+	suite.repo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *DispatcherTestSuite) TestDispatch_SignsWithSendTime() {
+	sentAt := suite.now.Add(5 * time.Minute)
+	calls := 0
+	suite.dispatcher.Now = func() time.Time {
+		calls++
+		if calls == 1 {
+			return suite.now
+		}
+		return sentAt
+	}
+
+	var body []byte
+	var header http.Header
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer subscriber.Close()
+
+	suite.expectClaim([]entity.Delivery{suite.delivery(subscriber.URL, 1)})
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("MarkDelivered", mock.Anything, mock.Anything, "delivery-1", mock.Anything).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	_, err := suite.dispatcher.Dispatch(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), Sign("subscriber-secret", sentAt, body), header.Get(SignatureHeader))
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *DispatcherTestSuite) TestDispatch_ReschedulesFailedDelivery() {
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer subscriber.Close()
+
+	suite.expectClaim([]entity.Delivery{suite.delivery(subscriber.URL, 2)})
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("Reschedule", mock.Anything, mock.Anything, "delivery-1", "subscriber responded 502 Bad Gateway", suite.now.Add(time.Minute), mock.Anything).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	delivered, err := suite.dispatcher.Dispatch(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, delivered)
+	suite.repo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *DispatcherTestSuite) TestDispatch_AbandonsAfterMaxAttempts() {
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer subscriber.Close()
+
+	suite.expectClaim([]entity.Delivery{suite.delivery(subscriber.URL, 3)})
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("Abandon", mock.Anything, mock.Anything, "delivery-1", "subscriber responded 500 Internal Server Error", mock.Anything).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	_, err := suite.dispatcher.Dispatch(context.Background())
+
+	assert.NoError(suite.T(), err)
+	suite.repo.AssertNotCalled(suite.T(), "Reschedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.repo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *DispatcherTestSuite) TestDispatch_NothingDue() {
+	suite.expectClaim([]entity.Delivery{})
+
+	delivered, err := suite.dispatcher.Dispatch(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, delivered)
+	suite.repo.AssertNotCalled(suite.T(), "MarkDelivered", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *DispatcherTestSuite) TestDispatch_ClaimErrorRollsBack() {
+	suite.mockSql.ExpectBegin()
+	suite.repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]entity.Delivery{}, errors.New("claim error"))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.dispatcher.Dispatch(context.Background())
+
+	assert.Error(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *DispatcherTestSuite) TestSign() {
+	signature := Sign("secret", time.Unix(1722589200, 0), []byte(`{"id":"event-1"}`))
+
+	assert.Equal(suite.T(), "t=1722589200,v1=ebb78f799e7308f83f33be9270f9e86bb823037d55f810ff7b6d6f902377e253", signature)
+}
+
+func (suite *DispatcherTestSuite) TestBackoff() {
+	assert.Equal(suite.T(), 30*time.Second, Backoff(1))
+	assert.Equal(suite.T(), time.Minute, Backoff(2))
+	assert.Equal(suite.T(), 4*time.Minute, Backoff(4))
+	assert.Equal(suite.T(), 6*time.Hour, Backoff(20))
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	entityPayment "bioskuy/api/v1/payment/entity"
+	"encoding/json"
+	"time"
+)
+
+// CreateSubscriptionRequest leaves Secret empty to have one generated.
+type CreateSubscriptionRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types" validate:"required,min=1"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16"`
+}
+
+// UpdateSubscriptionRequest only changes the fields that are set.
+type UpdateSubscriptionRequest struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url" validate:"omitempty,url"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// SubscriptionResponse only carries the secret when the subscription is
+// created.
+type SubscriptionResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type DeliveryResponse struct {
+	ID            string     `json:"id"`
+	EventID       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// EventMessage is the body POSTed to subscribers.
+type EventMessage struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// SeatBookingData is the data of seatbooking events.
+type SeatBookingData struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	ShowtimeID    string    `json:"showtime_id"`
+	SeatIDs       []string  `json:"seat_ids"`
+	TotalPrice    int       `json:"total_price"`
+	HoldExpiresAt time.Time `json:"hold_expires_at"`
+}
+
+// PaymentData is the data of payment events. RefundedSeatIDs is set on
+// refunds made through the refund endpoint.
+type PaymentData struct {
+	ID              string   `json:"id"`
+	UserID          string   `json:"user_id"`
+	SeatBookingID   string   `json:"seatbooking_id"`
+	Status          string   `json:"status"`
+	PreviousStatus  string   `json:"previous_status"`
+	TotalPrice      int      `json:"total_price"`
+	RefundedSeatIDs []string `json:"refunded_seat_ids,omitempty"`
+	RefundedAmount  int      `json:"refunded_amount,omitempty"`
+}
+
+// FailedPaymentData is the data of the payment.failed event of a payment
+// failed because its booking was released.
+func FailedPaymentData(payment entityPayment.FailedPayment) PaymentData {
+	return PaymentData{
+		ID:             payment.ID,
+		UserID:         payment.UserID,
+		SeatBookingID:  payment.SeatBookingID,
+		Status:         entityPayment.PaymentStatusFailed,
+		PreviousStatus: payment.PreviousStatus,
+		TotalPrice:     payment.TotalPrice,
+	}
+}
+
+// ShowtimeData is the data of showtime events. Showtimes with bookings are
+// cancelled rather than deleted, and their sold seats flagged for refund.
+type ShowtimeData struct {
+	ID                    string    `json:"id"`
+	MovieID               string    `json:"movie_id"`
+	StudioID              string    `json:"studio_id"`
+	CinemaID              string    `json:"cinema_id"`
+	ShowStart             time.Time `json:"show_start"`
+	SeatsFlaggedForRefund int       `json:"seats_flagged_for_refund"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Delivery is one event sent to one subscription. URL and Secret are the
+// subscription's, filled in when the delivery is claimed for sending.
+type Delivery struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	Event          Event      `json:"event"`
+	URL            string     `json:"url"`
+	Secret         string     `json:"secret"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	// FailedAt is set once the delivery has run out of attempts.
+	FailedAt  *time.Time `json:"failed_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (d Delivery) Status() string {
+	if d.DeliveredAt != nil {
+		return DeliveryStatusDelivered
+	}
+	if d.FailedAt != nil {
+		return DeliveryStatusFailed
+	}
+	return DeliveryStatusPending
+}
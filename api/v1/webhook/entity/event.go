@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventSeatBookingCreated       = "seatbooking.created"
+	EventSeatBookingExpired       = "seatbooking.expired"
+	EventSeatBookingCancelled     = "seatbooking.cancelled"
+	EventPaymentPending           = "payment.pending"
+	EventPaymentPaid              = "payment.paid"
+	EventPaymentFailed            = "payment.failed"
+	EventPaymentRefunded          = "payment.refunded"
+	EventPaymentPartiallyRefunded = "payment.partially_refunded"
+	EventShowtimeDeleted          = "showtime.deleted"
+	EventShowtimeCancelled        = "showtime.cancelled"
+)
+
+// EventTypes lists every event type a subscription can ask for.
+var EventTypes = []string{
+	EventSeatBookingCreated,
+	EventSeatBookingExpired,
+	EventSeatBookingCancelled,
+	EventPaymentPending,
+	EventPaymentPaid,
+	EventPaymentFailed,
+	EventPaymentRefunded,
+	EventPaymentPartiallyRefunded,
+	EventShowtimeDeleted,
+	EventShowtimeCancelled,
+}
+
+func IsEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentEventType is the event a payment moving to status publishes.
+func PaymentEventType(status string) string {
+	return "payment." + status
+}
+
+// Event is a lifecycle change recorded in the outbox. Payload is its data as
+// JSON.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Subscription receives the listed event types at URL. Every delivery is
+// signed with Secret.
+type Subscription struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Publish(ctx context.Context, tx *sql.Tx, eventType string, data interface{}, c *gin.Context) error {
+	args := m.Called(ctx, tx, eventType, data, c)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ClaimDue(ctx context.Context, tx *sql.Tx, now time.Time, leaseUntil time.Time, limit int, c *gin.Context) ([]entity.Delivery, error) {
+	args := m.Called(ctx, tx, now, leaseUntil, limit, c)
+	return args.Get(0).([]entity.Delivery), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Reschedule(ctx context.Context, tx *sql.Tx, id string, lastError string, nextAttemptAt time.Time, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, lastError, nextAttemptAt, c)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Abandon(ctx context.Context, tx *sql.Tx, id string, lastError string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, lastError, c)
+	return args.Error(0)
+}
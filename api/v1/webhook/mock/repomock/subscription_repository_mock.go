@@ -0,0 +1,45 @@
+package repomock
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionRepository) Save(ctx context.Context, tx *sql.Tx, subscription entity.Subscription, c *gin.Context) (entity.Subscription, error) {
+	args := m.Called(ctx, tx, subscription, c)
+	return args.Get(0).(entity.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Subscription, error) {
+	args := m.Called(ctx, tx, id, c)
+	return args.Get(0).(entity.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Subscription, error) {
+	args := m.Called(ctx, tx, c)
+	return args.Get(0).([]entity.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Update(ctx context.Context, tx *sql.Tx, subscription entity.Subscription, c *gin.Context) (entity.Subscription, error) {
+	args := m.Called(ctx, tx, subscription, c)
+	return args.Get(0).(entity.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	args := m.Called(ctx, tx, id, c)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) FindDeliveries(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Delivery, int, error) {
+	args := m.Called(ctx, tx, query, c)
+	return args.Get(0).([]entity.Delivery), args.Int(1), args.Error(2)
+}
@@ -0,0 +1,45 @@
+package servicemock
+
+import (
+	"bioskuy/api/v1/webhook/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Create(ctx context.Context, request dto.CreateSubscriptionRequest, c *gin.Context) (dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.SubscriptionResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) FindAll(ctx context.Context, c *gin.Context) ([]dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).([]dto.SubscriptionResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) FindByID(ctx context.Context, id string, c *gin.Context) (dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, id, c)
+	return args.Get(0).(dto.SubscriptionResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) Update(ctx context.Context, request dto.UpdateSubscriptionRequest, c *gin.Context) (dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, request, c)
+	return args.Get(0).(dto.SubscriptionResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) Delete(ctx context.Context, id string, c *gin.Context) error {
+	args := m.Called(ctx, id, c)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) FindDeliveries(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.DeliveryResponse, web.Paging, error) {
+	args := m.Called(ctx, query, c)
+	return args.Get(0).([]dto.DeliveryResponse), args.Get(1).(web.Paging), args.Error(2)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/exception"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type outboxRepository struct {
+}
+
+func NewOutboxRepository() OutboxRepository {
+	return &outboxRepository{}
+}
+
+// Publish records the event and queues a delivery to every active
+// subscription that asked for its type. It must run in the transaction of
+// the change it describes.
+func (r *outboxRepository) Publish(ctx context.Context, tx *sql.Tx, eventType string, data interface{}, c *gin.Context) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	query := `WITH event AS (
+			INSERT INTO outbox_events (event_type, payload) VALUES ($1, $2) RETURNING id
+		)
+		INSERT INTO webhook_deliveries (event_id, subscription_id)
+		SELECT event.id, s.id FROM event
+		JOIN webhook_subscriptions s ON s.active AND $1 = ANY(s.event_types)`
+
+	_, err = tx.ExecContext(ctx, query, eventType, string(payload))
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDue takes up to limit pending deliveries of active subscriptions that
+// are due at now, counts an attempt on each and holds them until leaseUntil.
+// A delivery whose sender dies before recording the outcome is retried once
+// the lease runs out. Rows claimed by another dispatcher are skipped.
+func (r *outboxRepository) ClaimDue(ctx context.Context, tx *sql.Tx, now time.Time, leaseUntil time.Time, limit int, c *gin.Context) ([]entity.Delivery, error) {
+	query := `UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = $2
+		FROM outbox_events e, webhook_subscriptions s
+		WHERE d.id IN (
+			SELECT due.id FROM webhook_deliveries due
+			JOIN webhook_subscriptions active ON due.subscription_id = active.id
+			WHERE active.active AND due.delivered_at IS NULL AND due.failed_at IS NULL AND due.next_attempt_at <= $1
+			ORDER BY due.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF due SKIP LOCKED
+		)
+		AND e.id = d.event_id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, d.attempts, d.next_attempt_at, d.created_at,
+			e.id, e.event_type, e.payload, e.created_at, s.url, s.secret`
+
+	rows, err := tx.QueryContext(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.Delivery{}
+	for rows.Next() {
+		delivery := entity.Delivery{}
+		var payload []byte
+
+		err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt,
+			&delivery.Event.ID, &delivery.Event.Type, &payload, &delivery.Event.CreatedAt, &delivery.URL, &delivery.Secret)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		delivery.Event.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	query := `UPDATE webhook_deliveries SET delivered_at = (NOW() AT TIME ZONE 'UTC'), last_error = NULL WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// Reschedule records a failed attempt and when to try again.
+func (r *outboxRepository) Reschedule(ctx context.Context, tx *sql.Tx, id string, lastError string, nextAttemptAt time.Time, c *gin.Context) error {
+	query := `UPDATE webhook_deliveries SET last_error = $2, next_attempt_at = $3 WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, lastError, nextAttemptAt)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// Abandon records the last failed attempt of a delivery that is not retried.
+func (r *outboxRepository) Abandon(ctx context.Context, tx *sql.Tx, id string, lastError string, c *gin.Context) error {
+	query := `UPDATE webhook_deliveries SET last_error = $2, failed_at = (NOW() AT TIME ZONE 'UTC') WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, lastError)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
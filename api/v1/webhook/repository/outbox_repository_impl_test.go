@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxRepositoryTestSuite struct {
+	suite.Suite
+	mockDb  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    OutboxRepository
+	ctx     context.Context
+	ginCtx  *gin.Context
+}
+
+var dispatchedAt = time.Date(2024, 8, 2, 9, 0, 0, 0, time.UTC)
+
+func (suite *OutboxRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.repo = NewOutboxRepository()
+	suite.ctx = context.Background()
+	suite.ginCtx = &gin.Context{}
+}
+
+func (suite *OutboxRepositoryTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestOutboxRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxRepositoryTestSuite))
+}
+
+func (suite *OutboxRepositoryTestSuite) begin() *sql.Tx {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+	return tx
+}
+
+func (suite *OutboxRepositoryTestSuite) TestPublish_QueuesDeliveriesForSubscribers() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox_events (event_type, payload) VALUES ($1, $2) RETURNING id`)+`.*`+regexp.QuoteMeta(`$1 = ANY(s.event_types)`)).
+		WithArgs(entity.EventPaymentPaid, `{"id":"payment-1"}`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.Publish(suite.ctx, tx, entity.EventPaymentPaid, map[string]string{"id": "payment-1"}, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *OutboxRepositoryTestSuite) TestPublish_Error() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox_events`)).WillReturnError(errors.New("insert error"))
+
+	err := suite.repo.Publish(suite.ctx, tx, entity.EventPaymentPaid, map[string]string{"id": "payment-1"}, suite.ginCtx)
+
+	assert.EqualError(suite.T(), err, "insert error")
+	assert.Len(suite.T(), suite.ginCtx.Errors, 1)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestClaimDue() {
+	tx := suite.begin()
+	leaseUntil := dispatchedAt.Add(20 * time.Second)
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`UPDATE webhook_deliveries d`)+`.*`+regexp.QuoteMeta(`FOR UPDATE OF due SKIP LOCKED`)).
+		WithArgs(dispatchedAt, leaseUntil, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "attempts", "next_attempt_at", "created_at", "id", "event_type", "payload", "created_at", "url", "secret"}).
+			AddRow("delivery-1", "subscription-1", 1, leaseUntil, dispatchedAt, "event-1", entity.EventPaymentPaid, []byte(`{"id":"payment-1"}`), dispatchedAt, "https://crm.example.com/hooks", "secret"))
+
+	deliveries, err := suite.repo.ClaimDue(suite.ctx, tx, dispatchedAt, leaseUntil, 50, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), deliveries, 1)
+	assert.Equal(suite.T(), 1, deliveries[0].Attempts)
+	assert.Equal(suite.T(), "https://crm.example.com/hooks", deliveries[0].URL)
+	assert.JSONEq(suite.T(), `{"id":"payment-1"}`, string(deliveries[0].Event.Payload))
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *OutboxRepositoryTestSuite) TestReschedule() {
+	tx := suite.begin()
+	next := dispatchedAt.Add(time.Minute)
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE webhook_deliveries SET last_error = $2, next_attempt_at = $3 WHERE id = $1`)).
+		WithArgs("delivery-1", "subscriber responded 502 Bad Gateway", next).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Reschedule(suite.ctx, tx, "delivery-1", "subscriber responded 502 Bad Gateway", next, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *OutboxRepositoryTestSuite) TestAbandon() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectExec(regexp.QuoteMeta(`UPDATE webhook_deliveries SET last_error = $2, failed_at = (NOW() AT TIME ZONE 'UTC') WHERE id = $1`)).
+		WithArgs("delivery-1", "connection refused").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Abandon(suite.ctx, tx, "delivery-1", "connection refused", suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OutboxRepository records lifecycle events and tracks their delivery.
+type OutboxRepository interface {
+	Publish(ctx context.Context, tx *sql.Tx, eventType string, data interface{}, c *gin.Context) error
+	ClaimDue(ctx context.Context, tx *sql.Tx, now time.Time, leaseUntil time.Time, limit int, c *gin.Context) ([]entity.Delivery, error)
+	MarkDelivered(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	Reschedule(ctx context.Context, tx *sql.Tx, id string, lastError string, nextAttemptAt time.Time, c *gin.Context) error
+	Abandon(ctx context.Context, tx *sql.Tx, id string, lastError string, c *gin.Context) error
+}
+
+type SubscriptionRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, subscription entity.Subscription, c *gin.Context) (entity.Subscription, error)
+	FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Subscription, error)
+	FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Subscription, error)
+	Update(ctx context.Context, tx *sql.Tx, subscription entity.Subscription, c *gin.Context) (entity.Subscription, error)
+	Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error
+	FindDeliveries(ctx context.Context, tx *sql.Tx, query helper.ListQuery, c *gin.Context) ([]entity.Delivery, int, error)
+}
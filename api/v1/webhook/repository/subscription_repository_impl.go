@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type subscriptionRepository struct {
+}
+
+func NewSubscriptionRepository() SubscriptionRepository {
+	return &subscriptionRepository{}
+}
+
+const subscriptionColumns = `id, url, secret, event_types, active, created_at, updated_at`
+
+func (r *subscriptionRepository) Save(ctx context.Context, tx *sql.Tx, subscription entity.Subscription, c *gin.Context) (entity.Subscription, error) {
+	query := `INSERT INTO webhook_subscriptions (url, secret, event_types, active)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		subscription.URL, subscription.Secret, pq.Array(subscription.EventTypes), subscription.Active,
+	).Scan(&subscription.ID, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscription, err
+	}
+
+	return subscription, nil
+}
+
+func (r *subscriptionRepository) FindByID(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) (entity.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id::text = $1`
+
+	subscription, err := scanSubscription(tx.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return subscription, errors.New("webhook subscription not found")
+	}
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscription, err
+	}
+
+	return subscription, nil
+}
+
+func (r *subscriptionRepository) FindAll(ctx context.Context, tx *sql.Tx, c *gin.Context) ([]entity.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions ORDER BY created_at, id`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []entity.Subscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *subscriptionRepository) Update(ctx context.Context, tx *sql.Tx, subscription entity.Subscription, c *gin.Context) (entity.Subscription, error) {
+	query := `UPDATE webhook_subscriptions SET url = $1, event_types = $2, active = $3, updated_at = (NOW() AT TIME ZONE 'UTC')
+		WHERE id = $4 RETURNING updated_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		subscription.URL, pq.Array(subscription.EventTypes), subscription.Active, subscription.ID,
+	).Scan(&subscription.UpdatedAt)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscription, err
+	}
+
+	return subscription, nil
+}
+
+// Delete removes the subscription together with its deliveries.
+func (r *subscriptionRepository) Delete(ctx context.Context, tx *sql.Tx, id string, c *gin.Context) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return nil
+}
+
+// deliveryListSpec is what the delivery log can be sorted and filtered by.
+var deliveryListSpec = helper.ListSpec{
+	Sorts: map[string]string{
+		"created_at":      "d.created_at",
+		"next_attempt_at": "d.next_attempt_at",
+	},
+	DefaultSort: "created_at",
+	Key:         "d.id",
+	Filters: map[string]string{
+		"subscription_id": "d.subscription_id::text",
+		"event_type":      "e.event_type",
+		"event_id":        "e.id::text",
+	},
+	Scopes: map[string]map[string]string{
+		"status": {
+			entity.DeliveryStatusPending:   "d.delivered_at IS NULL AND d.failed_at IS NULL",
+			entity.DeliveryStatusDelivered: "d.delivered_at IS NOT NULL",
+			entity.DeliveryStatusFailed:    "d.failed_at IS NOT NULL",
+		},
+	},
+}
+
+func (r *subscriptionRepository) FindDeliveries(ctx context.Context, tx *sql.Tx, listQuery helper.ListQuery, c *gin.Context) ([]entity.Delivery, int, error) {
+	clauses, err := listQuery.Clauses(deliveryListSpec)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	joins := ` FROM webhook_deliveries d JOIN outbox_events e ON d.event_id = e.id`
+
+	total := 0
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*)`+joins+` `+clauses.Where, clauses.Args...).Scan(&total); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	query := `SELECT d.id, d.subscription_id, d.attempts, d.next_attempt_at, d.last_error, d.delivered_at, d.failed_at, d.created_at,
+		e.id, e.event_type, e.created_at` + joins + `
+		` + clauses.Where + ` ` + clauses.Page
+
+	rows, err := tx.QueryContext(ctx, query, clauses.AllArgs()...)
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.Delivery{}
+	for rows.Next() {
+		delivery := entity.Delivery{}
+		var lastError sql.NullString
+		var deliveredAt, failedAt sql.NullTime
+
+		err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.Attempts, &delivery.NextAttemptAt, &lastError, &deliveredAt, &failedAt, &delivery.CreatedAt,
+			&delivery.Event.ID, &delivery.Event.Type, &delivery.Event.CreatedAt)
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return nil, 0, err
+		}
+		delivery.LastError = lastError.String
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		if failedAt.Valid {
+			delivery.FailedAt = &failedAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscription(row scanner) (entity.Subscription, error) {
+	subscription := entity.Subscription{}
+
+	err := row.Scan(
+		&subscription.ID, &subscription.URL, &subscription.Secret, pq.Array(&subscription.EventTypes),
+		&subscription.Active, &subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+
+	return subscription, err
+}
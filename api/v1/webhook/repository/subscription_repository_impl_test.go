@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SubscriptionRepositoryTestSuite struct {
+	suite.Suite
+	mockDb  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    SubscriptionRepository
+	ctx     context.Context
+	ginCtx  *gin.Context
+}
+
+func (suite *SubscriptionRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.repo = NewSubscriptionRepository()
+	suite.ctx = context.Background()
+	suite.ginCtx = &gin.Context{}
+}
+
+func (suite *SubscriptionRepositoryTestSuite) TearDownTest() {
+	suite.mockDb.Close()
+}
+
+func TestSubscriptionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionRepositoryTestSuite))
+}
+
+func (suite *SubscriptionRepositoryTestSuite) begin() *sql.Tx {
+	suite.mockSql.ExpectBegin()
+	tx, err := suite.mockDb.Begin()
+	assert.NoError(suite.T(), err)
+	return tx
+}
+
+func (suite *SubscriptionRepositoryTestSuite) TestSave() {
+	tx := suite.begin()
+	subscription := entity.Subscription{URL: "https://crm.example.com/hooks", Secret: "secret", EventTypes: []string{entity.EventPaymentPaid}, Active: true}
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`INSERT INTO webhook_subscriptions (url, secret, event_types, active)`)).
+		WithArgs("https://crm.example.com/hooks", "secret", pq.Array([]string{entity.EventPaymentPaid}), true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("subscription-1", dispatchedAt, dispatchedAt))
+
+	result, err := suite.repo.Save(suite.ctx, tx, subscription, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "subscription-1", result.ID)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SubscriptionRepositoryTestSuite) TestFindByID_NotFound() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`FROM webhook_subscriptions WHERE id::text = $1`)).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := suite.repo.FindByID(suite.ctx, tx, "missing", suite.ginCtx)
+
+	assert.EqualError(suite.T(), err, "webhook subscription not found")
+	assert.Empty(suite.T(), suite.ginCtx.Errors)
+}
+
+func (suite *SubscriptionRepositoryTestSuite) TestFindAll() {
+	tx := suite.begin()
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT id, url, secret, event_types, active, created_at, updated_at FROM webhook_subscriptions ORDER BY created_at, id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "event_types", "active", "created_at", "updated_at"}).
+			AddRow("subscription-1", "https://crm.example.com/hooks", "secret", "{payment.paid,showtime.cancelled}", true, dispatchedAt, dispatchedAt))
+
+	subscriptions, err := suite.repo.FindAll(suite.ctx, tx, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), subscriptions, 1)
+	assert.Equal(suite.T(), []string{entity.EventPaymentPaid, entity.EventShowtimeCancelled}, subscriptions[0].EventTypes)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SubscriptionRepositoryTestSuite) TestFindDeliveries_FailedBySubscription() {
+	tx := suite.begin()
+	query := helper.ListQuery{Page: 1, Size: 10, Desc: true}.With("subscription_id", "subscription-1").With("status", "failed")
+
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM webhook_deliveries d JOIN outbox_events e ON d.event_id = e.id`) + `.*` + regexp.QuoteMeta(`d.failed_at IS NOT NULL`)).
+		WithArgs("subscription-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT d.id, d.subscription_id`)+`.*`+regexp.QuoteMeta(`ORDER BY d.created_at DESC, d.id LIMIT`)).
+		WithArgs("subscription-1", 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "attempts", "next_attempt_at", "last_error", "delivered_at", "failed_at", "created_at", "id", "event_type", "created_at"}).
+			AddRow("delivery-1", "subscription-1", 8, dispatchedAt, "connection refused", nil, dispatchedAt, dispatchedAt, "event-1", entity.EventPaymentPaid, dispatchedAt))
+
+	deliveries, total, err := suite.repo.FindDeliveries(suite.ctx, tx, query, suite.ginCtx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	assert.Equal(suite.T(), entity.DeliveryStatusFailed, deliveries[0].Status())
+	assert.Equal(suite.T(), "connection refused", deliveries[0].LastError)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *SubscriptionRepositoryTestSuite) TestFindDeliveries_UnknownStatus() {
+	tx := suite.begin()
+	query := helper.ListQuery{Page: 1, Size: 10}.With("status", "lost")
+
+	_, _, err := suite.repo.FindDeliveries(suite.ctx, tx, query, suite.ginCtx)
+
+	assert.Error(suite.T(), err)
+	assert.Len(suite.T(), suite.ginCtx.Errors, 1)
+}
@@ -0,0 +1,36 @@
+package route
+
+import (
+	"bioskuy/api/v1/webhook/controller"
+	"bioskuy/api/v1/webhook/repository"
+	"bioskuy/api/v1/webhook/service"
+	"bioskuy/auth"
+	"bioskuy/helper"
+	"bioskuy/middleware"
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func WebhookRoute(router *gin.Engine, validate *validator.Validate, db *sql.DB, config *helper.Config) {
+
+	authService := auth.NewService(config)
+
+	subscriptionRepo := repository.NewSubscriptionRepository()
+	webhookService := service.NewWebhookService(subscriptionRepo, validate, db)
+	webhookController := controller.NewWebhookController(webhookService)
+
+	v1 := router.Group("/api/v1")
+	{
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.POST("/", middleware.AuthMiddleware(authService, "admin", "super admin"), webhookController.Create)
+			webhooks.GET("/", middleware.AuthMiddleware(authService, "admin", "super admin"), webhookController.FindAll)
+			webhooks.GET("/deliveries", middleware.AuthMiddleware(authService, "admin", "super admin"), webhookController.FindDeliveries)
+			webhooks.GET("/:subscriptionId", middleware.AuthMiddleware(authService, "admin", "super admin"), webhookController.FindByID)
+			webhooks.PUT("/:subscriptionId", middleware.AuthMiddleware(authService, "admin", "super admin"), webhookController.Update)
+			webhooks.DELETE("/:subscriptionId", middleware.AuthMiddleware(authService, "admin", "super admin"), webhookController.Delete)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"bioskuy/api/v1/webhook/dto"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, request dto.CreateSubscriptionRequest, c *gin.Context) (dto.SubscriptionResponse, error)
+	FindAll(ctx context.Context, c *gin.Context) ([]dto.SubscriptionResponse, error)
+	FindByID(ctx context.Context, id string, c *gin.Context) (dto.SubscriptionResponse, error)
+	Update(ctx context.Context, request dto.UpdateSubscriptionRequest, c *gin.Context) (dto.SubscriptionResponse, error)
+	Delete(ctx context.Context, id string, c *gin.Context) error
+	FindDeliveries(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.DeliveryResponse, web.Paging, error)
+}
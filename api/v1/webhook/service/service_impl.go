@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bioskuy/api/v1/webhook/dto"
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/api/v1/webhook/repository"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"bioskuy/web"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type webhookServiceImpl struct {
+	Repo     repository.SubscriptionRepository
+	Validate *validator.Validate
+	DB       *sql.DB
+}
+
+func NewWebhookService(repo repository.SubscriptionRepository, validate *validator.Validate, DB *sql.DB) WebhookService {
+	return &webhookServiceImpl{Repo: repo, Validate: validate, DB: DB}
+}
+
+// Create registers a subscription. The secret is only returned here, so the
+// subscriber has to keep it to verify deliveries.
+func (s *webhookServiceImpl) Create(ctx context.Context, request dto.CreateSubscriptionRequest, c *gin.Context) (dto.SubscriptionResponse, error) {
+	subscriptionResponse := dto.SubscriptionResponse{}
+
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+
+	err = validateEventTypes(request.EventTypes)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		secret, err = newSecret()
+		if err != nil {
+			c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return subscriptionResponse, err
+		}
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	result, err := s.Repo.Save(ctx, tx, entity.Subscription{
+		URL:        request.URL,
+		Secret:     secret,
+		EventTypes: request.EventTypes,
+		Active:     true,
+	}, c)
+	if err != nil {
+		return subscriptionResponse, err
+	}
+
+	subscriptionResponse = toSubscriptionResponse(result)
+	subscriptionResponse.Secret = result.Secret
+
+	return subscriptionResponse, nil
+}
+
+func (s *webhookServiceImpl) FindAll(ctx context.Context, c *gin.Context) ([]dto.SubscriptionResponse, error) {
+	subscriptionResponses := []dto.SubscriptionResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponses, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	results, err := s.Repo.FindAll(ctx, tx, c)
+	if err != nil {
+		return subscriptionResponses, err
+	}
+
+	for _, result := range results {
+		subscriptionResponses = append(subscriptionResponses, toSubscriptionResponse(result))
+	}
+
+	return subscriptionResponses, nil
+}
+
+func (s *webhookServiceImpl) FindByID(ctx context.Context, id string, c *gin.Context) (dto.SubscriptionResponse, error) {
+	subscriptionResponse := dto.SubscriptionResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	result, err := s.Repo.FindByID(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+
+	return toSubscriptionResponse(result), nil
+}
+
+// Update changes the URL, event types or active flag. Deactivated
+// subscriptions stop receiving new events and their pending deliveries wait
+// until they are activated again.
+func (s *webhookServiceImpl) Update(ctx context.Context, request dto.UpdateSubscriptionRequest, c *gin.Context) (dto.SubscriptionResponse, error) {
+	subscriptionResponse := dto.SubscriptionResponse{}
+
+	err := s.Validate.Struct(request)
+	if err != nil {
+		c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+
+	if request.EventTypes != nil {
+		err = validateEventTypes(request.EventTypes)
+		if err != nil {
+			c.Error(exception.ValidationError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+			return subscriptionResponse, err
+		}
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	subscription, err := s.Repo.FindByID(ctx, tx, request.ID, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return subscriptionResponse, err
+	}
+
+	if request.URL != "" {
+		subscription.URL = request.URL
+	}
+	if request.EventTypes != nil {
+		subscription.EventTypes = request.EventTypes
+	}
+	if request.Active != nil {
+		subscription.Active = *request.Active
+	}
+
+	result, err := s.Repo.Update(ctx, tx, subscription, c)
+	if err != nil {
+		return subscriptionResponse, err
+	}
+
+	return toSubscriptionResponse(result), nil
+}
+
+func (s *webhookServiceImpl) Delete(ctx context.Context, id string, c *gin.Context) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	subscription, err := s.Repo.FindByID(ctx, tx, id, c)
+	if err != nil {
+		c.Error(exception.NotFoundError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return err
+	}
+
+	return s.Repo.Delete(ctx, tx, subscription.ID, c)
+}
+
+// FindDeliveries lists the delivery log, newest first unless sorted
+// otherwise.
+func (s *webhookServiceImpl) FindDeliveries(ctx context.Context, query helper.ListQuery, c *gin.Context) ([]dto.DeliveryResponse, web.Paging, error) {
+	deliveryResponses := []dto.DeliveryResponse{}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
+		return deliveryResponses, web.Paging{}, err
+	}
+	defer helper.CommitAndRollback(tx, c)
+
+	results, total, err := s.Repo.FindDeliveries(ctx, tx, query, c)
+	if err != nil {
+		return deliveryResponses, web.Paging{}, err
+	}
+
+	for _, result := range results {
+		deliveryResponse := dto.DeliveryResponse{
+			ID:          result.ID,
+			EventID:     result.Event.ID,
+			EventType:   result.Event.Type,
+			Status:      result.Status(),
+			Attempts:    result.Attempts,
+			LastError:   result.LastError,
+			DeliveredAt: result.DeliveredAt,
+			FailedAt:    result.FailedAt,
+			CreatedAt:   result.CreatedAt,
+		}
+		if deliveryResponse.Status == entity.DeliveryStatusPending {
+			nextAttemptAt := result.NextAttemptAt
+			deliveryResponse.NextAttemptAt = &nextAttemptAt
+		}
+		deliveryResponses = append(deliveryResponses, deliveryResponse)
+	}
+
+	return deliveryResponses, query.Paging(total), nil
+}
+
+func validateEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return errors.New("event_types must list at least one event type")
+	}
+
+	for _, eventType := range eventTypes {
+		if !entity.IsEventType(eventType) {
+			return errors.New("unknown event type " + eventType)
+		}
+	}
+
+	return nil
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+func toSubscriptionResponse(subscription entity.Subscription) dto.SubscriptionResponse {
+	return dto.SubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
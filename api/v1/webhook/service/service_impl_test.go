@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bioskuy/api/v1/webhook/dto"
+	"bioskuy/api/v1/webhook/entity"
+	"bioskuy/api/v1/webhook/mock/repomock"
+	"bioskuy/exception"
+	"bioskuy/helper"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	mockRepo   *repomock.MockSubscriptionRepository
+	mockDb     *sql.DB
+	mockSql    sqlmock.Sqlmock
+	service    WebhookService
+	ctx        context.Context
+	ginContext *gin.Context
+}
+
+func (suite *WebhookServiceTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+
+	suite.mockRepo = new(repomock.MockSubscriptionRepository)
+	suite.mockDb = db
+	suite.mockSql = mock
+	suite.service = NewWebhookService(suite.mockRepo, validator.New(), db)
+	suite.ctx = context.Background()
+	suite.ginContext, _ = gin.CreateTestContext(httptest.NewRecorder())
+}
+
+func TestWebhookServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
+
+func (suite *WebhookServiceTestSuite) subscription() entity.Subscription {
+	return entity.Subscription{
+		ID:         "subscription-1",
+		URL:        "https://crm.example.com/hooks",
+		Secret:     "0123456789abcdef0123",
+		EventTypes: []string{entity.EventPaymentPaid},
+		Active:     true,
+	}
+}
+
+func (suite *WebhookServiceTestSuite) TestCreate_GeneratesSecret() {
+	request := dto.CreateSubscriptionRequest{URL: "https://crm.example.com/hooks", EventTypes: []string{entity.EventPaymentPaid, entity.EventShowtimeCancelled}}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("Save", suite.ctx, mock.Anything, mock.MatchedBy(func(s entity.Subscription) bool {
+		return s.URL == request.URL && len(s.Secret) == 64 && s.Active && len(s.EventTypes) == 2
+	}), suite.ginContext).Return(entity.Subscription{ID: "subscription-1", Secret: "generated-secret", Active: true}, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Create(suite.ctx, request, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "subscription-1", response.ID)
+	assert.Equal(suite.T(), "generated-secret", response.Secret)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *WebhookServiceTestSuite) TestCreate_UnknownEventType() {
+	request := dto.CreateSubscriptionRequest{URL: "https://crm.example.com/hooks", EventTypes: []string{"payment.teleported"}}
+
+	_, err := suite.service.Create(suite.ctx, request, suite.ginContext)
+
+	assert.EqualError(suite.T(), err, "unknown event type payment.teleported")
+	assert.IsType(suite.T(), exception.ValidationError{}, suite.ginContext.Errors.Last().Err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookServiceTestSuite) TestCreate_ValidationError() {
+	invalid := []dto.CreateSubscriptionRequest{
+		{URL: "not a url", EventTypes: []string{entity.EventPaymentPaid}},
+		{URL: "https://crm.example.com/hooks"},
+		{URL: "https://crm.example.com/hooks", EventTypes: []string{entity.EventPaymentPaid}, Secret: "short"},
+	}
+
+	for _, request := range invalid {
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		_, err := suite.service.Create(suite.ctx, request, ginContext)
+
+		assert.Error(suite.T(), err, request.URL)
+		assert.IsType(suite.T(), exception.ValidationError{}, ginContext.Errors.Last().Err, request.URL)
+	}
+}
+
+func (suite *WebhookServiceTestSuite) TestFindAll_HidesSecrets() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindAll", suite.ctx, mock.Anything, suite.ginContext).Return([]entity.Subscription{suite.subscription()}, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.FindAll(suite.ctx, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), response, 1)
+	assert.Equal(suite.T(), "", response[0].Secret)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *WebhookServiceTestSuite) TestUpdate_Deactivates() {
+	active := false
+	request := dto.UpdateSubscriptionRequest{ID: "subscription-1", Active: &active}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "subscription-1", suite.ginContext).Return(suite.subscription(), nil)
+	suite.mockRepo.On("Update", suite.ctx, mock.Anything, mock.MatchedBy(func(s entity.Subscription) bool {
+		return !s.Active && s.URL == "https://crm.example.com/hooks" && len(s.EventTypes) == 1
+	}), suite.ginContext).Return(entity.Subscription{ID: "subscription-1", Active: false}, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, err := suite.service.Update(suite.ctx, request, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), response.Active)
+	suite.mockRepo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *WebhookServiceTestSuite) TestUpdate_NotFound() {
+	request := dto.UpdateSubscriptionRequest{ID: "missing", URL: "https://crm.example.com/v2"}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "missing", suite.ginContext).Return(entity.Subscription{}, errors.New("webhook subscription not found"))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.service.Update(suite.ctx, request, suite.ginContext)
+
+	assert.Error(suite.T(), err)
+	assert.IsType(suite.T(), exception.NotFoundError{}, suite.ginContext.Errors.Last().Err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *WebhookServiceTestSuite) TestDelete_Success() {
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindByID", suite.ctx, mock.Anything, "subscription-1", suite.ginContext).Return(suite.subscription(), nil)
+	suite.mockRepo.On("Delete", suite.ctx, mock.Anything, "subscription-1", suite.ginContext).Return(nil)
+	suite.mockSql.ExpectCommit()
+
+	err := suite.service.Delete(suite.ctx, "subscription-1", suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *WebhookServiceTestSuite) TestFindDeliveries_Status() {
+	deliveredAt := time.Date(2024, 8, 2, 9, 0, 5, 0, time.UTC)
+	query := helper.ListQuery{Page: 1, Size: 10}
+
+	suite.mockSql.ExpectBegin()
+	suite.mockRepo.On("FindDeliveries", suite.ctx, mock.Anything, query, suite.ginContext).Return([]entity.Delivery{
+		{ID: "delivery-1", Attempts: 1, DeliveredAt: &deliveredAt, Event: entity.Event{ID: "event-1", Type: entity.EventPaymentPaid}},
+		{ID: "delivery-2", Attempts: 2, LastError: "subscriber responded 502 Bad Gateway", NextAttemptAt: deliveredAt.Add(time.Minute), Event: entity.Event{ID: "event-2", Type: entity.EventPaymentPaid}},
+	}, 2, nil)
+	suite.mockSql.ExpectCommit()
+
+	response, paging, err := suite.service.FindDeliveries(suite.ctx, query, suite.ginContext)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, paging.TotalData)
+	assert.Equal(suite.T(), entity.DeliveryStatusDelivered, response[0].Status)
+	assert.Nil(suite.T(), response[0].NextAttemptAt)
+	assert.Equal(suite.T(), entity.DeliveryStatusPending, response[1].Status)
+	assert.Equal(suite.T(), deliveredAt.Add(time.Minute), *response[1].NextAttemptAt)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
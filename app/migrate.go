@@ -0,0 +1,248 @@
+package app
+
+import (
+	"bioskuy/app/migrations"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies the embedded migrations and records them in
+// schema_migrations. Each migration runs in its own transaction.
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	loaded, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{DB: db, Migrations: loaded}, nil
+}
+
+// LoadMigrations reads every <version>_<name>.up.sql / .down.sql pair in
+// fsys, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[string]*Migration{}
+	for _, file := range files {
+		base, direction, ok := splitMigrationFile(file)
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected <version>_<name>.up.sql or .down.sql", file)
+		}
+
+		version, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: missing version prefix", file)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	loaded := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %s_%s has no up file", migration.Version, migration.Name)
+		}
+		loaded = append(loaded, *migration)
+	}
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Version < loaded[j].Version })
+
+	return loaded, nil
+}
+
+func splitMigrationFile(file string) (string, string, bool) {
+	if base, ok := strings.CutSuffix(file, ".up.sql"); ok {
+		return base, "up", true
+	}
+	if base, ok := strings.CutSuffix(file, ".down.sql"); ok {
+		return base, "down", true
+	}
+
+	return "", "", false
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR PRIMARY KEY,
+		name VARCHAR NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC')
+	)`
+
+	_, err := m.DB.ExecContext(ctx, query)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := m.DB.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[string]time.Time{}
+	for rows.Next() {
+		var version string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// Up applies every pending migration in order and returns the ones it ran.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	ran := []Migration{}
+	for _, migration := range m.Migrations {
+		done, err := m.run(ctx, migration, true)
+		if err != nil {
+			return ran, fmt.Errorf("migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+		if done {
+			ran = append(ran, migration)
+		}
+	}
+
+	return ran, nil
+}
+
+// Down rolls back the most recently applied migrations, at most steps of them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ran := []Migration{}
+	for i := len(m.Migrations) - 1; i >= 0 && len(ran) < steps; i-- {
+		migration := m.Migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return ran, fmt.Errorf("migration %s_%s has no down file", migration.Version, migration.Name)
+		}
+
+		done, err := m.run(ctx, migration, false)
+		if err != nil {
+			return ran, fmt.Errorf("migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+		if done {
+			ran = append(ran, migration)
+		}
+	}
+
+	return ran, nil
+}
+
+// Status lists every known migration with the time it was applied, if it was.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []MigrationStatus{}
+	for _, migration := range m.Migrations {
+		status := MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// run applies one migration in a transaction. The schema_migrations lock makes
+// concurrent runners (several instances starting at once) wait for each other
+// and re-check, so a migration is never applied twice.
+func (m *Migrator) run(ctx context.Context, migration Migration, up bool) (bool, error) {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE schema_migrations IN EXCLUSIVE MODE"); err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", migration.Version).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	if exists == up {
+		return false, nil
+	}
+
+	if up {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return false, err
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+	} else {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return false, err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
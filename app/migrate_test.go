@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMigrations = fstest.MapFS{
+	"20240101000000_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id INT);")},
+	"20240101000000_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+	"20240102000000_add_name.up.sql":        {Data: []byte("ALTER TABLE things ADD COLUMN name VARCHAR;")},
+	"20240102000000_add_name.down.sql":      {Data: []byte("ALTER TABLE things DROP COLUMN name;")},
+}
+
+func TestLoadMigrations_OrdersByVersion(t *testing.T) {
+	loaded, err := LoadMigrations(testMigrations)
+
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, "20240101000000", loaded[0].Version)
+	assert.Equal(t, "create_things", loaded[0].Name)
+	assert.Equal(t, "DROP TABLE things;", loaded[0].Down)
+	assert.Equal(t, "add_name", loaded[1].Name)
+}
+
+func TestLoadMigrations_RejectsBadNames(t *testing.T) {
+	_, err := LoadMigrations(fstest.MapFS{"create_things.sql": {Data: []byte("")}})
+	assert.Error(t, err)
+
+	_, err = LoadMigrations(fstest.MapFS{"20240101000000_only_down.down.sql": {Data: []byte("DROP TABLE x;")}})
+	assert.Error(t, err)
+}
+
+func TestNewMigrator_LoadsEmbeddedSchema(t *testing.T) {
+	migrator, err := NewMigrator(nil)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrator.Migrations)
+	assert.Equal(t, "create_bioskuy", migrator.Migrations[0].Name)
+	for _, migration := range migrator.Migrations {
+		assert.NotEmpty(t, migration.Down, migration.Version)
+	}
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	loaded, err := LoadMigrations(testMigrations)
+	assert.NoError(t, err)
+
+	return &Migrator{DB: db, Migrations: loaded}, mock
+}
+
+func TestMigrator_UpAppliesPending(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)")
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(exists).WithArgs("20240101000000").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(exists).WithArgs("20240102000000").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE things ADD COLUMN name VARCHAR;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)")).
+		WithArgs("20240102000000", "add_name").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ran, err := migrator.Up(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, ran, 1)
+	assert.Equal(t, "add_name", ran[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpStopsOnFailure(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE things (id INT);")).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	ran, err := migrator.Up(context.Background())
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, ran)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_DownRollsBackLatest(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	appliedAt := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow("20240101000000", appliedAt).AddRow("20240102000000", appliedAt))
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").WithArgs("20240102000000").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE things DROP COLUMN name;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).
+		WithArgs("20240102000000").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ran, err := migrator.Down(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, ran, 1)
+	assert.Equal(t, "20240102000000", ran[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	appliedAt := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow("20240101000000", appliedAt))
+
+	statuses, err := migrator.Status(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, appliedAt, *statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+}
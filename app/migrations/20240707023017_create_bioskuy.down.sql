@@ -2,11 +2,11 @@ DROP TABLE IF EXISTS payments_history;
 
 DROP TABLE IF EXISTS payments;
 
-DROP TABLE IF EXISTS genre_to_movie;
+DROP TABLE IF EXISTS genre_to_movies;
 
 DROP TABLE IF EXISTS seat_detail_for_bookings;
 
-DROP TABLE IF EXISTS seat_booking;
+DROP TABLE IF EXISTS seat_bookings;
 
 DROP TABLE IF EXISTS showtimes;
 
@@ -19,3 +19,11 @@ DROP TABLE IF EXISTS movies;
 DROP TABLE IF EXISTS genres;
 
 DROP TABLE IF EXISTS users;
+
+DROP TYPE IF EXISTS payment_status;
+
+DROP TYPE IF EXISTS seat_booking_status;
+
+DROP TYPE IF EXISTS movie_status;
+
+DROP TYPE IF EXISTS user_role;
@@ -0,0 +1,5 @@
+ALTER TABLE seat_detail_for_bookings DROP CONSTRAINT IF EXISTS seat_detail_for_bookings_showtime_seat_key;
+
+ALTER TABLE seat_detail_for_bookings DROP CONSTRAINT IF EXISTS seat_detail_for_bookings_showtime_id_fkey;
+
+ALTER TABLE seat_detail_for_bookings DROP COLUMN IF EXISTS showtime_id;
@@ -0,0 +1,18 @@
+ALTER TABLE seat_detail_for_bookings ADD COLUMN showtime_id UUID;
+
+UPDATE seat_detail_for_bookings sdfb
+SET showtime_id = sb.showtime_id
+FROM seat_bookings sb
+WHERE sdfb.seatBooking_id = sb.id;
+
+ALTER TABLE seat_detail_for_bookings ALTER COLUMN showtime_id SET NOT NULL;
+
+ALTER TABLE seat_detail_for_bookings
+    ADD CONSTRAINT seat_detail_for_bookings_showtime_id_fkey FOREIGN KEY (showtime_id) REFERENCES showtimes(id);
+
+-- A seat can only be sold once per screening, but freely across screenings.
+ALTER TABLE seat_detail_for_bookings
+    ADD CONSTRAINT seat_detail_for_bookings_showtime_seat_key UNIQUE (showtime_id, seat_id);
+
+-- seats.isAvailable no longer tracks bookings, it only marks whether a seat can be sold at all.
+UPDATE seats SET isAvailable = true;
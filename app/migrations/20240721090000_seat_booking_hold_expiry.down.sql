@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS seat_bookings_status_hold_expires_at_idx;
+
+ALTER TABLE seat_bookings DROP COLUMN IF EXISTS hold_expires_at;
@@ -0,0 +1,4 @@
+-- Pending bookings hold their seats only until hold_expires_at; the sweeper releases them afterwards.
+ALTER TABLE seat_bookings ADD COLUMN hold_expires_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC' + INTERVAL '15 minutes');
+
+CREATE INDEX IF NOT EXISTS seat_bookings_status_hold_expires_at_idx ON seat_bookings (status, hold_expires_at);
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS payment_events;
+
+ALTER TABLE payments ADD COLUMN seatdetailforbooking_id UUID;
+
+UPDATE payments p
+SET seatdetailforbooking_id = (
+    SELECT sdfb.id FROM seat_detail_for_bookings sdfb WHERE sdfb.seatBooking_id = p.seatbooking_id LIMIT 1
+);
+
+ALTER TABLE payments
+    ADD CONSTRAINT payments_seatdetailforbooking_id_fkey FOREIGN KEY (seatdetailforbooking_id) REFERENCES seat_detail_for_bookings(id);
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_seatbooking_id_fkey;
+ALTER TABLE payments DROP COLUMN IF EXISTS seatbooking_id;
+
+-- Enum values cannot be dropped in PostgreSQL; the added payment_status and
+-- seat_booking_status values are left in place.
@@ -0,0 +1,37 @@
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'pending';
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'failed';
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'refunded';
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'partially_refunded';
+
+ALTER TYPE seat_booking_status ADD VALUE IF NOT EXISTS 'cancelled';
+
+-- Payments belong to the whole booking so that a failed payment can be kept
+-- after the seat rows it covered have been released.
+ALTER TABLE payments ADD COLUMN seatbooking_id UUID;
+
+UPDATE payments p
+SET seatbooking_id = sdfb.seatBooking_id
+FROM seat_detail_for_bookings sdfb
+WHERE p.seatdetailforbooking_id = sdfb.id;
+
+ALTER TABLE payments ALTER COLUMN seatbooking_id SET NOT NULL;
+
+ALTER TABLE payments
+    ADD CONSTRAINT payments_seatbooking_id_fkey FOREIGN KEY (seatbooking_id) REFERENCES seat_bookings(id);
+
+ALTER TABLE payments DROP COLUMN seatdetailforbooking_id;
+
+-- One row per Midtrans notification. Midtrans retries until it gets a 200, so
+-- the same (transaction_id, transaction_status) pair may arrive many times.
+CREATE TABLE payment_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    payment_id UUID NOT NULL,
+    transaction_id VARCHAR NOT NULL,
+    transaction_status VARCHAR NOT NULL,
+    status_code VARCHAR,
+    gross_amount VARCHAR,
+    fraud_status VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    FOREIGN KEY (payment_id) REFERENCES payments(id),
+    CONSTRAINT payment_events_transaction_key UNIQUE (transaction_id, transaction_status)
+);
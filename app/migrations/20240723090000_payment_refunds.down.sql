@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_refund_seats;
+DROP TABLE IF EXISTS payment_refunds;
@@ -0,0 +1,20 @@
+CREATE TABLE payment_refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    payment_id UUID NOT NULL,
+    amount INT NOT NULL,
+    reason TEXT NOT NULL,
+    refunded_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    FOREIGN KEY (payment_id) REFERENCES payments(id),
+    FOREIGN KEY (refunded_by) REFERENCES users(id)
+);
+
+CREATE TABLE payment_refund_seats (
+    refund_id UUID NOT NULL,
+    seat_id UUID NOT NULL,
+    PRIMARY KEY (refund_id, seat_id),
+    FOREIGN KEY (refund_id) REFERENCES payment_refunds(id),
+    FOREIGN KEY (seat_id) REFERENCES seats(id)
+);
+
+CREATE INDEX payment_refunds_payment_id_idx ON payment_refunds (payment_id);
@@ -0,0 +1 @@
+ALTER TABLE studios DROP COLUMN IF EXISTS turnaround_minutes;
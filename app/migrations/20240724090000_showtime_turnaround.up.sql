@@ -0,0 +1,8 @@
+-- Time a studio needs between screenings (cleaning, ads and trailers).
+ALTER TABLE studios ADD COLUMN turnaround_minutes INT NOT NULL DEFAULT 15 CHECK (turnaround_minutes >= 0);
+
+-- movies.duration is in minutes; show_end used to be computed as if it were hours.
+UPDATE showtimes s
+SET show_end = s.show_start + m.duration * INTERVAL '1 minute'
+FROM movies m
+WHERE s.movie_id = m.id;
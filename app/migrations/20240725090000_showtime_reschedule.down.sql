@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS seat_refund_flags;
+
+ALTER TABLE showtimes DROP COLUMN IF EXISTS cancelled_at;
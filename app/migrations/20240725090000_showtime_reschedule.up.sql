@@ -0,0 +1,16 @@
+-- Showtimes with bookings are cancelled instead of deleted so their bookings and payments survive.
+ALTER TABLE showtimes ADD COLUMN cancelled_at TIMESTAMP;
+
+-- Sold seats that could not follow a rescheduled or cancelled showtime and are owed a refund.
+CREATE TABLE seat_refund_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    showtime_id UUID NOT NULL,
+    seatbooking_id UUID NOT NULL,
+    seat_id UUID NOT NULL,
+    reason VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    FOREIGN KEY (showtime_id) REFERENCES showtimes(id),
+    FOREIGN KEY (seatbooking_id) REFERENCES seat_bookings(id),
+    FOREIGN KEY (seat_id) REFERENCES seats(id),
+    UNIQUE (seatbooking_id, seat_id)
+);
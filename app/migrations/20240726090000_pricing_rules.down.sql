@@ -0,0 +1,7 @@
+ALTER TABLE seat_detail_for_bookings DROP COLUMN IF EXISTS unit_price;
+
+DROP TABLE IF EXISTS pricing_rules;
+DROP TABLE IF EXISTS holidays;
+
+ALTER TABLE seats DROP COLUMN IF EXISTS category;
+ALTER TABLE studios DROP COLUMN IF EXISTS class;
@@ -0,0 +1,36 @@
+-- Studio class and seat category are the inputs pricing rules key on besides time.
+ALTER TABLE studios ADD COLUMN class VARCHAR NOT NULL DEFAULT 'regular';
+ALTER TABLE seats ADD COLUMN category VARCHAR NOT NULL DEFAULT 'regular';
+
+CREATE TABLE holidays (
+    date DATE PRIMARY KEY NOT NULL,
+    name VARCHAR NOT NULL
+);
+
+-- A rule applies when every non-NULL condition matches. Matching rules are
+-- applied to movies.price in ascending priority: first the percentage, then
+-- the fixed amount.
+CREATE TABLE pricing_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    name VARCHAR NOT NULL,
+    day_of_week SMALLINT CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time TIME,
+    end_time TIME,
+    holiday BOOLEAN,
+    studio_class VARCHAR,
+    seat_category VARCHAR,
+    adjustment_percent INT NOT NULL DEFAULT 0,
+    adjustment_amount INT NOT NULL DEFAULT 0,
+    priority INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC')
+);
+
+-- The price a seat was sold at, fixed when the booking is made.
+ALTER TABLE seat_detail_for_bookings ADD COLUMN unit_price INT NOT NULL DEFAULT 0;
+
+UPDATE seat_detail_for_bookings sdfb
+SET unit_price = m.price
+FROM showtimes s
+JOIN movies m ON s.movie_id = m.id
+WHERE sdfb.showtime_id = s.id;
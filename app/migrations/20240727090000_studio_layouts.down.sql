@@ -0,0 +1,5 @@
+ALTER TABLE seats DROP COLUMN IF EXISTS grid_col;
+ALTER TABLE seats DROP COLUMN IF EXISTS grid_row;
+ALTER TABLE seats DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE studios DROP COLUMN IF EXISTS layout;
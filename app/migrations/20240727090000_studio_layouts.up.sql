@@ -0,0 +1,13 @@
+-- The layout a studio's seats were generated from; NULL for studios created
+-- before layouts existed, whose seat map is drawn from the seats alone.
+ALTER TABLE studios ADD COLUMN layout JSONB;
+
+ALTER TABLE seats ADD COLUMN kind VARCHAR NOT NULL DEFAULT 'standard';
+ALTER TABLE seats ADD COLUMN grid_row INT NOT NULL DEFAULT 0;
+ALTER TABLE seats ADD COLUMN grid_col INT NOT NULL DEFAULT 0;
+
+-- Place existing generated seats ("A-1", "B-12", ...) on the grid.
+UPDATE seats
+SET grid_row = ascii(split_part(seat_name, '-', 1)) - ascii('A'),
+    grid_col = split_part(seat_name, '-', 2)::int - 1
+WHERE seat_name ~ '^[A-Z]-[0-9]+$';
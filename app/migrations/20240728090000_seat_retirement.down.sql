@@ -0,0 +1 @@
+ALTER TABLE seats DROP COLUMN IF EXISTS retired_at;
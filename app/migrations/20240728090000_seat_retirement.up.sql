@@ -0,0 +1,3 @@
+-- Seats dropped from a studio's layout are retired rather than deleted when
+-- bookings, refunds or refund flags still point at them.
+ALTER TABLE seats ADD COLUMN retired_at TIMESTAMP;
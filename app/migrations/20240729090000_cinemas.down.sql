@@ -0,0 +1,8 @@
+-- Postgres cannot drop a value from an enum, so 'branch admin' stays defined;
+-- branch admins go back to being plain users.
+UPDATE users SET role = 'user' WHERE role = 'branch admin';
+ALTER TABLE users DROP COLUMN IF EXISTS cinema_id;
+
+ALTER TABLE studios DROP COLUMN IF EXISTS cinema_id;
+
+DROP TABLE IF EXISTS cinemas;
@@ -0,0 +1,27 @@
+-- A cinema is one location of the chain; its studios, showtimes and branch
+-- admins all belong to it.
+CREATE TABLE cinemas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    name VARCHAR NOT NULL,
+    address TEXT NOT NULL,
+    city VARCHAR NOT NULL,
+    timezone VARCHAR NOT NULL DEFAULT 'Asia/Jakarta',
+    opens_at TIME NOT NULL DEFAULT '10:00',
+    closes_at TIME NOT NULL DEFAULT '23:00',
+    created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC')
+);
+
+CREATE INDEX cinemas_city_idx ON cinemas (LOWER(city));
+
+-- Studios from before cinemas existed are placed in a single default cinema,
+-- to be renamed or split up by an admin.
+INSERT INTO cinemas (name, address, city)
+SELECT 'Bioskuy', '-', '-' WHERE EXISTS (SELECT 1 FROM studios);
+
+ALTER TABLE studios ADD COLUMN cinema_id UUID REFERENCES cinemas(id);
+UPDATE studios SET cinema_id = (SELECT id FROM cinemas ORDER BY created_at LIMIT 1);
+ALTER TABLE studios ALTER COLUMN cinema_id SET NOT NULL;
+
+-- Branch admins manage the studios and showtimes of users.cinema_id only.
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'branch admin';
+ALTER TABLE users ADD COLUMN cinema_id UUID REFERENCES cinemas(id);
@@ -0,0 +1,6 @@
+UPDATE showtimes s
+SET show_start = (s.show_start AT TIME ZONE 'UTC') AT TIME ZONE ci.timezone,
+    show_end = (s.show_end AT TIME ZONE 'UTC') AT TIME ZONE ci.timezone
+FROM studios st
+JOIN cinemas ci ON st.cinema_id = ci.id
+WHERE s.studio_id = st.id;
@@ -0,0 +1,9 @@
+-- showtimes.show_start/show_end are TIMESTAMP columns, so the offset a client
+-- sent was dropped on insert and rows hold the cinema's wall-clock time.
+-- From now on they hold UTC; convert the existing rows once.
+UPDATE showtimes s
+SET show_start = (s.show_start AT TIME ZONE ci.timezone) AT TIME ZONE 'UTC',
+    show_end = (s.show_end AT TIME ZONE ci.timezone) AT TIME ZONE 'UTC'
+FROM studios st
+JOIN cinemas ci ON st.cinema_id = ci.id
+WHERE s.studio_id = st.id;
@@ -0,0 +1,26 @@
+-- A ticket admits one seat of a paid booking. It is issued when the payment
+-- settles and voided when its seat is refunded; seat_detail_for_bookings rows
+-- are deleted on refund, so tickets keep their own copy of the seat.
+CREATE TABLE tickets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    payment_id UUID NOT NULL,
+    showtime_id UUID NOT NULL,
+    seat_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    issued_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    voided_at TIMESTAMP,
+    FOREIGN KEY (payment_id) REFERENCES payments(id),
+    FOREIGN KEY (showtime_id) REFERENCES showtimes(id),
+    FOREIGN KEY (seat_id) REFERENCES seats(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT tickets_payment_seat_key UNIQUE (payment_id, seat_id)
+);
+
+CREATE INDEX tickets_user_id_idx ON tickets (user_id);
+
+-- Payments settled before tickets existed get theirs now.
+INSERT INTO tickets (payment_id, showtime_id, seat_id, user_id)
+SELECT p.id, sdfb.showtime_id, sdfb.seat_id, p.user_id
+FROM payments p
+JOIN seat_detail_for_bookings sdfb ON sdfb.seatBooking_id = p.seatbooking_id
+WHERE p.status IN ('paid', 'partially_refunded');
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ticket_checkins;
+
+-- Postgres cannot drop a value from an enum, so 'staff' stays defined; staff
+-- go back to being plain users.
+UPDATE users SET role = 'user', cinema_id = NULL WHERE role = 'staff';
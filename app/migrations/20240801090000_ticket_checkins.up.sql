@@ -0,0 +1,18 @@
+-- Staff check tickets in at the doors of the cinema in users.cinema_id.
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'staff';
+
+-- A ticket can be checked in once. scanned_at is when the scanner read it,
+-- which is earlier than checked_in_at for scans uploaded after working
+-- offline.
+CREATE TABLE ticket_checkins (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    ticket_id UUID NOT NULL,
+    studio_id UUID NOT NULL,
+    checked_in_by UUID NOT NULL,
+    scanned_at TIMESTAMP NOT NULL,
+    checked_in_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id),
+    FOREIGN KEY (studio_id) REFERENCES studios(id),
+    FOREIGN KEY (checked_in_by) REFERENCES users(id),
+    CONSTRAINT ticket_checkins_ticket_key UNIQUE (ticket_id)
+);
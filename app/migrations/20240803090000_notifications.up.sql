@@ -0,0 +1,26 @@
+-- Emails to customers are queued here in the transaction of the change they
+-- announce and sent afterwards by the notifier, so a rolled back change never
+-- sends mail and a mail server outage only delays it.
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    user_id UUID NOT NULL,
+    kind VARCHAR NOT NULL,
+    recipient VARCHAR NOT NULL,
+    recipient_name VARCHAR NOT NULL,
+    data JSONB NOT NULL,
+    -- Set for notifications that must be queued at most once, such as the
+    -- reminder of a booking.
+    dedupe_key VARCHAR,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    last_error TEXT,
+    sent_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT notifications_dedupe_key UNIQUE (dedupe_key)
+);
+
+CREATE INDEX notifications_due_idx ON notifications (next_attempt_at)
+    WHERE sent_at IS NULL AND failed_at IS NULL;
+CREATE INDEX notifications_user_id_idx ON notifications (user_id);
@@ -0,0 +1,3 @@
+DELETE FROM payment_refund_seats WHERE refund_id IN (SELECT id FROM payment_refunds WHERE refunded_by IS NULL);
+DELETE FROM payment_refunds WHERE refunded_by IS NULL;
+ALTER TABLE payment_refunds ALTER COLUMN refunded_by SET NOT NULL;
@@ -0,0 +1,3 @@
+-- Refunds the system makes on its own, such as for a payment that settles
+-- after its seat hold expired, have no user behind them.
+ALTER TABLE payment_refunds ALTER COLUMN refunded_by DROP NOT NULL;
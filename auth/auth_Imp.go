@@ -30,6 +30,7 @@ func (s *jwtService) GenerateToken(user entity.User, c *gin.Context) (string, er
 	claim["name"] = user.Name
 	claim["role"] = user.Role
 	claim["email"] = user.Email
+	claim["cinema_id"] = user.CinemaID
 	claim["exp"] = time.Now().Add(time.Duration(duration) * time.Minute).Unix()
 
 
@@ -1,6 +1,7 @@
 package exception
 
 import (
+	"bioskuy/logger"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +25,10 @@ func ErrorHandler(c *gin.Context) {
 				return
 			}
 
+			if conflictError(c, err) {
+				return
+			}
+
 			if internalServerError(c, err) {
 				return
 			}
@@ -33,7 +38,7 @@ func ErrorHandler(c *gin.Context) {
 
 func forbiddenError(c *gin.Context, err error) bool {
 	if e, ok := err.(ForbiddenError); ok {
-		c.JSON(http.StatusForbidden, gin.H{"error": e.Error()})
+		c.JSON(http.StatusForbidden, errorBody(c, e.Error()))
 		return true
 	}
 	return false
@@ -41,7 +46,7 @@ func forbiddenError(c *gin.Context, err error) bool {
 
 func validationError(c *gin.Context, err error) bool {
 	if e, ok := err.(ValidationError); ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": e.Error()})
+		c.JSON(http.StatusBadRequest, errorBody(c, e.Error()))
 		return true
 	}
 	return false
@@ -49,7 +54,15 @@ func validationError(c *gin.Context, err error) bool {
 
 func notFoundError(c *gin.Context, err error) bool {
 	if e, ok := err.(NotFoundError); ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": e.Message})
+		c.JSON(http.StatusNotFound, errorBody(c, e.Message))
+		return true
+	}
+	return false
+}
+
+func conflictError(c *gin.Context, err error) bool {
+	if e, ok := err.(ConflictError); ok {
+		c.JSON(http.StatusConflict, errorBody(c, e.Message))
 		return true
 	}
 	return false
@@ -57,8 +70,21 @@ func notFoundError(c *gin.Context, err error) bool {
 
 func internalServerError(c *gin.Context, err error) bool {
 	if e, ok := err.(InternalServerError); ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": e.Message})
+		c.JSON(http.StatusInternalServerError, errorBody(c, e.Message))
 		panic(e)
 	}
 	return false
 }
+
+// errorBody is the JSON body of an error response. It carries the request ID
+// so that a client can quote it when reporting the error.
+func errorBody(c *gin.Context, message string) gin.H {
+	body := gin.H{"error": message}
+	if c.Request != nil {
+		if id := logger.RequestID(c.Request.Context()); id != "" {
+			body["request_id"] = id
+		}
+	}
+
+	return body
+}
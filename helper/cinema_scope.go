@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"bioskuy/api/v1/user/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CanManageCinema reports whether the signed-in admin may manage studios and
+// showtimes of the given cinema. Branch admins are limited to the cinema in
+// their token; the other roles let through by the route are not limited.
+func CanManageCinema(c *gin.Context, cinemaID string) bool {
+	if c.GetString("role") != entity.RoleBranchAdmin {
+		return true
+	}
+	return cinemaID != "" && c.GetString("cinema_id") == cinemaID
+}
+
+// ScopeToCinema limits a listing to the cinema of a signed-in branch admin,
+// overriding any cinema_id filter they asked for. Other roles are unchanged.
+// It reports false for a branch admin without a cinema, who may list nothing.
+func ScopeToCinema(c *gin.Context, query ListQuery) (ListQuery, bool) {
+	if c.GetString("role") != entity.RoleBranchAdmin {
+		return query, true
+	}
+	cinemaID := c.GetString("cinema_id")
+	if cinemaID == "" {
+		return query, false
+	}
+	return query.With("cinema_id", cinemaID), true
+}
+
+// CanWorkAtCinema reports whether the signed-in user may run the doors of the
+// given cinema. Staff and branch admins are limited to the cinema in their
+// token; the other roles let through by the route are not limited.
+func CanWorkAtCinema(c *gin.Context, cinemaID string) bool {
+	role := c.GetString("role")
+	if role != entity.RoleStaff && role != entity.RoleBranchAdmin {
+		return true
+	}
+	return cinemaID != "" && c.GetString("cinema_id") == cinemaID
+}
@@ -2,7 +2,11 @@ package helper
 
 import (
 	"bioskuy/exception"
+	"bioskuy/logger"
+	"log/slog"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -20,6 +24,28 @@ type Config struct {
 	GOOGLE_CLIENT_ID string
 	GOOGLE_CLIENT_SECRET string
 	MIDTRANS_SERVER_KEY string
+	SeatHoldDuration string
+	SeatHoldSweepInterval string
+	PaymentGateway string
+	MidtransEnvironment string
+	RefundCutoff string
+	CheckInOpensBefore string
+	ManifestSigningKey string
+	ManifestValidFor string
+	MigrateOnStartup string
+	WebhookDispatchInterval string
+	WebhookMaxAttempts string
+	EmailSender string
+	SMTPHost string
+	SMTPPort string
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom string
+	EmailOutboxDir string
+	NotificationInterval string
+	NotificationMaxAttempts string
+	ReminderBefore string
+	LogLevel string
 }
 
 func NewConfig( c *gin.Context) *Config {
@@ -40,5 +66,168 @@ func NewConfig( c *gin.Context) *Config {
 		GOOGLE_CLIENT_ID: os.Getenv("GOOGLE_CLIENT_ID"),
 		GOOGLE_CLIENT_SECRET: os.Getenv("GOOGLE_CLIENT_SECRET"),
 		MIDTRANS_SERVER_KEY: os.Getenv("MIDTRANS_SERVER_KEY"),
+		SeatHoldDuration: os.Getenv("SEAT_HOLD_DURATION"),
+		SeatHoldSweepInterval: os.Getenv("SEAT_HOLD_SWEEP_INTERVAL"),
+		PaymentGateway: os.Getenv("PAYMENT_GATEWAY"),
+		MidtransEnvironment: os.Getenv("MIDTRANS_ENVIRONMENT"),
+		RefundCutoff: os.Getenv("REFUND_CUTOFF"),
+		CheckInOpensBefore: os.Getenv("CHECKIN_OPENS_BEFORE"),
+		ManifestSigningKey: os.Getenv("MANIFEST_SIGNING_KEY"),
+		ManifestValidFor: os.Getenv("MANIFEST_VALID_FOR"),
+		MigrateOnStartup: os.Getenv("MIGRATE_ON_STARTUP"),
+		WebhookDispatchInterval: os.Getenv("WEBHOOK_DISPATCH_INTERVAL"),
+		WebhookMaxAttempts: os.Getenv("WEBHOOK_MAX_ATTEMPTS"),
+		EmailSender: os.Getenv("EMAIL_SENDER"),
+		SMTPHost: os.Getenv("SMTP_HOST"),
+		SMTPPort: os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		EmailFrom: os.Getenv("EMAIL_FROM"),
+		EmailOutboxDir: os.Getenv("EMAIL_OUTBOX_DIR"),
+		NotificationInterval: os.Getenv("NOTIFICATION_INTERVAL"),
+		NotificationMaxAttempts: os.Getenv("NOTIFICATION_MAX_ATTEMPTS"),
+		ReminderBefore: os.Getenv("REMINDER_BEFORE"),
+		LogLevel: os.Getenv("LOG_LEVEL"),
 	}
-}
\ No newline at end of file
+}
+
+// HoldDuration is how long a pending seat booking keeps its seats, read from
+// SEAT_HOLD_DURATION in minutes. Defaults to 15 minutes.
+func (c *Config) HoldDuration() time.Duration {
+	minutes, err := strconv.Atoi(c.SeatHoldDuration)
+	if err != nil || minutes <= 0 {
+		return 15 * time.Minute
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// HoldSweepInterval is how often expired holds are released, read from
+// SEAT_HOLD_SWEEP_INTERVAL in seconds. Defaults to one minute.
+func (c *Config) HoldSweepInterval() time.Duration {
+	seconds, err := strconv.Atoi(c.SeatHoldSweepInterval)
+	if err != nil || seconds <= 0 {
+		return time.Minute
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// RefundCutoffDuration is how long before show start customers can still
+// refund a booking, read from REFUND_CUTOFF in minutes. Defaults to 2 hours.
+func (c *Config) RefundCutoffDuration() time.Duration {
+	minutes, err := strconv.Atoi(c.RefundCutoff)
+	if err != nil || minutes < 0 {
+		return 2 * time.Hour
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// CheckInOpensBeforeDuration is how long before show start tickets can be
+// checked in, read from CHECKIN_OPENS_BEFORE in minutes. Defaults to 1 hour.
+func (c *Config) CheckInOpensBeforeDuration() time.Duration {
+	minutes, err := strconv.Atoi(c.CheckInOpensBefore)
+	if err != nil || minutes < 0 {
+		return time.Hour
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// ManifestValidity is how long a downloaded manifest lets scanners keep
+// checking tickets in offline, read from MANIFEST_VALID_FOR in minutes.
+// Defaults to 12 hours.
+func (c *Config) ManifestValidity() time.Duration {
+	minutes, err := strconv.Atoi(c.ManifestValidFor)
+	if err != nil || minutes <= 0 {
+		return 12 * time.Hour
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// MidtransProduction reports whether MIDTRANS_ENVIRONMENT selects the
+// production API. Anything else uses the sandbox.
+func (c *Config) MidtransProduction() bool {
+	return c.MidtransEnvironment == "production"
+}
+
+// ShouldMigrateOnStartup reports whether MIGRATE_ON_STARTUP asks the server
+// to apply pending migrations before serving.
+func (c *Config) ShouldMigrateOnStartup() bool {
+	migrate, err := strconv.ParseBool(c.MigrateOnStartup)
+	return err == nil && migrate
+}
+
+// WebhookDispatchEvery is how often due webhook deliveries are sent, read
+// from WEBHOOK_DISPATCH_INTERVAL in seconds. Defaults to 10 seconds.
+func (c *Config) WebhookDispatchEvery() time.Duration {
+	seconds, err := strconv.Atoi(c.WebhookDispatchInterval)
+	if err != nil || seconds <= 0 {
+		return 10 * time.Second
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// WebhookAttempts is how many times a webhook delivery is tried before it is
+// given up, read from WEBHOOK_MAX_ATTEMPTS. Defaults to 8.
+func (c *Config) WebhookAttempts() int {
+	attempts, err := strconv.Atoi(c.WebhookMaxAttempts)
+	if err != nil || attempts <= 0 {
+		return 8
+	}
+
+	return attempts
+}
+
+// EmailOutboxDirectory is where the file sender writes emails, read from
+// EMAIL_OUTBOX_DIR. Defaults to tmp/emails.
+func (c *Config) EmailOutboxDirectory() string {
+	if c.EmailOutboxDir == "" {
+		return "tmp/emails"
+	}
+
+	return c.EmailOutboxDir
+}
+
+// NotificationEvery is how often queued emails are sent and reminders
+// queued, read from NOTIFICATION_INTERVAL in seconds. Defaults to 30 seconds.
+func (c *Config) NotificationEvery() time.Duration {
+	seconds, err := strconv.Atoi(c.NotificationInterval)
+	if err != nil || seconds <= 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// NotificationAttempts is how many times an email is tried before it is
+// given up, read from NOTIFICATION_MAX_ATTEMPTS. Defaults to 5.
+func (c *Config) NotificationAttempts() int {
+	attempts, err := strconv.Atoi(c.NotificationMaxAttempts)
+	if err != nil || attempts <= 0 {
+		return 5
+	}
+
+	return attempts
+}
+
+// ReminderBeforeDuration is how long before show start customers are
+// reminded of their booking, read from REMINDER_BEFORE in minutes. Defaults
+// to 3 hours.
+func (c *Config) ReminderBeforeDuration() time.Duration {
+	minutes, err := strconv.Atoi(c.ReminderBefore)
+	if err != nil || minutes <= 0 {
+		return 3 * time.Hour
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// LoggingLevel is the minimum level of log records, read from LOG_LEVEL as
+// debug, info, warn or error. Defaults to info.
+func (c *Config) LoggingLevel() slog.Level {
+	return logger.ParseLevel(c.LogLevel)
+}
@@ -0,0 +1,213 @@
+package helper
+
+import (
+	"bioskuy/web"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// ListQuery is the paging, sorting and filtering of a list endpoint.
+type ListQuery struct {
+	Page int
+	Size int
+	Sort string
+	Desc bool
+	// Search is matched case-insensitively against the listing's search
+	// columns.
+	Search string
+	// Filters are exact matches keyed by API name; empty values are skipped.
+	Filters map[string]string
+}
+
+// ListSpec describes what one listing may be sorted, filtered and searched
+// by. Map keys are API names, values are SQL expressions.
+type ListSpec struct {
+	Sorts       map[string]string
+	DefaultSort string
+	// Key is a unique column appended to every ORDER BY so pages are stable.
+	Key     string
+	Filters map[string]string
+	// Scopes are filters whose values name a fixed condition instead of being
+	// matched, e.g. when=upcoming.
+	Scopes map[string]map[string]string
+	Search []string
+}
+
+// ListClauses is a ListQuery rendered against a ListSpec. Where and Args are
+// shared by the count and the list query; Page holds OrderBy plus LIMIT and
+// OFFSET with placeholders numbered after Args.
+type ListClauses struct {
+	Where    string
+	Args     []interface{}
+	OrderBy  string
+	Page     string
+	PageArgs []interface{}
+}
+
+// ListQueryFrom reads page, size, sort, order and q from the query string,
+// plus the given filter keys. Page defaults to 1 and size to DefaultPageSize,
+// capped at MaxPageSize.
+func ListQueryFrom(c *gin.Context, filters ...string) (ListQuery, error) {
+	query := ListQuery{Page: 1, Size: DefaultPageSize, Sort: c.Query("sort"), Search: c.Query("q")}
+
+	if page := c.Query("page"); page != "" {
+		value, err := strconv.Atoi(page)
+		if err != nil || value < 1 {
+			return query, fmt.Errorf("page must be a positive number")
+		}
+		query.Page = value
+	}
+
+	if size := c.Query("size"); size != "" {
+		value, err := strconv.Atoi(size)
+		if err != nil || value < 1 {
+			return query, fmt.Errorf("size must be a positive number")
+		}
+		query.Size = int(math.Min(float64(value), MaxPageSize))
+	}
+
+	switch c.Query("order") {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return query, fmt.Errorf("order must be asc or desc")
+	}
+
+	for _, key := range filters {
+		if value := c.Query(key); value != "" {
+			query = query.With(key, value)
+		}
+	}
+
+	return query, nil
+}
+
+// OwnListQuery reads a listing of the signed-in user's own records. Besides
+// the given filters it accepts when=upcoming|past; past listings default to
+// the most recent first.
+func OwnListQuery(c *gin.Context, filters ...string) (ListQuery, error) {
+	query, err := ListQueryFrom(c, append(filters, "when")...)
+	if err != nil {
+		return query, err
+	}
+
+	if query.Filters["when"] == "past" && c.Query("order") == "" {
+		query.Desc = true
+	}
+
+	return query.With("user_id", c.GetString("user_id")), nil
+}
+
+// With returns a copy of the query with one more filter, leaving the
+// receiver's filters untouched.
+func (q ListQuery) With(key, value string) ListQuery {
+	filters := make(map[string]string, len(q.Filters)+1)
+	for k, v := range q.Filters {
+		filters[k] = v
+	}
+	filters[key] = value
+	q.Filters = filters
+	return q
+}
+
+// Clauses renders the query against spec. Unknown sort or filter keys are
+// rejected rather than ignored.
+func (q ListQuery) Clauses(spec ListSpec) (ListClauses, error) {
+	clauses := ListClauses{}
+	conditions := []string{}
+
+	for _, key := range sortedKeys(q.Filters) {
+		value := q.Filters[key]
+		if value == "" {
+			continue
+		}
+		if scopes, ok := spec.Scopes[key]; ok {
+			condition, ok := scopes[value]
+			if !ok {
+				return clauses, fmt.Errorf("%s must be one of %s", key, strings.Join(sortedKeys(scopes), ", "))
+			}
+			conditions = append(conditions, condition)
+			continue
+		}
+		column, ok := spec.Filters[key]
+		if !ok {
+			return clauses, fmt.Errorf("cannot filter by %s", key)
+		}
+		clauses.Args = append(clauses.Args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(clauses.Args)))
+	}
+
+	if q.Search != "" && len(spec.Search) > 0 {
+		clauses.Args = append(clauses.Args, "%"+escapeLike(q.Search)+"%")
+		matches := make([]string, len(spec.Search))
+		for i, column := range spec.Search {
+			matches[i] = fmt.Sprintf("%s ILIKE $%d", column, len(clauses.Args))
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	if len(conditions) > 0 {
+		clauses.Where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sortKey := q.Sort
+	if sortKey == "" {
+		sortKey = spec.DefaultSort
+	}
+	column, ok := spec.Sorts[sortKey]
+	if !ok {
+		return clauses, fmt.Errorf("cannot sort by %s", sortKey)
+	}
+
+	direction := "ASC"
+	if q.Desc {
+		direction = "DESC"
+	}
+
+	clauses.OrderBy = fmt.Sprintf("ORDER BY %s %s, %s", column, direction, spec.Key)
+	clauses.PageArgs = []interface{}{q.Size, (q.Page - 1) * q.Size}
+	clauses.Page = fmt.Sprintf("%s LIMIT $%d OFFSET $%d", clauses.OrderBy, len(clauses.Args)+1, len(clauses.Args)+2)
+
+	return clauses, nil
+}
+
+// AllArgs returns the args of the paged list query.
+func (l ListClauses) AllArgs() []interface{} {
+	args := make([]interface{}, 0, len(l.Args)+len(l.PageArgs))
+	args = append(args, l.Args...)
+	return append(args, l.PageArgs...)
+}
+
+// Paging reports where the query sits among total matching rows.
+func (q ListQuery) Paging(total int) web.Paging {
+	return web.Paging{
+		Page:       q.Page,
+		Size:       q.Size,
+		TotalData:  total,
+		TotalPages: int(math.Ceil(float64(total) / float64(q.Size))),
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,23 @@
+package helper
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// MidtransSignature computes the signature_key Midtrans attaches to HTTP
+// notifications: SHA512(order_id + status_code + gross_amount + server_key).
+func MidtransSignature(orderID, statusCode, grossAmount, serverKey string) string {
+	sum := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func VerifyMidtransSignature(orderID, statusCode, grossAmount, serverKey, signature string) bool {
+	if serverKey == "" || signature == "" {
+		return false
+	}
+
+	expected := MidtransSignature(orderID, statusCode, grossAmount, serverKey)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
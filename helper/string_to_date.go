@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// StringToDate parses an RFC3339 time and returns it in UTC.
 func StringToDate(value string, c *gin.Context) (time.Time){
 	result, err := time.Parse(time.RFC3339, value)
 	if err != nil {
@@ -14,5 +15,5 @@ func StringToDate(value string, c *gin.Context) (time.Time){
 		return  result
 	}
 
-	return result
+	return result.UTC()
 }
\ No newline at end of file
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"fmt"
+	"time"
+)
+
+// localLayouts are the wall-clock formats accepted without a UTC offset.
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// Location loads an IANA timezone such as a cinema's. An empty or unknown
+// name falls back to UTC so a bad row never breaks a response.
+func Location(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ParseLocalTime parses a time sent by a client. RFC3339 values keep their
+// offset, wall-clock values without one are read in loc. The result is in
+// UTC, which is how showtimes are stored.
+func ParseLocalTime(value string, loc *time.Location) (time.Time, error) {
+	if result, err := time.Parse(time.RFC3339, value); err == nil {
+		return result.UTC(), nil
+	}
+	for _, layout := range localLayouts {
+		if result, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return result.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use RFC3339 or YYYY-MM-DDTHH:MM", value)
+}
+
+// InZone renders t as wall-clock time in the named timezone; see Location.
+func InZone(t time.Time, name string) time.Time {
+	return t.In(Location(name))
+}
@@ -0,0 +1,101 @@
+// Package logger sets up structured JSON logging and carries the request ID
+// of the current request through context.Context, so that every layer that
+// logs with FromContext tags its lines with it.
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+type requestIDKey struct{}
+
+// New returns a JSON logger writing to w at level. Sensitive attributes are
+// redacted; see Redact.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: replaceAttr,
+	}))
+}
+
+// ParseLevel reads debug, info, warn or error, falling back to info.
+func ParseLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo
+	}
+
+	return level
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" outside a request.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger, tagged with the request ID of ctx
+// when it has one.
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+
+	return slog.Default()
+}
+
+// sensitiveKeys are attributes and query parameters whose values are never
+// logged.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"password":      true,
+	"secret":        true,
+	"signature_key": true,
+	"code":          true,
+	"state":         true,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+	jwtPattern   = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+)
+
+// IsSensitive reports whether values under key must be redacted.
+func IsSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// Redact masks email addresses down to their first letter and domain, and
+// replaces JSON web tokens, wherever they appear in value.
+func Redact(value string) string {
+	value = jwtPattern.ReplaceAllString(value, "[REDACTED]")
+	return emailPattern.ReplaceAllString(value, "$1***@$2")
+}
+
+func replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, "[REDACTED]")
+	}
+
+	if attr.Value.Kind() == slog.KindString {
+		return slog.String(attr.Key, Redact(attr.Value.String()))
+	}
+
+	return attr
+}
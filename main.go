@@ -1,19 +1,36 @@
 package main
 
 import (
+	checkinroute "bioskuy/api/v1/checkin/route"
+	cinemaroute "bioskuy/api/v1/cinema/route"
+	"bioskuy/api/v1/notification/notifier"
+	notificationRepo "bioskuy/api/v1/notification/repository"
+	"bioskuy/api/v1/notification/sender"
 	genreroute "bioskuy/api/v1/genre/route"
 	genretomovieroute "bioskuy/api/v1/genretomovie/route"
 	movieroute "bioskuy/api/v1/movies/route"
 	paymentRoute "bioskuy/api/v1/payment/route"
+	pricingroute "bioskuy/api/v1/pricing/route"
 	seatroute "bioskuy/api/v1/seat/route"
+	seatbookingRepo "bioskuy/api/v1/seatbooking/repository"
 	seatbookingroute "bioskuy/api/v1/seatbooking/route"
+	"bioskuy/api/v1/seatbooking/sweeper"
 	showtimeroute "bioskuy/api/v1/showtime/route"
 	studioroute "bioskuy/api/v1/studio/route"
+	ticketroute "bioskuy/api/v1/ticket/route"
 	"bioskuy/api/v1/user/route"
+	"bioskuy/api/v1/webhook/dispatcher"
+	webhookRepo "bioskuy/api/v1/webhook/repository"
+	webhookroute "bioskuy/api/v1/webhook/route"
 	"bioskuy/app"
 	"bioskuy/exception"
 	"bioskuy/helper"
+	"bioskuy/logger"
+	"bioskuy/middleware"
+	"context"
 	"fmt"
+	"log/slog"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -22,12 +39,30 @@ import (
 func main() {
 
 	var c *gin.Context
-	router := gin.Default()
 	validate := validator.New()
 	config := helper.NewConfig(c)
+	slog.SetDefault(logger.New(os.Stdout, config.LoggingLevel()))
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware(), middleware.LoggerMiddleware(), middleware.RecoveryMiddleware())
 	db := app.GetConnection(config)
 	defer db.Close()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), db, os.Args[2:]); err != nil {
+			slog.Error("migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if config.ShouldMigrateOnStartup() {
+		if err := runMigrate(context.Background(), db, []string{"up"}); err != nil {
+			slog.Error("migration failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	router.GET("/", func(ctx *gin.Context) {
 		ctx.String(200, fmt.Sprint("Welcome to bioksuy"))
 	})
@@ -38,15 +73,29 @@ func main() {
 	genreroute.GenreRoute(router, validate, db, config)
 	movieroute.MovieRoute(router, validate, db, config)
 	genretomovieroute.GenreToMovieRoute(router, validate, db, config)
+	cinemaroute.CinemaRoute(router, validate, db, config)
 	studioroute.StudioRoute(router, validate, db, config)
 	seatroute.SeatRoute(router, validate, db)
 	showtimeroute.ShowtimeRoute(router, validate, db, config)
 	seatbookingroute.SeatBookingRoute(router, validate, db, config)
 	paymentRoute.PaymentRoute(router, validate, db, config)
-	
+	pricingroute.PricingRoute(router, validate, db, config)
+	ticketroute.TicketRoute(router, db, config)
+	checkinroute.CheckInRoute(router, validate, db, config)
+	webhookroute.WebhookRoute(router, validate, db, config)
+
+	holdSweeper := sweeper.NewSweeper(seatbookingRepo.NewSeatBookingRepository(), notificationRepo.NewNotificationRepository(), webhookRepo.NewOutboxRepository(), db, config.HoldSweepInterval())
+	holdSweeper.Start(context.Background())
+
+	webhookDispatcher := dispatcher.NewDispatcher(webhookRepo.NewOutboxRepository(), db, config.WebhookDispatchEvery(), config.WebhookAttempts())
+	webhookDispatcher.Start(context.Background())
+
+	emailNotifier := notifier.NewNotifier(notificationRepo.NewNotificationRepository(), db, sender.NewSender(config), config.NotificationEvery(), config.NotificationAttempts(), config.ReminderBeforeDuration())
+	emailNotifier.Start(context.Background())
+
 	err := router.Run(":3000")
 	if err != nil {
-		c.Error(exception.InternalServerError{Message: err.Error()}).SetType(gin.ErrorTypePublic)
-		return
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
 	}
 }
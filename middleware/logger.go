@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bioskuy/exception"
+	"bioskuy/logger"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoggerMiddleware writes one structured access log line per request,
+// replacing gin's text logger. Server errors are logged at error level and
+// client errors at warn.
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		if query := redactQuery(c.Request.URL.Query()); query != "" {
+			attrs = append(attrs, "query", query)
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			attrs = append(attrs, "user_id", fmt.Sprint(userID))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "error", c.Errors.Last().Error())
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		logger.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// RecoveryMiddleware turns a panic into a 500 response. Panics other than
+// the ones exception.ErrorHandler raises for internal server errors, which
+// are already reported, are logged with their stack.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			if _, ok := r.(exception.InternalServerError); !ok {
+				logger.FromContext(c.Request.Context()).Error("panic recovered", "error", fmt.Sprint(r), "stack", string(debug.Stack()))
+			}
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+
+		c.Next()
+	}
+}
+
+// redactQuery encodes query with the values of sensitive parameters, such
+// as the OAuth code, replaced.
+func redactQuery(query url.Values) string {
+	for key := range query {
+		if logger.IsSensitive(key) {
+			query[key] = []string{"[REDACTED]"}
+		}
+	}
+
+	return query.Encode()
+}
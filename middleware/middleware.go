@@ -42,6 +42,7 @@ func AuthMiddleware(authService auth.Auth, allowedRoles ...string) gin.HandlerFu
 		c.Set("name", claims["name"])
 		c.Set("role", role)
 		c.Set("email", claims["email"])
+		c.Set("cinema_id", claims["cinema_id"])
 
 		c.Next()
 	}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bioskuy/app"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const migrateUsage = "usage: bioskuy migrate up | down [steps] | status"
+
+// runMigrate handles `bioskuy migrate up`, `migrate down [steps]` and
+// `migrate status`. down rolls back one migration unless told otherwise.
+func runMigrate(ctx context.Context, db *sql.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, err := app.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up(ctx)
+		for _, migration := range ran {
+			fmt.Printf("applied %s_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(ran) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errors.New("down: steps must be a positive number")
+			}
+		}
+
+		ran, err := migrator.Down(ctx, steps)
+		for _, migration := range ran {
+			fmt.Printf("rolled back %s_%s\n", migration.Version, migration.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-16s %-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	}
+
+	return errors.New(migrateUsage)
+}
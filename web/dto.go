@@ -7,8 +7,10 @@ type FormatResponsePaging struct {
 }
 
 type Paging struct {
-	Page      int `json:"page"`
-	TotalData int `json:"total-data"`
+	Page       int `json:"page"`
+	Size       int `json:"size"`
+	TotalData  int `json:"total-data"`
+	TotalPages int `json:"total-pages"`
 }
 
 type FormatResponse struct {